@@ -17,11 +17,26 @@ package olric
 import (
 	"errors"
 
+	"github.com/olric-data/olric/config"
 	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/server"
 	"github.com/tidwall/redcon"
 )
 
+// defaultUser is the user that authenticates with AUTH <password>.
+const defaultUser = config.DefaultUser
+
+// authenticate checks the credentials of a connection.
+func (db *Olric) authenticate(username, password string) error {
+	if !db.config.Authentication.Enabled() {
+		return errors.New("AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?")
+	}
+	if !db.acl.Authenticate(username, password) {
+		return ErrWrongPass
+	}
+	return nil
+}
+
 // authCommandHandler handles authentication requests sent by clients and verifies the provided password for access.
 func (db *Olric) authCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 	authCmd, err := protocol.ParseAuthCommand(cmd)
@@ -30,16 +45,53 @@ func (db *Olric) authCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		return
 	}
 
-	if !db.config.Authentication.Enabled() {
-		protocol.WriteError(conn, errors.New("AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?"))
+	username := authCmd.Username
+	if username == "" {
+		username = defaultUser
+	}
+	if err = db.authenticate(username, authCmd.Password); err != nil {
+		protocol.WriteError(conn, err)
 		return
 	}
 
-	if authCmd.Password == db.config.Authentication.Password {
-		ctx := conn.Context().(*server.ConnContext)
-		ctx.SetAuthenticated(true)
-		conn.WriteString(protocol.StatusOK)
+	ctx := conn.Context().(*server.ConnContext)
+	ctx.SetAuthenticated(true)
+	ctx.SetUser(username)
+	conn.WriteString(protocol.StatusOK)
+}
+
+// aclWhoAmICommandHandler replies with the name of the user that the connection is authenticated as.
+func (db *Olric) aclWhoAmICommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	_, err := protocol.ParseACLWhoAmICommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
 		return
 	}
-	protocol.WriteError(conn, ErrWrongPass)
+
+	ctx := conn.Context().(*server.ConnContext)
+	user := ctx.User()
+	if user == "" {
+		// Authentication is disabled, or the connection is not authenticated yet.
+		user = defaultUser
+	}
+	conn.WriteBulkString(user)
+}
+
+// aclListCommandHandler replies with the users and their rules.
+func (db *Olric) aclListCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	_, err := protocol.ParseACLListCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	if db.acl == nil {
+		conn.WriteArray(0)
+		return
+	}
+	users := db.acl.List()
+	conn.WriteArray(len(users))
+	for _, user := range users {
+		conn.WriteBulkString(user)
+	}
 }
@@ -15,11 +15,18 @@
 package olric
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/testutil"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 )
 
@@ -63,3 +70,208 @@ func TestAuthCommandHandler_Auth_Disabled(t *testing.T) {
 	_, err := NewClusterClient([]string{db.name}, WithPassword("test-password"))
 	require.ErrorContains(t, err, "error while discovering the cluster members: AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?")
 }
+
+func newTestConfigWithUsers() *config.Config {
+	c := testutil.NewConfig()
+	c.Authentication = &config.Authentication{
+		Password: "test-password",
+		Users: []config.User{
+			{
+				Name:      "alice",
+				Passwords: []string{config.HashPassword("alice-password")},
+				Rules:     []string{"+@cluster", "+@dmap", "-dm.destroy", "%R~sessions.*", "~cache"},
+			},
+			{
+				Name:      "bob",
+				Passwords: []string{config.HashPassword("bob-password")},
+				Rules:     []string{"+@cluster", "+@pubsub", "&events.*"},
+			},
+		},
+	}
+	return c
+}
+
+func TestAuthCommandHandler_Users(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMemberWithConfig(t, newTestConfigWithUsers())
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db.name}, WithUser("alice", "alice-password"))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	sessions, err := c.NewDMap("sessions.eu")
+	require.NoError(t, err)
+	_, err = sessions.Get(ctx, "mykey")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	err = sessions.Put(ctx, "mykey", "myvalue")
+	require.ErrorIs(t, err, ErrNoPermission)
+
+	cache, err := c.NewDMap("cache")
+	require.NoError(t, err)
+	require.NoError(t, cache.Put(ctx, "mykey", "myvalue"))
+
+	err = cache.Destroy(ctx)
+	require.ErrorIs(t, err, ErrNoPermission)
+
+	users, err := c.NewDMap("users")
+	require.NoError(t, err)
+	_, err = users.Get(ctx, "mykey")
+	require.ErrorIs(t, err, ErrNoPermission)
+
+	t.Run("Wrong password", func(t *testing.T) {
+		_, err := NewClusterClient([]string{db.name}, WithUser("alice", "test-password"))
+		require.ErrorContains(t, err, "wrong password")
+	})
+}
+
+func TestAuthCommandHandler_ACL_Commands(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMemberWithConfig(t, newTestConfigWithUsers())
+
+	ctx := context.Background()
+	rc := redis.NewClient(&redis.Options{Addr: db.name, Protocol: 2})
+	defer func() {
+		require.NoError(t, rc.Close())
+	}()
+	conn := rc.Conn()
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+
+	require.NoError(t, conn.Process(ctx, protocol.NewAuth("bob-password").SetUsername("bob").Command(ctx)))
+
+	whoAmI := protocol.NewACLWhoAmI().Command(ctx)
+	require.NoError(t, conn.Process(ctx, whoAmI))
+	require.Equal(t, "bob", whoAmI.Val())
+
+	// bob is not an admin.
+	err := conn.Process(ctx, protocol.NewACLList().Command(ctx))
+	require.ErrorIs(t, protocol.ConvertError(err), ErrNoPermission)
+
+	require.NoError(t, conn.Publish(ctx, "events.user", "message").Err())
+	err = conn.Publish(ctx, "orders", "message").Err()
+	require.ErrorIs(t, protocol.ConvertError(err), ErrNoPermission)
+
+	// The default user is able to run all the commands.
+	require.NoError(t, conn.Process(ctx, protocol.NewAuth("test-password").Command(ctx)))
+
+	whoAmI = protocol.NewACLWhoAmI().Command(ctx)
+	require.NoError(t, conn.Process(ctx, whoAmI))
+	require.Equal(t, "default", whoAmI.Val())
+
+	list := protocol.NewACLList().Command(ctx)
+	require.NoError(t, conn.Process(ctx, list))
+	require.Equal(t, []string{
+		"user alice #" + config.HashPassword("alice-password") + " +@cluster +@dmap -dm.destroy %R~sessions.* ~cache",
+		"user bob #" + config.HashPassword("bob-password") + " +@cluster +@pubsub &events.*",
+		"user default #" + config.HashPassword("test-password") + " allcommands allkeys allchannels",
+	}, list.Val())
+}
+
+func TestAuthCommandHandler_ACL_PubSub(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMemberWithConfig(t, newTestConfigWithUsers())
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db.name}, WithUser("bob", "bob-password"))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	ps, err := c.NewPubSub(ToAddress(db.name))
+	require.NoError(t, err)
+
+	_, err = ps.Subscribe(ctx, []string{"orders"})
+	require.ErrorIs(t, err, ErrNoPermission)
+}
+
+type auditOutput struct {
+	mtx sync.Mutex
+	buf bytes.Buffer
+}
+
+func (o *auditOutput) Write(p []byte) (int, error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	return o.buf.Write(p)
+}
+
+func (o *auditOutput) outcomes(t *testing.T) []string {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	var outcomes []string
+	scanner := bufio.NewScanner(bytes.NewReader(o.buf.Bytes()))
+	for scanner.Scan() {
+		var record struct {
+			Command string `json:"command"`
+			User    string `json:"user"`
+			Outcome string `json:"outcome"`
+		}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		outcomes = append(outcomes, record.Command+" "+record.User+" "+record.Outcome)
+	}
+	require.NoError(t, scanner.Err())
+	return outcomes
+}
+
+func TestAuthCommandHandler_ACL_PubSub_Subscribed_Connection(t *testing.T) {
+	output := &auditOutput{}
+	c := newTestConfigWithUsers()
+	c.AuditLog = &config.AuditLog{
+		Output:   output,
+		Commands: []string{protocol.PubSub.Subscribe, protocol.PubSub.PSubscribe},
+	}
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMemberWithConfig(t, c)
+
+	ctx := context.Background()
+	rc := redis.NewClient(&redis.Options{Addr: db.name, Username: "bob", Password: "bob-password"})
+	defer func() {
+		require.NoError(t, rc.Close())
+	}()
+
+	sub := rc.Subscribe(ctx, "events.a")
+	defer func() {
+		require.NoError(t, sub.Close())
+	}()
+	_, err := sub.Receive(ctx)
+	require.NoError(t, err)
+
+	// The connection is subscribed, the next subscriptions are read by the
+	// Pub/Sub service, not by the mux.
+	require.NoError(t, sub.Subscribe(ctx, "secret"))
+	_, err = sub.Receive(ctx)
+	require.ErrorIs(t, protocol.ConvertError(err), ErrNoPermission)
+
+	require.NoError(t, sub.PSubscribe(ctx, "*"))
+	_, err = sub.Receive(ctx)
+	require.ErrorIs(t, protocol.ConvertError(err), ErrNoPermission)
+
+	// The permitted channels are still allowed on the connection.
+	require.NoError(t, sub.Subscribe(ctx, "events.b"))
+	msg, err := sub.ReceiveTimeout(ctx, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "events.b", msg.(*redis.Subscription).Channel)
+
+	admin := redis.NewClient(&redis.Options{Addr: db.name, Password: "test-password"})
+	defer func() {
+		require.NoError(t, admin.Close())
+	}()
+	receivers, err := admin.Publish(ctx, "secret", "message").Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), receivers)
+
+	require.Equal(t, []string{
+		"subscribe bob success",
+		"subscribe bob denied",
+		"psubscribe bob denied",
+		"subscribe bob success",
+	}, output.outcomes(t))
+}
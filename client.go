@@ -18,6 +18,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/olric-data/olric/events"
 	"github.com/olric-data/olric/internal/dmap"
 	"github.com/olric-data/olric/pkg/storage"
 	"github.com/olric-data/olric/stats"
@@ -301,6 +302,40 @@ func ToAddress(addr string) PubSubOption {
 // PubSubOption is a function for defining options to control behavior of the Publish-Subscribe service.
 type PubSubOption func(option *pubsubConfig)
 
+type subscribeConfig struct {
+	FromSequence *uint64
+}
+
+// FromSequence is a SubscribeOption for replaying the retained messages of the channels, starting from
+// the given sequence ID.
+func FromSequence(id uint64) SubscribeOption {
+	return func(cfg *subscribeConfig) {
+		cfg.FromSequence = &id
+	}
+}
+
+// SubscribeOption is a function for defining options to control behavior of a Subscription.
+type SubscribeOption func(option *subscribeConfig)
+
+type clusterEventsConfig struct {
+	Kinds map[string]struct{}
+}
+
+// EventKinds is a ClusterEventsOption for receiving only the given kinds of events, e.g. events.KindNodeJoinEvent.
+func EventKinds(kinds ...string) ClusterEventsOption {
+	return func(cfg *clusterEventsConfig) {
+		if cfg.Kinds == nil {
+			cfg.Kinds = make(map[string]struct{})
+		}
+		for _, kind := range kinds {
+			cfg.Kinds[kind] = struct{}{}
+		}
+	}
+}
+
+// ClusterEventsOption is a function for defining options to control behavior of the ClusterEvents method.
+type ClusterEventsOption func(option *clusterEventsConfig)
+
 // Client is an interface that denotes an Olric client.
 type Client interface {
 	// NewDMap returns a new DMap client with the given options.
@@ -309,9 +344,56 @@ type Client interface {
 	// NewPubSub returns a new PubSub client with the given options.
 	NewPubSub(options ...PubSubOption) (*PubSub, error)
 
+	// NewStream returns a new Stream client.
+	NewStream(name string) (Stream, error)
+
+	// ClusterEvents subscribes to the cluster events and returns a channel of decoded events. The values are
+	// pointers to the event types in the events package, e.g. *events.NodeJoinEvent. The channel is closed when
+	// the context is canceled. The cluster members must be configured with EnableClusterEventsChannel.
+	ClusterEvents(ctx context.Context, options ...ClusterEventsOption) (<-chan events.Event, error)
+
 	// Stats returns stats.Stats with the given options.
 	Stats(ctx context.Context, address string, options ...StatsOption) (stats.Stats, error)
 
+	// SlowLog returns the latest count records of the slow log of the member, the newest first.
+	// A negative count returns all the records.
+	SlowLog(ctx context.Context, address string, count int) ([]SlowLogEntry, error)
+
+	// SlowLogLen returns the number of records in the slow log of the member.
+	SlowLogLen(ctx context.Context, address string) (int, error)
+
+	// SlowLogReset removes all the records of the slow log of the member.
+	SlowLogReset(ctx context.Context, address string) error
+
+	// InstallGossipKey adds the base64 encoded gossip encryption key to the keyrings of
+	// all the cluster members. The members keep using their primary keys.
+	InstallGossipKey(ctx context.Context, key string) error
+
+	// UseGossipKey makes the installed gossip encryption key the primary key of all the
+	// cluster members. The key must be installed on all the members.
+	UseGossipKey(ctx context.Context, key string) error
+
+	// RemoveGossipKey removes the gossip encryption key from the keyrings of all the
+	// cluster members. The primary key cannot be removed.
+	RemoveGossipKey(ctx context.Context, key string) error
+
+	// GossipKeys returns the fingerprints of the gossip encryption keys that are installed on
+	// the cluster members.
+	GossipKeys(ctx context.Context) ([]GossipKey, error)
+
+	// AddPassword makes all the cluster members accept the password as the
+	// secondary password of the default user.
+	AddPassword(ctx context.Context, password string) error
+
+	// PromotePassword makes the secondary password the primary password of the
+	// default user on all the cluster members. The members connect to each
+	// other with it, and the old primary password becomes the secondary password.
+	PromotePassword(ctx context.Context, password string) error
+
+	// RetirePassword removes the secondary password of the default user from
+	// all the cluster members.
+	RetirePassword(ctx context.Context, password string) error
+
 	// Ping sends a ping message to an Olric node. Returns PONG if message is empty,
 	// otherwise return a copy of the message as a bulk. This command is often used to test
 	// if a connection is still alive, or to measure latency.
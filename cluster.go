@@ -20,6 +20,7 @@ import (
 	"strconv"
 
 	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
 	"github.com/tidwall/redcon"
 )
 
@@ -171,6 +172,16 @@ func (db *Olric) clusterMembersCommandHandler(conn redcon.Conn, cmd redcon.Comma
 	members := db.rt.Discovery().GetMembers()
 	conn.WriteArray(len(members))
 	for _, member := range members {
+		if server.ProtocolVersion(conn) == protocol.RESP3 {
+			server.WriteMap(conn, 3)
+			conn.WriteBulkString("name")
+			conn.WriteBulkString(member.Name)
+			conn.WriteBulkString("birthdate")
+			conn.WriteInt64(member.Birthdate)
+			conn.WriteBulkString("coordinator")
+			server.WriteBool(conn, coordinator.CompareByID(member))
+			continue
+		}
 		conn.WriteArray(3)
 		conn.WriteBulkString(member.Name)
 		// go-redis/redis package cannot handle uint64. At the time of this writing,
@@ -16,7 +16,6 @@ package olric
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -28,6 +27,7 @@ import (
 	"time"
 
 	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/events"
 	"github.com/olric-data/olric/hasher"
 	"github.com/olric-data/olric/internal/bufpool"
 	"github.com/olric-data/olric/internal/cluster/partitions"
@@ -40,6 +40,7 @@ import (
 	"github.com/olric-data/olric/pkg/storage"
 	"github.com/olric-data/olric/stats"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var pool = bufpool.New()
@@ -646,16 +647,104 @@ func (cl *ClusterClient) Stats(ctx context.Context, address string, options ...S
 	if err = cmd.Err(); err != nil {
 		return stats.Stats{}, processProtocolError(err)
 	}
-	data, err := cmd.Bytes()
+	return readStatsReply(cmd.Val())
+}
+
+// SlowLog returns the latest count records of the slow log of the member, the newest first.
+// A negative count returns all the records.
+func (cl *ClusterClient) SlowLog(ctx context.Context, address string, count int) ([]SlowLogEntry, error) {
+	return slowLogGet(ctx, cl.client.Get(address), count)
+}
+
+// SlowLogLen returns the number of records in the slow log of the member.
+func (cl *ClusterClient) SlowLogLen(ctx context.Context, address string) (int, error) {
+	return slowLogLen(ctx, cl.client.Get(address))
+}
+
+// SlowLogReset removes all the records of the slow log of the member.
+func (cl *ClusterClient) SlowLogReset(ctx context.Context, address string) error {
+	return slowLogReset(ctx, cl.client.Get(address))
+}
+
+// InstallGossipKey adds the base64 encoded gossip encryption key to the keyrings of
+// all the cluster members. The members keep using their primary keys.
+func (cl *ClusterClient) InstallGossipKey(ctx context.Context, key string) error {
+	rc, err := cl.client.Pick()
 	if err != nil {
-		return stats.Stats{}, processProtocolError(err)
+		return err
 	}
-	var s stats.Stats
-	err = json.Unmarshal(data, &s)
+	return processStatusCommand(ctx, rc, protocol.NewClusterKeyringInstall(key).Command(ctx))
+}
+
+// UseGossipKey makes the installed gossip encryption key the primary key of all the
+// cluster members. The key must be installed on all the members.
+func (cl *ClusterClient) UseGossipKey(ctx context.Context, key string) error {
+	rc, err := cl.client.Pick()
 	if err != nil {
-		return stats.Stats{}, processProtocolError(err)
+		return err
+	}
+	return processStatusCommand(ctx, rc, protocol.NewClusterKeyringUse(key).Command(ctx))
+}
+
+// RemoveGossipKey removes the gossip encryption key from the keyrings of all the
+// cluster members. The primary key cannot be removed.
+func (cl *ClusterClient) RemoveGossipKey(ctx context.Context, key string) error {
+	rc, err := cl.client.Pick()
+	if err != nil {
+		return err
+	}
+	return processStatusCommand(ctx, rc, protocol.NewClusterKeyringRemove(key).Command(ctx))
+}
+
+// GossipKeys returns the fingerprints of the gossip encryption keys that are installed on the
+// cluster members.
+func (cl *ClusterClient) GossipKeys(ctx context.Context) ([]GossipKey, error) {
+	rc, err := cl.client.Pick()
+	if err != nil {
+		return nil, err
+	}
+	return gossipKeys(ctx, rc)
+}
+
+// AddPassword makes all the cluster members accept the password as the
+// secondary password of the default user.
+func (cl *ClusterClient) AddPassword(ctx context.Context, password string) error {
+	rc, err := cl.client.Pick()
+	if err != nil {
+		return err
+	}
+	return processStatusCommand(ctx, rc, protocol.NewClusterPasswordAdd(password).Command(ctx))
+}
+
+// PromotePassword makes the secondary password the primary password of the
+// default user on all the cluster members. The members connect to each
+// other with it, and the old primary password becomes the secondary password.
+// If the client authenticates as the default user, its new connections use
+// the promoted password.
+func (cl *ClusterClient) PromotePassword(ctx context.Context, password string) error {
+	rc, err := cl.client.Pick()
+	if err != nil {
+		return err
+	}
+	err = processStatusCommand(ctx, rc, protocol.NewClusterPasswordPromote(password).Command(ctx))
+	if err != nil {
+		return err
 	}
-	return s, nil
+	username := cl.config.config.Authentication.Username
+	if username == "" || username == config.DefaultUser {
+		cl.client.SetPassword(password)
+	}
+	return nil
+}
+
+// RetirePassword removes the secondary password of the default user from
+// all the cluster members.
+func (cl *ClusterClient) RetirePassword(ctx context.Context, password string) error {
+	rc, err := cl.client.Pick()
+	if err != nil {
+		return err
+	}
+	return processStatusCommand(ctx, rc, protocol.NewClusterPasswordRetire(password).Command(ctx))
 }
 
 // Members returns a thread-safe list of cluster members.
@@ -682,17 +771,25 @@ func (cl *ClusterClient) Members(ctx context.Context) ([]Member, error) {
 	var members []Member
 	for _, rawItem := range items {
 		m := Member{}
-		item := rawItem.([]interface{})
-		m.Name = item[0].(string)
-		m.Birthdate = item[1].(int64)
+		switch item := rawItem.(type) {
+		case map[interface{}]interface{}:
+			// RESP3
+			m.Name, _ = item["name"].(string)
+			m.Birthdate, _ = item["birthdate"].(int64)
+			m.Coordinator, _ = item["coordinator"].(bool)
+		case []interface{}:
+			m.Name = item[0].(string)
+			m.Birthdate = item[1].(int64)
+			if item[2] == "true" {
+				m.Coordinator = true
+			}
+		default:
+			return []Member{}, fmt.Errorf("invalid member: %v", rawItem)
+		}
 
 		// go-redis/redis package cannot handle uint64 type. At the time of this writing,
 		// there is no solution for this, and I don't want to use a soft fork to repair it.
 		m.ID = discovery.MemberID(m.Name, m.Birthdate)
-
-		if item[2] == "true" {
-			m.Coordinator = true
-		}
 		members = append(members, m)
 	}
 	return members, nil
@@ -754,7 +851,21 @@ func (cl *ClusterClient) Close(ctx context.Context) error {
 
 // NewPubSub returns a new PubSub client with the given options.
 func (cl *ClusterClient) NewPubSub(options ...PubSubOption) (*PubSub, error) {
-	return newPubSub(cl.client, options...)
+	return newPubSub(cl.client, cl, options...)
+}
+
+// ClusterEvents subscribes to the cluster events on a cluster member and returns a channel of decoded events.
+func (cl *ClusterClient) ClusterEvents(ctx context.Context, options ...ClusterEventsOption) (<-chan events.Event, error) {
+	rc, err := cl.client.Pick()
+	if err != nil {
+		return nil, err
+	}
+	return subscribeClusterEvents(ctx, rc, options...)
+}
+
+// shardOwner returns a client for the partition owner of a sharded channel.
+func (cl *ClusterClient) shardOwner(channel string) (*redis.Client, error) {
+	return cl.smartPick("", channel)
 }
 
 // NewDMap returns a new DMap client with the given options.
@@ -788,6 +899,7 @@ type clusterClientConfig struct {
 	authentication            *config.Authentication
 	hasher                    hasher.Hasher
 	routingTableFetchInterval time.Duration
+	tracerProvider            trace.TracerProvider
 }
 
 // WithHasher sets a custom hasher implementation to the cluster client configuration.
@@ -820,6 +932,16 @@ func WithPassword(password string) ClusterClientOption {
 	}
 }
 
+// WithUser configures a cluster client to authenticate as the named user.
+func WithUser(username, password string) ClusterClientOption {
+	return func(cfg *clusterClientConfig) {
+		cfg.authentication = &config.Authentication{
+			Username: username,
+			Password: password,
+		}
+	}
+}
+
 // WithRoutingTableFetchInterval sets the interval for periodic fetching of the routing table in a cluster client configuration.
 func WithRoutingTableFetchInterval(interval time.Duration) ClusterClientOption {
 	return func(cfg *clusterClientConfig) {
@@ -827,6 +949,14 @@ func WithRoutingTableFetchInterval(interval time.Duration) ClusterClientOption {
 	}
 }
 
+// WithTracerProvider sets the OpenTelemetry tracer provider of the cluster client. A client span is
+// created for every command and the trace context is propagated to the cluster members.
+func WithTracerProvider(tp trace.TracerProvider) ClusterClientOption {
+	return func(cfg *clusterClientConfig) {
+		cfg.tracerProvider = tp
+	}
+}
+
 // fetchRoutingTable updates the cluster routing table by fetching the latest version from the cluster.
 // It initializes the partition count if it's the first invocation. Returns an error if fetching fails.
 func (cl *ClusterClient) fetchRoutingTable() error {
@@ -895,6 +1025,10 @@ func NewClusterClient(addresses []string, options ...ClusterClientOption) (*Clus
 		cc.config.Authentication = cc.authentication
 	}
 
+	if cc.tracerProvider != nil {
+		cc.config.TracerProvider = cc.tracerProvider
+	}
+
 	if cc.routingTableFetchInterval <= 0 {
 		cc.routingTableFetchInterval = DefaultRoutingTableFetchInterval
 	}
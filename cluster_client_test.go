@@ -23,9 +23,13 @@ import (
 
 	"github.com/olric-data/olric/config"
 	"github.com/olric-data/olric/hasher"
+	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/testutil"
 	"github.com/olric-data/olric/stats"
 	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -791,3 +795,57 @@ func TestClusterClient_smartPick(t *testing.T) {
 	}
 	require.Len(t, clients, 4)
 }
+
+func TestClusterClient_Tracing(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	cluster := newTestOlricCluster(t)
+	newConfig := func() *config.Config {
+		c := testutil.NewConfig()
+		c.ReplicaCount = 2
+		c.WriteQuorum = 2
+		c.TracerProvider = tp
+		return c
+	}
+	db := cluster.addMemberWithConfig(t, newConfig())
+	cluster.addMemberWithConfig(t, newConfig())
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db.name}, WithTracerProvider(tp))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	dm, err := c.NewDMap("mydmap")
+	require.NoError(t, err)
+
+	ctx, parent := tp.Tracer("test").Start(ctx, "parent")
+	require.NoError(t, dm.Put(ctx, "mykey", "myvalue"))
+	parent.End()
+
+	// Server spans end after the replies are written.
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	require.Eventually(t, func() bool {
+		for _, span := range sr.Ended() {
+			if span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+				continue
+			}
+			spans[span.Name()+"/"+span.SpanKind().String()] = span
+		}
+		return len(spans) == 5
+	}, 5*time.Second, 10*time.Millisecond)
+
+	client := spans[protocol.DMap.Put+"/"+trace.SpanKindClient.String()]
+	require.Equal(t, parent.SpanContext().SpanID(), client.Parent().SpanID())
+
+	owner := spans[protocol.DMap.Put+"/"+trace.SpanKindServer.String()]
+	require.Equal(t, client.SpanContext().SpanID(), owner.Parent().SpanID())
+
+	internal := spans[protocol.DMap.PutEntry+"/"+trace.SpanKindClient.String()]
+	require.Equal(t, owner.SpanContext().SpanID(), internal.Parent().SpanID())
+
+	backup := spans[protocol.DMap.PutEntry+"/"+trace.SpanKindServer.String()]
+	require.Equal(t, internal.SpanContext().SpanID(), backup.Parent().SpanID())
+}
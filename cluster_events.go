@@ -0,0 +1,80 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+
+	"github.com/olric-data/olric/events"
+	"github.com/redis/go-redis/v9"
+)
+
+// clusterEventsBufferSize is the capacity of the channel returned by
+// ClusterEvents.
+const clusterEventsBufferSize = 100
+
+// subscribeClusterEvents subscribes to events.ClusterEventsChannel on the given
+// member. The members forward the published messages to each other, so a single
+// subscription receives the events of the whole cluster.
+func subscribeClusterEvents(ctx context.Context, rc *redis.Client, options ...ClusterEventsOption) (<-chan events.Event, error) {
+	var cc clusterEventsConfig
+	for _, opt := range options {
+		opt(&cc)
+	}
+
+	rp := rc.Subscribe(ctx, events.ClusterEventsChannel)
+	// Wait for confirmation that subscription is created.
+	if _, err := rp.Receive(ctx); err != nil {
+		_ = rp.Close()
+		return nil, processProtocolError(err)
+	}
+
+	ch := make(chan events.Event, clusterEventsBufferSize)
+	go func() {
+		defer close(ch)
+		defer func() {
+			_ = rp.Close()
+		}()
+
+		messages := rp.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				e, err := events.Decode(msg.Payload)
+				if err != nil {
+					// Skip the unknown kinds of events, they may be
+					// published by a newer version.
+					continue
+				}
+				if cc.Kinds != nil {
+					if _, ok := cc.Kinds[events.KindOf(e)]; !ok {
+						continue
+					}
+				}
+				select {
+				case ch <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
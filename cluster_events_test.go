@@ -0,0 +1,82 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/events"
+	"github.com/olric-data/olric/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOlric_ClusterEvents(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	c1 := testutil.NewConfig()
+	c1.EnableClusterEventsChannel = true
+	db1 := cluster.addMemberWithConfig(t, c1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cc, err := NewClusterClient([]string{db1.name})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, cc.Close(context.Background()))
+	}()
+
+	var channels []<-chan events.Event
+	for _, client := range []Client{db1.NewEmbeddedClient(), cc} {
+		ch, err := client.ClusterEvents(ctx, EventKinds(events.KindNodeJoinEvent))
+		require.NoError(t, err)
+		channels = append(channels, ch)
+	}
+
+	c2 := testutil.NewConfig()
+	c2.EnableClusterEventsChannel = true
+	db2 := cluster.addMemberWithConfig(t, c2)
+
+	// Every member publishes an event when it sees another member.
+	for _, ch := range channels {
+	L:
+		for {
+			select {
+			case e := <-ch:
+				joinEvent, ok := e.(*events.NodeJoinEvent)
+				require.True(t, ok, "unexpected event: %T", e)
+				require.Equal(t, events.KindNodeJoinEvent, joinEvent.Kind)
+				if joinEvent.NodeJoin == db2.name {
+					break L
+				}
+			case <-time.After(10 * time.Second):
+				require.Fail(t, "no event received")
+			}
+		}
+	}
+
+	cancel()
+	for _, ch := range channels {
+		require.Eventually(t, func() bool {
+			select {
+			case _, ok := <-ch:
+				return !ok
+			default:
+				return false
+			}
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+}
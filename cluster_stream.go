@@ -0,0 +1,226 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"time"
+
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/stream"
+	"github.com/redis/go-redis/v9"
+)
+
+// ClusterStream implements a client for distributed streams.
+type ClusterStream struct {
+	name          string
+	clusterClient *ClusterClient
+}
+
+// Name exposes name of the Stream.
+func (s *ClusterStream) Name() string {
+	return s.name
+}
+
+func processStreamError(err error) error {
+	return convertStreamError(processProtocolError(err))
+}
+
+func (s *ClusterStream) process(ctx context.Context, cmd redis.Cmder) error {
+	rc, err := s.clusterClient.smartPick(s.name, "")
+	if err != nil {
+		return err
+	}
+	err = rc.Process(ctx, cmd)
+	if err != nil {
+		return processStreamError(err)
+	}
+	return processStreamError(cmd.Err())
+}
+
+func (s *ClusterStream) processEntries(ctx context.Context, cmd *redis.SliceCmd) ([]StreamEntry, error) {
+	if err := s.process(ctx, cmd); err != nil {
+		return nil, err
+	}
+	entries, err := stream.ParseEntries(cmd.Val())
+	if err != nil {
+		return nil, err
+	}
+	return toStreamEntries(entries), nil
+}
+
+// XAdd appends a new entry to the stream and returns its ID.
+func (s *ClusterStream) XAdd(ctx context.Context, id string, fields map[string]string, options ...XAddOption) (string, error) {
+	cfg := newAddConfig(options)
+	xaddCmd := protocol.NewXAdd(s.name, id, fields)
+	if cfg.HasMaxLen {
+		xaddCmd.SetMaxLen(cfg.MaxLen)
+	}
+	cmd := xaddCmd.Command(ctx)
+	if err := s.process(ctx, cmd); err != nil {
+		return "", err
+	}
+	return cmd.Val(), nil
+}
+
+// XLen returns the number of entries in the stream.
+func (s *ClusterStream) XLen(ctx context.Context) (int64, error) {
+	cmd := protocol.NewXLen(s.name).Command(ctx)
+	if err := s.process(ctx, cmd); err != nil {
+		return 0, err
+	}
+	return cmd.Val(), nil
+}
+
+// XInfo returns the length, the latest ID and the number of consumer groups of the stream.
+func (s *ClusterStream) XInfo(ctx context.Context) (*StreamInfo, error) {
+	cmd := protocol.NewXInfo(s.name).Command(ctx)
+	if err := s.process(ctx, cmd); err != nil {
+		return nil, err
+	}
+	info, err := stream.ParseInfo(cmd.Val())
+	if err != nil {
+		return nil, err
+	}
+	return &StreamInfo{
+		Length: info.Length,
+		LastID: info.LastID.String(),
+		Groups: info.Groups,
+	}, nil
+}
+
+// XRange returns the entries with IDs between start and end, inclusive.
+func (s *ClusterStream) XRange(ctx context.Context, start, end string, options ...StreamReadOption) ([]StreamEntry, error) {
+	cfg := newStreamReadConfig(options)
+	xrangeCmd := protocol.NewXRange(s.name, start, end)
+	if cfg.HasCount {
+		xrangeCmd.SetCount(cfg.Count)
+	}
+	return s.processEntries(ctx, xrangeCmd.Command(ctx))
+}
+
+// blockInRounds runs a blocking read in rounds that fit into the read timeout of the client.
+func (s *ClusterStream) blockInRounds(
+	ctx context.Context,
+	cfg *stream.ReadConfig,
+	read func(cfg *stream.ReadConfig) ([]StreamEntry, error),
+) ([]StreamEntry, error) {
+	return stream.BlockInRounds(ctx, cfg.Block, s.clusterClient.client.ReadTimeout(),
+		func(block time.Duration) ([]StreamEntry, error) {
+			rc := *cfg
+			rc.Block = block
+			return read(&rc)
+		})
+}
+
+// XRead returns the entries with IDs greater than id.
+func (s *ClusterStream) XRead(ctx context.Context, id string, options ...StreamReadOption) ([]StreamEntry, error) {
+	cfg := newStreamReadConfig(options)
+	if id == StreamLastID && cfg.HasBlock {
+		// Resolve the latest ID here. Otherwise, the entries added between
+		// two rounds may be missed.
+		info, err := s.XInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		id = info.LastID
+	}
+
+	return s.blockInRounds(ctx, cfg, func(cfg *stream.ReadConfig) ([]StreamEntry, error) {
+		xreadCmd := protocol.NewXRead(s.name, id)
+		if cfg.HasCount {
+			xreadCmd.SetCount(cfg.Count)
+		}
+		if cfg.HasBlock {
+			xreadCmd.SetBlock(cfg.Block.Milliseconds())
+		}
+		return s.processEntries(ctx, xreadCmd.Command(ctx))
+	})
+}
+
+// XGroupCreate creates a new consumer group.
+func (s *ClusterStream) XGroupCreate(ctx context.Context, group, id string) error {
+	cmd := protocol.NewXGroupCreate(s.name, group, id).Command(ctx)
+	return s.process(ctx, cmd)
+}
+
+// XReadGroup reads entries on behalf of a consumer in a consumer group.
+func (s *ClusterStream) XReadGroup(ctx context.Context, group, consumer, id string, options ...StreamReadOption) ([]StreamEntry, error) {
+	cfg := newStreamReadConfig(options)
+	return s.blockInRounds(ctx, cfg, func(cfg *stream.ReadConfig) ([]StreamEntry, error) {
+		xreadGroupCmd := protocol.NewXReadGroup(s.name, group, consumer, id)
+		if cfg.HasCount {
+			xreadGroupCmd.SetCount(cfg.Count)
+		}
+		if cfg.HasBlock {
+			xreadGroupCmd.SetBlock(cfg.Block.Milliseconds())
+		}
+		return s.processEntries(ctx, xreadGroupCmd.Command(ctx))
+	})
+}
+
+// XAck removes the given entries from the pending entries list of the consumer group.
+func (s *ClusterStream) XAck(ctx context.Context, group string, ids ...string) (int, error) {
+	cmd := protocol.NewXAck(s.name, group, ids...).Command(ctx)
+	if err := s.process(ctx, cmd); err != nil {
+		return 0, err
+	}
+	return int(cmd.Val()), nil
+}
+
+// XPending returns the pending entries list of the consumer group.
+func (s *ClusterStream) XPending(ctx context.Context, group string) ([]StreamPendingEntry, error) {
+	cmd := protocol.NewXPending(s.name, group).Command(ctx)
+	if err := s.process(ctx, cmd); err != nil {
+		return nil, err
+	}
+	pending, err := stream.ParsePendingEntries(cmd.Val())
+	if err != nil {
+		return nil, err
+	}
+	return toStreamPendingEntries(pending), nil
+}
+
+// XClaim transfers the ownership of the given pending entries to the consumer.
+func (s *ClusterStream) XClaim(ctx context.Context, group, consumer string, minIdle time.Duration, ids ...string) ([]StreamEntry, error) {
+	cmd := protocol.NewXClaim(s.name, group, consumer, minIdle.Milliseconds(), ids...).Command(ctx)
+	return s.processEntries(ctx, cmd)
+}
+
+// XAutoClaim transfers the ownership of the idle pending entries to the consumer.
+func (s *ClusterStream) XAutoClaim(ctx context.Context, group, consumer string, minIdle time.Duration, start string, options ...StreamReadOption) ([]StreamEntry, error) {
+	cfg := newStreamReadConfig(options)
+	xautoClaimCmd := protocol.NewXAutoClaim(s.name, group, consumer, minIdle.Milliseconds(), start)
+	if cfg.HasCount {
+		xautoClaimCmd.SetCount(cfg.Count)
+	}
+	return s.processEntries(ctx, xautoClaimCmd.Command(ctx))
+}
+
+// Close stops background routines and frees allocated resources.
+func (s *ClusterStream) Close(_ context.Context) error {
+	// ClusterStream shares the connections of the ClusterClient.
+	return nil
+}
+
+// NewStream returns a new Stream client.
+func (cl *ClusterClient) NewStream(name string) (Stream, error) {
+	return &ClusterStream{
+		name:          name,
+		clusterClient: cl,
+	}, nil
+}
+
+var _ Stream = (*ClusterStream)(nil)
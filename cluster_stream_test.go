@@ -0,0 +1,138 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterClient_Stream_XAdd_XRead(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	cluster.addMember(t)
+	db := cluster.addMember(t)
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db.name})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	st, err := c.NewStream("mystream")
+	require.NoError(t, err)
+
+	var ids []string
+	for i := 0; i < 10; i++ {
+		id, err := st.XAdd(ctx, StreamAutoID, map[string]string{"key": "value"}, MaxLen(5))
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	length, err := st.XLen(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), length)
+
+	entries, err := st.XRead(ctx, ids[6])
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, ids[7], entries[0].ID)
+}
+
+func TestClusterClient_Stream_XRead_Block(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	cluster.addMember(t)
+	db := cluster.addMember(t)
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db.name})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	st, err := c.NewStream("mystream")
+	require.NoError(t, err)
+
+	result := make(chan []StreamEntry, 1)
+	go func() {
+		entries, err := st.XRead(ctx, StreamLastID, Block(5*time.Second))
+		require.NoError(t, err)
+		result <- entries
+	}()
+
+	<-time.After(250 * time.Millisecond)
+	id, err := st.XAdd(ctx, StreamAutoID, map[string]string{"key": "value"})
+	require.NoError(t, err)
+
+	select {
+	case entries := <-result:
+		require.Len(t, entries, 1)
+		require.Equal(t, id, entries[0].ID)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "No entry received")
+	}
+}
+
+func TestClusterClient_Stream_ConsumerGroup(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	cluster.addMember(t)
+	db := cluster.addMember(t)
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db.name})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	st, err := c.NewStream("mystream")
+	require.NoError(t, err)
+
+	_, err = st.XReadGroup(ctx, "mygroup", "consumer-1", StreamNewEntriesID)
+	require.ErrorIs(t, err, ErrNoSuchGroup)
+
+	require.NoError(t, st.XGroupCreate(ctx, "mygroup", "0"))
+	require.ErrorIs(t, st.XGroupCreate(ctx, "mygroup", "0"), ErrGroupExists)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id, err := st.XAdd(ctx, StreamAutoID, map[string]string{"key": "value"})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	entries, err := st.XReadGroup(ctx, "mygroup", "consumer-1", StreamNewEntriesID)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	<-time.After(20 * time.Millisecond)
+	entries, err = st.XClaim(ctx, "mygroup", "consumer-2", 10*time.Millisecond, ids[0])
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	pending, err := st.XPending(ctx, "mygroup")
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	require.Equal(t, "consumer-2", pending[0].Consumer)
+	require.Equal(t, int64(2), pending[0].DeliveryCount)
+
+	count, err := st.XAck(ctx, "mygroup", ids...)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+}
@@ -0,0 +1,530 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*Package benchmark implements the load generator of olric-benchmark.*/
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/olric-data/olric"
+)
+
+const (
+	// DefaultDMap is the name of the DMap that is used by the benchmark.
+	DefaultDMap = "olric-benchmark"
+
+	// DefaultConcurrency is the default number of the workers.
+	DefaultConcurrency = 50
+
+	// DefaultRequests is the default number of the operations to run.
+	DefaultRequests = 100000
+
+	// DefaultKeys is the default number of the distinct keys.
+	DefaultKeys = 100000
+
+	// DefaultZipfS and DefaultZipfV are the default parameters of the Zipf distribution.
+	DefaultZipfS = 1.1
+	DefaultZipfV = 1.0
+
+	// DefaultValueSize is the default size of the values, in bytes.
+	DefaultValueSize = 128
+
+	// DefaultOperations is the default mix of the operations.
+	DefaultOperations = "get:80,put:20"
+
+	// DefaultTimeout is the default timeout of an operation.
+	DefaultTimeout = 10 * time.Second
+)
+
+// The operations of the benchmark.
+const (
+	OpPut  = "put"
+	OpGet  = "get"
+	OpIncr = "incr"
+	OpCAS  = "cas"
+	OpLock = "lock"
+)
+
+var operations = []string{OpPut, OpGet, OpIncr, OpCAS, OpLock}
+
+func isOperation(name string) bool {
+	for _, op := range operations {
+		if op == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isPipelined returns true if the operation can be sent in a pipeline.
+// CompareAndSwap and Lock are not supported by DMapPipeline.
+func isPipelined(name string) bool {
+	return name == OpPut || name == OpGet || name == OpIncr
+}
+
+// The operations use different keys, so a lock doesn't block a get, and incr
+// never runs on a key that holds a value written by put.
+var keyPrefixes = map[string]string{
+	OpPut:  "key:",
+	OpGet:  "key:",
+	OpIncr: "counter:",
+	OpCAS:  "cas:",
+	OpLock: "lock:",
+}
+
+// Config is the configuration of a benchmark.
+type Config struct {
+	// DMap is the name of the DMap.
+	DMap string
+
+	// Concurrency is the number of the workers that run the operations
+	// concurrently.
+	Concurrency int
+
+	// Requests is the number of the operations to run. It's ignored if
+	// Duration is set.
+	Requests int64
+
+	// Duration runs the benchmark for the given duration instead of a fixed
+	// number of operations.
+	Duration time.Duration
+
+	// Keys is the number of the distinct keys for each operation.
+	Keys int
+
+	// Distribution is the distribution of the keys, DistributionUniform or
+	// DistributionZipfian.
+	Distribution string
+
+	// ZipfS and ZipfV are the parameters of the Zipf distribution, see rand.NewZipf.
+	ZipfS float64
+	ZipfV float64
+
+	// ValueSize is the size of the values. If MaxValueSize is greater than
+	// ValueSize, the sizes are picked uniformly from [ValueSize, MaxValueSize].
+	ValueSize    int
+	MaxValueSize int
+
+	// Operations is the mix of the operations, e.g. "get:80,put:20".
+	Operations string
+
+	// Pipeline is the number of the operations that are sent in a single
+	// pipeline. The operations are not pipelined if it's less than 2.
+	Pipeline int
+
+	// Preload writes all the keys of put and get before the benchmark, so
+	// get doesn't miss.
+	Preload bool
+
+	// Timeout is the timeout of an operation. It's also the time to wait to
+	// acquire a lock.
+	Timeout time.Duration
+}
+
+func (c *Config) sanitize() error {
+	if c.DMap == "" {
+		c.DMap = DefaultDMap
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = DefaultConcurrency
+	}
+	if c.Requests <= 0 && c.Duration <= 0 {
+		c.Requests = DefaultRequests
+	}
+	if c.Keys <= 0 {
+		c.Keys = DefaultKeys
+	}
+	switch c.Distribution {
+	case "":
+		c.Distribution = DistributionUniform
+	case DistributionUniform:
+	case DistributionZipfian:
+		if c.ZipfS == 0 {
+			c.ZipfS = DefaultZipfS
+		}
+		if c.ZipfV == 0 {
+			c.ZipfV = DefaultZipfV
+		}
+		// See rand.NewZipf
+		if c.ZipfS <= 1 || c.ZipfV < 1 {
+			return fmt.Errorf("invalid zipf parameters: s must be greater than 1 and v must be at least 1")
+		}
+	default:
+		return fmt.Errorf("unknown key distribution: %s", c.Distribution)
+	}
+	if c.ValueSize <= 0 {
+		c.ValueSize = DefaultValueSize
+	}
+	if c.MaxValueSize < c.ValueSize {
+		c.MaxValueSize = c.ValueSize
+	}
+	if c.Operations == "" {
+		c.Operations = DefaultOperations
+	}
+	if c.Pipeline <= 0 {
+		c.Pipeline = 1
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	return nil
+}
+
+// Benchmark runs a workload on a cluster.
+type Benchmark struct {
+	config *Config
+	client olric.Client
+	dm     olric.DMap
+	mix    *mix
+
+	// remaining is the number of the operations that are not started yet, if
+	// the benchmark runs a fixed number of operations.
+	remaining atomic.Int64
+	deadline  time.Time
+}
+
+// New returns a new Benchmark. The client is not closed by the Benchmark.
+func New(client olric.Client, c *Config) (*Benchmark, error) {
+	if err := c.sanitize(); err != nil {
+		return nil, err
+	}
+	m, err := parseMix(c.Operations)
+	if err != nil {
+		return nil, err
+	}
+	dm, err := client.NewDMap(c.DMap)
+	if err != nil {
+		return nil, err
+	}
+	return &Benchmark{
+		config: c,
+		client: client,
+		dm:     dm,
+		mix:    m,
+	}, nil
+}
+
+func key(op string, i uint64) string {
+	return keyPrefixes[op] + strconv.FormatUint(i, 10)
+}
+
+// acquire reserves at most n operations and returns the number of the reserved
+// operations. It returns 0 if the benchmark is done.
+func (b *Benchmark) acquire(ctx context.Context, n int) int {
+	if ctx.Err() != nil {
+		return 0
+	}
+	if b.config.Duration > 0 {
+		if time.Now().After(b.deadline) {
+			return 0
+		}
+		return n
+	}
+	remaining := b.remaining.Add(-int64(n))
+	switch {
+	case remaining >= 0:
+		return n
+	case remaining > -int64(n):
+		return n + int(remaining)
+	default:
+		return 0
+	}
+}
+
+// Preload writes a value for all the keys of put and get.
+func (b *Benchmark) Preload(ctx context.Context) error {
+	var next atomic.Int64
+	keys := int64(b.config.Keys)
+
+	errCh := make(chan error, b.config.Concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < b.config.Concurrency; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for {
+				i := next.Add(1) - 1
+				if i >= keys || ctx.Err() != nil {
+					return
+				}
+				value := make([]byte, b.config.valueSize(r))
+				r.Read(value)
+				opCtx, cancel := context.WithTimeout(ctx, b.config.Timeout)
+				err := b.dm.Put(opCtx, key(OpPut, uint64(i)), value)
+				cancel()
+				if err != nil {
+					errCh <- fmt.Errorf("failed to preload: %w", err)
+					return
+				}
+			}
+		}(time.Now().UnixNano() + int64(i))
+	}
+	wg.Wait()
+	close(errCh)
+	if err := <-errCh; err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// Run runs the benchmark until the operations are done, the duration is
+// elapsed or ctx is canceled. The operations that are in flight when ctx is
+// canceled are counted as errors.
+func (b *Benchmark) Run(ctx context.Context) (*Report, error) {
+	if b.config.Preload {
+		if err := b.Preload(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	b.remaining.Store(b.config.Requests)
+	start := time.Now()
+	b.deadline = start.Add(b.config.Duration)
+
+	workers := make([]*worker, b.config.Concurrency)
+	var wg sync.WaitGroup
+	for i := range workers {
+		w, err := b.newWorker(start.UnixNano() + int64(i))
+		if err != nil {
+			for _, w := range workers[:i] {
+				w.close()
+			}
+			return nil, err
+		}
+		workers[i] = w
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.run(ctx)
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	results := make(map[string]*result)
+	for _, w := range workers {
+		w.close()
+		for op, res := range w.results {
+			if total, ok := results[op]; ok {
+				total.merge(res)
+			} else {
+				results[op] = res
+			}
+		}
+	}
+	return newReport(b.config, elapsed, results), nil
+}
+
+// worker runs the operations sequentially. The workers don't share any state
+// but the remaining number of the operations.
+type worker struct {
+	b        *Benchmark
+	r        *rand.Rand
+	keys     keyGenerator
+	pipeline *olric.DMapPipeline
+	results  map[string]*result
+	buf      []byte
+}
+
+func (b *Benchmark) newWorker(seed int64) (*worker, error) {
+	r := rand.New(rand.NewSource(seed))
+	w := &worker{
+		b:       b,
+		r:       r,
+		keys:    newKeyGenerator(b.config, r),
+		results: make(map[string]*result),
+		buf:     make([]byte, b.config.MaxValueSize),
+	}
+	for _, op := range b.mix.operations {
+		w.results[op.name] = newResult()
+	}
+	if b.config.Pipeline > 1 {
+		dp, err := b.dm.Pipeline()
+		if err != nil {
+			return nil, err
+		}
+		w.pipeline = dp
+	}
+	return w, nil
+}
+
+func (w *worker) close() {
+	if w.pipeline != nil {
+		w.pipeline.Close()
+	}
+}
+
+// value returns a random value. The value is only valid until the next call.
+func (w *worker) value() []byte {
+	value := w.buf[:w.b.config.valueSize(w.r)]
+	w.r.Read(value)
+	return value
+}
+
+func (w *worker) run(ctx context.Context) {
+	for {
+		n := w.b.acquire(ctx, w.b.config.Pipeline)
+		if n == 0 {
+			return
+		}
+		if w.pipeline == nil {
+			w.runOperation(ctx, w.b.mix.pick(w.r))
+			continue
+		}
+		w.runPipeline(ctx, n)
+	}
+}
+
+func (w *worker) runOperation(ctx context.Context, op string) {
+	ctx, cancel := context.WithTimeout(ctx, w.b.config.Timeout)
+	defer cancel()
+
+	res := w.results[op]
+	k := key(op, w.keys.next())
+	var (
+		err   error
+		start time.Time
+		miss  bool
+	)
+	switch op {
+	case OpPut:
+		value := w.value()
+		start = time.Now()
+		err = w.b.dm.Put(ctx, k, value)
+	case OpGet:
+		start = time.Now()
+		_, err = w.b.dm.Get(ctx, k)
+		if errors.Is(err, olric.ErrKeyNotFound) {
+			err, miss = nil, true
+		}
+	case OpIncr:
+		start = time.Now()
+		_, err = w.b.dm.Incr(ctx, k, 1)
+	case OpCAS:
+		// The current value is read before the timer is started, only
+		// CompareAndSwap is measured. A concurrent write to the same key
+		// between Get and CompareAndSwap is counted as a miss.
+		var expected []byte
+		var current *olric.GetResponse
+		current, err = w.b.dm.Get(ctx, k)
+		switch {
+		case errors.Is(err, olric.ErrKeyNotFound):
+			err = nil
+		case err == nil:
+			expected, err = current.Byte()
+		}
+		if err != nil {
+			start = time.Now()
+			break
+		}
+		value := w.value()
+		start = time.Now()
+		var swapped bool
+		swapped, _, err = w.b.dm.CompareAndSwap(ctx, k, expected, value)
+		miss = err == nil && !swapped
+	case OpLock:
+		// Lock and Unlock are measured together, a lock is useless
+		// without releasing it. The lock is released automatically after
+		// the timeout if Unlock fails.
+		start = time.Now()
+		var lock olric.LockContext
+		lock, err = w.b.dm.LockWithTimeout(ctx, k, w.b.config.Timeout, w.b.config.Timeout)
+		if err == nil {
+			err = lock.Unlock(ctx)
+		}
+	}
+	res.record(time.Since(start), err, miss)
+}
+
+type future struct {
+	op  string
+	put *olric.FuturePut
+	get *olric.FutureGet
+	inc *olric.FutureIncr
+}
+
+// runPipeline runs n operations. The operations that can be pipelined are sent
+// in a single pipeline and the latency of the pipeline is recorded for each of
+// them. The others run one by one before the pipeline.
+func (w *worker) runPipeline(ctx context.Context, n int) {
+	ctx, cancel := context.WithTimeout(ctx, w.b.config.Timeout)
+	defer cancel()
+
+	futures := make([]future, 0, n)
+	for i := 0; i < n; i++ {
+		op := w.b.mix.pick(w.r)
+		if !isPipelined(op) {
+			w.runOperation(ctx, op)
+			continue
+		}
+
+		f := future{op: op}
+		k := key(op, w.keys.next())
+		var err error
+		switch op {
+		case OpPut:
+			// The pipeline encodes the value immediately, so the buffer can
+			// be reused.
+			f.put, err = w.pipeline.Put(ctx, k, w.value())
+		case OpGet:
+			f.get = w.pipeline.Get(ctx, k)
+		case OpIncr:
+			f.inc, err = w.pipeline.Incr(ctx, k, 1)
+		}
+		if err != nil {
+			w.results[op].record(0, err, false)
+			continue
+		}
+		futures = append(futures, f)
+	}
+	if len(futures) == 0 {
+		return
+	}
+
+	start := time.Now()
+	execErr := w.pipeline.Exec(ctx)
+	latency := time.Since(start)
+	for _, f := range futures {
+		err := execErr
+		var miss bool
+		if err == nil {
+			switch f.op {
+			case OpPut:
+				err = f.put.Result()
+			case OpGet:
+				_, err = f.get.Result()
+				if errors.Is(err, olric.ErrKeyNotFound) {
+					err, miss = nil, true
+				}
+			case OpIncr:
+				_, err = f.inc.Result()
+			}
+		}
+		w.results[f.op].record(latency, err, miss)
+	}
+
+	// Discard only fails if the pipeline is closed, and only the worker
+	// closes it.
+	_ = w.pipeline.Discard()
+}
@@ -0,0 +1,218 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric"
+	"github.com/olric-data/olric/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) olric.Client {
+	c := testutil.NewConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Started = func() {
+		cancel()
+	}
+	db, err := olric.New(c)
+	require.NoError(t, err)
+
+	go func() {
+		if err := db.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to run Olric: %v", err))
+		}
+	}()
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("Olric cannot be started in one second")
+	case <-ctx.Done():
+	}
+
+	address := net.JoinHostPort(c.BindAddr, strconv.Itoa(c.BindPort))
+	client, err := olric.NewClusterClient([]string{address})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(context.Background()))
+		require.NoError(t, db.Shutdown(context.Background()))
+	})
+	return client
+}
+
+func findOperation(t *testing.T, r *Report, name string) *OperationReport {
+	for _, o := range r.Operations {
+		if o.Operation == name {
+			return o
+		}
+	}
+	t.Fatalf("operation not found: %s", name)
+	return nil
+}
+
+func TestBenchmark_Run(t *testing.T) {
+	client := newTestClient(t)
+
+	for _, pipeline := range []int{1, 8} {
+		t.Run(fmt.Sprintf("pipeline=%d", pipeline), func(t *testing.T) {
+			b, err := New(client, &Config{
+				DMap:         fmt.Sprintf("mydmap-%d", pipeline),
+				Concurrency:  4,
+				Requests:     1003,
+				Keys:         50,
+				Distribution: DistributionZipfian,
+				ValueSize:    16,
+				MaxValueSize: 64,
+				Operations:   "put,get,incr,cas,lock",
+				Pipeline:     pipeline,
+			})
+			require.NoError(t, err)
+
+			r, err := b.Run(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, int64(0), r.Errors())
+			require.Equal(t, int64(1003), r.Total.Count)
+			require.Len(t, r.Operations, 5)
+
+			var count int64
+			for i, o := range r.Operations {
+				require.Equal(t, operations[i], o.Operation)
+				require.Greater(t, o.Count, int64(0))
+				require.Greater(t, o.Throughput, 0.0)
+				require.GreaterOrEqual(t, o.Latency.Max, o.Latency.P50)
+				count += o.Count
+			}
+			require.Equal(t, r.Total.Count, count)
+
+			// Every incr is counted.
+			dm, err := client.NewDMap(b.config.DMap)
+			require.NoError(t, err)
+			var counters int64
+			for i := uint64(0); i < uint64(b.config.Keys); i++ {
+				gr, err := dm.Get(context.Background(), key(OpIncr, i))
+				if err == olric.ErrKeyNotFound {
+					continue
+				}
+				require.NoError(t, err)
+				value, err := gr.Int()
+				require.NoError(t, err)
+				counters += int64(value)
+			}
+			require.Equal(t, findOperation(t, r, OpIncr).Count, counters)
+		})
+	}
+}
+
+func TestBenchmark_Preload(t *testing.T) {
+	client := newTestClient(t)
+
+	b, err := New(client, &Config{
+		Concurrency: 4,
+		Requests:    500,
+		Keys:        100,
+		Operations:  "get",
+		Preload:     true,
+	})
+	require.NoError(t, err)
+
+	r, err := b.Run(context.Background())
+	require.NoError(t, err)
+	get := findOperation(t, r, OpGet)
+	require.Equal(t, int64(500), get.Count)
+	require.Equal(t, int64(0), get.Misses)
+}
+
+func TestBenchmark_Duration(t *testing.T) {
+	client := newTestClient(t)
+
+	b, err := New(client, &Config{
+		Concurrency: 2,
+		Duration:    200 * time.Millisecond,
+		Operations:  "get:1,put:1",
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	r, err := b.Run(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	require.Greater(t, r.Total.Count, int64(0))
+	require.Equal(t, int64(0), r.Errors())
+}
+
+func TestBenchmark_Report(t *testing.T) {
+	client := newTestClient(t)
+
+	b, err := New(client, &Config{
+		Concurrency: 2,
+		Requests:    100,
+		Operations:  "put",
+	})
+	require.NoError(t, err)
+	r, err := b.Run(context.Background())
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, r.WriteJSON(buf))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, DefaultDMap, decoded.DMap)
+	require.Equal(t, int64(100), decoded.Total.Count)
+	require.Equal(t, OpPut, decoded.Operations[0].Operation)
+
+	buf.Reset()
+	require.NoError(t, r.WriteText(buf))
+	require.Contains(t, buf.String(), "100 operations in")
+	require.Contains(t, buf.String(), "p99.99")
+
+	buf.Reset()
+	require.NoError(t, r.WriteHistograms(buf))
+	require.Contains(t, buf.String(), "put latency distribution (ms)")
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	client := newTestClient(t)
+
+	_, err := New(client, &Config{Operations: "foo"})
+	require.Error(t, err)
+
+	_, err = New(client, &Config{Distribution: "normal"})
+	require.Error(t, err)
+
+	_, err = New(client, &Config{Distribution: DistributionZipfian, ZipfS: 0.5})
+	require.Error(t, err)
+}
+
+func TestResult_Record(t *testing.T) {
+	r := newResult()
+	r.record(0, nil, false)
+	r.record(2*time.Minute, nil, true)
+	r.record(time.Millisecond, fmt.Errorf("boom"), false)
+	r.record(time.Millisecond, fmt.Errorf("bang"), false)
+
+	require.Equal(t, int64(2), r.histogram.TotalCount())
+	require.Equal(t, int64(minLatency), r.histogram.Min())
+	require.Equal(t, int64(1), r.misses)
+	require.Equal(t, int64(2), r.errors)
+	require.EqualError(t, r.err, "boom")
+}
@@ -0,0 +1,262 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// The latencies are recorded in microseconds, from 1µs to 1 minute with 3
+// significant figures.
+const (
+	minLatency         = 1
+	maxLatency         = int64(time.Minute / time.Microsecond)
+	significantFigures = 3
+)
+
+// result holds the latencies and the failures of an operation. It's not safe
+// for concurrent use, every worker has its own results.
+type result struct {
+	histogram *hdrhistogram.Histogram
+	errors    int64
+	misses    int64
+	err       error
+}
+
+func newResult() *result {
+	return &result{
+		histogram: hdrhistogram.New(minLatency, maxLatency, significantFigures),
+	}
+}
+
+// record records the latency of a successful operation, or the error of a
+// failed one. A miss is a get on a key that doesn't exist, or a
+// CompareAndSwap that doesn't swap.
+func (r *result) record(latency time.Duration, err error, miss bool) {
+	if err != nil {
+		r.errors++
+		if r.err == nil {
+			r.err = err
+		}
+		return
+	}
+	if miss {
+		r.misses++
+	}
+	value := latency.Microseconds()
+	switch {
+	case value < minLatency:
+		value = minLatency
+	case value > maxLatency:
+		value = maxLatency
+	}
+	// The value is always in the range of the histogram.
+	_ = r.histogram.RecordValue(value)
+}
+
+func (r *result) merge(other *result) {
+	r.histogram.Merge(other.histogram)
+	r.errors += other.errors
+	r.misses += other.misses
+	if r.err == nil {
+		r.err = other.err
+	}
+}
+
+// Latency is the summary of the latencies of an operation, in microseconds.
+type Latency struct {
+	Min   int64   `json:"min"`
+	Mean  float64 `json:"mean"`
+	P50   int64   `json:"p50"`
+	P90   int64   `json:"p90"`
+	P99   int64   `json:"p99"`
+	P999  int64   `json:"p99_9"`
+	P9999 int64   `json:"p99_99"`
+	Max   int64   `json:"max"`
+}
+
+// OperationReport is the result of an operation.
+type OperationReport struct {
+	Operation string `json:"operation"`
+
+	// Count is the number of the successful operations, including the misses.
+	Count int64 `json:"count"`
+
+	// Throughput is the number of the successful operations per second.
+	Throughput float64 `json:"ops_per_second"`
+
+	// Misses is the number of the gets on a missing key, or the
+	// CompareAndSwaps that didn't swap.
+	Misses int64 `json:"misses"`
+
+	// Errors is the number of the failed operations. Error is the first error.
+	Errors int64  `json:"errors"`
+	Error  string `json:"error,omitempty"`
+
+	Latency Latency `json:"latency_us"`
+
+	histogram *hdrhistogram.Histogram
+}
+
+func newOperationReport(name string, elapsed time.Duration, r *result) *OperationReport {
+	h := r.histogram
+	o := &OperationReport{
+		Operation: name,
+		Count:     h.TotalCount(),
+		Misses:    r.misses,
+		Errors:    r.errors,
+		histogram: h,
+	}
+	if r.err != nil {
+		o.Error = r.err.Error()
+	}
+	if elapsed > 0 {
+		o.Throughput = float64(o.Count) / elapsed.Seconds()
+	}
+	if o.Count > 0 {
+		o.Latency = Latency{
+			Min:   h.Min(),
+			Mean:  h.Mean(),
+			P50:   h.ValueAtQuantile(50),
+			P90:   h.ValueAtQuantile(90),
+			P99:   h.ValueAtQuantile(99),
+			P999:  h.ValueAtQuantile(99.9),
+			P9999: h.ValueAtQuantile(99.99),
+			Max:   h.Max(),
+		}
+	}
+	return o
+}
+
+// Report is the result of a benchmark.
+type Report struct {
+	DMap         string  `json:"dmap"`
+	Concurrency  int     `json:"concurrency"`
+	Pipeline     int     `json:"pipeline"`
+	Keys         int     `json:"keys"`
+	Distribution string  `json:"distribution"`
+	ValueSize    int     `json:"value_size"`
+	MaxValueSize int     `json:"max_value_size"`
+	Elapsed      float64 `json:"elapsed_seconds"`
+
+	// Operations are the results of the operations, in the order of
+	// OpPut, OpGet, OpIncr, OpCAS and OpLock. Total is the result of all
+	// the operations.
+	Operations []*OperationReport `json:"operations"`
+	Total      *OperationReport   `json:"total"`
+}
+
+func newReport(c *Config, elapsed time.Duration, results map[string]*result) *Report {
+	r := &Report{
+		DMap:         c.DMap,
+		Concurrency:  c.Concurrency,
+		Pipeline:     c.Pipeline,
+		Keys:         c.Keys,
+		Distribution: c.Distribution,
+		ValueSize:    c.ValueSize,
+		MaxValueSize: c.MaxValueSize,
+		Elapsed:      elapsed.Seconds(),
+	}
+	total := newResult()
+	for _, op := range operations {
+		res, ok := results[op]
+		if !ok {
+			continue
+		}
+		r.Operations = append(r.Operations, newOperationReport(op, elapsed, res))
+		total.merge(res)
+	}
+	r.Total = newOperationReport("total", elapsed, total)
+	return r
+}
+
+// Errors returns the number of the failed operations.
+func (r *Report) Errors() int64 {
+	return r.Total.Errors
+}
+
+// WriteJSON writes the report in JSON format.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func formatLatency(us int64) string {
+	return (time.Duration(us) * time.Microsecond).String()
+}
+
+// WriteText writes the report as a table.
+func (r *Report) WriteText(w io.Writer) error {
+	elapsed := time.Duration(r.Elapsed * float64(time.Second)).Round(time.Millisecond)
+	valueSize := fmt.Sprintf("%d bytes", r.ValueSize)
+	if r.MaxValueSize > r.ValueSize {
+		valueSize = fmt.Sprintf("%d-%d bytes", r.ValueSize, r.MaxValueSize)
+	}
+	_, err := fmt.Fprintf(w, "%d operations in %s, %.2f ops/s\n"+
+		"dmap: %s, concurrency: %d, pipeline: %d, keys: %d (%s), value size: %s\n\n",
+		r.Total.Count, elapsed, r.Total.Throughput,
+		r.DMap, r.Concurrency, r.Pipeline, r.Keys, r.Distribution, valueSize)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprintln(tw, "operation\tcount\tops/s\tmisses\terrors\tmean\tp50\tp90\tp99\tp99.9\tp99.99\tmax\t")
+	for _, o := range append(r.Operations, r.Total) {
+		l := o.Latency
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%.2f\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			o.Operation, o.Count, o.Throughput, o.Misses, o.Errors,
+			formatLatency(int64(l.Mean)), formatLatency(l.P50), formatLatency(l.P90), formatLatency(l.P99),
+			formatLatency(l.P999), formatLatency(l.P9999), formatLatency(l.Max))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, o := range r.Operations {
+		if o.Errors == 0 {
+			continue
+		}
+		_, err = fmt.Fprintf(w, "\n%s: %d errors, the first one: %s", o.Operation, o.Errors, o.Error)
+		if err != nil {
+			return err
+		}
+	}
+	if r.Total.Errors > 0 {
+		_, err = fmt.Fprintln(w)
+	}
+	return err
+}
+
+// WriteHistograms writes the latency distribution of every operation, in
+// milliseconds.
+func (r *Report) WriteHistograms(w io.Writer) error {
+	for _, o := range r.Operations {
+		if _, err := fmt.Fprintf(w, "\n%s latency distribution (ms)\n", o.Operation); err != nil {
+			return err
+		}
+		if _, err := o.histogram.PercentilesPrint(w, 5, 1000); err != nil {
+			return err
+		}
+	}
+	return nil
+}
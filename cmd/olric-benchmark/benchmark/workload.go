@@ -0,0 +1,149 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DistributionUniform picks every key with the same probability.
+	DistributionUniform = "uniform"
+
+	// DistributionZipfian picks the keys with a Zipf distribution, a few keys
+	// are hot and the most of the keys are cold.
+	DistributionZipfian = "zipfian"
+)
+
+// keyGenerator returns the index of the next key, in [0, keys).
+type keyGenerator interface {
+	next() uint64
+}
+
+type uniform struct {
+	r    *rand.Rand
+	keys uint64
+}
+
+func (u *uniform) next() uint64 {
+	return uint64(u.r.Int63n(int64(u.keys)))
+}
+
+type zipfian struct {
+	z *rand.Zipf
+}
+
+func (z *zipfian) next() uint64 {
+	return z.z.Uint64()
+}
+
+func newKeyGenerator(c *Config, r *rand.Rand) keyGenerator {
+	if c.Distribution == DistributionZipfian {
+		return &zipfian{z: rand.NewZipf(r, c.ZipfS, c.ZipfV, uint64(c.Keys-1))}
+	}
+	return &uniform{r: r, keys: uint64(c.Keys)}
+}
+
+// operation is an operation of the workload with its weight.
+type operation struct {
+	name   string
+	weight int
+}
+
+// mix picks the operations randomly, with the probabilities of their weights.
+type mix struct {
+	operations []operation
+	total      int
+}
+
+// parseMix parses a comma separated list of operations with their weights,
+// e.g. "get:80,put:20". The weight is 1 if it's not given.
+func parseMix(s string) (*mix, error) {
+	m := &mix{}
+	seen := make(map[string]struct{})
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, weight := item, 1
+		if i := strings.IndexByte(item, ':'); i >= 0 {
+			name = item[:i]
+			w, err := strconv.Atoi(item[i+1:])
+			if err != nil || w < 0 {
+				return nil, fmt.Errorf("invalid weight: %s", item)
+			}
+			weight = w
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isOperation(name) {
+			return nil, fmt.Errorf("unknown operation: %s", name)
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("duplicate operation: %s", name)
+		}
+		seen[name] = struct{}{}
+		if weight == 0 {
+			continue
+		}
+		m.operations = append(m.operations, operation{name: name, weight: weight})
+		m.total += weight
+	}
+	if m.total == 0 {
+		return nil, fmt.Errorf("no operations")
+	}
+	return m, nil
+}
+
+func (m *mix) pick(r *rand.Rand) string {
+	n := r.Intn(m.total)
+	for _, op := range m.operations {
+		if n < op.weight {
+			return op.name
+		}
+		n -= op.weight
+	}
+	// Not reachable, the weights sum up to total.
+	return m.operations[len(m.operations)-1].name
+}
+
+// valueSize returns the size of the next value.
+func (c *Config) valueSize(r *rand.Rand) int {
+	if c.MaxValueSize <= c.ValueSize {
+		return c.ValueSize
+	}
+	return c.ValueSize + r.Intn(c.MaxValueSize-c.ValueSize+1)
+}
+
+// ParseValueSize parses a value size, e.g. "128", or a range of the value sizes,
+// e.g. "64-1024".
+func ParseValueSize(s string) (int, int, error) {
+	min, max, found := strings.Cut(s, "-")
+	minSize, err := strconv.Atoi(strings.TrimSpace(min))
+	if err != nil || minSize <= 0 {
+		return 0, 0, fmt.Errorf("invalid value size: %s", s)
+	}
+	if !found {
+		return minSize, minSize, nil
+	}
+	maxSize, err := strconv.Atoi(strings.TrimSpace(max))
+	if err != nil || maxSize < minSize {
+		return 0, 0, fmt.Errorf("invalid value size: %s", s)
+	}
+	return minSize, maxSize, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package benchmark
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMix(t *testing.T) {
+	m, err := parseMix("get:80, PUT:20,incr:0")
+	require.NoError(t, err)
+	require.Equal(t, []operation{{name: OpGet, weight: 80}, {name: OpPut, weight: 20}}, m.operations)
+	require.Equal(t, 100, m.total)
+
+	m, err = parseMix("put,get,incr,cas,lock")
+	require.NoError(t, err)
+	require.Len(t, m.operations, 5)
+	require.Equal(t, 5, m.total)
+
+	for _, s := range []string{"", "get:0", "foo", "get:x", "get:-1", "get,get:2"} {
+		_, err = parseMix(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestMix_Pick(t *testing.T) {
+	m, err := parseMix("get:3,put:1")
+	require.NoError(t, err)
+
+	r := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		counts[m.pick(r)]++
+	}
+	require.Len(t, counts, 2)
+	require.InDelta(t, 7500, counts[OpGet], 300)
+	require.InDelta(t, 2500, counts[OpPut], 300)
+}
+
+func TestParseValueSize(t *testing.T) {
+	min, max, err := ParseValueSize("128")
+	require.NoError(t, err)
+	require.Equal(t, 128, min)
+	require.Equal(t, 128, max)
+
+	min, max, err = ParseValueSize("64-1024")
+	require.NoError(t, err)
+	require.Equal(t, 64, min)
+	require.Equal(t, 1024, max)
+
+	for _, s := range []string{"", "0", "-1", "x", "64-", "1024-64"} {
+		_, _, err = ParseValueSize(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestKeyGenerator(t *testing.T) {
+	for _, distribution := range []string{DistributionUniform, DistributionZipfian} {
+		t.Run(distribution, func(t *testing.T) {
+			c := &Config{Keys: 100, Distribution: distribution}
+			require.NoError(t, c.sanitize())
+
+			g := newKeyGenerator(c, rand.New(rand.NewSource(1)))
+			counts := make([]int, c.Keys)
+			for i := 0; i < 100000; i++ {
+				k := g.next()
+				require.Less(t, k, uint64(c.Keys))
+				counts[k]++
+			}
+			if distribution == DistributionZipfian {
+				// The first key is the hottest one.
+				require.Greater(t, counts[0], 10*counts[c.Keys-1])
+			} else {
+				require.InDelta(t, 1000, counts[0], 200)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// olric-benchmark is a load generator for Olric clusters.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/olric-data/olric"
+	"github.com/olric-data/olric/cmd/olric-benchmark/benchmark"
+	"github.com/olric-data/olric/config"
+)
+
+func usage() {
+	var msg = `Usage: olric-benchmark [options]
+
+Load generator for Olric clusters
+
+Options:
+  -h, --help         Print this message and exit.
+  -v, --version      Print the version number and exit.
+  -a, --address      Comma separated addresses of the cluster members. Default is 127.0.0.1:3320.
+  -u, --user         The user to authenticate as.
+  -p, --password     The password. Set OLRIC_BENCHMARK_PASSWORD to keep it out of the process list.
+  -d, --dmap         The name of the DMap. Default is olric-benchmark.
+  -c, --concurrency  The number of the concurrent workers. Default is 50.
+  -n, --requests     The number of the operations to run. Default is 100000.
+  --duration         Run the benchmark for the given duration, e.g. 1m, instead of
+                     a fixed number of operations.
+  -k, --keys         The number of the distinct keys. Default is 100000.
+  --distribution     The distribution of the keys, uniform or zipfian. Default is uniform.
+  --zipf-s           The s parameter of the Zipf distribution, must be greater than 1. Default is 1.1.
+  --zipf-v           The v parameter of the Zipf distribution, must be at least 1. Default is 1.
+  -s, --value-size   The size of the values in bytes, or a range of the sizes, e.g. 64-1024.
+                     Default is 128.
+  -o, --operations   The mix of the operations with their weights. The operations are
+                     put, get, incr, cas and lock. Default is get:80,put:20.
+  -P, --pipeline     The number of the operations in a pipeline. put, get and incr are
+                     pipelined if it's greater than 1. Default is 1.
+  --preload          Write all the keys before the benchmark, so get doesn't miss.
+  -t, --timeout      The timeout of an operation. Default is 10s.
+  -f, --format       The format of the report, text or json. Default is text.
+  --histogram        Print the latency distribution of every operation.
+
+The weights are relative, get:3,put:1 runs 3 gets per put. An operation without a
+weight has the weight 1, e.g. put,get runs the same number of puts and gets.
+
+The latencies are measured by the client. The latency of a pipelined operation is
+the latency of its pipeline. Only CompareAndSwap is measured in cas, the current
+value is read before. lock measures LockWithTimeout and Unlock together.
+
+olric-benchmark exits with status 1 if an operation fails.
+
+The Go runtime version %s
+Report bugs to https://github.com/olric-data/olric/issues
+`
+	_, err := fmt.Fprintf(os.Stdout, msg, runtime.Version())
+	if err != nil {
+		panic(err)
+	}
+}
+
+type arguments struct {
+	address      string
+	user         string
+	password     string
+	dmap         string
+	concurrency  int
+	requests     int64
+	duration     time.Duration
+	keys         int
+	distribution string
+	zipfS        float64
+	zipfV        float64
+	valueSize    string
+	operations   string
+	pipeline     int
+	preload      bool
+	timeout      time.Duration
+	format       string
+	histogram    bool
+	help         bool
+	version      bool
+}
+
+const (
+	// DefaultAddress is the default address of the cluster member.
+	DefaultAddress = "127.0.0.1:3320"
+
+	// EnvPassword is the name of environment variable which can be used to set the password.
+	EnvPassword = "OLRIC_BENCHMARK_PASSWORD"
+)
+
+func main() {
+	args := &arguments{}
+
+	// Parse command line parameters
+	f := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	f.SetOutput(io.Discard)
+	f.BoolVar(&args.help, "h", false, "")
+	f.BoolVar(&args.help, "help", false, "")
+
+	f.BoolVar(&args.version, "version", false, "")
+	f.BoolVar(&args.version, "v", false, "")
+
+	f.StringVar(&args.address, "address", DefaultAddress, "")
+	f.StringVar(&args.address, "a", DefaultAddress, "")
+
+	f.StringVar(&args.user, "user", "", "")
+	f.StringVar(&args.user, "u", "", "")
+
+	f.StringVar(&args.password, "password", os.Getenv(EnvPassword), "")
+	f.StringVar(&args.password, "p", os.Getenv(EnvPassword), "")
+
+	f.StringVar(&args.dmap, "dmap", benchmark.DefaultDMap, "")
+	f.StringVar(&args.dmap, "d", benchmark.DefaultDMap, "")
+
+	f.IntVar(&args.concurrency, "concurrency", benchmark.DefaultConcurrency, "")
+	f.IntVar(&args.concurrency, "c", benchmark.DefaultConcurrency, "")
+
+	f.Int64Var(&args.requests, "requests", benchmark.DefaultRequests, "")
+	f.Int64Var(&args.requests, "n", benchmark.DefaultRequests, "")
+
+	f.DurationVar(&args.duration, "duration", 0, "")
+
+	f.IntVar(&args.keys, "keys", benchmark.DefaultKeys, "")
+	f.IntVar(&args.keys, "k", benchmark.DefaultKeys, "")
+
+	f.StringVar(&args.distribution, "distribution", benchmark.DistributionUniform, "")
+	f.Float64Var(&args.zipfS, "zipf-s", benchmark.DefaultZipfS, "")
+	f.Float64Var(&args.zipfV, "zipf-v", benchmark.DefaultZipfV, "")
+
+	defaultValueSize := fmt.Sprintf("%d", benchmark.DefaultValueSize)
+	f.StringVar(&args.valueSize, "value-size", defaultValueSize, "")
+	f.StringVar(&args.valueSize, "s", defaultValueSize, "")
+
+	f.StringVar(&args.operations, "operations", benchmark.DefaultOperations, "")
+	f.StringVar(&args.operations, "o", benchmark.DefaultOperations, "")
+
+	f.IntVar(&args.pipeline, "pipeline", 1, "")
+	f.IntVar(&args.pipeline, "P", 1, "")
+
+	f.BoolVar(&args.preload, "preload", false, "")
+
+	f.DurationVar(&args.timeout, "timeout", benchmark.DefaultTimeout, "")
+	f.DurationVar(&args.timeout, "t", benchmark.DefaultTimeout, "")
+
+	f.StringVar(&args.format, "format", "text", "")
+	f.StringVar(&args.format, "f", "text", "")
+
+	f.BoolVar(&args.histogram, "histogram", false, "")
+
+	if err := f.Parse(os.Args[1:]); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "parsing error: %v\n", err)
+		usage()
+		os.Exit(1)
+	}
+
+	if args.version {
+		_, _ = fmt.Fprintf(os.Stderr, "olric-benchmark version %s %s %s/%s\n",
+			olric.ReleaseVersion,
+			runtime.Version(),
+			runtime.GOOS,
+			runtime.GOARCH,
+		)
+		return
+	} else if args.help {
+		usage()
+		return
+	}
+
+	if f.NArg() > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "unexpected arguments: %s\n", strings.Join(f.Args(), " "))
+		os.Exit(1)
+	}
+	if args.format != "text" && args.format != "json" {
+		_, _ = fmt.Fprintf(os.Stderr, "unknown format: %s\n", args.format)
+		os.Exit(1)
+	}
+	valueSize, maxValueSize, err := benchmark.ParseValueSize(args.valueSize)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	var addresses []string
+	for _, address := range strings.Split(args.address, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "at least one address is required")
+		os.Exit(1)
+	}
+
+	// The replies of lock and pipelines may take longer than the default
+	// read timeout of the client.
+	cc := config.NewClient()
+	cc.ReadTimeout = args.timeout
+	cc.WriteTimeout = args.timeout
+	// Every worker keeps a connection busy.
+	if cc.PoolSize < args.concurrency {
+		cc.PoolSize = args.concurrency
+	}
+	options := []olric.ClusterClientOption{olric.WithConfig(cc)}
+	switch {
+	case args.user != "":
+		options = append(options, olric.WithUser(args.user, args.password))
+	case args.password != "":
+		options = append(options, olric.WithPassword(args.password))
+	}
+
+	client, err := olric.NewClusterClient(addresses, options...)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to connect to the cluster: %v\n", err)
+		os.Exit(1)
+	}
+
+	b, err := benchmark.New(client, &benchmark.Config{
+		DMap:         args.dmap,
+		Concurrency:  args.concurrency,
+		Requests:     args.requests,
+		Duration:     args.duration,
+		Keys:         args.keys,
+		Distribution: args.distribution,
+		ZipfS:        args.zipfS,
+		ZipfV:        args.zipfV,
+		ValueSize:    valueSize,
+		MaxValueSize: maxValueSize,
+		Operations:   args.operations,
+		Pipeline:     args.pipeline,
+		Preload:      args.preload,
+		Timeout:      args.timeout,
+	})
+	if err == nil {
+		// Interrupting the benchmark stops it and prints the report of the
+		// operations that have been run so far.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		var report *benchmark.Report
+		report, err = b.Run(ctx)
+		stop()
+		if err == nil {
+			err = writeReport(report, args)
+			if err == nil && report.Errors() > 0 {
+				err = fmt.Errorf("%d operations failed", report.Errors())
+			}
+		}
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), args.timeout)
+	defer cancel()
+	if cerr := client.Close(ctx); cerr != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to close the client: %v\n", cerr)
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func writeReport(report *benchmark.Report, args *arguments) error {
+	if args.format == "json" {
+		return report.WriteJSON(os.Stdout)
+	}
+	if err := report.WriteText(os.Stdout); err != nil {
+		return err
+	}
+	if args.histogram {
+		return report.WriteHistograms(os.Stdout)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*Package cli implements the commands, the interactive shell and the JSON output of olric-cli.*/
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/olric-data/olric"
+)
+
+// DefaultTimeout is the default timeout of a command. The subscriptions are not
+// limited by the timeout.
+const DefaultTimeout = 10 * time.Second
+
+// ErrUnknownCommand is returned when the command is not one of the commands of olric-cli.
+var ErrUnknownCommand = errors.New("unknown command")
+
+// Config is the configuration of a CLI.
+type Config struct {
+	// Address is the member that runs the commands that target a single member,
+	// like stats and ping, if an address is not given.
+	Address string
+
+	// Timeout is the timeout of a command.
+	Timeout time.Duration
+
+	// JSON prints the results in JSON format, one object per line.
+	JSON bool
+
+	// Output is the destination of the results. It's os.Stdout in olric-cli.
+	Output io.Writer
+}
+
+// CLI runs the commands of olric-cli on a cluster.
+type CLI struct {
+	mtx    sync.Mutex
+	config *Config
+	client olric.Client
+	pubsub *olric.PubSub
+	dmaps  map[string]olric.DMap
+
+	// locks holds the locks acquired by this session. The tokens are kept by
+	// the client, so a lock can only be released by the session that acquired it.
+	locks map[lockKey]olric.LockContext
+}
+
+type lockKey struct {
+	dmap string
+	key  string
+}
+
+// New returns a new CLI. The client is not closed by the CLI.
+func New(client olric.Client, c *Config) *CLI {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	return &CLI{
+		config: c,
+		client: client,
+		dmaps:  make(map[string]olric.DMap),
+		locks:  make(map[lockKey]olric.LockContext),
+	}
+}
+
+func (c *CLI) getDMap(name string) (olric.DMap, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	dm, ok := c.dmaps[name]
+	if ok {
+		return dm, nil
+	}
+	dm, err := c.client.NewDMap(name)
+	if err != nil {
+		return nil, err
+	}
+	c.dmaps[name] = dm
+	return dm, nil
+}
+
+func (c *CLI) getPubSub() (*olric.PubSub, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.pubsub != nil {
+		return c.pubsub, nil
+	}
+	ps, err := c.client.NewPubSub()
+	if err != nil {
+		return nil, err
+	}
+	c.pubsub = ps
+	return ps, nil
+}
+
+// Close releases the locks that are still held by the session.
+func (c *CLI) Close(ctx context.Context) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var result error
+	for key, lock := range c.locks {
+		if err := lock.Unlock(ctx); err != nil && !errors.Is(err, olric.ErrNoSuchLock) {
+			result = fmt.Errorf("failed to release the lock: %s/%s: %w", key.dmap, key.key, err)
+		}
+		delete(c.locks, key)
+	}
+	return result
+}
+
+// Execute runs a command line and writes its result to the output. The
+// subscriptions run until ctx is canceled.
+func (c *CLI) Execute(ctx context.Context, line string) error {
+	args, err := splitArgs(line)
+	if err != nil {
+		c.writeError(err)
+		return err
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	return c.Run(ctx, args)
+}
+
+// Run runs a command with its arguments and writes its result to the output.
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	cmd, ok := commands[strings.ToLower(args[0])]
+	if !ok {
+		err := fmt.Errorf("%w: '%s', try 'help'", ErrUnknownCommand, args[0])
+		c.writeError(err)
+		return err
+	}
+
+	args = args[1:]
+	if len(args) < cmd.minArgs || (cmd.maxArgs >= 0 && len(args) > cmd.maxArgs) {
+		err := fmt.Errorf("wrong number of arguments for '%s', usage: %s", cmd.name, cmd.usageLine())
+		c.writeError(err)
+		return err
+	}
+
+	if !cmd.stream {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+
+	result, err := cmd.run(ctx, c, args)
+	if errors.Is(err, errQuit) {
+		return err
+	}
+	if err != nil {
+		c.writeError(err)
+		return err
+	}
+	if cmd.stream {
+		// The results have already been written.
+		return nil
+	}
+	c.writeResult(result)
+	return nil
+}
+
+// Commands returns the names of the commands, sorted.
+func Commands() []string {
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *CLI) writeError(err error) {
+	if c.config.JSON {
+		c.writeJSON(map[string]string{"error": err.Error()})
+		return
+	}
+	_, _ = fmt.Fprintf(c.config.Output, "(error) %v\n", err)
+}
+
+func (c *CLI) writeResult(result interface{}) {
+	if c.config.JSON {
+		c.writeJSON(map[string]interface{}{"result": result})
+		return
+	}
+	_, _ = io.WriteString(c.config.Output, formatText(result))
+}
+
+func (c *CLI) writeJSON(v interface{}) {
+	data, err := marshalJSON(v, "")
+	if err != nil {
+		data, _ = marshalJSON(map[string]string{"error": err.Error()}, "")
+	}
+	_, _ = c.config.Output.Write(data)
+}
@@ -0,0 +1,383 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric"
+	"github.com/olric-data/olric/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCLI(t *testing.T) (*CLI, *bytes.Buffer) {
+	c := testutil.NewConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Started = func() {
+		cancel()
+	}
+	db, err := olric.New(c)
+	require.NoError(t, err)
+
+	go func() {
+		if err := db.Start(); err != nil {
+			panic(fmt.Sprintf("Failed to run Olric: %v", err))
+		}
+	}()
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("Olric cannot be started in one second")
+	case <-ctx.Done():
+	}
+
+	address := net.JoinHostPort(c.BindAddr, strconv.Itoa(c.BindPort))
+	client, err := olric.NewClusterClient([]string{address})
+	require.NoError(t, err)
+
+	output := bytes.NewBuffer(nil)
+	cli := New(client, &Config{
+		Address: address,
+		JSON:    true,
+		Output:  output,
+	})
+	t.Cleanup(func() {
+		require.NoError(t, cli.Close(context.Background()))
+		require.NoError(t, client.Close(context.Background()))
+		require.NoError(t, db.Shutdown(context.Background()))
+	})
+	return cli, output
+}
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use.
+type syncBuffer struct {
+	mtx sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.String()
+}
+
+type reply struct {
+	Result interface{} `json:"result"`
+	Error  string      `json:"error"`
+}
+
+func readReplies(t *testing.T, output *bytes.Buffer) []reply {
+	var replies []reply
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		var r reply
+		require.NoError(t, json.Unmarshal([]byte(line), &r))
+		replies = append(replies, r)
+	}
+	output.Reset()
+	return replies
+}
+
+func TestCLI_DMap(t *testing.T) {
+	cli, output := newTestCLI(t)
+
+	script := `
+# Comments and empty lines are skipped.
+put mymap mykey "hello world" EX 100
+get mymap mykey
+get mymap nokey
+put mymap mykey other NX
+incr mymap counter 5
+decr mymap counter
+incrbyfloat mymap float 1.5
+getput mymap mykey new-value
+cas mymap mykey new-value swapped
+cas mymap mykey new-value again
+scan mymap
+scan mymap MATCH ^c
+del mymap counter float
+expire mymap mykey 10
+destroy mymap
+get mymap mykey
+`
+	err := cli.RunScript(context.Background(), strings.NewReader(script))
+	require.ErrorIs(t, err, ErrScriptFailed)
+
+	require.Equal(t, []reply{
+		{Result: "OK"},
+		{Result: "hello world"},
+		{Result: nil},
+		{Error: olric.ErrKeyFound.Error()},
+		{Result: float64(5)},
+		{Result: float64(4)},
+		{Result: 1.5},
+		{Result: "hello world"},
+		{Result: map[string]interface{}{"swapped": true, "current": nil}},
+		{Result: map[string]interface{}{"swapped": false, "current": "swapped"}},
+		{Result: []interface{}{"counter", "float", "mykey"}},
+		{Result: []interface{}{"counter"}},
+		{Result: float64(2)},
+		{Result: "OK"},
+		{Result: "OK"},
+		{Result: nil},
+	}, readReplies(t, output))
+}
+
+func TestCLI_Lock(t *testing.T) {
+	cli, output := newTestCLI(t)
+
+	script := `
+lock mymap mykey 1 EX 10
+lock mymap mykey 1
+locks
+lease mymap mykey 20
+unlock mymap mykey
+unlock mymap mykey
+lock mymap other 1 PX 10000
+`
+	err := cli.RunScript(context.Background(), strings.NewReader(script))
+	require.ErrorIs(t, err, ErrScriptFailed)
+
+	replies := readReplies(t, output)
+	require.Len(t, replies, 7)
+	require.Equal(t, reply{Result: "OK"}, replies[0])
+	require.Contains(t, replies[1].Error, "already held by this session")
+	require.Equal(t, reply{Result: []interface{}{"mymap/mykey"}}, replies[2])
+	require.Equal(t, reply{Result: "OK"}, replies[3])
+	require.Equal(t, reply{Result: "OK"}, replies[4])
+	require.Contains(t, replies[5].Error, olric.ErrNoSuchLock.Error())
+	require.Equal(t, reply{Result: "OK"}, replies[6])
+
+	// Close releases the lock, so it can be acquired again.
+	require.NoError(t, cli.Close(context.Background()))
+	require.NoError(t, cli.Run(context.Background(), []string{"lock", "mymap", "other", "1"}))
+}
+
+func TestCLI_Cluster(t *testing.T) {
+	cli, output := newTestCLI(t)
+
+	require.NoError(t, cli.Run(context.Background(), []string{"members"}))
+	replies := readReplies(t, output)
+	members := replies[0].Result.([]interface{})
+	require.Len(t, members, 1)
+	require.Equal(t, cli.config.Address, members[0].(map[string]interface{})["name"])
+	require.Equal(t, true, members[0].(map[string]interface{})["coordinator"])
+
+	require.NoError(t, cli.Run(context.Background(), []string{"routingtable"}))
+	replies = readReplies(t, output)
+	require.Len(t, replies[0].Result.([]interface{}), 7)
+
+	require.NoError(t, cli.Run(context.Background(), []string{"stats", "RUNTIME"}))
+	replies = readReplies(t, output)
+	s := replies[0].Result.(map[string]interface{})
+	require.NotNil(t, s["runtime"])
+
+	require.NoError(t, cli.Run(context.Background(), []string{"ping"}))
+	require.NoError(t, cli.Run(context.Background(), []string{"ping", cli.config.Address, "hello"}))
+	require.Equal(t, []reply{{Result: "PONG"}, {Result: "hello"}}, readReplies(t, output))
+}
+
+func TestCLI_PubSub(t *testing.T) {
+	cli, output := newTestCLI(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscriberOutput := &syncBuffer{}
+	subscriber := New(cli.client, &Config{
+		Address: cli.config.Address,
+		JSON:    true,
+		Output:  subscriberOutput,
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- subscriber.Run(ctx, []string{"subscribe", "mychannel"})
+	}()
+
+	err := testutil.TryWithInterval(50, 10*time.Millisecond, func() error {
+		output.Reset()
+		if err := cli.Run(context.Background(), []string{"publish", "mychannel", "hello"}); err != nil {
+			return err
+		}
+		if strings.TrimSpace(output.String()) != `{"result":1}` {
+			return fmt.Errorf("no subscribers")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = testutil.TryWithInterval(50, 10*time.Millisecond, func() error {
+		if !strings.Contains(subscriberOutput.String(), `"payload":"hello"`) {
+			return fmt.Errorf("no message")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	lines := strings.Split(strings.TrimSpace(subscriberOutput.String()), "\n")
+	require.Equal(t, `{"result":{"kind":"subscribe","channel":"mychannel","count":1}}`, lines[0])
+	require.Equal(t, `{"result":{"kind":"message","channel":"mychannel","payload":"hello"}}`, lines[1])
+}
+
+func TestCLI_Errors(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+	cli := New(nil, &Config{Output: output})
+
+	require.ErrorIs(t, cli.Execute(context.Background(), "foobar"), ErrUnknownCommand)
+	require.Error(t, cli.Execute(context.Background(), "get mymap"))
+	require.Error(t, cli.Execute(context.Background(), `get "mymap`))
+	require.Error(t, cli.Execute(context.Background(), "put mymap key value EX"))
+	require.Error(t, cli.Execute(context.Background(), "lock mymap key 1 TIMEOUT 10"))
+	require.ErrorIs(t, cli.Execute(context.Background(), "quit"), errQuit)
+	require.Equal(t, `(error) unknown command: 'foobar', try 'help'
+(error) wrong number of arguments for 'get', usage: get <dmap> <key>
+(error) unbalanced quotes
+(error) EX requires a value
+(error) syntax error: TIMEOUT
+`, output.String())
+}
+
+func TestCLI_Help(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+	cli := New(nil, &Config{Output: output})
+
+	require.NoError(t, cli.Execute(context.Background(), "help get"))
+	require.Equal(t, "  get <dmap> <key>\n      Gets the value for the given key.\n", output.String())
+
+	output.Reset()
+	require.NoError(t, cli.Execute(context.Background(), "help"))
+	for _, group := range groups {
+		require.Contains(t, output.String(), group+":\n")
+	}
+	for _, name := range Commands() {
+		if name == "exit" {
+			continue
+		}
+		require.Contains(t, output.String(), "  "+name)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	args, err := splitArgs(`put  mymap 'my key' "a \"quoted\"\tvalue" EX 10`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"put", "mymap", "my key", "a \"quoted\"\tvalue", "EX", "10"}, args)
+
+	args, err = splitArgs(`get mymap ""`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"get", "mymap", ""}, args)
+
+	_, err = splitArgs(`get 'mymap`)
+	require.Error(t, err)
+}
+
+func TestFormatText(t *testing.T) {
+	require.Equal(t, "(nil)\n", formatText(nil))
+	require.Equal(t, "OK\n", formatText(statusOK))
+	require.Equal(t, "\"a b\"\n", formatText("a b"))
+	require.Equal(t, "(integer) 10\n", formatText(10))
+	require.Equal(t, "(double) 1.5\n", formatText(1.5))
+	require.Equal(t, "(empty array)\n", formatText([]string{}))
+	require.Equal(t, "1) \"a\"\n2) \"b\"\n", formatText([]string{"a", "b"}))
+	require.Equal(t, "(not swapped) current: \"x\"\n", formatText(casResult{Current: "x"}))
+	require.Equal(t, "{\n  \"total\": 1\n}\n", formatText(map[string]int{"total": 1}))
+}
+
+func TestCLI_Complete(t *testing.T) {
+	cli := New(nil, &Config{Output: bytes.NewBuffer(nil)})
+	cli.dmaps["mymap"] = nil
+	cli.dmaps["other"] = nil
+
+	tests := []struct {
+		line       string
+		word       string
+		candidates []string
+	}{
+		{line: "", word: "", candidates: Commands()},
+		{line: "pu", word: "pu", candidates: []string{"publish", "pubsub", "put"}},
+		{line: "PUT", word: "PUT", candidates: []string{"put"}},
+		{line: "get ", word: "", candidates: []string{"mymap", "other"}},
+		{line: "get m", word: "m", candidates: []string{"mymap"}},
+		{line: "put mymap key value e", word: "e", candidates: []string{"EX"}},
+		{line: "pubsub ", word: "", candidates: []string{"CHANNELS", "NUMSUB", "NUMPAT"}},
+		{line: "help lo", word: "lo", candidates: []string{"lock", "locks"}},
+		{line: "foobar ", word: "", candidates: nil},
+	}
+	for _, tt := range tests {
+		word, candidates := cli.candidates(tt.line)
+		require.Equal(t, tt.word, word, tt.line)
+		require.Equal(t, tt.candidates, candidates, tt.line)
+	}
+
+	line, pos, ok := cli.complete(nil, "put mymap key value e", 21, '\t')
+	require.True(t, ok)
+	require.Equal(t, "put mymap key value EX ", line)
+	require.Equal(t, 23, pos)
+
+	line, pos, ok = cli.complete(nil, "loc mymap", 3, '\t')
+	require.True(t, ok)
+	require.Equal(t, "lock mymap", line)
+	require.Equal(t, 4, pos)
+
+	_, _, ok = cli.complete(nil, "get", 3, 'x')
+	require.False(t, ok)
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h := loadHistory(path)
+	h.Add("get mymap key")
+	h.Add("get mymap key")
+	h.Add(" ")
+	h.Add("members")
+	require.Equal(t, 2, h.Len())
+	require.Equal(t, "members", h.At(0))
+	require.Equal(t, "get mymap key", h.At(1))
+
+	h = loadHistory(path)
+	require.Equal(t, 2, h.Len())
+	require.Equal(t, "members", h.At(0))
+
+	for i := 0; i < MaxHistorySize+10; i++ {
+		h.Add(strconv.Itoa(i))
+	}
+	require.Equal(t, MaxHistorySize, h.Len())
+
+	h = loadHistory(path)
+	require.Equal(t, MaxHistorySize, h.Len())
+	require.Equal(t, strconv.Itoa(MaxHistorySize+9), h.At(0))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), MaxHistorySize)
+}
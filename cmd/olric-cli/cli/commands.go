@@ -0,0 +1,923 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olric-data/olric"
+)
+
+const (
+	groupDMap    = "DMap"
+	groupAtomic  = "Atomic operations"
+	groupLock    = "Locking"
+	groupPubSub  = "Publish-Subscribe"
+	groupCluster = "Cluster"
+	groupCLI     = "olric-cli"
+)
+
+// groups is the order of the groups in the output of help.
+var groups = []string{groupDMap, groupAtomic, groupLock, groupPubSub, groupCluster, groupCLI}
+
+type command struct {
+	name    string
+	group   string
+	args    string
+	summary string
+	minArgs int
+	// maxArgs is -1 if the number of the arguments is not limited.
+	maxArgs int
+	// keywords are the options of the command. They are used for completion.
+	keywords []string
+	// dmap is true if the first argument is the name of a DMap.
+	dmap bool
+	// stream is true if the command runs until its context is canceled.
+	stream bool
+	run    func(ctx context.Context, c *CLI, args []string) (interface{}, error)
+}
+
+func (cmd *command) usageLine() string {
+	if cmd.args == "" {
+		return cmd.name
+	}
+	return cmd.name + " " + cmd.args
+}
+
+var commands = make(map[string]*command)
+
+func register(cmd *command) {
+	commands[cmd.name] = cmd
+}
+
+func init() {
+	register(&command{
+		name:     "put",
+		group:    groupDMap,
+		args:     "<dmap> <key> <value> [EX seconds | PX milliseconds] [NX | XX]",
+		summary:  "Sets the value for the given key.",
+		minArgs:  3,
+		maxArgs:  6,
+		keywords: []string{"EX", "PX", "NX", "XX"},
+		dmap:     true,
+		run:      runPut,
+	})
+	register(&command{
+		name:    "get",
+		group:   groupDMap,
+		args:    "<dmap> <key>",
+		summary: "Gets the value for the given key.",
+		minArgs: 2,
+		maxArgs: 2,
+		dmap:    true,
+		run:     runGet,
+	})
+	register(&command{
+		name:    "del",
+		group:   groupDMap,
+		args:    "<dmap> <key> [key ...]",
+		summary: "Deletes the given keys and returns the number of the deleted keys.",
+		minArgs: 2,
+		maxArgs: -1,
+		dmap:    true,
+		run:     runDel,
+	})
+	register(&command{
+		name:    "expire",
+		group:   groupDMap,
+		args:    "<dmap> <key> <seconds>",
+		summary: "Updates the timeout of the given key.",
+		minArgs: 3,
+		maxArgs: 3,
+		dmap:    true,
+		run:     runExpire,
+	})
+	register(&command{
+		name:     "scan",
+		group:    groupDMap,
+		args:     "<dmap> [MATCH pattern] [COUNT count]",
+		summary:  "Returns the keys of the DMap on the cluster.",
+		minArgs:  1,
+		maxArgs:  5,
+		keywords: []string{"MATCH", "COUNT"},
+		dmap:     true,
+		run:      runScan,
+	})
+	register(&command{
+		name:    "destroy",
+		group:   groupDMap,
+		args:    "<dmap>",
+		summary: "Deletes the DMap and all of its keys on the cluster.",
+		minArgs: 1,
+		maxArgs: 1,
+		dmap:    true,
+		run:     runDestroy,
+	})
+	register(&command{
+		name:    "incr",
+		group:   groupAtomic,
+		args:    "<dmap> <key> [delta]",
+		summary: "Increments the number stored at the key by delta, 1 by default.",
+		minArgs: 2,
+		maxArgs: 3,
+		dmap:    true,
+		run:     runIncr,
+	})
+	register(&command{
+		name:    "decr",
+		group:   groupAtomic,
+		args:    "<dmap> <key> [delta]",
+		summary: "Decrements the number stored at the key by delta, 1 by default.",
+		minArgs: 2,
+		maxArgs: 3,
+		dmap:    true,
+		run:     runDecr,
+	})
+	register(&command{
+		name:    "incrbyfloat",
+		group:   groupAtomic,
+		args:    "<dmap> <key> <delta>",
+		summary: "Increments the floating point number stored at the key by delta.",
+		minArgs: 3,
+		maxArgs: 3,
+		dmap:    true,
+		run:     runIncrByFloat,
+	})
+	register(&command{
+		name:    "getput",
+		group:   groupAtomic,
+		args:    "<dmap> <key> <value>",
+		summary: "Sets the value for the given key and returns the old value.",
+		minArgs: 3,
+		maxArgs: 3,
+		dmap:    true,
+		run:     runGetPut,
+	})
+	register(&command{
+		name:     "cas",
+		group:    groupAtomic,
+		args:     "<dmap> <key> <expected> <value> [EX seconds | PX milliseconds]",
+		summary:  "Sets the value if the current value is expected. An empty expected value means that the key doesn't exist.",
+		minArgs:  4,
+		maxArgs:  6,
+		keywords: []string{"EX", "PX"},
+		dmap:     true,
+		run:      runCAS,
+	})
+	register(&command{
+		name:     "lock",
+		group:    groupLock,
+		args:     "<dmap> <key> <seconds> [EX seconds | PX milliseconds]",
+		summary:  "Acquires a lock for the key, waits for it up to the given seconds. EX or PX releases the lock after the timeout.",
+		minArgs:  3,
+		maxArgs:  5,
+		keywords: []string{"EX", "PX"},
+		dmap:     true,
+		run:      runLock,
+	})
+	register(&command{
+		name:    "unlock",
+		group:   groupLock,
+		args:    "<dmap> <key>",
+		summary: "Releases a lock acquired by this session.",
+		minArgs: 2,
+		maxArgs: 2,
+		dmap:    true,
+		run:     runUnlock,
+	})
+	register(&command{
+		name:    "lease",
+		group:   groupLock,
+		args:    "<dmap> <key> <seconds>",
+		summary: "Updates the timeout of a lock acquired by this session.",
+		minArgs: 3,
+		maxArgs: 3,
+		dmap:    true,
+		run:     runLease,
+	})
+	register(&command{
+		name:    "locks",
+		group:   groupLock,
+		summary: "Lists the locks acquired by this session.",
+		maxArgs: 0,
+		run:     runLocks,
+	})
+	register(&command{
+		name:    "publish",
+		group:   groupPubSub,
+		args:    "<channel> <message>",
+		summary: "Publishes a message and returns the number of the receivers.",
+		minArgs: 2,
+		maxArgs: 2,
+		run:     runPublish,
+	})
+	register(&command{
+		name:    "spublish",
+		group:   groupPubSub,
+		args:    "<channel> <message>",
+		summary: "Publishes a message to a sharded channel.",
+		minArgs: 2,
+		maxArgs: 2,
+		run:     runSPublish,
+	})
+	register(&command{
+		name:    "subscribe",
+		group:   groupPubSub,
+		args:    "<channel> [channel ...]",
+		summary: "Prints the messages of the channels until Ctrl-C.",
+		minArgs: 1,
+		maxArgs: -1,
+		stream:  true,
+		run:     runSubscribe,
+	})
+	register(&command{
+		name:    "psubscribe",
+		group:   groupPubSub,
+		args:    "<pattern> [pattern ...]",
+		summary: "Prints the messages of the channels that match the patterns until Ctrl-C.",
+		minArgs: 1,
+		maxArgs: -1,
+		stream:  true,
+		run:     runPSubscribe,
+	})
+	register(&command{
+		name:    "ssubscribe",
+		group:   groupPubSub,
+		args:    "<channel> [channel ...]",
+		summary: "Prints the messages of the sharded channels until Ctrl-C.",
+		minArgs: 1,
+		maxArgs: -1,
+		stream:  true,
+		run:     runSSubscribe,
+	})
+	register(&command{
+		name:     "pubsub",
+		group:    groupPubSub,
+		args:     "CHANNELS [pattern] | NUMSUB [channel ...] | NUMPAT",
+		summary:  "Inspects the Pub/Sub state of the cluster.",
+		minArgs:  1,
+		maxArgs:  -1,
+		keywords: []string{"CHANNELS", "NUMSUB", "NUMPAT"},
+		run:      runPubSub,
+	})
+	register(&command{
+		name:    "members",
+		group:   groupCluster,
+		summary: "Lists the cluster members.",
+		maxArgs: 0,
+		run:     runMembers,
+	})
+	register(&command{
+		name:    "routingtable",
+		group:   groupCluster,
+		summary: "Prints the owners of every partition.",
+		maxArgs: 0,
+		run:     runRoutingTable,
+	})
+	register(&command{
+		name:     "stats",
+		group:    groupCluster,
+		args:     "[address] [RUNTIME]",
+		summary:  "Prints the statistics of a member.",
+		maxArgs:  2,
+		keywords: []string{"RUNTIME"},
+		run:      runStats,
+	})
+	register(&command{
+		name:    "ping",
+		group:   groupCluster,
+		args:    "[address] [message]",
+		summary: "Pings a member.",
+		maxArgs: 2,
+		run:     runPing,
+	})
+	register(&command{
+		name:    "help",
+		group:   groupCLI,
+		args:    "[command]",
+		summary: "Prints the usage of the commands.",
+		maxArgs: 1,
+		run:     runHelp,
+	})
+	register(&command{
+		name:    "quit",
+		group:   groupCLI,
+		summary: "Exits the interactive shell. exit does the same.",
+		maxArgs: 0,
+		run: func(context.Context, *CLI, []string) (interface{}, error) {
+			return nil, errQuit
+		},
+	})
+	commands["exit"] = commands["quit"]
+}
+
+// errQuit is returned by quit and exit to stop the interactive shell.
+var errQuit = errors.New("quit")
+
+// status is a reply that is printed without quotes, like OK.
+type status string
+
+const statusOK = status("OK")
+
+func parseSeconds(s string) (time.Duration, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid number of seconds: %s", s)
+	}
+	return time.Duration(f * float64(time.Second)), nil
+}
+
+func parseInt(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("value is not an integer: %s", s)
+	}
+	return n, nil
+}
+
+// parsePutOptions parses the EX, PX, NX and XX options of put and cas.
+func parsePutOptions(args []string, allowCondition bool) ([]olric.PutOption, error) {
+	var options []olric.PutOption
+	for i := 0; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s requires a value", strings.ToUpper(args[i]))
+			}
+			n, err := parseInt(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(args[i], "EX") {
+				options = append(options, olric.EX(time.Duration(n)*time.Second))
+			} else {
+				options = append(options, olric.PX(time.Duration(n)*time.Millisecond))
+			}
+			i++
+		case "NX":
+			if !allowCondition {
+				return nil, fmt.Errorf("syntax error: %s", args[i])
+			}
+			options = append(options, olric.NX())
+		case "XX":
+			if !allowCondition {
+				return nil, fmt.Errorf("syntax error: %s", args[i])
+			}
+			options = append(options, olric.XX())
+		default:
+			return nil, fmt.Errorf("syntax error: %s", args[i])
+		}
+	}
+	return options, nil
+}
+
+func getValue(gr *olric.GetResponse) (interface{}, error) {
+	if gr == nil {
+		return nil, nil
+	}
+	value, err := gr.String()
+	if errors.Is(err, olric.ErrNilResponse) {
+		return nil, nil
+	}
+	return value, err
+}
+
+func runPut(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	options, err := parsePutOptions(args[3:], true)
+	if err != nil {
+		return nil, err
+	}
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if err = dm.Put(ctx, args[1], args[2], options...); err != nil {
+		return nil, err
+	}
+	return statusOK, nil
+}
+
+func runGet(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	gr, err := dm.Get(ctx, args[1])
+	if errors.Is(err, olric.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return getValue(gr)
+}
+
+func runDel(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return dm.Delete(ctx, args[1:]...)
+}
+
+func runExpire(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	timeout, err := parseSeconds(args[2])
+	if err != nil {
+		return nil, err
+	}
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if err = dm.Expire(ctx, args[1], timeout); err != nil {
+		return nil, err
+	}
+	return statusOK, nil
+}
+
+func runScan(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	var options []olric.ScanOption
+	for i := 1; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("%s requires a value", strings.ToUpper(args[i]))
+		}
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			options = append(options, olric.Match(args[i+1]))
+		case "COUNT":
+			count, err := parseInt(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			options = append(options, olric.Count(count))
+		default:
+			return nil, fmt.Errorf("syntax error: %s", args[i])
+		}
+	}
+
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	i, err := dm.Scan(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	defer i.Close()
+
+	keys := []string{}
+	for i.Next() {
+		keys = append(keys, i.Key())
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func runDestroy(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if err = dm.Destroy(ctx); err != nil {
+		return nil, err
+	}
+	return statusOK, nil
+}
+
+func parseDelta(args []string) (int, error) {
+	if len(args) < 3 {
+		return 1, nil
+	}
+	return parseInt(args[2])
+}
+
+func runIncr(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	delta, err := parseDelta(args)
+	if err != nil {
+		return nil, err
+	}
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return dm.Incr(ctx, args[1], delta)
+}
+
+func runDecr(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	delta, err := parseDelta(args)
+	if err != nil {
+		return nil, err
+	}
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return dm.Decr(ctx, args[1], delta)
+}
+
+func runIncrByFloat(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	delta, err := strconv.ParseFloat(args[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("value is not a valid float: %s", args[2])
+	}
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return dm.IncrByFloat(ctx, args[1], delta)
+}
+
+func runGetPut(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	gr, err := dm.GetPut(ctx, args[1], args[2])
+	if err != nil {
+		return nil, err
+	}
+	return getValue(gr)
+}
+
+// casResult is the result of cas.
+type casResult struct {
+	Swapped bool `json:"swapped"`
+	// Current is the current value if the value is not swapped. It's nil if
+	// the key doesn't exist.
+	Current interface{} `json:"current"`
+}
+
+func (r casResult) Text() string {
+	if r.Swapped {
+		return "(swapped)\n"
+	}
+	return "(not swapped) current: " + strings.TrimSuffix(formatText(r.Current), "\n") + "\n"
+}
+
+func runCAS(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	options, err := parsePutOptions(args[4:], false)
+	if err != nil {
+		return nil, err
+	}
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	swapped, current, err := dm.CompareAndSwap(ctx, args[1], []byte(args[2]), args[3], options...)
+	if err != nil {
+		return nil, err
+	}
+	result := casResult{Swapped: swapped}
+	if !swapped {
+		result.Current, err = getValue(current)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func runLock(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	deadline, err := parseSeconds(args[2])
+	if err != nil {
+		return nil, err
+	}
+	var timeout time.Duration
+	if len(args) > 3 {
+		if len(args) != 5 {
+			return nil, fmt.Errorf("syntax error: %s", args[3])
+		}
+		n, err := parseInt(args[4])
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToUpper(args[3]) {
+		case "EX":
+			timeout = time.Duration(n) * time.Second
+		case "PX":
+			timeout = time.Duration(n) * time.Millisecond
+		default:
+			return nil, fmt.Errorf("syntax error: %s", args[3])
+		}
+	}
+
+	key := lockKey{dmap: args[0], key: args[1]}
+	c.mtx.Lock()
+	_, ok := c.locks[key]
+	c.mtx.Unlock()
+	if ok {
+		return nil, fmt.Errorf("the lock is already held by this session: %s/%s", key.dmap, key.key)
+	}
+
+	dm, err := c.getDMap(args[0])
+	if err != nil {
+		return nil, err
+	}
+	var lock olric.LockContext
+	if timeout > 0 {
+		lock, err = dm.LockWithTimeout(ctx, args[1], timeout, deadline)
+	} else {
+		lock, err = dm.Lock(ctx, args[1], deadline)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.mtx.Lock()
+	c.locks[key] = lock
+	c.mtx.Unlock()
+	return statusOK, nil
+}
+
+func (c *CLI) getLock(dmap, key string) (olric.LockContext, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	lock, ok := c.locks[lockKey{dmap: dmap, key: key}]
+	if !ok {
+		return nil, fmt.Errorf("%w: no lock is held by this session: %s/%s", olric.ErrNoSuchLock, dmap, key)
+	}
+	return lock, nil
+}
+
+func runUnlock(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	lock, err := c.getLock(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	err = lock.Unlock(ctx)
+	if err == nil || errors.Is(err, olric.ErrNoSuchLock) {
+		// The lock is released, or it has already expired.
+		c.mtx.Lock()
+		delete(c.locks, lockKey{dmap: args[0], key: args[1]})
+		c.mtx.Unlock()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return statusOK, nil
+}
+
+func runLease(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	duration, err := parseSeconds(args[2])
+	if err != nil {
+		return nil, err
+	}
+	lock, err := c.getLock(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	if err = lock.Lease(ctx, duration); err != nil {
+		return nil, err
+	}
+	return statusOK, nil
+}
+
+func runLocks(_ context.Context, c *CLI, _ []string) (interface{}, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	locks := []string{}
+	for key := range c.locks {
+		locks = append(locks, key.dmap+"/"+key.key)
+	}
+	sort.Strings(locks)
+	return locks, nil
+}
+
+func runPublish(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	ps, err := c.getPubSub()
+	if err != nil {
+		return nil, err
+	}
+	return ps.Publish(ctx, args[0], args[1])
+}
+
+func runSPublish(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	ps, err := c.getPubSub()
+	if err != nil {
+		return nil, err
+	}
+	return ps.SPublish(ctx, args[0], args[1])
+}
+
+func runPubSub(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	ps, err := c.getPubSub()
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToUpper(args[0]) {
+	case "CHANNELS":
+		if len(args) > 2 {
+			return nil, fmt.Errorf("wrong number of arguments for 'pubsub channels'")
+		}
+		var pattern string
+		if len(args) == 2 {
+			pattern = args[1]
+		}
+		return ps.ClusterPubSubChannels(ctx, pattern)
+	case "NUMSUB":
+		return ps.ClusterPubSubNumSub(ctx, args[1:]...)
+	case "NUMPAT":
+		if len(args) > 1 {
+			return nil, fmt.Errorf("wrong number of arguments for 'pubsub numpat'")
+		}
+		return ps.ClusterPubSubNumPat(ctx)
+	default:
+		return nil, fmt.Errorf("unknown subcommand: %s", args[0])
+	}
+}
+
+// member is a cluster member in the output of members.
+type member struct {
+	Name        string `json:"name"`
+	ID          uint64 `json:"id"`
+	Birthdate   int64  `json:"birthdate"`
+	Coordinator bool   `json:"coordinator"`
+}
+
+type memberList []member
+
+func (l memberList) Text() string {
+	var sb strings.Builder
+	for i, m := range l {
+		sb.WriteString(fmt.Sprintf("%d) %s", i+1, m.Name))
+		if m.Coordinator {
+			sb.WriteString(" (coordinator)")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func runMembers(ctx context.Context, c *CLI, _ []string) (interface{}, error) {
+	members, err := c.client.Members(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := memberList{}
+	for _, m := range members {
+		result = append(result, member{
+			Name:        m.Name,
+			ID:          m.ID,
+			Birthdate:   m.Birthdate,
+			Coordinator: m.Coordinator,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// route is a partition in the output of routingtable.
+type route struct {
+	PartitionID   uint64   `json:"partition_id"`
+	PrimaryOwners []string `json:"primary_owners"`
+	ReplicaOwners []string `json:"replica_owners"`
+}
+
+type routingTable []route
+
+func (rt routingTable) Text() string {
+	var sb strings.Builder
+	for _, r := range rt {
+		sb.WriteString(fmt.Sprintf("%d) primary: %s", r.PartitionID, strings.Join(r.PrimaryOwners, ", ")))
+		if len(r.ReplicaOwners) > 0 {
+			sb.WriteString(fmt.Sprintf(" replicas: %s", strings.Join(r.ReplicaOwners, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func runRoutingTable(ctx context.Context, c *CLI, _ []string) (interface{}, error) {
+	rt, err := c.client.RoutingTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := routingTable{}
+	for partID, r := range rt {
+		rr := route{
+			PartitionID:   partID,
+			PrimaryOwners: r.PrimaryOwners,
+			ReplicaOwners: r.ReplicaOwners,
+		}
+		if rr.ReplicaOwners == nil {
+			rr.ReplicaOwners = []string{}
+		}
+		result = append(result, rr)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PartitionID < result[j].PartitionID
+	})
+	return result, nil
+}
+
+func runStats(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	address := c.config.Address
+	var options []olric.StatsOption
+	for _, arg := range args {
+		if strings.EqualFold(arg, "RUNTIME") {
+			options = append(options, olric.CollectRuntime())
+			continue
+		}
+		address = arg
+	}
+	return c.client.Stats(ctx, address, options...)
+}
+
+func runPing(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	address := c.config.Address
+	var message string
+	if len(args) > 0 {
+		address = args[0]
+	}
+	if len(args) > 1 {
+		message = args[1]
+	}
+	reply, err := c.client.Ping(ctx, address, message)
+	if err != nil {
+		return nil, err
+	}
+	if message == "" {
+		return status(reply), nil
+	}
+	return reply, nil
+}
+
+// usage is a command in the output of help.
+type usage struct {
+	Name    string `json:"name"`
+	Group   string `json:"group"`
+	Usage   string `json:"usage"`
+	Summary string `json:"summary"`
+}
+
+type usageList []usage
+
+func (l usageList) Text() string {
+	var sb strings.Builder
+	for _, group := range groups {
+		var header bool
+		for _, u := range l {
+			if u.Group != group {
+				continue
+			}
+			if !header && len(l) > 1 {
+				sb.WriteString(group + ":\n")
+				header = true
+			}
+			sb.WriteString(fmt.Sprintf("  %s\n      %s\n", u.Usage, u.Summary))
+		}
+	}
+	return sb.String()
+}
+
+func newUsage(cmd *command) usage {
+	return usage{
+		Name:    cmd.name,
+		Group:   cmd.group,
+		Usage:   cmd.usageLine(),
+		Summary: cmd.summary,
+	}
+}
+
+func runHelp(_ context.Context, _ *CLI, args []string) (interface{}, error) {
+	if len(args) == 1 {
+		cmd, ok := commands[strings.ToLower(args[0])]
+		if !ok {
+			return nil, fmt.Errorf("%w: '%s'", ErrUnknownCommand, args[0])
+		}
+		return usageList{newUsage(cmd)}, nil
+	}
+
+	var result usageList
+	for _, name := range Commands() {
+		cmd := commands[name]
+		if name != cmd.name {
+			// exit is an alias of quit.
+			continue
+		}
+		result = append(result, newUsage(cmd))
+	}
+	return result, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// texter is implemented by the results that have a special text format.
+type texter interface {
+	Text() string
+}
+
+// formatText formats a result like redis-cli does. The other results are
+// printed as indented JSON.
+func formatText(result interface{}) string {
+	switch v := result.(type) {
+	case nil:
+		return "(nil)\n"
+	case status:
+		return string(v) + "\n"
+	case string:
+		return strconv.Quote(v) + "\n"
+	case int:
+		return fmt.Sprintf("(integer) %d\n", v)
+	case int64:
+		return fmt.Sprintf("(integer) %d\n", v)
+	case float64:
+		return fmt.Sprintf("(double) %s\n", strconv.FormatFloat(v, 'f', -1, 64))
+	case []string:
+		if len(v) == 0 {
+			return "(empty array)\n"
+		}
+		var sb strings.Builder
+		for i, item := range v {
+			sb.WriteString(fmt.Sprintf("%d) %s\n", i+1, strconv.Quote(item)))
+		}
+		return sb.String()
+	case texter:
+		return v.Text()
+	default:
+		data, err := marshalJSON(v, "  ")
+		if err != nil {
+			return fmt.Sprintf("(error) %v\n", err)
+		}
+		return string(data)
+	}
+}
+
+// marshalJSON encodes v with a trailing newline. The values are printed as
+// they are, so the HTML characters are not escaped.
+func marshalJSON(v interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// splitArgs splits a command line into its arguments. An argument can be quoted
+// with double or single quotes to keep its spaces. The escape sequences of Go
+// are supported in double quotes, e.g. "\n" or "\x00".
+func splitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+	)
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		case ch == '"':
+			end := closingQuote(line, i, '"')
+			if end < 0 {
+				return nil, errors.New("unbalanced quotes")
+			}
+			s, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted argument: %s", line[i:end+1])
+			}
+			current.WriteString(s)
+			inArg = true
+			i = end
+		case ch == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unbalanced quotes")
+			}
+			current.WriteString(line[i+1 : i+1+end])
+			inArg = true
+			i += end + 1
+		default:
+			current.WriteByte(ch)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// closingQuote returns the index of the quote that closes the quote at start.
+// The escaped quotes are skipped. It returns -1 if the quote is not closed.
+func closingQuote(line string, start int, quote byte) int {
+	for i := start + 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
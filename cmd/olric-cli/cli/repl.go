@@ -0,0 +1,264 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// MaxHistorySize is the maximum number of the lines that are kept in the history file.
+const MaxHistorySize = 1000
+
+// ErrScriptFailed is returned by RunScript if any of the commands has failed.
+var ErrScriptFailed = errors.New("one or more commands have failed")
+
+// history implements term.History. The lines are appended to a file, so they
+// are available in the next sessions.
+type history struct {
+	path    string
+	entries []string
+}
+
+// loadHistory reads the history file. An empty path disables the history file.
+func loadHistory(path string) *history {
+	h := &history{path: path}
+	if path == "" {
+		return h
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return h
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if len(h.entries) > MaxHistorySize {
+		h.entries = h.entries[len(h.entries)-MaxHistorySize:]
+		h.rewrite()
+	}
+	return h
+}
+
+// rewrite replaces the history file with the current entries.
+func (h *history) rewrite() {
+	data := strings.Join(h.entries, "\n") + "\n"
+	_ = os.WriteFile(h.path, []byte(data), 0600)
+}
+
+// Add implements term.History.
+func (h *history) Add(entry string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if h.path == "" {
+		if len(h.entries) > MaxHistorySize {
+			h.entries = h.entries[1:]
+		}
+		return
+	}
+	if len(h.entries) > MaxHistorySize {
+		h.entries = h.entries[1:]
+		h.rewrite()
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	_, _ = f.WriteString(entry + "\n")
+	_ = f.Close()
+}
+
+// Len implements term.History.
+func (h *history) Len() int {
+	return len(h.entries)
+}
+
+// At implements term.History. The index 0 is the most recent entry.
+func (h *history) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
+
+// candidates returns the completions of the last word of the line.
+func (c *CLI) candidates(line string) (string, []string) {
+	fields := strings.Fields(line)
+	var word string
+	if len(fields) > 0 && !strings.HasSuffix(line, " ") {
+		word = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	var words []string
+	if len(fields) == 0 {
+		words = Commands()
+	} else {
+		cmd, ok := commands[strings.ToLower(fields[0])]
+		if !ok {
+			return word, nil
+		}
+		switch {
+		case cmd.name == "help" && len(fields) == 1:
+			words = Commands()
+		case cmd.dmap && len(fields) == 1:
+			c.mtx.Lock()
+			for name := range c.dmaps {
+				words = append(words, name)
+			}
+			c.mtx.Unlock()
+			sort.Strings(words)
+		case len(fields) > 1 || !cmd.dmap:
+			words = cmd.keywords
+		}
+	}
+
+	var result []string
+	for _, w := range words {
+		if strings.HasPrefix(strings.ToLower(w), strings.ToLower(word)) {
+			result = append(result, w)
+		}
+	}
+	return word, result
+}
+
+// commonPrefix returns the longest common prefix of the words.
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// complete is the AutoCompleteCallback of the terminal. It completes the word
+// before the cursor with the commands, their options and the names of the
+// DMaps that are used in this session. If there is more than one candidate,
+// it completes the common prefix and prints the candidates.
+func (c *CLI) complete(t *term.Terminal, line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	word, candidates := c.candidates(line[:pos])
+	if len(candidates) == 0 {
+		return "", 0, false
+	}
+
+	var completion string
+	if len(candidates) == 1 {
+		completion = candidates[0] + " "
+	} else {
+		completion = commonPrefix(candidates)
+		if len(completion) <= len(word) {
+			_, _ = fmt.Fprintln(t, strings.Join(candidates, "  "))
+		}
+	}
+	if len(completion) < len(word) {
+		return "", 0, false
+	}
+	newLine := line[:pos-len(word)] + completion + line[pos:]
+	return newLine, pos - len(word) + len(completion), true
+}
+
+// RunInteractive runs the interactive shell until quit, exit, Ctrl-C or Ctrl-D.
+// The lines are saved to historyPath if it's not empty.
+func (c *CLI) RunInteractive(prompt, historyPath string) error {
+	fd := int(os.Stdin.Fd())
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, prompt)
+	t.History = loadHistory(historyPath)
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		return c.complete(t, line, pos, key)
+	}
+
+	for {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		if width, height, err := term.GetSize(fd); err == nil && width > 0 {
+			_ = t.SetSize(width, height)
+		}
+		line, err := t.ReadLine()
+		_ = term.Restore(fd, state)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Ctrl-C cancels the running command, the subscriptions run until then.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err = c.Execute(ctx, line)
+		stop()
+		if errors.Is(err, errQuit) {
+			return nil
+		}
+	}
+}
+
+// RunScript runs the commands in r, one command per line, until ctx is
+// canceled. The empty lines and the lines that start with # are skipped. It
+// runs all the commands, and returns ErrScriptFailed if any of them has failed.
+func (c *CLI) RunScript(ctx context.Context, r io.Reader) error {
+	var failed bool
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		err := c.Execute(ctx, line)
+		if errors.Is(err, errQuit) {
+			break
+		}
+		if err != nil {
+			failed = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if failed {
+		return ErrScriptFailed
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/olric-data/olric"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrSubscriptionMoved is returned when the partition of a sharded channel is
+// moved to another member.
+var ErrSubscriptionMoved = errors.New("the partition of the sharded channel has moved, subscribe again")
+
+// event is a message or a subscription event in the output of the subscriptions.
+type event struct {
+	Kind    string `json:"kind"`
+	Pattern string `json:"pattern,omitempty"`
+	Channel string `json:"channel"`
+	Payload string `json:"payload,omitempty"`
+	// Count is the number of the subscriptions of the connection after a
+	// subscription event.
+	Count int `json:"count,omitempty"`
+}
+
+func (e event) Text() string {
+	switch e.Kind {
+	case "message", "smessage":
+		return fmt.Sprintf("%s %s: %s\n", e.Kind, e.Channel, strconv.Quote(e.Payload))
+	case "pmessage":
+		return fmt.Sprintf("%s %s %s: %s\n", e.Kind, e.Pattern, e.Channel, strconv.Quote(e.Payload))
+	default:
+		return fmt.Sprintf("%s %s (%d)\n", e.Kind, e.Channel, e.Count)
+	}
+}
+
+func toEvent(msg interface{}) (event, bool) {
+	switch m := msg.(type) {
+	case *redis.Subscription:
+		return event{Kind: m.Kind, Channel: m.Channel, Count: m.Count}, true
+	case *redis.Message:
+		kind := "message"
+		if m.Pattern != "" {
+			kind = "pmessage"
+		}
+		return event{Kind: kind, Pattern: m.Pattern, Channel: m.Channel, Payload: m.Payload}, true
+	default:
+		return event{}, false
+	}
+}
+
+// receive prints the events of the subscription until ctx is canceled.
+func (c *CLI) receive(ctx context.Context, sub *redis.PubSub, sharded bool) error {
+	defer func() {
+		_ = sub.Close()
+	}()
+
+	// Wait for the first reply to find out that the subscription works.
+	msg, err := sub.ReceiveTimeout(ctx, c.config.Timeout)
+	if err != nil {
+		return err
+	}
+	if !c.config.JSON {
+		_, _ = fmt.Fprintln(c.config.Output, "Reading messages... (press Ctrl-C to quit)")
+	}
+
+	ch := sub.ChannelWithSubscriptions()
+	for {
+		if e, ok := toEvent(msg); ok {
+			if sharded && e.Kind == "message" {
+				e.Kind = "smessage"
+			}
+			c.writeResult(e)
+			if e.Kind == "sunsubscribe" && e.Count == 0 {
+				return ErrSubscriptionMoved
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg = <-ch:
+			if msg == nil {
+				return nil
+			}
+		}
+	}
+}
+
+// receiveMessages prints the messages of the subscription until ctx is canceled.
+// Subscribe returns after the subscription is created, so the subscription
+// events are printed like Redis replies them.
+func (c *CLI) receiveMessages(ctx context.Context, sub *olric.Subscription, channels []string) error {
+	defer func() {
+		_ = sub.Close()
+	}()
+
+	if !c.config.JSON {
+		_, _ = fmt.Fprintln(c.config.Output, "Reading messages... (press Ctrl-C to quit)")
+	}
+	for i, channel := range channels {
+		c.writeResult(event{Kind: "subscribe", Channel: channel, Count: i + 1})
+	}
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			c.writeResult(event{Kind: "message", Channel: msg.Channel, Payload: msg.Payload})
+			sub.Ack(msg)
+		}
+	}
+}
+
+func runSubscribe(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	ps, err := c.getPubSub()
+	if err != nil {
+		return nil, err
+	}
+	subscribeCtx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	sub, err := ps.Subscribe(subscribeCtx, args)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	return nil, c.receiveMessages(ctx, sub, args)
+}
+
+func runPSubscribe(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	ps, err := c.getPubSub()
+	if err != nil {
+		return nil, err
+	}
+	return nil, c.receive(ctx, ps.PSubscribe(ctx, args...), false)
+}
+
+func runSSubscribe(ctx context.Context, c *CLI, args []string) (interface{}, error) {
+	ps, err := c.getPubSub()
+	if err != nil {
+		return nil, err
+	}
+	sub, err := ps.SSubscribe(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return nil, c.receive(ctx, sub, true)
+}
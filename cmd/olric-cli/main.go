@@ -0,0 +1,211 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// olric-cli is a command line interface for Olric clusters.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/olric-data/olric"
+	"github.com/olric-data/olric/cmd/olric-cli/cli"
+	"github.com/olric-data/olric/config"
+	"golang.org/x/term"
+)
+
+func usage() {
+	var msg = `Usage: olric-cli [options] [command [arguments ...]]
+
+Command line interface for Olric clusters
+
+Options:
+  -h, --help     Print this message and exit.
+  -v, --version  Print the version number and exit.
+  -a, --address  Comma separated addresses of the cluster members. Default is 127.0.0.1:3320.
+  -u, --user     The user to authenticate as.
+  -p, --password The password. Set OLRIC_CLI_PASSWORD to keep it out of the process list.
+  -t, --timeout  The timeout of a command. Default is 10s.
+  --history      The history file of the interactive shell. Default is ~/.olric_cli_history.
+                 Set it to an empty string to disable the history.
+
+olric-cli runs the command and prints the result in JSON format if a command is
+given. Otherwise, it runs an interactive shell if the standard input is a
+terminal, or it runs the commands in the standard input, one command per line,
+and prints the results in JSON format, one object per line.
+
+Type 'help' in the interactive shell to see the commands.
+
+The Go runtime version %s
+Report bugs to https://github.com/olric-data/olric/issues
+`
+	_, err := fmt.Fprintf(os.Stdout, msg, runtime.Version())
+	if err != nil {
+		panic(err)
+	}
+}
+
+type arguments struct {
+	address  string
+	user     string
+	password string
+	timeout  time.Duration
+	history  string
+	help     bool
+	version  bool
+}
+
+const (
+	// DefaultAddress is the default address of the cluster member.
+	DefaultAddress = "127.0.0.1:3320"
+
+	// DefaultHistoryFile is the name of the history file in the home directory.
+	DefaultHistoryFile = ".olric_cli_history"
+
+	// EnvPassword is the name of environment variable which can be used to set the password.
+	EnvPassword = "OLRIC_CLI_PASSWORD"
+)
+
+func defaultHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, DefaultHistoryFile)
+}
+
+func main() {
+	args := &arguments{}
+
+	// Parse command line parameters
+	f := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	f.SetOutput(io.Discard)
+	f.BoolVar(&args.help, "h", false, "")
+	f.BoolVar(&args.help, "help", false, "")
+
+	f.BoolVar(&args.version, "version", false, "")
+	f.BoolVar(&args.version, "v", false, "")
+
+	f.StringVar(&args.address, "address", DefaultAddress, "")
+	f.StringVar(&args.address, "a", DefaultAddress, "")
+
+	f.StringVar(&args.user, "user", "", "")
+	f.StringVar(&args.user, "u", "", "")
+
+	f.StringVar(&args.password, "password", os.Getenv(EnvPassword), "")
+	f.StringVar(&args.password, "p", os.Getenv(EnvPassword), "")
+
+	f.DurationVar(&args.timeout, "timeout", cli.DefaultTimeout, "")
+	f.DurationVar(&args.timeout, "t", cli.DefaultTimeout, "")
+
+	f.StringVar(&args.history, "history", defaultHistoryPath(), "")
+
+	if err := f.Parse(os.Args[1:]); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "parsing error: %v\n", err)
+		usage()
+		os.Exit(1)
+	}
+
+	if args.version {
+		_, _ = fmt.Fprintf(os.Stderr, "olric-cli version %s %s %s/%s\n",
+			olric.ReleaseVersion,
+			runtime.Version(),
+			runtime.GOOS,
+			runtime.GOARCH,
+		)
+		return
+	} else if args.help {
+		usage()
+		return
+	}
+
+	var addresses []string
+	for _, address := range strings.Split(args.address, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "at least one address is required")
+		os.Exit(1)
+	}
+
+	// The commands wait for the replies up to the timeout, e.g. lock waits for
+	// the lock.
+	cc := config.NewClient()
+	cc.ReadTimeout = args.timeout
+	cc.WriteTimeout = args.timeout
+	options := []olric.ClusterClientOption{olric.WithConfig(cc)}
+	switch {
+	case args.user != "":
+		options = append(options, olric.WithUser(args.user, args.password))
+	case args.password != "":
+		options = append(options, olric.WithPassword(args.password))
+	}
+
+	client, err := olric.NewClusterClient(addresses, options...)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to connect to the cluster: %v\n", err)
+		os.Exit(1)
+	}
+
+	interactive := f.NArg() == 0 && term.IsTerminal(int(os.Stdin.Fd()))
+	c := cli.New(client, &cli.Config{
+		Address: addresses[0],
+		Timeout: args.timeout,
+		JSON:    !interactive,
+		Output:  os.Stdout,
+	})
+
+	switch {
+	case f.NArg() > 0:
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err = c.Run(ctx, f.Args())
+		stop()
+	case interactive:
+		err = c.RunInteractive(addresses[0]+"> ", args.history)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	default:
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err = c.RunScript(ctx, os.Stdin)
+		stop()
+		if err != nil && !errors.Is(err, cli.ErrScriptFailed) {
+			_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), args.timeout)
+	defer cancel()
+	if err := c.Close(ctx); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+	if err := client.Close(ctx); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to close the client: %v\n", err)
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -41,6 +41,12 @@ Options:
   -c, --config  Sets configuration file path. Default is olric-server-local.yaml in the
                 current folder. Set OLRIC_SERVER_CONFIG to overwrite it.
 
+The keys of the configuration file can be overridden with environment variables,
+e.g. OLRIC_SERVER_REPLICACOUNT=2 or OLRIC_MEMBERLIST_BINDADDR=10.0.0.1.
+
+Send SIGHUP to reload the log level and the DMap settings from the configuration
+file. The other changes require a restart.
+
 The Go runtime version %s
 Report bugs to https://github.com/olric-data/olric/issues
 `
@@ -115,7 +121,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	s, err := server.New(c)
+	s, err := server.New(c, args.config)
 	if err != nil {
 		c.Logger.Fatalf("[ERROR] Failed to create a new Olric instance: %v", err)
 	}
@@ -31,25 +31,46 @@ import (
 // It encapsulates logging, configuration, the Olric database instance, and an error group for
 // concurrency management.
 type OlricServer struct {
-	log    *log.Logger
-	config *config.Config
-	db     *olric.Olric
-	errGr  errgroup.Group
+	log        *log.Logger
+	config     *config.Config
+	configPath string
+	db         *olric.Olric
+	errGr      errgroup.Group
 }
 
 // New initializes a new OlricServer instance using the provided configuration and returns it or an error.
-func New(c *config.Config) (*OlricServer, error) {
+// The configuration is reloaded from configPath on SIGHUP.
+func New(c *config.Config, configPath string) (*OlricServer, error) {
 	db, err := olric.New(c)
 	if err != nil {
 		return nil, err
 	}
 	return &OlricServer{
-		config: c,
-		log:    c.Logger,
-		db:     db,
+		config:     c,
+		configPath: configPath,
+		log:        c.Logger,
+		db:         db,
 	}, nil
 }
 
+// waitForReload reloads the configuration file on SIGHUP. The changes that require
+// a restart are rejected, and the running configuration is kept.
+func (s *OlricServer) waitForReload() {
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	for ch := range reloadChan {
+		s.log.Printf("[INFO] Signal caught: %s, reloading %s", ch.String(), s.configPath)
+		c, err := config.Load(s.configPath)
+		if err != nil {
+			s.log.Printf("[ERROR] Failed to load the configuration file: %s: %v", s.configPath, err)
+			continue
+		}
+		if err = s.db.Reload(c); err != nil {
+			s.log.Printf("[ERROR] Failed to reload the configuration: %v", err)
+		}
+	}
+}
+
 // waitForInterrupt waits for termination signals (SIGTERM, SIGINT) to gracefully shut down the Olric server instance.
 func (s *OlricServer) waitForInterrupt() {
 	shutDownChan := make(chan os.Signal, 1)
@@ -90,6 +111,8 @@ func (s *OlricServer) Start() error {
 	s.log.Printf("[INFO] pid: %d has been started", os.Getpid())
 	// Wait for SIGTERM or SIGINT
 	go s.waitForInterrupt()
+	// Wait for SIGHUP
+	go s.waitForReload()
 
 	s.errGr.Go(func() error {
 		return s.db.Start()
@@ -0,0 +1,92 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/olric-data/olric/internal/protocol"
+)
+
+// DefaultAuditLogCommands are the commands recorded by the audit log by
+// default: the ones that destroy data, change locks, authenticate or rotate
+// the secrets of the cluster.
+var DefaultAuditLogCommands = []string{
+	protocol.Generic.Auth,
+	protocol.DMap.Destroy,
+	protocol.DMap.Lock,
+	protocol.DMap.Unlock,
+	protocol.DMap.LockLease,
+	protocol.DMap.PLockLease,
+	protocol.Generic.SlowLogReset,
+	protocol.Cluster.KeyringInstall,
+	protocol.Cluster.KeyringUse,
+	protocol.Cluster.KeyringRemove,
+	protocol.Cluster.PasswordAdd,
+	protocol.Cluster.PasswordPromote,
+	protocol.Cluster.PasswordRetire,
+}
+
+// AuditLog denotes configuration for the audit log. Olric records the command
+// name, the target DMap, the client address, the authenticated user, the
+// outcome and the timestamp of the audited commands as JSON lines. The values
+// of the arguments are never recorded. The audit log is enabled if Path or
+// Output is set.
+type AuditLog struct {
+	// Path is the file that the records are appended to. It's created if it
+	// doesn't exist. "stdout" and "stderr" are also accepted.
+	Path string
+
+	// Output is the writer that the records are sent to. Every record is
+	// written with a single Write call. If it's set, Path is ignored.
+	Output io.Writer
+
+	// Commands are the names of the audited commands, e.g. dm.destroy. The
+	// subcommands are written with a space, e.g. "slowlog reset". The default
+	// value is DefaultAuditLogCommands.
+	Commands []string
+}
+
+// Enabled returns true if Path or Output is set.
+func (a *AuditLog) Enabled() bool {
+	return a.Path != "" || a.Output != nil
+}
+
+// Sanitize sets default values to empty configuration variables, if it's possible.
+func (a *AuditLog) Sanitize() error {
+	a.Path = strings.TrimSpace(a.Path)
+
+	if len(a.Commands) == 0 {
+		a.Commands = append([]string(nil), DefaultAuditLogCommands...)
+	}
+	for i, command := range a.Commands {
+		a.Commands[i] = strings.ToLower(strings.TrimSpace(command))
+	}
+	return nil
+}
+
+// Validate finds errors in the current configuration.
+func (a *AuditLog) Validate() error {
+	for _, command := range a.Commands {
+		if command == "" {
+			return errors.New("command name cannot be empty")
+		}
+	}
+	return nil
+}
+
+var _ IConfig = (*AuditLog)(nil)
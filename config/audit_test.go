@@ -0,0 +1,49 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_AuditLog(t *testing.T) {
+	a := &AuditLog{}
+	require.NoError(t, a.Sanitize())
+	require.NoError(t, a.Validate())
+
+	require.False(t, a.Enabled())
+	require.Equal(t, DefaultAuditLogCommands, a.Commands)
+}
+
+func TestConfig_AuditLog_Commands(t *testing.T) {
+	a := &AuditLog{
+		Path:     " /var/log/olric/audit.log ",
+		Commands: []string{"DM.DESTROY", " SlowLog Reset"},
+	}
+	require.NoError(t, a.Sanitize())
+	require.NoError(t, a.Validate())
+
+	require.True(t, a.Enabled())
+	require.Equal(t, "/var/log/olric/audit.log", a.Path)
+	require.Equal(t, []string{"dm.destroy", "slowlog reset"}, a.Commands)
+}
+
+func TestConfig_AuditLog_Invalid(t *testing.T) {
+	a := &AuditLog{Commands: []string{"dm.destroy", " "}}
+	require.NoError(t, a.Sanitize())
+	require.Error(t, a.Validate())
+}
@@ -14,21 +14,103 @@
 
 package config
 
-import "strings"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// DefaultUser is the name of the user that authenticates with Password. It has
+// all the permissions, and the cluster members use it to talk to each other.
+const DefaultUser = "default"
 
 type Authentication struct {
+	// Password is the password of the default user.
 	Password string
+
+	// SecondaryPassword is also accepted for the default user during a
+	// credential rotation. The clients, including the cluster members, keep
+	// using Password until the secondary password is promoted. It's only used
+	// by the servers.
+	SecondaryPassword string
+
+	// Username is the name of the user that a client authenticates as. It's
+	// only used by the clients. The default user is used if it's empty.
+	Username string
+
+	// Users are the named users and their permissions. The default user must
+	// be enabled with Password to define them.
+	Users []User
+}
+
+// User is a named user with hashed passwords and ACL rules.
+type User struct {
+	// Name is the name of the user. It cannot be "default".
+	Name string
+
+	// Passwords are the hex encoded SHA-256 hashes of the passwords. A user
+	// can authenticate with any of them.
+	Passwords []string
+
+	// Rules are the ACL rules of the user, e.g. "+@read", "-dm.destroy",
+	// "%R~sessions:*" or "&events.*". A user has no permissions by default.
+	Rules []string
+}
+
+// HashPassword returns the hex encoded SHA-256 hash of the password.
+func HashPassword(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
 }
 
 // Sanitize ensures the Authentication configuration is pre-processed and prepared for use, with no changes currently applied.
 func (a *Authentication) Sanitize() error {
 	a.Password = strings.TrimSpace(a.Password)
+	a.SecondaryPassword = strings.TrimSpace(a.SecondaryPassword)
+	a.Username = strings.TrimSpace(a.Username)
+	for i := range a.Users {
+		a.Users[i].Name = strings.TrimSpace(a.Users[i].Name)
+		for j := range a.Users[i].Passwords {
+			a.Users[i].Passwords[j] = strings.ToLower(strings.TrimSpace(a.Users[i].Passwords[j]))
+		}
+	}
 	return nil
 }
 
 // Validate checks the current Authentication configuration for validity and returns an error if issues are found.
+// The syntax of the ACL rules is checked when the server starts.
 func (a *Authentication) Validate() error {
-	// Nothing to do
+	if a.SecondaryPassword != "" && !a.Enabled() {
+		return fmt.Errorf("secondary password requires the password of the %s user", DefaultUser)
+	}
+	if len(a.Users) > 0 && !a.Enabled() {
+		return fmt.Errorf("users require the password of the %s user", DefaultUser)
+	}
+
+	names := make(map[string]struct{})
+	for _, user := range a.Users {
+		if user.Name == "" {
+			return fmt.Errorf("user name cannot be empty")
+		}
+		if user.Name == DefaultUser {
+			return fmt.Errorf("user name cannot be %s", DefaultUser)
+		}
+		if _, ok := names[user.Name]; ok {
+			return fmt.Errorf("duplicate user: %s", user.Name)
+		}
+		names[user.Name] = struct{}{}
+
+		if len(user.Passwords) == 0 {
+			return fmt.Errorf("user %s has no passwords", user.Name)
+		}
+		for _, hash := range user.Passwords {
+			decoded, err := hex.DecodeString(hash)
+			if err != nil || len(decoded) != sha256.Size {
+				return fmt.Errorf("user %s has an invalid password hash, it must be a hex encoded SHA-256 hash", user.Name)
+			}
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,78 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Authentication_Users(t *testing.T) {
+	c := &Authentication{
+		Password: "secret",
+		Users: []User{{
+			Name:      " alice ",
+			Passwords: []string{" " + HashPassword("alice-password") + " "},
+			Rules:     []string{"+@read"},
+		}},
+	}
+	require.NoError(t, c.Sanitize())
+	require.NoError(t, c.Validate())
+	require.True(t, c.Enabled())
+	require.Equal(t, "alice", c.Users[0].Name)
+	require.Equal(t, HashPassword("alice-password"), c.Users[0].Passwords[0])
+}
+
+func TestConfig_Authentication_Users_Invalid(t *testing.T) {
+	hash := HashPassword("alice-password")
+	tests := map[string]*Authentication{
+		"Secondary password without password": {
+			SecondaryPassword: "new-secret",
+		},
+		"Without default password": {
+			Users: []User{{Name: "alice", Passwords: []string{hash}}},
+		},
+		"Empty name": {
+			Password: "secret",
+			Users:    []User{{Passwords: []string{hash}}},
+		},
+		"Default user": {
+			Password: "secret",
+			Users:    []User{{Name: DefaultUser, Passwords: []string{hash}}},
+		},
+		"Duplicate user": {
+			Password: "secret",
+			Users: []User{
+				{Name: "alice", Passwords: []string{hash}},
+				{Name: "alice", Passwords: []string{hash}},
+			},
+		},
+		"Without password": {
+			Password: "secret",
+			Users:    []User{{Name: "alice"}},
+		},
+		"Plain text password": {
+			Password: "secret",
+			Users:    []User{{Name: "alice", Passwords: []string{"alice-password"}}},
+		},
+	}
+	for name, c := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, c.Sanitize())
+			require.Error(t, c.Validate())
+		})
+	}
+}
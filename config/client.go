@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -104,6 +105,11 @@ type Client struct {
 
 	// Limiter interface used to implemented circuit breaker or rate limiter.
 	Limiter redis.Limiter
+
+	// TracerProvider is used to create OpenTelemetry spans for the commands.
+	// The trace context is propagated to the cluster members. Tracing is
+	// disabled if it's nil.
+	TracerProvider trace.TracerProvider
 }
 
 // NewClient returns a new configuration object for clients.
@@ -213,6 +219,7 @@ func (c *Client) RedisOptions() *redis.Options {
 		Limiter:         c.Limiter,
 	}
 	if c.Authentication.Enabled() {
+		options.Username = c.Authentication.Username
 		options.Password = c.Authentication.Password
 	}
 	return options
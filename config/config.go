@@ -25,6 +25,7 @@ import (
 
 	"github.com/hashicorp/memberlist"
 	"github.com/olric-data/olric/hasher"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // IConfig is an interface that has to be implemented by Config and its nested
@@ -163,6 +164,27 @@ const (
 	DefaultKeepAlivePeriod = 300 * time.Second
 )
 
+// DefaultCommandLatencyBuckets is the default value of the upper bounds of
+// the command latency histograms.
+var DefaultCommandLatencyBuckets = []time.Duration{
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
 // Config represents the configuration structure for customizing the behavior and properties of Olric.
 type Config struct {
 	// Authentication defines authentication settings, including password protection, for securing access.
@@ -257,6 +279,12 @@ type Config struct {
 	// cluster.events channel. Default is false.
 	EnableClusterEventsChannel bool
 
+	// CommandLatencyBuckets are the upper bounds of the latency histograms of
+	// the commands, in increasing order. Olric records a histogram for every
+	// command, including the internal ones. The default value is
+	// DefaultCommandLatencyBuckets.
+	CommandLatencyBuckets []time.Duration
+
 	// Default hasher is github.com/cespare/xxhash/v2
 	Hasher hasher.Hasher
 
@@ -269,11 +297,35 @@ type Config struct {
 	// it as-is and will not inspect or modify LogOutput.
 	Logger *log.Logger
 
+	// TracerProvider is used to create OpenTelemetry spans for the commands
+	// served by this member and the requests sent to the other members. The
+	// trace context is propagated between the members. Tracing is disabled if
+	// it's nil.
+	TracerProvider trace.TracerProvider
+
 	// DMaps denotes a global configuration for DMaps. You can still overwrite it
 	// by setting a DMap for a particular distributed map via DMaps.Custom field.
 	// Most of the fields are related with distributed cache implementation.
 	DMaps *DMaps
 
+	// PubSub denotes configuration for the Publish-Subscribe service.
+	PubSub *PubSub
+
+	// Metrics denotes configuration for the Prometheus metrics endpoint.
+	Metrics *Metrics
+
+	// SlowLog denotes configuration for the slow log.
+	SlowLog *SlowLog
+
+	// RedisCompat denotes configuration for the Redis compatibility layer.
+	RedisCompat *RedisCompat
+
+	// TLS denotes configuration for the client-facing RESP listener.
+	TLS *TLS
+
+	// AuditLog denotes configuration for the audit log.
+	AuditLog *AuditLog
+
 	// JoinRetryInterval is the time gap between attempts to join an existing
 	// cluster.
 	JoinRetryInterval time.Duration
@@ -367,6 +419,15 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	for i, bucket := range c.CommandLatencyBuckets {
+		if bucket <= 0 {
+			return fmt.Errorf("invalid CommandLatencyBuckets: %s", bucket)
+		}
+		if i > 0 && bucket <= c.CommandLatencyBuckets[i-1] {
+			return fmt.Errorf("CommandLatencyBuckets must be in increasing order")
+		}
+	}
+
 	if err := c.Client.Validate(); err != nil {
 		return fmt.Errorf("failed to validate client configuration: %w", err)
 	}
@@ -375,6 +436,30 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("failed to validate DMap configuration: %w", err)
 	}
 
+	if err := c.PubSub.Validate(); err != nil {
+		return fmt.Errorf("failed to validate PubSub configuration: %w", err)
+	}
+
+	if err := c.Metrics.Validate(); err != nil {
+		return fmt.Errorf("failed to validate metrics configuration: %w", err)
+	}
+
+	if err := c.SlowLog.Validate(); err != nil {
+		return fmt.Errorf("failed to validate slow log configuration: %w", err)
+	}
+
+	if err := c.RedisCompat.Validate(); err != nil {
+		return fmt.Errorf("failed to validate Redis compatibility configuration: %w", err)
+	}
+
+	if err := c.TLS.Validate(); err != nil {
+		return fmt.Errorf("failed to validate TLS configuration: %w", err)
+	}
+
+	if err := c.AuditLog.Validate(); err != nil {
+		return fmt.Errorf("failed to validate audit log configuration: %w", err)
+	}
+
 	if err := c.Authentication.Validate(); err != nil {
 		return fmt.Errorf("failed to sanitize authentication configuration: %w", err)
 	}
@@ -478,6 +563,10 @@ func (c *Config) Sanitize() error {
 		c.KeepAlivePeriod = DefaultKeepAlivePeriod
 	}
 
+	if len(c.CommandLatencyBuckets) == 0 {
+		c.CommandLatencyBuckets = DefaultCommandLatencyBuckets
+	}
+
 	if c.Client == nil {
 		c.Client = NewClient()
 	}
@@ -486,6 +575,30 @@ func (c *Config) Sanitize() error {
 		c.DMaps = &DMaps{}
 	}
 
+	if c.PubSub == nil {
+		c.PubSub = &PubSub{}
+	}
+
+	if c.Metrics == nil {
+		c.Metrics = &Metrics{}
+	}
+
+	if c.SlowLog == nil {
+		c.SlowLog = &SlowLog{}
+	}
+
+	if c.RedisCompat == nil {
+		c.RedisCompat = &RedisCompat{}
+	}
+
+	if c.TLS == nil {
+		c.TLS = &TLS{}
+	}
+
+	if c.AuditLog == nil {
+		c.AuditLog = &AuditLog{}
+	}
+
 	if c.Authentication == nil {
 		c.Authentication = &Authentication{}
 	}
@@ -502,6 +615,30 @@ func (c *Config) Sanitize() error {
 		return fmt.Errorf("failed to sanitize DMap configuration: %w", err)
 	}
 
+	if err := c.PubSub.Sanitize(); err != nil {
+		return fmt.Errorf("failed to sanitize PubSub configuration: %w", err)
+	}
+
+	if err := c.Metrics.Sanitize(); err != nil {
+		return fmt.Errorf("failed to sanitize metrics configuration: %w", err)
+	}
+
+	if err := c.SlowLog.Sanitize(); err != nil {
+		return fmt.Errorf("failed to sanitize slow log configuration: %w", err)
+	}
+
+	if err := c.RedisCompat.Sanitize(); err != nil {
+		return fmt.Errorf("failed to sanitize Redis compatibility configuration: %w", err)
+	}
+
+	if err := c.TLS.Sanitize(); err != nil {
+		return fmt.Errorf("failed to sanitize TLS configuration: %w", err)
+	}
+
+	if err := c.AuditLog.Sanitize(); err != nil {
+		return fmt.Errorf("failed to sanitize audit log configuration: %w", err)
+	}
+
 	return nil
 }
 
@@ -541,6 +678,12 @@ func New(env string) *Config {
 		MemberCountQuorum: 1,
 		Peers:             []string{},
 		DMaps:             &DMaps{},
+		PubSub:            &PubSub{},
+		Metrics:           &Metrics{},
+		SlowLog:           &SlowLog{},
+		RedisCompat:       &RedisCompat{},
+		TLS:               &TLS{},
+		AuditLog:          &AuditLog{},
 		Authentication:    &Authentication{},
 	}
 
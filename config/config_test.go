@@ -39,10 +39,16 @@ var testConfig = `server:
   replicationMode: 0 # sync mode. for async, set 1
   memberCountQuorum: 1
   enableClusterEventsChannel: true
+  commandLatencyBuckets: ["1ms", "10ms", "100ms"]
 
 
 authentication:
   password: "secret"
+  secondaryPassword: "new-secret"
+  users:
+    - name: "alice"
+      passwords: ["2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"]
+      rules: ["+@read", "%R~sessions:*"]
 
 client:
   dialTimeout: 8s
@@ -105,6 +111,43 @@ dmaps:
       lruSamples: 60
       evictionPolicy: "NONE"
 
+pubsub:
+  subscriberQueueSize: 512
+  overflowPolicy: "drop-newest"
+  retention:
+    orders:
+      maxMessages: 1000
+      maxAge: "10m"
+
+metrics:
+  enabled: true
+  bindAddr: "127.0.0.1"
+  bindPort: 9320
+  path: "/olric/metrics"
+
+slowLog:
+  threshold: "50ms"
+  maxLen: 256
+
+redisCompat:
+  enabled: true
+  defaultDMap: "mydmap"
+  keyPrefixSeparator: ":"
+
+tls:
+  certFile: "/etc/olric/server.crt"
+  keyFile: "/etc/olric/server.key"
+  clientCAFile: "/etc/olric/ca.crt"
+  clusterRole: "olric-cluster"
+  minVersion: "1.3"
+  reloadInterval: "1m"
+
+auditLog:
+  path: "/var/log/olric/audit.log"
+  commands:
+    - "dm.destroy"
+    - "dm.lock"
+
 serviceDiscovery:
   path: "/usr/lib/olric-consul-plugin.so"
   provider: "consul"
@@ -155,6 +198,7 @@ func TestConfig(t *testing.T) {
 	c.ReplicationMode = SyncReplicationMode
 	c.MemberCountQuorum = 1
 	c.EnableClusterEventsChannel = true
+	c.CommandLatencyBuckets = []time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond}
 
 	c.DMaps.Engine = NewEngine()
 
@@ -209,6 +253,45 @@ func TestConfig(t *testing.T) {
 		EvictionPolicy:  "NONE",
 	}}
 
+	c.PubSub.SubscriberQueueSize = 512
+	c.PubSub.OverflowPolicy = DropNewest
+	c.PubSub.Retention = map[string]ChannelRetention{"orders": {
+		MaxMessages: 1000,
+		MaxAge:      10 * time.Minute,
+	}}
+
+	c.Metrics = &Metrics{
+		Enabled:  true,
+		BindAddr: "127.0.0.1",
+		BindPort: 9320,
+		Path:     "/olric/metrics",
+	}
+
+	c.SlowLog = &SlowLog{
+		Threshold: 50 * time.Millisecond,
+		MaxLen:    256,
+	}
+
+	c.RedisCompat = &RedisCompat{
+		Enabled:            true,
+		DefaultDMap:        "mydmap",
+		KeyPrefixSeparator: ":",
+	}
+
+	c.TLS = &TLS{
+		CertFile:       "/etc/olric/server.crt",
+		KeyFile:        "/etc/olric/server.key",
+		ClientCAFile:   "/etc/olric/ca.crt",
+		ClusterRole:    "olric-cluster",
+		MinVersion:     "1.3",
+		ReloadInterval: time.Minute,
+	}
+
+	c.AuditLog = &AuditLog{
+		Path:     "/var/log/olric/audit.log",
+		Commands: []string{"dm.destroy", "dm.lock"},
+	}
+
 	c.ServiceDiscovery = make(map[string]interface{})
 	c.ServiceDiscovery["path"] = "/usr/lib/olric-consul-plugin.so"
 	c.ServiceDiscovery["provider"] = "consul"
@@ -219,9 +302,17 @@ func TestConfig(t *testing.T) {
 	c.ServiceDiscovery["payload"] = "SAMPLE-PAYLOAD"
 
 	c.Authentication = &Authentication{
+		Password:          "secret",
+		SecondaryPassword: "new-secret",
+		Users: []User{{
+			Name:      "alice",
+			Passwords: []string{HashPassword("secret")},
+			Rules:     []string{"+@read", "%R~sessions:*"},
+		}},
+	}
+	c.Client.Authentication = &Authentication{
 		Password: "secret",
 	}
-	c.Client.Authentication = c.Authentication
 
 	err = c.Sanitize()
 	require.NoError(t, err)
@@ -242,3 +333,17 @@ func TestConfig_Initialize(t *testing.T) {
 	require.NoError(t, c.Sanitize())
 	require.NoError(t, c.Validate())
 }
+
+func TestConfig_Invalid_CommandLatencyBuckets(t *testing.T) {
+	buckets := [][]time.Duration{
+		{0},
+		{time.Millisecond, -time.Second},
+		{10 * time.Millisecond, time.Millisecond},
+		{time.Millisecond, time.Millisecond},
+	}
+	for _, b := range buckets {
+		c := New("local")
+		c.CommandLatencyBuckets = b
+		require.Error(t, c.Validate())
+	}
+}
@@ -74,7 +74,11 @@ type DMaps struct {
 	// different values per DMap.
 	TriggerCompactionInterval time.Duration
 
-	// Custom is useful to set custom cache config per DMap instance.
+	// Custom is useful to set custom cache config per DMap instance. A key is
+	// the name of a DMap, a prefix that ends with *, like "sessions:*", or a
+	// glob pattern with * and ?, like "cache:v?:users". An exact name has the
+	// highest precedence, then the longest prefix, then the longest glob
+	// pattern. The DMaps that match none of them use the global configuration.
 	Custom map[string]DMap
 }
 
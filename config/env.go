@@ -0,0 +1,199 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// EnvPrefix is the prefix of the environment variables that override the keys
+// of the configuration file.
+//
+// The name of a variable is the prefix followed by the path of the key, with
+// underscores between the parts, e.g. OLRIC_SERVER_REPLICACOUNT for
+// server.replicaCount and OLRIC_MEMBERLIST_BINDADDR for memberlist.bindAddr.
+// The keys are case-insensitive. The names of the items of dmaps.custom and
+// pubsub.retention, and the keys of dmaps.engine.config and serviceDiscovery,
+// are written as they are, e.g. OLRIC_DMAPS_CUSTOM_sessions_MAXKEYS for
+// dmaps.custom.sessions.maxKeys. If an item with the same name, ignoring the
+// case, exists in the configuration file, it's overridden.
+//
+// The names of the variables are limited to letters, digits and underscores by
+// the shells, so the items whose names contain other characters, like the
+// patterns "sessions:*" and "events.*", cannot be overridden. Such a variable
+// adds a new item instead, e.g. OLRIC_DMAPS_CUSTOM_sessions_MAXKEYS adds the
+// DMap "sessions". Set these items in the configuration file.
+//
+// The lists are comma separated, or written in YAML flow style, e.g.
+// OLRIC_MEMBERLIST_PEERS=10.0.0.1:3322,10.0.0.2:3322. The other values are
+// parsed as YAML. The empty variables and the unknown keys are ignored.
+const EnvPrefix = "OLRIC_"
+
+// applyEnv overrides the values parsed from the configuration file with the
+// environment variables. environ is in the form of os.Environ.
+func applyEnv(dst interface{}, environ []string) error {
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || value == "" || !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		if _, err := setEnv(reflect.ValueOf(dst).Elem(), name[len(EnvPrefix):], value); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// yamlKey returns the key of a struct field in the configuration file.
+func yamlKey(field reflect.StructField) string {
+	tag := field.Tag.Get("yaml")
+	if tag == "" || tag == "-" {
+		return ""
+	}
+	return strings.Split(tag, ",")[0]
+}
+
+// setEnv sets the value of the key at path in v. It returns false if there
+// is no such key.
+func setEnv(v reflect.Value, path, value string) (bool, error) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			break
+		}
+		elem := reflect.New(v.Type().Elem())
+		if !v.IsNil() {
+			elem.Elem().Set(v.Elem())
+		}
+		ok, err := setEnv(elem.Elem(), path, value)
+		if ok && err == nil {
+			v.Set(elem)
+		}
+		return ok, err
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			key := strings.ToUpper(yamlKey(v.Type().Field(i)))
+			if key == "" {
+				continue
+			}
+			upper := strings.ToUpper(path)
+			if upper == key {
+				return true, setValue(v.Field(i), value)
+			}
+			if strings.HasPrefix(upper, key+"_") {
+				if ok, err := setEnv(v.Field(i), path[len(key)+1:], value); ok || err != nil {
+					return ok, err
+				}
+			}
+		}
+		return false, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return false, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Interface {
+			// The rest of the path is the key, e.g. a parameter of the storage engine.
+			var item interface{}
+			if err := yaml.Unmarshal([]byte(value), &item); err != nil {
+				return true, err
+			}
+			if v.IsNil() {
+				v.Set(reflect.MakeMap(v.Type()))
+			}
+			v.SetMapIndex(mapKey(v, path), reflect.ValueOf(&item).Elem())
+			return true, nil
+		}
+		return setMapItem(v, path, value)
+	}
+	return false, nil
+}
+
+// setMapItem sets a key of a struct in a map. The path begins with the name of
+// the item. The names may contain underscores, so every possible split is tried.
+func setMapItem(v reflect.Value, path, value string) (bool, error) {
+	for i := strings.Index(path, "_"); i > 0; i = nextIndex(path, i) {
+		key := mapKey(v, path[:i])
+		item := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			item.Set(existing)
+		}
+		ok, err := setEnv(item, path[i+1:], value)
+		if err != nil {
+			return true, err
+		}
+		if ok {
+			if v.IsNil() {
+				v.Set(reflect.MakeMap(v.Type()))
+			}
+			v.SetMapIndex(key, item)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func nextIndex(path string, i int) int {
+	j := strings.Index(path[i+1:], "_")
+	if j < 0 {
+		return -1
+	}
+	return i + 1 + j
+}
+
+// mapKey returns the existing key that matches name, ignoring the case. It
+// returns name if there is no such key.
+func mapKey(v reflect.Value, name string) reflect.Value {
+	for _, key := range v.MapKeys() {
+		if strings.EqualFold(key.String(), name) {
+			return key
+		}
+	}
+	return reflect.ValueOf(name).Convert(v.Type().Key())
+}
+
+// setValue parses the value of an environment variable and sets it.
+func setValue(v reflect.Value, value string) error {
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(value)
+		return nil
+	case v.Kind() == reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String && !strings.HasPrefix(value, "["):
+		items := strings.Split(value, ",")
+		slice := reflect.MakeSlice(v.Type(), 0, len(items))
+		for _, item := range items {
+			slice = reflect.Append(slice, reflect.ValueOf(strings.TrimSpace(item)).Convert(v.Type().Elem()))
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	item := reflect.New(v.Type())
+	if err := yaml.Unmarshal([]byte(value), item.Interface()); err != nil {
+		return err
+	}
+	v.Set(item.Elem())
+	return nil
+}
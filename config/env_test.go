@@ -0,0 +1,138 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/config/internal/loader"
+	"github.com/stretchr/testify/require"
+)
+
+const testEnvConfig = `server:
+  bindAddr: "127.0.0.1"
+  bindPort: 3320
+  replicaCount: 1
+
+memberlist:
+  environment: "local"
+  bindAddr: "127.0.0.1"
+  bindPort: 3322
+
+dmaps:
+  engine:
+    name: "kvstore"
+    config:
+      tableSize: 524288
+  custom:
+    Sessions:
+      maxKeys: 100
+      ttlDuration: "10s"
+`
+
+func loadEnvConfig(t *testing.T) (*Config, error) {
+	f := createTmpFile(t, "olric-yaml-config-test")
+	_, err := f.WriteString(testEnvConfig)
+	require.NoError(t, err)
+	return Load(f.Name())
+}
+
+func TestConfig_Env(t *testing.T) {
+	t.Setenv("OLRIC_SERVER_REPLICACOUNT", "2")
+	t.Setenv("OLRIC_SERVER_READREPAIR", "true")
+	t.Setenv("OLRIC_SERVER_KEEPALIVEPERIOD", "5s")
+	t.Setenv("OLRIC_MEMBERLIST_BINDADDR", "10.0.0.1")
+	t.Setenv("OLRIC_MEMBERLIST_ADVERTISEPORT", "4000")
+	t.Setenv("OLRIC_MEMBERLIST_PEERS", "10.0.0.2:3322, 10.0.0.3:3322")
+	t.Setenv("OLRIC_LOGGING_LEVEL", "DEBUG")
+	t.Setenv("OLRIC_DMAPS_ENGINE_CONFIG_tableSize", "1024")
+	t.Setenv("OLRIC_DMAPS_CUSTOM_SESSIONS_MAXKEYS", "500")
+	t.Setenv("OLRIC_DMAPS_CUSTOM_user_profiles_EVICTIONPOLICY", "LRU")
+	t.Setenv("OLRIC_DMAPS_CUSTOM_user_profiles_MAXINUSE", "1048576")
+	t.Setenv("OLRIC_AUTHENTICATION_PASSWORD", "secret")
+	t.Setenv("OLRIC_AUTHENTICATION_USERS", `[{name: alice, passwords: [1ec1c26b50d5d3c58d9583181af8076655fe00756bf7285940ba3670f99fcba0], rules: ["+@read"]}]`)
+	// Unknown keys and empty values are ignored.
+	t.Setenv("OLRIC_SERVER_CONFIG", "/etc/olric/olric.yaml")
+	t.Setenv("OLRIC_SERVER_BINDPORT", "")
+
+	c, err := loadEnvConfig(t)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, c.ReplicaCount)
+	require.True(t, c.ReadRepair)
+	require.Equal(t, 5*time.Second, c.KeepAlivePeriod)
+	require.Equal(t, 3320, c.BindPort)
+	require.Equal(t, "10.0.0.1", c.MemberlistConfig.BindAddr)
+	require.Equal(t, 4000, c.MemberlistConfig.AdvertisePort)
+	require.Equal(t, []string{"10.0.0.2:3322", "10.0.0.3:3322"}, c.Peers)
+	require.Equal(t, "DEBUG", c.LogLevel)
+	require.Equal(t, 1024, c.DMaps.Engine.Config["tableSize"])
+
+	// The existing item is overridden, the names are case-insensitive.
+	require.Len(t, c.DMaps.Custom, 2)
+	require.Equal(t, 500, c.DMaps.Custom["Sessions"].MaxKeys)
+	require.Equal(t, 10*time.Second, c.DMaps.Custom["Sessions"].TTLDuration)
+	require.Equal(t, LRUEviction, c.DMaps.Custom["user_profiles"].EvictionPolicy)
+	require.Equal(t, 1048576, c.DMaps.Custom["user_profiles"].MaxInuse)
+
+	require.Equal(t, "secret", c.Authentication.Password)
+	require.Len(t, c.Authentication.Users, 1)
+	require.Equal(t, "alice", c.Authentication.Users[0].Name)
+	require.Equal(t, []string{"+@read"}, c.Authentication.Users[0].Rules)
+}
+
+func TestConfig_Env_Invalid(t *testing.T) {
+	t.Setenv("OLRIC_SERVER_REPLICACOUNT", "two")
+
+	_, err := loadEnvConfig(t)
+	require.ErrorContains(t, err, "OLRIC_SERVER_REPLICACOUNT")
+}
+
+func TestConfig_Env_Loader(t *testing.T) {
+	l, err := loader.New([]byte(testEnvConfig))
+	require.NoError(t, err)
+
+	err = applyEnv(l, []string{
+		"OLRIC_PUBSUB_RETENTION_events_MAXMESSAGES=100",
+		"OLRIC_MEMBERLIST_ENABLECOMPRESSION=false",
+		"OLRIC_SERVICEDISCOVERY_provider=consul",
+		"OTHER_VARIABLE=1",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 100, l.PubSub.Retention["events"].MaxMessages)
+	require.NotNil(t, l.Memberlist.EnableCompression)
+	require.False(t, *l.Memberlist.EnableCompression)
+	require.Equal(t, "consul", l.ServiceDiscovery["provider"])
+	// The other keys of the engine are kept.
+	require.Equal(t, "kvstore", l.DMaps.Engine.Name)
+}
+
+func TestConfig_Env_Custom_Pattern(t *testing.T) {
+	l, err := loader.New([]byte(`dmaps:
+  custom:
+    "sessions:*":
+      maxKeys: 100
+`))
+	require.NoError(t, err)
+
+	err = applyEnv(l, []string{"OLRIC_DMAPS_CUSTOM_sessions_MAXKEYS=500"})
+	require.NoError(t, err)
+
+	// The pattern cannot be addressed, a rule for the DMap is added.
+	require.Equal(t, 100, l.DMaps.Custom["sessions:*"].MaxKeys)
+	require.Equal(t, 500, l.DMaps.Custom["sessions"].MaxKeys)
+}
@@ -17,29 +17,38 @@ package loader
 import "gopkg.in/yaml.v2"
 
 type server struct {
-	Name                       string  `yaml:"name"`
-	BindAddr                   string  `yaml:"bindAddr"`
-	BindPort                   int     `yaml:"bindPort"`
-	Interface                  string  `yaml:"interface"`
-	ReplicationMode            int     `yaml:"replicationMode"`
-	PartitionCount             uint64  `yaml:"partitionCount"`
-	LoadFactor                 float64 `yaml:"loadFactor"`
-	KeepAlivePeriod            string  `yaml:"keepAlivePeriod"`
-	IdleClose                  string  `yaml:"idleClose"`
-	BootstrapTimeout           string  `yaml:"bootstrapTimeout"`
-	ReplicaCount               int     `yaml:"replicaCount"`
-	WriteQuorum                int     `yaml:"writeQuorum"`
-	ReadQuorum                 int     `yaml:"readQuorum"`
-	ReadRepair                 bool    `yaml:"readRepair"`
-	MemberCountQuorum          int32   `yaml:"memberCountQuorum"`
-	RoutingTablePushInterval   string  `yaml:"routingTablePushInterval"`
-	TriggerBalancerInterval    string  `yaml:"triggerBalancerInterval"`
-	LeaveTimeout               string  `yaml:"leaveTimeout"`
-	EnableClusterEventsChannel bool    `yaml:"enableClusterEventsChannel"`
+	Name                       string   `yaml:"name"`
+	BindAddr                   string   `yaml:"bindAddr"`
+	BindPort                   int      `yaml:"bindPort"`
+	Interface                  string   `yaml:"interface"`
+	ReplicationMode            int      `yaml:"replicationMode"`
+	PartitionCount             uint64   `yaml:"partitionCount"`
+	LoadFactor                 float64  `yaml:"loadFactor"`
+	KeepAlivePeriod            string   `yaml:"keepAlivePeriod"`
+	IdleClose                  string   `yaml:"idleClose"`
+	BootstrapTimeout           string   `yaml:"bootstrapTimeout"`
+	ReplicaCount               int      `yaml:"replicaCount"`
+	WriteQuorum                int      `yaml:"writeQuorum"`
+	ReadQuorum                 int      `yaml:"readQuorum"`
+	ReadRepair                 bool     `yaml:"readRepair"`
+	MemberCountQuorum          int32    `yaml:"memberCountQuorum"`
+	RoutingTablePushInterval   string   `yaml:"routingTablePushInterval"`
+	TriggerBalancerInterval    string   `yaml:"triggerBalancerInterval"`
+	LeaveTimeout               string   `yaml:"leaveTimeout"`
+	EnableClusterEventsChannel bool     `yaml:"enableClusterEventsChannel"`
+	CommandLatencyBuckets      []string `yaml:"commandLatencyBuckets"`
 }
 
 type authentication struct {
-	Password string `yaml:"password"`
+	Password          string `yaml:"password"`
+	SecondaryPassword string `yaml:"secondaryPassword"`
+	Users             []user `yaml:"users"`
+}
+
+type user struct {
+	Name      string   `yaml:"name"`
+	Passwords []string `yaml:"passwords"`
+	Rules     []string `yaml:"rules"`
 }
 
 type client struct {
@@ -93,6 +102,8 @@ type memberlist struct {
 	DNSConfigPath           *string  `yaml:"dnsConfigPath"`
 	HandoffQueueDepth       *int     `yaml:"handoffQueueDepth"`
 	UDPBufferSize           *int     `yaml:"udpBufferSize"`
+	SecretKey               *string  `yaml:"secretKey"`
+	Keyring                 []string `yaml:"keyring"`
 }
 
 type engine struct {
@@ -124,6 +135,49 @@ type dmaps struct {
 	Custom                      map[string]dmap `yaml:"custom"`
 }
 
+type retention struct {
+	MaxMessages int    `yaml:"maxMessages"`
+	MaxAge      string `yaml:"maxAge"`
+}
+
+type pubsub struct {
+	SubscriberQueueSize int                  `yaml:"subscriberQueueSize"`
+	OverflowPolicy      string               `yaml:"overflowPolicy"`
+	Retention           map[string]retention `yaml:"retention"`
+}
+
+type metrics struct {
+	Enabled  bool   `yaml:"enabled"`
+	BindAddr string `yaml:"bindAddr"`
+	BindPort int    `yaml:"bindPort"`
+	Path     string `yaml:"path"`
+}
+
+type slowLog struct {
+	Threshold string `yaml:"threshold"`
+	MaxLen    int    `yaml:"maxLen"`
+}
+
+type redisCompat struct {
+	Enabled            bool   `yaml:"enabled"`
+	DefaultDMap        string `yaml:"defaultDMap"`
+	KeyPrefixSeparator string `yaml:"keyPrefixSeparator"`
+}
+
+type tlsConfig struct {
+	CertFile       string `yaml:"certFile"`
+	KeyFile        string `yaml:"keyFile"`
+	ClientCAFile   string `yaml:"clientCAFile"`
+	ClusterRole    string `yaml:"clusterRole"`
+	MinVersion     string `yaml:"minVersion"`
+	ReloadInterval string `yaml:"reloadInterval"`
+}
+
+type auditLog struct {
+	Path     string   `yaml:"path"`
+	Commands []string `yaml:"commands"`
+}
+
 type serviceDiscovery map[string]interface{}
 
 // Loader is the main configuration struct
@@ -133,6 +187,12 @@ type Loader struct {
 	Server           server           `yaml:"server"`
 	Client           client           `yaml:"client"`
 	DMaps            dmaps            `yaml:"dmaps"`
+	PubSub           pubsub           `yaml:"pubsub"`
+	Metrics          metrics          `yaml:"metrics"`
+	SlowLog          slowLog          `yaml:"slowLog"`
+	RedisCompat      redisCompat      `yaml:"redisCompat"`
+	TLS              tlsConfig        `yaml:"tls"`
+	AuditLog         auditLog         `yaml:"auditLog"`
 	ServiceDiscovery serviceDiscovery `yaml:"serviceDiscovery"`
 	Authentication   authentication   `yaml:"authentication"`
 }
@@ -141,6 +141,31 @@ func loadDMapConfig(c *loader.Loader) (*DMaps, error) {
 	return res, nil
 }
 
+// loadPubSubConfig creates a new *PubSub by parsing olric.yaml
+func loadPubSubConfig(c *loader.Loader) (*PubSub, error) {
+	res := &PubSub{
+		SubscriberQueueSize: c.PubSub.SubscriberQueueSize,
+		OverflowPolicy:      OverflowPolicy(c.PubSub.OverflowPolicy),
+	}
+	if c.PubSub.Retention != nil {
+		res.Retention = make(map[string]ChannelRetention)
+		for channel, rc := range c.PubSub.Retention {
+			cr := ChannelRetention{
+				MaxMessages: rc.MaxMessages,
+			}
+			if rc.MaxAge != "" {
+				maxAge, err := time.ParseDuration(rc.MaxAge)
+				if err != nil {
+					return nil, errors.WithMessagef(err, "failed to parse pubsub.retention.%s.MaxAge", channel)
+				}
+				cr.MaxAge = maxAge
+			}
+			res.Retention[channel] = cr
+		}
+	}
+	return res, nil
+}
+
 // loadMemberlistConfig creates a new *memberlist.Config by parsing olric.yaml
 func loadMemberlistConfig(c *loader.Loader, mc *memberlist.Config) (*memberlist.Config, error) {
 	var err error
@@ -252,9 +277,69 @@ func loadMemberlistConfig(c *loader.Loader, mc *memberlist.Config) (*memberlist.
 	if c.Memberlist.UDPBufferSize != nil {
 		mc.UDPBufferSize = *c.Memberlist.UDPBufferSize
 	}
+
+	if err = loadGossipKeys(c, mc); err != nil {
+		return nil, err
+	}
 	return mc, nil
 }
 
+// loadUsers reads the users of the ACL. The passwords are SHA-256 hashes, they
+// are validated by Authentication.Validate.
+func loadUsers(c *loader.Loader) []User {
+	var users []User
+	for _, u := range c.Authentication.Users {
+		users = append(users, User{
+			Name:      u.Name,
+			Passwords: u.Passwords,
+			Rules:     u.Rules,
+		})
+	}
+	return users
+}
+
+// loadGossipKeys sets the gossip encryption keys. EnvMemberlistSecretKey and
+// EnvMemberlistKeyring override the configuration file, so the keys don't have
+// to be stored in it. The first key of the keyring is the primary key if the
+// secret key is not set.
+func loadGossipKeys(c *loader.Loader, mc *memberlist.Config) error {
+	secretKey := c.Memberlist.SecretKey
+	keyring := c.Memberlist.Keyring
+
+	if secretKey == nil && len(keyring) == 0 {
+		// Gossip encryption is disabled.
+		return nil
+	}
+
+	var keys [][]byte
+	for i, encoded := range keyring {
+		key, err := DecodeGossipKey(encoded)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to parse memberlist.keyring[%d]", i)
+		}
+		keys = append(keys, key)
+	}
+
+	var primaryKey []byte
+	if secretKey != nil {
+		key, err := DecodeGossipKey(*secretKey)
+		if err != nil {
+			return errors.WithMessage(err, "failed to parse memberlist.secretKey")
+		}
+		primaryKey = key
+	} else {
+		primaryKey = keys[0]
+	}
+
+	kr, err := memberlist.NewKeyring(keys, primaryKey)
+	if err != nil {
+		return err
+	}
+	mc.SecretKey = primaryKey
+	mc.Keyring = kr
+	return nil
+}
+
 // Load reads and loads Olric configuration.
 func Load(filename string) (*Config, error) {
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
@@ -273,6 +358,11 @@ func Load(filename string) (*Config, error) {
 		return nil, err
 	}
 
+	// The environment variables override the configuration file. See EnvPrefix.
+	if err = applyEnv(c, os.Environ()); err != nil {
+		return nil, err
+	}
+
 	var logOutput io.Writer
 	switch {
 	case c.Logging.Output == "stderr":
@@ -315,6 +405,16 @@ func Load(filename string) (*Config, error) {
 		}
 	}
 
+	var commandLatencyBuckets []time.Duration
+	for _, raw := range c.Server.CommandLatencyBuckets {
+		bucket, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, errors.WithMessage(err,
+				fmt.Sprintf("failed to parse server.commandLatencyBuckets: '%s'", raw))
+		}
+		commandLatencyBuckets = append(commandLatencyBuckets, bucket)
+	}
+
 	if c.Server.IdleClose != "" {
 		idleClose, err = time.ParseDuration(c.Server.IdleClose)
 		if err != nil {
@@ -362,6 +462,24 @@ func Load(filename string) (*Config, error) {
 		}
 	}
 
+	var slowLogThreshold time.Duration
+	if c.SlowLog.Threshold != "" {
+		slowLogThreshold, err = time.ParseDuration(c.SlowLog.Threshold)
+		if err != nil {
+			return nil, errors.WithMessage(err,
+				fmt.Sprintf("failed to parse slowLog.threshold: '%s'", c.SlowLog.Threshold))
+		}
+	}
+
+	var tlsReloadInterval time.Duration
+	if c.TLS.ReloadInterval != "" {
+		tlsReloadInterval, err = time.ParseDuration(c.TLS.ReloadInterval)
+		if err != nil {
+			return nil, errors.WithMessage(err,
+				fmt.Sprintf("failed to parse tls.reloadInterval: '%s'", c.TLS.ReloadInterval))
+		}
+	}
+
 	clientConfig := Client{
 		Authentication: &Authentication{
 			Password: c.Authentication.Password,
@@ -377,6 +495,11 @@ func Load(filename string) (*Config, error) {
 		return nil, err
 	}
 
+	pubsubConfig, err := loadPubSubConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		BindAddr:                   c.Server.BindAddr,
 		BindPort:                   c.Server.BindPort,
@@ -390,6 +513,7 @@ func Load(filename string) (*Config, error) {
 		RoutingTablePushInterval:   routingTablePushInterval,
 		TriggerBalancerInterval:    triggerBalancerInterval,
 		EnableClusterEventsChannel: c.Server.EnableClusterEventsChannel,
+		CommandLatencyBuckets:      commandLatencyBuckets,
 		MaxJoinAttempts:            c.Memberlist.MaxJoinAttempts,
 		Peers:                      c.Memberlist.Peers,
 		PartitionCount:             c.Server.PartitionCount,
@@ -409,8 +533,38 @@ func Load(filename string) (*Config, error) {
 		BootstrapTimeout:           bootstrapTimeout,
 		LeaveTimeout:               leaveTimeout,
 		DMaps:                      dmapConfig,
+		PubSub:                     pubsubConfig,
+		Metrics: &Metrics{
+			Enabled:  c.Metrics.Enabled,
+			BindAddr: c.Metrics.BindAddr,
+			BindPort: c.Metrics.BindPort,
+			Path:     c.Metrics.Path,
+		},
+		SlowLog: &SlowLog{
+			Threshold: slowLogThreshold,
+			MaxLen:    c.SlowLog.MaxLen,
+		},
+		RedisCompat: &RedisCompat{
+			Enabled:            c.RedisCompat.Enabled,
+			DefaultDMap:        c.RedisCompat.DefaultDMap,
+			KeyPrefixSeparator: c.RedisCompat.KeyPrefixSeparator,
+		},
+		TLS: &TLS{
+			CertFile:       c.TLS.CertFile,
+			KeyFile:        c.TLS.KeyFile,
+			ClientCAFile:   c.TLS.ClientCAFile,
+			ClusterRole:    c.TLS.ClusterRole,
+			MinVersion:     c.TLS.MinVersion,
+			ReloadInterval: tlsReloadInterval,
+		},
+		AuditLog: &AuditLog{
+			Path:     c.AuditLog.Path,
+			Commands: c.AuditLog.Commands,
+		},
 		Authentication: &Authentication{
-			Password: c.Authentication.Password,
+			Password:          c.Authentication.Password,
+			SecondaryPassword: c.Authentication.SecondaryPassword,
+			Users:             loadUsers(c),
 		},
 	}
 
@@ -15,6 +15,7 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net"
 	"strings"
@@ -23,6 +24,29 @@ import (
 	"github.com/hashicorp/memberlist"
 )
 
+const (
+	// EnvMemberlistSecretKey is the environment variable of the primary gossip
+	// encryption key. It overrides memberlist.secretKey in the configuration file.
+	EnvMemberlistSecretKey = "OLRIC_MEMBERLIST_SECRETKEY"
+
+	// EnvMemberlistKeyring is the environment variable of the comma separated
+	// gossip encryption keys. It overrides memberlist.keyring in the configuration file.
+	EnvMemberlistKeyring = "OLRIC_MEMBERLIST_KEYRING"
+)
+
+// DecodeGossipKey decodes a base64 encoded gossip encryption key. The key must
+// be 16, 24 or 32 bytes to select AES-128, AES-192 or AES-256.
+func DecodeGossipKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gossip encryption key: %w", err)
+	}
+	if err = memberlist.ValidateKey(key); err != nil {
+		return nil, fmt.Errorf("invalid gossip encryption key: %w", err)
+	}
+	return key, nil
+}
+
 func (c *Config) validateMemberlistConfig() error {
 	var result error
 	if c.MemberlistConfig.AdvertiseAddr != "" {
@@ -35,6 +59,11 @@ func (c *Config) validateMemberlistConfig() error {
 		result = multierror.Append(result,
 			fmt.Errorf("memberlist: BindAddr cannot be an empty string"))
 	}
+	if len(c.MemberlistConfig.SecretKey) > 0 {
+		if err := memberlist.ValidateKey(c.MemberlistConfig.SecretKey); err != nil {
+			result = multierror.Append(result, fmt.Errorf("memberlist: invalid SecretKey: %w", err))
+		}
+	}
 	return result
 }
 
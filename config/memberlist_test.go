@@ -0,0 +1,102 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testGossipKeysConfig = `memberlist:
+  environment: "local"
+  bindAddr: "127.0.0.1"
+  bindPort: 3322
+  secretKey: "%s"
+  keyring:
+    - "%s"
+`
+
+func loadGossipKeysConfig(t *testing.T, data string) (*Config, error) {
+	f := createTmpFile(t, "olric-yaml-config-test")
+	_, err := f.WriteString(data)
+	require.NoError(t, err)
+	return Load(f.Name())
+}
+
+func sprintfKeys(format string, keys ...[]byte) string {
+	var args []interface{}
+	for _, key := range keys {
+		args = append(args, base64.StdEncoding.EncodeToString(key))
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func TestConfig_Memberlist_GossipKeys(t *testing.T) {
+	primary := []byte("0123456789abcdef")
+	secondary := []byte("fedcba9876543210")
+	data := sprintfKeys(testGossipKeysConfig, primary, secondary)
+
+	t.Run("Configuration file", func(t *testing.T) {
+		c, err := loadGossipKeysConfig(t, data)
+		require.NoError(t, err)
+		require.Equal(t, primary, c.MemberlistConfig.SecretKey)
+		require.Equal(t, primary, c.MemberlistConfig.Keyring.GetPrimaryKey())
+		require.Equal(t, [][]byte{primary, secondary}, c.MemberlistConfig.Keyring.GetKeys())
+	})
+
+	t.Run("Environment variables", func(t *testing.T) {
+		key := []byte("0123456789abcdef01234567")
+		t.Setenv(EnvMemberlistSecretKey, base64.StdEncoding.EncodeToString(key))
+		t.Setenv(EnvMemberlistKeyring, base64.StdEncoding.EncodeToString(primary))
+
+		c, err := loadGossipKeysConfig(t, data)
+		require.NoError(t, err)
+		require.Equal(t, key, c.MemberlistConfig.SecretKey)
+		require.Equal(t, [][]byte{key, primary}, c.MemberlistConfig.Keyring.GetKeys())
+	})
+
+	t.Run("Keyring without secret key", func(t *testing.T) {
+		t.Setenv(EnvMemberlistKeyring, base64.StdEncoding.EncodeToString(secondary)+","+base64.StdEncoding.EncodeToString(primary))
+
+		c, err := loadGossipKeysConfig(t, `memberlist:
+  environment: "local"
+  bindAddr: "127.0.0.1"
+`)
+		require.NoError(t, err)
+		require.Equal(t, secondary, c.MemberlistConfig.SecretKey)
+		require.Equal(t, [][]byte{secondary, primary}, c.MemberlistConfig.Keyring.GetKeys())
+	})
+
+	t.Run("Invalid key", func(t *testing.T) {
+		t.Setenv(EnvMemberlistSecretKey, base64.StdEncoding.EncodeToString([]byte("short")))
+		_, err := loadGossipKeysConfig(t, data)
+		require.Error(t, err)
+	})
+}
+
+func TestConfig_DecodeGossipKey(t *testing.T) {
+	key, err := DecodeGossipKey(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef")))
+	require.NoError(t, err)
+	require.Equal(t, []byte("0123456789abcdef"), key)
+
+	_, err = DecodeGossipKey("not-base64")
+	require.Error(t, err)
+
+	_, err = DecodeGossipKey(base64.StdEncoding.EncodeToString([]byte("0123")))
+	require.Error(t, err)
+}
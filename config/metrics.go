@@ -0,0 +1,80 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultMetricsBindAddr is the default bind address of the metrics endpoint.
+	DefaultMetricsBindAddr = "0.0.0.0"
+
+	// DefaultMetricsPort is the default port of the metrics endpoint.
+	DefaultMetricsPort = 3324
+
+	// DefaultMetricsPath is the default HTTP path of the metrics endpoint.
+	DefaultMetricsPath = "/metrics"
+)
+
+// Metrics denotes configuration for the Prometheus metrics endpoint. If it's
+// enabled, Olric runs an HTTP server and exposes the statistics of the member
+// in Prometheus text exposition format.
+type Metrics struct {
+	// Enabled starts the HTTP server of the metrics endpoint. The default is false.
+	Enabled bool
+
+	// BindAddr denotes the address that the metrics endpoint binds to.
+	// The default value is 0.0.0.0.
+	BindAddr string
+
+	// BindPort denotes the port that the metrics endpoint binds to.
+	// The default value is 3324.
+	BindPort int
+
+	// Path is the HTTP path of the metrics endpoint. The default value is /metrics.
+	Path string
+}
+
+// Sanitize sets default values to empty configuration variables, if it's possible.
+func (m *Metrics) Sanitize() error {
+	if m.BindAddr == "" {
+		m.BindAddr = DefaultMetricsBindAddr
+	}
+
+	if m.BindPort == 0 {
+		m.BindPort = DefaultMetricsPort
+	}
+
+	if m.Path == "" {
+		m.Path = DefaultMetricsPath
+	}
+	return nil
+}
+
+// Validate finds errors in the current configuration.
+func (m *Metrics) Validate() error {
+	if m.BindPort < 0 || m.BindPort > 65535 {
+		return fmt.Errorf("invalid BindPort: %d", m.BindPort)
+	}
+
+	if !strings.HasPrefix(m.Path, "/") {
+		return fmt.Errorf("invalid Path: %s", m.Path)
+	}
+	return nil
+}
+
+var _ IConfig = (*Metrics)(nil)
@@ -0,0 +1,43 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Metrics(t *testing.T) {
+	m := &Metrics{}
+	require.NoError(t, m.Sanitize())
+	require.NoError(t, m.Validate())
+
+	require.False(t, m.Enabled)
+	require.Equal(t, DefaultMetricsBindAddr, m.BindAddr)
+	require.Equal(t, DefaultMetricsPort, m.BindPort)
+	require.Equal(t, DefaultMetricsPath, m.Path)
+}
+
+func TestConfig_Metrics_Invalid(t *testing.T) {
+	configs := []*Metrics{
+		{Path: "metrics"},
+		{BindPort: 70000},
+	}
+	for _, m := range configs {
+		require.NoError(t, m.Sanitize())
+		require.Error(t, m.Validate())
+	}
+}
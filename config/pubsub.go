@@ -0,0 +1,111 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// OverflowPolicy determines what happens when the outbound queue of a
+// subscriber is full.
+type OverflowPolicy string
+
+const (
+	// DropOldest discards the oldest message in the queue to make room for the new one.
+	DropOldest OverflowPolicy = "drop-oldest"
+
+	// DropNewest discards the new message and keeps the queue as is.
+	DropNewest OverflowPolicy = "drop-newest"
+
+	// Disconnect closes the connection of the slow subscriber.
+	Disconnect OverflowPolicy = "disconnect"
+)
+
+const (
+	// DefaultSubscriberQueueSize is the default capacity of the outbound message
+	// queue of a subscriber connection.
+	DefaultSubscriberQueueSize = 1024
+
+	// DefaultOverflowPolicy is the default policy for slow subscribers.
+	DefaultOverflowPolicy = DropOldest
+)
+
+// PubSub denotes configuration for the Publish-Subscribe service.
+type PubSub struct {
+	// SubscriberQueueSize is the capacity of the outbound message queue of
+	// a subscriber connection. Every subscriber has its own queue, and a
+	// dedicated goroutine writes the queued messages to the network. So a slow
+	// subscriber cannot stall the publishers. The default value is 1024.
+	SubscriberQueueSize int
+
+	// OverflowPolicy determines what happens when the queue of a subscriber is
+	// full. Available policies: "drop-oldest", "drop-newest" and "disconnect".
+	// The default policy is "drop-oldest".
+	OverflowPolicy OverflowPolicy
+
+	// Retention enables the short-term message history of the channels, keyed
+	// by channel name. The messages of a retained channel have sequence IDs,
+	// and a subscriber can resume from the last seen sequence after a
+	// reconnect. Every member keeps the history in memory.
+	Retention map[string]ChannelRetention
+}
+
+// ChannelRetention denotes the history settings of a channel. At least one of
+// the limits must be set. If both are set, a message is discarded when either
+// limit is exceeded.
+type ChannelRetention struct {
+	// MaxMessages is the maximum number of messages in the history.
+	MaxMessages int
+
+	// MaxAge is the maximum age of a message in the history.
+	MaxAge time.Duration
+}
+
+// Sanitize sets default values to empty configuration variables, if it's possible.
+func (p *PubSub) Sanitize() error {
+	if p.SubscriberQueueSize <= 0 {
+		p.SubscriberQueueSize = DefaultSubscriberQueueSize
+	}
+
+	if p.OverflowPolicy == "" {
+		p.OverflowPolicy = DefaultOverflowPolicy
+	}
+	return nil
+}
+
+// Validate finds errors in the current configuration.
+func (p *PubSub) Validate() error {
+	switch p.OverflowPolicy {
+	case DropOldest, DropNewest, Disconnect:
+	default:
+		return fmt.Errorf("invalid OverflowPolicy: %s", p.OverflowPolicy)
+	}
+
+	for channel, retention := range p.Retention {
+		if retention.MaxMessages < 0 {
+			return fmt.Errorf("invalid MaxMessages for channel: %s", channel)
+		}
+		if retention.MaxAge < 0 {
+			return fmt.Errorf("invalid MaxAge for channel: %s", channel)
+		}
+		if retention.MaxMessages == 0 && retention.MaxAge == 0 {
+			return fmt.Errorf("MaxMessages or MaxAge is required for channel: %s", channel)
+		}
+	}
+	return nil
+}
+
+var _ IConfig = (*PubSub)(nil)
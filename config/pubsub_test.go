@@ -0,0 +1,59 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_PubSub(t *testing.T) {
+	p := &PubSub{}
+	require.NoError(t, p.Sanitize())
+	require.NoError(t, p.Validate())
+
+	require.Equal(t, DefaultSubscriberQueueSize, p.SubscriberQueueSize)
+	require.Equal(t, DefaultOverflowPolicy, p.OverflowPolicy)
+}
+
+func TestConfig_PubSub_Invalid_OverflowPolicy(t *testing.T) {
+	p := &PubSub{OverflowPolicy: "block"}
+	require.NoError(t, p.Sanitize())
+	require.Error(t, p.Validate())
+}
+
+func TestConfig_PubSub_Retention(t *testing.T) {
+	p := &PubSub{Retention: map[string]ChannelRetention{
+		"orders": {MaxMessages: 100},
+		"events": {MaxAge: time.Minute},
+	}}
+	require.NoError(t, p.Sanitize())
+	require.NoError(t, p.Validate())
+}
+
+func TestConfig_PubSub_Invalid_Retention(t *testing.T) {
+	retentions := []ChannelRetention{
+		{},
+		{MaxMessages: -1},
+		{MaxMessages: 10, MaxAge: -time.Second},
+	}
+	for _, retention := range retentions {
+		p := &PubSub{Retention: map[string]ChannelRetention{"orders": retention}}
+		require.NoError(t, p.Sanitize())
+		require.Error(t, p.Validate())
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultRedisCompatDMap is the default DMap of the Redis compatibility layer.
+const DefaultRedisCompatDMap = "default"
+
+// RedisCompat denotes configuration for the Redis compatibility layer. If it's
+// enabled, Olric accepts GET, SET, DEL, EXPIRE, TTL, INCR, SCAN, EXISTS and MGET
+// commands, so redis-cli, redis-benchmark and the Redis client libraries can
+// talk to the cluster members.
+type RedisCompat struct {
+	// Enabled registers the Redis commands. The default is false.
+	Enabled bool
+
+	// DefaultDMap is the DMap of the keys that don't have a prefix.
+	// The default value is "default".
+	DefaultDMap string
+
+	// KeyPrefixSeparator enables the key prefix convention. If it's set, the
+	// part of the key before the first separator is used as the DMap name,
+	// e.g. the key "users:42" is stored in the "users" DMap if the separator is
+	// ":". The key itself is not modified. It's empty by default.
+	KeyPrefixSeparator string
+}
+
+// DMapName returns the DMap name of the given key.
+func (r *RedisCompat) DMapName(key string) string {
+	if r.KeyPrefixSeparator == "" {
+		return r.DefaultDMap
+	}
+	if i := strings.Index(key, r.KeyPrefixSeparator); i > 0 {
+		return key[:i]
+	}
+	return r.DefaultDMap
+}
+
+// Sanitize sets default values to empty configuration variables, if it's possible.
+func (r *RedisCompat) Sanitize() error {
+	if r.DefaultDMap == "" {
+		r.DefaultDMap = DefaultRedisCompatDMap
+	}
+	return nil
+}
+
+// Validate finds errors in the current configuration.
+func (r *RedisCompat) Validate() error {
+	if r.DefaultDMap == "" {
+		return fmt.Errorf("DefaultDMap cannot be empty")
+	}
+	return nil
+}
+
+var _ IConfig = (*RedisCompat)(nil)
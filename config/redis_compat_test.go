@@ -0,0 +1,46 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_RedisCompat(t *testing.T) {
+	r := &RedisCompat{}
+	require.NoError(t, r.Sanitize())
+	require.NoError(t, r.Validate())
+
+	require.False(t, r.Enabled)
+	require.Equal(t, DefaultRedisCompatDMap, r.DefaultDMap)
+	require.Equal(t, DefaultRedisCompatDMap, r.DMapName("users:42"))
+}
+
+func TestConfig_RedisCompat_DMapName(t *testing.T) {
+	r := &RedisCompat{
+		DefaultDMap:        "mydmap",
+		KeyPrefixSeparator: ":",
+	}
+	require.NoError(t, r.Sanitize())
+	require.NoError(t, r.Validate())
+
+	require.Equal(t, "users", r.DMapName("users:42"))
+	require.Equal(t, "users", r.DMapName("users:42:name"))
+	require.Equal(t, "mydmap", r.DMapName("users"))
+	require.Equal(t, "mydmap", r.DMapName(":42"))
+	require.Equal(t, "mydmap", r.DMapName(""))
+}
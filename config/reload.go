@@ -0,0 +1,167 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/memberlist"
+)
+
+// reloadable are the fields that can be changed without a restart. The keys of
+// the maps are written as [*].
+var reloadable = map[string]struct{}{
+	"LogLevel":                          {},
+	"LogVerbosity":                      {},
+	"DMaps.MaxIdleDuration":             {},
+	"DMaps.TTLDuration":                 {},
+	"DMaps.MaxKeys":                     {},
+	"DMaps.MaxInuse":                    {},
+	"DMaps.LRUSamples":                  {},
+	"DMaps.EvictionPolicy":              {},
+	"DMaps.CheckEmptyFragmentsInterval": {},
+	"DMaps.TriggerCompactionInterval":   {},
+	"DMaps.Custom[*].MaxIdleDuration":   {},
+	"DMaps.Custom[*].TTLDuration":       {},
+	"DMaps.Custom[*].MaxKeys":           {},
+	"DMaps.Custom[*].MaxInuse":          {},
+	"DMaps.Custom[*].LRUSamples":        {},
+	"DMaps.Custom[*].EvictionPolicy":    {},
+}
+
+// derived are the fields that are set from the other fields on startup.
+var derived = map[string]struct{}{
+	"Client.Authentication": {},
+	"MemberlistConfig.Name": {},
+}
+
+// secrets are the names of the fields whose values are never shown.
+var secrets = map[string]struct{}{
+	"Password":          {},
+	"SecondaryPassword": {},
+	"Users":             {},
+	"SecretKey":         {},
+}
+
+var mapKeyRegex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// RestartRequired compares the configuration with next, and returns the
+// changes that cannot be applied without a restart, one line per field, e.g.
+// "ReplicaCount: 1 -> 2". Both configurations should be sanitized. The values
+// of the secrets are redacted, and the functions, interfaces and pointers to
+// non-configuration types, e.g. the loggers, are ignored.
+func (c *Config) RestartRequired(next *Config) []string {
+	var changes []string
+	diffValues("", "", reflect.ValueOf(c).Elem(), reflect.ValueOf(next).Elem(), &changes)
+	return changes
+}
+
+func isConfigType(t reflect.Type) bool {
+	return t.PkgPath() == reflect.TypeOf(Config{}).PkgPath() || t == reflect.TypeOf(memberlist.Config{})
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func diffValues(path, name string, current, next reflect.Value, changes *[]string) {
+	pattern := mapKeyRegex.ReplaceAllString(path, "[*]")
+	if _, ok := reloadable[pattern]; ok {
+		return
+	}
+	if _, ok := derived[pattern]; ok {
+		return
+	}
+
+	changed := func() {
+		if _, ok := secrets[name]; ok {
+			*changes = append(*changes, fmt.Sprintf("%s: (redacted)", path))
+			return
+		}
+		*changes = append(*changes, fmt.Sprintf("%s: %v -> %v", path, current.Interface(), next.Interface()))
+	}
+
+	switch current.Kind() {
+	case reflect.Func, reflect.Interface, reflect.Chan, reflect.UnsafePointer:
+		return
+	case reflect.Ptr:
+		if !isConfigType(current.Type().Elem()) {
+			return
+		}
+		if current.IsNil() || next.IsNil() {
+			if current.IsNil() != next.IsNil() {
+				changed()
+			}
+			return
+		}
+		diffValues(path, name, current.Elem(), next.Elem(), changes)
+	case reflect.Struct:
+		if !isConfigType(current.Type()) {
+			if !reflect.DeepEqual(current.Interface(), next.Interface()) {
+				changed()
+			}
+			return
+		}
+		for i := 0; i < current.NumField(); i++ {
+			field := current.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			diffValues(joinPath(path, field.Name), field.Name, current.Field(i), next.Field(i), changes)
+		}
+	case reflect.Map:
+		if current.Type().Key().Kind() != reflect.String || current.Type().Elem().Kind() != reflect.Struct {
+			if !reflect.DeepEqual(current.Interface(), next.Interface()) {
+				changed()
+			}
+			return
+		}
+		// Compare the items one by one, a missing item is a zero value.
+		keys := make(map[string]struct{})
+		for _, key := range current.MapKeys() {
+			keys[key.String()] = struct{}{}
+		}
+		for _, key := range next.MapKeys() {
+			keys[key.String()] = struct{}{}
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+		zero := reflect.Zero(current.Type().Elem())
+		for _, key := range sorted {
+			k := reflect.ValueOf(key).Convert(current.Type().Key())
+			a, b := current.MapIndex(k), next.MapIndex(k)
+			if !a.IsValid() {
+				a = zero
+			}
+			if !b.IsValid() {
+				b = zero
+			}
+			diffValues(fmt.Sprintf("%s[%s]", path, key), name, a, b, changes)
+		}
+	default:
+		if !reflect.DeepEqual(current.Interface(), next.Interface()) {
+			changed()
+		}
+	}
+}
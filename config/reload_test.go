@@ -0,0 +1,83 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newSanitizedConfig(t *testing.T) *Config {
+	c := New("local")
+	require.NoError(t, c.Sanitize())
+	return c
+}
+
+func TestConfig_RestartRequired(t *testing.T) {
+	current := newSanitizedConfig(t)
+	next := newSanitizedConfig(t)
+	require.Empty(t, current.RestartRequired(next))
+
+	next.ReplicaCount = 2
+	next.MemberlistConfig.BindPort = 4000
+	next.DMaps.NumEvictionWorkers = current.DMaps.NumEvictionWorkers + 1
+	require.Equal(t, []string{
+		"ReplicaCount: 1 -> 2",
+		fmt.Sprintf("DMaps.NumEvictionWorkers: %d -> %d", current.DMaps.NumEvictionWorkers, next.DMaps.NumEvictionWorkers),
+		"MemberlistConfig.BindPort: 3322 -> 4000",
+	}, current.RestartRequired(next))
+}
+
+func TestConfig_RestartRequired_Reloadable(t *testing.T) {
+	current := newSanitizedConfig(t)
+	next := newSanitizedConfig(t)
+
+	next.LogLevel = LogLevelDebug
+	next.LogVerbosity = 6
+	next.DMaps.MaxInuse = 1 << 20
+	next.DMaps.TriggerCompactionInterval = time.Minute
+	next.DMaps.Custom = map[string]DMap{
+		"mydmap": {
+			TTLDuration:    time.Hour,
+			MaxKeys:        1000,
+			EvictionPolicy: LRUEviction,
+		},
+	}
+	require.NoError(t, next.Sanitize())
+	require.Empty(t, current.RestartRequired(next))
+
+	// The storage engine of a DMap cannot be changed.
+	dc := next.DMaps.Custom["mydmap"]
+	dc.Engine = &Engine{Name: "custom"}
+	next.DMaps.Custom["mydmap"] = dc
+	require.Equal(t, []string{
+		"DMaps.Custom[mydmap].Engine: <nil> -> &{custom <nil> map[]}",
+	}, current.RestartRequired(next))
+}
+
+func TestConfig_RestartRequired_Secrets(t *testing.T) {
+	current := newSanitizedConfig(t)
+	next := newSanitizedConfig(t)
+
+	next.Authentication.Password = "secret"
+	next.MemberlistConfig.SecretKey = []byte("0123456789abcdef")
+	require.Equal(t, []string{
+		"Authentication.Password: (redacted)",
+		"MemberlistConfig.SecretKey: (redacted)",
+	}, current.RestartRequired(next))
+}
@@ -0,0 +1,64 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultSlowLogThreshold is the default execution time threshold of the slow log.
+	DefaultSlowLogThreshold = 10 * time.Millisecond
+
+	// DefaultSlowLogMaxLen is the default number of records kept by the slow log.
+	DefaultSlowLogMaxLen = 128
+)
+
+// SlowLog denotes configuration for the slow log. Olric records the commands
+// that take longer than the threshold to execute, and keeps the latest records
+// on every member. The records can be queried with the SLOWLOG command.
+type SlowLog struct {
+	// Threshold is the execution time, excluding I/O, that a command must
+	// exceed to be recorded. A negative value disables the slow log.
+	// The default value is 10ms.
+	Threshold time.Duration
+
+	// MaxLen is the maximum number of records. The oldest record is removed
+	// when a new one is added to a full slow log. The default value is 128.
+	MaxLen int
+}
+
+// Sanitize sets default values to empty configuration variables, if it's possible.
+func (s *SlowLog) Sanitize() error {
+	if s.Threshold == 0 {
+		s.Threshold = DefaultSlowLogThreshold
+	}
+
+	if s.MaxLen == 0 {
+		s.MaxLen = DefaultSlowLogMaxLen
+	}
+	return nil
+}
+
+// Validate finds errors in the current configuration.
+func (s *SlowLog) Validate() error {
+	if s.MaxLen < 0 {
+		return fmt.Errorf("invalid MaxLen: %d", s.MaxLen)
+	}
+	return nil
+}
+
+var _ IConfig = (*SlowLog)(nil)
@@ -0,0 +1,44 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_SlowLog(t *testing.T) {
+	s := &SlowLog{}
+	require.NoError(t, s.Sanitize())
+	require.NoError(t, s.Validate())
+
+	require.Equal(t, DefaultSlowLogThreshold, s.Threshold)
+	require.Equal(t, DefaultSlowLogMaxLen, s.MaxLen)
+}
+
+func TestConfig_SlowLog_Disabled(t *testing.T) {
+	s := &SlowLog{Threshold: -1}
+	require.NoError(t, s.Sanitize())
+	require.NoError(t, s.Validate())
+	require.Less(t, s.Threshold, time.Duration(0))
+}
+
+func TestConfig_SlowLog_Invalid(t *testing.T) {
+	s := &SlowLog{MaxLen: -1}
+	require.NoError(t, s.Sanitize())
+	require.Error(t, s.Validate())
+}
@@ -0,0 +1,123 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTLSMinVersion is the default minimum TLS version accepted by the server.
+	DefaultTLSMinVersion = "1.2"
+
+	// DefaultTLSReloadInterval is the default interval to check the certificate
+	// files for changes.
+	DefaultTLSReloadInterval = 10 * time.Second
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLS denotes configuration for the client-facing RESP listener. TLS is enabled
+// if CertFile is set. The files are checked periodically, and the certificates
+// are reloaded without a restart if they change.
+type TLS struct {
+	// CertFile is the path of the PEM encoded certificate of the server.
+	CertFile string
+
+	// KeyFile is the path of the PEM encoded private key of the certificate.
+	KeyFile string
+
+	// ClientCAFile is the path of the PEM encoded CA certificates to verify the
+	// client certificates. The clients have to present a valid certificate if
+	// it's set.
+	ClientCAFile string
+
+	// ClusterRole is the organizational unit (OU) in the certificates of the
+	// cluster members. If it's set, the internal commands, e.g. the ones to
+	// move fragments and update the routing table, are only accepted from the
+	// clients that present a certificate with this unit. It requires
+	// ClientCAFile.
+	ClusterRole string
+
+	// MinVersion is the minimum TLS version accepted by the server: 1.0, 1.1,
+	// 1.2 or 1.3. The default value is 1.2.
+	MinVersion string
+
+	// ReloadInterval is the interval to check the files for changes. A negative
+	// value disables reloading. The default value is 10s.
+	ReloadInterval time.Duration
+}
+
+// Enabled returns true if the certificate file is set.
+func (t *TLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// TLSMinVersion returns the minimum TLS version as a crypto/tls constant. It
+// returns zero if MinVersion is invalid.
+func (t *TLS) TLSMinVersion() uint16 {
+	return tlsVersions[t.MinVersion]
+}
+
+// Sanitize sets default values to empty configuration variables, if it's possible.
+func (t *TLS) Sanitize() error {
+	t.CertFile = strings.TrimSpace(t.CertFile)
+	t.KeyFile = strings.TrimSpace(t.KeyFile)
+	t.ClientCAFile = strings.TrimSpace(t.ClientCAFile)
+	t.ClusterRole = strings.TrimSpace(t.ClusterRole)
+
+	if t.MinVersion == "" {
+		t.MinVersion = DefaultTLSMinVersion
+	}
+
+	if t.ReloadInterval == 0 {
+		t.ReloadInterval = DefaultTLSReloadInterval
+	}
+	return nil
+}
+
+// Validate finds errors in the current configuration.
+func (t *TLS) Validate() error {
+	if !t.Enabled() {
+		if t.KeyFile != "" || t.ClientCAFile != "" || t.ClusterRole != "" {
+			return errors.New("CertFile cannot be empty")
+		}
+		return nil
+	}
+
+	if t.KeyFile == "" {
+		return errors.New("KeyFile cannot be empty")
+	}
+
+	if t.ClusterRole != "" && t.ClientCAFile == "" {
+		return errors.New("ClusterRole requires ClientCAFile")
+	}
+
+	if _, ok := tlsVersions[t.MinVersion]; !ok {
+		return fmt.Errorf("invalid MinVersion: %s", t.MinVersion)
+	}
+	return nil
+}
+
+var _ IConfig = (*TLS)(nil)
@@ -0,0 +1,77 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_TLS(t *testing.T) {
+	c := &TLS{
+		CertFile: "server.crt",
+		KeyFile:  "server.key",
+	}
+	require.NoError(t, c.Sanitize())
+	require.NoError(t, c.Validate())
+
+	require.True(t, c.Enabled())
+	require.Equal(t, DefaultTLSMinVersion, c.MinVersion)
+	require.Equal(t, uint16(tls.VersionTLS12), c.TLSMinVersion())
+	require.Equal(t, DefaultTLSReloadInterval, c.ReloadInterval)
+}
+
+func TestConfig_TLS_Disabled(t *testing.T) {
+	c := &TLS{}
+	require.NoError(t, c.Sanitize())
+	require.NoError(t, c.Validate())
+	require.False(t, c.Enabled())
+}
+
+func TestConfig_TLS_Invalid(t *testing.T) {
+	t.Run("Without certificate", func(t *testing.T) {
+		c := &TLS{KeyFile: "server.key"}
+		require.NoError(t, c.Sanitize())
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("Without key", func(t *testing.T) {
+		c := &TLS{CertFile: "server.crt"}
+		require.NoError(t, c.Sanitize())
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("ClusterRole without ClientCAFile", func(t *testing.T) {
+		c := &TLS{
+			CertFile:    "server.crt",
+			KeyFile:     "server.key",
+			ClusterRole: "olric-cluster",
+		}
+		require.NoError(t, c.Sanitize())
+		require.Error(t, c.Validate())
+	})
+
+	t.Run("Invalid MinVersion", func(t *testing.T) {
+		c := &TLS{
+			CertFile:   "server.crt",
+			KeyFile:    "server.key",
+			MinVersion: "1.4",
+		}
+		require.NoError(t, c.Sanitize())
+		require.Error(t, c.Validate())
+	})
+}
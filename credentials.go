@@ -0,0 +1,209 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/redis/go-redis/v9"
+	"github.com/tidwall/redcon"
+)
+
+// passwords are the passwords of the default user. The secondary password is
+// also accepted during a credential rotation.
+type passwords struct {
+	mtx       sync.Mutex
+	primary   string
+	secondary string
+}
+
+// applyPasswords updates the ACL and the internal clients. The caller must hold db.passwords.mtx.
+func (db *Olric) applyPasswords() {
+	p := &db.passwords
+	if p.secondary == "" {
+		db.acl.SetDefaultPasswords(p.primary)
+	} else {
+		db.acl.SetDefaultPasswords(p.primary, p.secondary)
+	}
+	db.client.SetPassword(p.primary)
+}
+
+func (db *Olric) checkAuthentication() error {
+	if !db.config.Authentication.Enabled() {
+		return ErrAuthenticationDisabled
+	}
+	return nil
+}
+
+// addPasswordLocally makes this member accept the password as the secondary password.
+func (db *Olric) addPasswordLocally(password string) error {
+	if err := db.checkAuthentication(); err != nil {
+		return err
+	}
+	if password == "" {
+		return fmt.Errorf("%w: password cannot be empty", protocol.ErrInvalidArgument)
+	}
+
+	p := &db.passwords
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if password == p.primary || password == p.secondary {
+		return nil
+	}
+	if p.secondary != "" {
+		return fmt.Errorf("%w: there is already a secondary password, promote or retire it first", protocol.ErrInvalidArgument)
+	}
+	p.secondary = password
+	db.applyPasswords()
+	db.log.V(2).Printf("[INFO] A secondary password has been added")
+	return nil
+}
+
+// promotePasswordLocally makes the secondary password the primary one. The
+// internal clients start using it, and the old primary password becomes the
+// secondary password.
+func (db *Olric) promotePasswordLocally(password string) error {
+	if err := db.checkAuthentication(); err != nil {
+		return err
+	}
+
+	p := &db.passwords
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if password == p.primary {
+		return nil
+	}
+	if password != p.secondary {
+		return fmt.Errorf("%w: the password is not the secondary password", protocol.ErrInvalidArgument)
+	}
+	p.primary, p.secondary = p.secondary, p.primary
+	db.applyPasswords()
+	db.log.V(2).Printf("[INFO] The secondary password has been promoted")
+	return nil
+}
+
+// retirePasswordLocally removes the secondary password.
+func (db *Olric) retirePasswordLocally(password string) error {
+	if err := db.checkAuthentication(); err != nil {
+		return err
+	}
+
+	p := &db.passwords
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if password == p.primary {
+		return fmt.Errorf("%w: the primary password cannot be retired", protocol.ErrInvalidArgument)
+	}
+	if password != p.secondary {
+		// Already retired
+		return nil
+	}
+	p.secondary = ""
+	db.applyPasswords()
+	db.log.V(2).Printf("[INFO] The secondary password has been retired")
+	return nil
+}
+
+func (db *Olric) addPassword(ctx context.Context, password string) error {
+	if err := db.checkAuthentication(); err != nil {
+		return err
+	}
+	return db.runOnCluster(ctx, func(ctx context.Context) redis.Cmder {
+		return protocol.NewClusterPasswordAdd(password).SetLocal().Command(ctx)
+	})
+}
+
+func (db *Olric) promotePassword(ctx context.Context, password string) error {
+	// All the members must accept the password before any of them starts
+	// using it. Adding it again is a no-op.
+	if err := db.addPassword(ctx, password); err != nil {
+		return err
+	}
+	return db.runOnCluster(ctx, func(ctx context.Context) redis.Cmder {
+		return protocol.NewClusterPasswordPromote(password).SetLocal().Command(ctx)
+	})
+}
+
+func (db *Olric) retirePassword(ctx context.Context, password string) error {
+	if err := db.checkAuthentication(); err != nil {
+		return err
+	}
+	return db.runOnCluster(ctx, func(ctx context.Context) redis.Cmder {
+		return protocol.NewClusterPasswordRetire(password).SetLocal().Command(ctx)
+	})
+}
+
+func (db *Olric) clusterPasswordAddCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	addCmd, err := protocol.ParseClusterPasswordAdd(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	if addCmd.Local {
+		err = db.addPasswordLocally(addCmd.Password)
+	} else {
+		err = db.addPassword(db.ctx, addCmd.Password)
+	}
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteString(protocol.StatusOK)
+}
+
+func (db *Olric) clusterPasswordPromoteCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	promoteCmd, err := protocol.ParseClusterPasswordPromote(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	if promoteCmd.Local {
+		err = db.promotePasswordLocally(promoteCmd.Password)
+	} else {
+		err = db.promotePassword(db.ctx, promoteCmd.Password)
+	}
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteString(protocol.StatusOK)
+}
+
+func (db *Olric) clusterPasswordRetireCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	retireCmd, err := protocol.ParseClusterPasswordRetire(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	if retireCmd.Local {
+		err = db.retirePasswordLocally(retireCmd.Password)
+	} else {
+		err = db.retirePassword(db.ctx, retireCmd.Password)
+	}
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteString(protocol.StatusOK)
+}
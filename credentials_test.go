@@ -0,0 +1,118 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"testing"
+
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfigWithPassword(password string) *config.Config {
+	c := testutil.NewConfig()
+	c.Authentication = &config.Authentication{
+		Password: password,
+	}
+	return c
+}
+
+func TestOlric_Password_Rotation(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db1 := cluster.addMemberWithConfig(t, newTestConfigWithPassword("old-password"))
+	db2 := cluster.addMemberWithConfig(t, newTestConfigWithPassword("old-password"))
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db1.name}, WithPassword("old-password"))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	_, err = NewClusterClient([]string{db1.name}, WithPassword("new-password"))
+	require.ErrorContains(t, err, "wrong password")
+
+	require.NoError(t, c.AddPassword(ctx, "new-password"))
+
+	// Both passwords are accepted during the rotation.
+	c2, err := NewClusterClient([]string{db2.name}, WithPassword("new-password"))
+	require.NoError(t, err)
+	require.NoError(t, c2.Close(ctx))
+
+	// Only one secondary password at a time.
+	err = c.AddPassword(ctx, "another-password")
+	require.ErrorIs(t, err, protocol.ErrInvalidArgument)
+
+	require.NoError(t, c.PromotePassword(ctx, "new-password"))
+
+	// The primary password cannot be retired.
+	err = c.RetirePassword(ctx, "new-password")
+	require.ErrorIs(t, err, protocol.ErrInvalidArgument)
+
+	require.NoError(t, c.RetirePassword(ctx, "old-password"))
+
+	_, err = NewClusterClient([]string{db1.name}, WithPassword("old-password"))
+	require.ErrorContains(t, err, "wrong password")
+
+	// The members connect to each other with the new password.
+	for _, db := range []*Olric{db1, db2} {
+		for _, member := range []*Olric{db1, db2} {
+			addr := member.rt.This().String()
+			require.NoError(t, db.client.Close(addr))
+			_, err = db.ping(ctx, addr, "")
+			require.NoError(t, err)
+		}
+	}
+
+	// The new connections of the cluster client use the promoted password.
+	require.NoError(t, c.RefreshMetadata(ctx))
+	dm, err := c.NewDMap("mydmap")
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, dm.Put(ctx, testutil.ToKey(i), i))
+	}
+}
+
+func TestOlric_Password_Rotation_EmbeddedClient(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMemberWithConfig(t, newTestConfigWithPassword("old-password"))
+
+	ctx := context.Background()
+	e := db.NewEmbeddedClient()
+
+	// PROMOTE adds the password to the members before promoting it.
+	require.NoError(t, e.PromotePassword(ctx, "new-password"))
+	require.True(t, db.acl.Authenticate(config.DefaultUser, "old-password"))
+	require.True(t, db.acl.Authenticate(config.DefaultUser, "new-password"))
+
+	require.NoError(t, e.RetirePassword(ctx, "old-password"))
+	require.False(t, db.acl.Authenticate(config.DefaultUser, "old-password"))
+
+	// Idempotent
+	require.NoError(t, e.RetirePassword(ctx, "old-password"))
+	require.NoError(t, e.PromotePassword(ctx, "new-password"))
+}
+
+func TestOlric_Password_Authentication_Disabled(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMember(t)
+
+	e := db.NewEmbeddedClient()
+	err := e.AddPassword(context.Background(), "new-password")
+	require.ErrorIs(t, err, ErrAuthenticationDisabled)
+}
@@ -16,15 +16,17 @@ package olric
 
 import (
 	"context"
-	"encoding/json"
 	"sync"
 	"time"
 
+	"github.com/olric-data/olric/events"
+	"github.com/olric-data/olric/internal/cluster/partitions"
 	"github.com/olric-data/olric/internal/discovery"
 	"github.com/olric-data/olric/internal/dmap"
 	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/util"
 	"github.com/olric-data/olric/stats"
+	"github.com/redis/go-redis/v9"
 )
 
 // EmbeddedLockContext is returned by Lock and LockWithTimeout methods.
@@ -364,16 +366,76 @@ func (e *EmbeddedClient) Stats(ctx context.Context, address string, options ...S
 	if err = cmd.Err(); err != nil {
 		return stats.Stats{}, processProtocolError(err)
 	}
-	data, err := cmd.Bytes()
-	if err != nil {
-		return stats.Stats{}, processProtocolError(err)
+	return readStatsReply(cmd.Val())
+}
+
+// SlowLog returns the latest count records of the slow log of the member, the newest first.
+// A negative count returns all the records.
+func (e *EmbeddedClient) SlowLog(ctx context.Context, address string, count int) ([]SlowLogEntry, error) {
+	if address == e.db.rt.This().String() {
+		return mapServerSlowLog(e.db.server.SlowLog().Get(count)), nil
 	}
-	var s stats.Stats
-	err = json.Unmarshal(data, &s)
-	if err != nil {
-		return stats.Stats{}, processProtocolError(err)
+	return slowLogGet(ctx, e.db.client.Get(address), count)
+}
+
+// SlowLogLen returns the number of records in the slow log of the member.
+func (e *EmbeddedClient) SlowLogLen(ctx context.Context, address string) (int, error) {
+	if address == e.db.rt.This().String() {
+		return e.db.server.SlowLog().Len(), nil
+	}
+	return slowLogLen(ctx, e.db.client.Get(address))
+}
+
+// SlowLogReset removes all the records of the slow log of the member.
+func (e *EmbeddedClient) SlowLogReset(ctx context.Context, address string) error {
+	if address == e.db.rt.This().String() {
+		e.db.server.SlowLog().Reset()
+		return nil
 	}
-	return s, nil
+	return slowLogReset(ctx, e.db.client.Get(address))
+}
+
+// InstallGossipKey adds the base64 encoded gossip encryption key to the keyrings of
+// all the cluster members. The members keep using their primary keys.
+func (e *EmbeddedClient) InstallGossipKey(ctx context.Context, key string) error {
+	return e.db.installGossipKey(ctx, key)
+}
+
+// UseGossipKey makes the installed gossip encryption key the primary key of all the
+// cluster members. The key must be installed on all the members.
+func (e *EmbeddedClient) UseGossipKey(ctx context.Context, key string) error {
+	return e.db.useGossipKey(ctx, key)
+}
+
+// RemoveGossipKey removes the gossip encryption key from the keyrings of all the
+// cluster members. The primary key cannot be removed.
+func (e *EmbeddedClient) RemoveGossipKey(ctx context.Context, key string) error {
+	return e.db.removeGossipKey(ctx, key)
+}
+
+// GossipKeys returns the fingerprints of the gossip encryption keys that are installed on the
+// cluster members.
+func (e *EmbeddedClient) GossipKeys(ctx context.Context) ([]GossipKey, error) {
+	return e.db.gossipKeys(ctx)
+}
+
+// AddPassword makes all the cluster members accept the password as the
+// secondary password of the default user.
+func (e *EmbeddedClient) AddPassword(ctx context.Context, password string) error {
+	return e.db.addPassword(ctx, password)
+}
+
+// PromotePassword makes the secondary password the primary password of the
+// default user on all the cluster members. The members connect to each
+// other with it, and the old primary password becomes the secondary password.
+func (e *EmbeddedClient) PromotePassword(ctx context.Context, password string) error {
+	return e.db.promotePassword(ctx, password)
+}
+
+// RetirePassword removes the secondary password of the default user from
+// all the cluster members.
+func (e *EmbeddedClient) RetirePassword(ctx context.Context, password string) error {
+	return e.db.retirePassword(ctx, password)
 }
 
 // Close stops background routines and frees allocated resources.
@@ -418,7 +480,21 @@ func (e *EmbeddedClient) Members(_ context.Context) ([]Member, error) {
 
 // NewPubSub returns a new PubSub client with the given options.
 func (e *EmbeddedClient) NewPubSub(options ...PubSubOption) (*PubSub, error) {
-	return newPubSub(e.db.client, options...)
+	return newPubSub(e.db.client, e, options...)
+}
+
+// ClusterEvents subscribes to the cluster events on this member and returns a channel of decoded events.
+func (e *EmbeddedClient) ClusterEvents(ctx context.Context, options ...ClusterEventsOption) (<-chan events.Event, error) {
+	return subscribeClusterEvents(ctx, e.db.client.Get(e.db.name), options...)
+}
+
+// shardOwner returns a client for the partition owner of a sharded channel.
+func (e *EmbeddedClient) shardOwner(channel string) (*redis.Client, error) {
+	if err := e.db.isOperable(); err != nil {
+		return nil, err
+	}
+	part := e.db.primary.PartitionByHKey(partitions.HKey("", channel))
+	return e.db.client.Get(part.Owner().String()), nil
 }
 
 // NewEmbeddedClient creates and returns a new EmbeddedClient instance.
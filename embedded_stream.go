@@ -0,0 +1,146 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"time"
+
+	"github.com/olric-data/olric/internal/stream"
+)
+
+// EmbeddedStream is a Stream client implementation for embedded-member scenario.
+type EmbeddedStream struct {
+	st   *stream.Stream
+	name string
+}
+
+// Name exposes name of the Stream.
+func (s *EmbeddedStream) Name() string {
+	return s.name
+}
+
+// XAdd appends a new entry to the stream and returns its ID.
+func (s *EmbeddedStream) XAdd(ctx context.Context, id string, fields map[string]string, options ...XAddOption) (string, error) {
+	newID, err := s.st.XAdd(ctx, id, fields, newAddConfig(options))
+	if err != nil {
+		return "", convertStreamError(err)
+	}
+	return newID.String(), nil
+}
+
+// XLen returns the number of entries in the stream.
+func (s *EmbeddedStream) XLen(ctx context.Context) (int64, error) {
+	length, err := s.st.XLen(ctx)
+	return length, convertStreamError(err)
+}
+
+// XInfo returns the length, the latest ID and the number of consumer groups of the stream.
+func (s *EmbeddedStream) XInfo(ctx context.Context) (*StreamInfo, error) {
+	info, err := s.st.XInfo(ctx)
+	if err != nil {
+		return nil, convertStreamError(err)
+	}
+	return &StreamInfo{
+		Length: info.Length,
+		LastID: info.LastID.String(),
+		Groups: info.Groups,
+	}, nil
+}
+
+// XRange returns the entries with IDs between start and end, inclusive.
+func (s *EmbeddedStream) XRange(ctx context.Context, start, end string, options ...StreamReadOption) ([]StreamEntry, error) {
+	entries, err := s.st.XRange(ctx, start, end, newStreamReadConfig(options))
+	if err != nil {
+		return nil, convertStreamError(err)
+	}
+	return toStreamEntries(entries), nil
+}
+
+// XRead returns the entries with IDs greater than id.
+func (s *EmbeddedStream) XRead(ctx context.Context, id string, options ...StreamReadOption) ([]StreamEntry, error) {
+	entries, err := s.st.XRead(ctx, id, newStreamReadConfig(options))
+	if err != nil {
+		return nil, convertStreamError(err)
+	}
+	return toStreamEntries(entries), nil
+}
+
+// XGroupCreate creates a new consumer group.
+func (s *EmbeddedStream) XGroupCreate(ctx context.Context, group, id string) error {
+	return convertStreamError(s.st.XGroupCreate(ctx, group, id))
+}
+
+// XReadGroup reads entries on behalf of a consumer in a consumer group.
+func (s *EmbeddedStream) XReadGroup(ctx context.Context, group, consumer, id string, options ...StreamReadOption) ([]StreamEntry, error) {
+	entries, err := s.st.XReadGroup(ctx, group, consumer, id, newStreamReadConfig(options))
+	if err != nil {
+		return nil, convertStreamError(err)
+	}
+	return toStreamEntries(entries), nil
+}
+
+// XAck removes the given entries from the pending entries list of the consumer group.
+func (s *EmbeddedStream) XAck(ctx context.Context, group string, ids ...string) (int, error) {
+	count, err := s.st.XAck(ctx, group, ids...)
+	return count, convertStreamError(err)
+}
+
+// XPending returns the pending entries list of the consumer group.
+func (s *EmbeddedStream) XPending(ctx context.Context, group string) ([]StreamPendingEntry, error) {
+	pending, err := s.st.XPending(ctx, group)
+	if err != nil {
+		return nil, convertStreamError(err)
+	}
+	return toStreamPendingEntries(pending), nil
+}
+
+// XClaim transfers the ownership of the given pending entries to the consumer.
+func (s *EmbeddedStream) XClaim(ctx context.Context, group, consumer string, minIdle time.Duration, ids ...string) ([]StreamEntry, error) {
+	entries, err := s.st.XClaim(ctx, group, consumer, minIdle, ids...)
+	if err != nil {
+		return nil, convertStreamError(err)
+	}
+	return toStreamEntries(entries), nil
+}
+
+// XAutoClaim transfers the ownership of the idle pending entries to the consumer.
+func (s *EmbeddedStream) XAutoClaim(ctx context.Context, group, consumer string, minIdle time.Duration, start string, options ...StreamReadOption) ([]StreamEntry, error) {
+	entries, err := s.st.XAutoClaim(ctx, group, consumer, minIdle, start, newStreamReadConfig(options))
+	if err != nil {
+		return nil, convertStreamError(err)
+	}
+	return toStreamEntries(entries), nil
+}
+
+// Close stops background routines and frees allocated resources.
+func (s *EmbeddedStream) Close(_ context.Context) error {
+	// EmbeddedStream has no background routines.
+	return nil
+}
+
+// NewStream returns a new Stream client.
+func (e *EmbeddedClient) NewStream(name string) (Stream, error) {
+	st, err := e.db.stream.NewStream(name)
+	if err != nil {
+		return nil, convertStreamError(err)
+	}
+	return &EmbeddedStream{
+		st:   st,
+		name: name,
+	}, nil
+}
+
+var _ Stream = (*EmbeddedStream)(nil)
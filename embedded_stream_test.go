@@ -0,0 +1,92 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedClient_Stream_XAdd_XRange(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMember(t)
+
+	ctx := context.Background()
+	e := db.NewEmbeddedClient()
+	st, err := e.NewStream("mystream")
+	require.NoError(t, err)
+
+	var ids []string
+	for i := 0; i < 10; i++ {
+		id, err := st.XAdd(ctx, StreamAutoID, map[string]string{"key": "value"})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	entries, err := st.XRange(ctx, StreamMinID, StreamMaxID, StreamCount(5))
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+	require.Equal(t, ids[0], entries[0].ID)
+	require.Equal(t, map[string]string{"key": "value"}, entries[0].Fields)
+
+	info, err := st.XInfo(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), info.Length)
+	require.Equal(t, ids[9], info.LastID)
+
+	_, err = st.XAdd(ctx, ids[0], map[string]string{"key": "value"})
+	require.ErrorIs(t, err, ErrStreamIDTooSmall)
+}
+
+func TestEmbeddedClient_Stream_ConsumerGroup(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMember(t)
+
+	ctx := context.Background()
+	e := db.NewEmbeddedClient()
+	st, err := e.NewStream("mystream")
+	require.NoError(t, err)
+
+	require.NoError(t, st.XGroupCreate(ctx, "mygroup", StreamLastID))
+	require.ErrorIs(t, st.XGroupCreate(ctx, "mygroup", StreamLastID), ErrGroupExists)
+
+	_, err = st.XPending(ctx, "foobar")
+	require.ErrorIs(t, err, ErrNoSuchGroup)
+
+	id, err := st.XAdd(ctx, StreamAutoID, map[string]string{"key": "value"})
+	require.NoError(t, err)
+
+	entries, err := st.XReadGroup(ctx, "mygroup", "consumer-1", StreamNewEntriesID, Block(time.Second))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, id, entries[0].ID)
+
+	<-time.After(20 * time.Millisecond)
+	entries, err = st.XAutoClaim(ctx, "mygroup", "consumer-2", 10*time.Millisecond, StreamMinID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	pending, err := st.XPending(ctx, "mygroup")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, "consumer-2", pending[0].Consumer)
+
+	count, err := st.XAck(ctx, "mygroup", id)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
@@ -31,6 +31,18 @@ const (
 	KindNodeLeftEvent          = "node-left-event"
 	KindFragmentMigrationEvent = "fragment-migration-event"
 	KindFragmentReceivedEvent  = "fragment-received-event"
+
+	KindPartitionOwnershipChangedEvent = "partition-ownership-changed-event"
+	KindRoutingTableUpdatedEvent       = "routing-table-updated-event"
+	KindDMapCreatedEvent               = "dmap-created-event"
+	KindDMapDestroyedEvent             = "dmap-destroyed-event"
+	KindBalancerStartedEvent           = "balancer-started-event"
+	KindBalancerFinishedEvent          = "balancer-finished-event"
+	KindMemberSuspectEvent             = "member-suspect-event"
+	KindMemberAliveEvent               = "member-alive-event"
+	KindQuorumLostEvent                = "quorum-lost-event"
+	KindQuorumRegainedEvent            = "quorum-regained-event"
+	KindEvictionPressureEvent          = "eviction-pressure-event"
 )
 
 type Event interface {
@@ -209,3 +221,362 @@ func (f *FragmentReceivedEvent) Encode() (string, error) {
 		return value, nil
 	})
 }
+
+// PartitionOwnershipChangedEvent is published by the cluster coordinator when the primary owner of a partition changes.
+type PartitionOwnershipChangedEvent struct {
+	Kind        string `json:"kind"`
+	Source      string `json:"source"`
+	PartitionID uint64 `json:"partition_id"`
+	OldOwner    string `json:"old_owner"`
+	NewOwner    string `json:"new_owner"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+func (p *PartitionOwnershipChangedEvent) Encode() (string, error) {
+	fields := []string{
+		"Timestamp",
+		"Source",
+		"Kind",
+		"PartitionID",
+		"OldOwner",
+		"NewOwner",
+	}
+	return encodeEvent(p, fields, func(r reflect.Value, field string) (interface{}, error) {
+		var value interface{}
+		switch field {
+		case "Timestamp":
+			value = r.FieldByName(field).Int()
+		case "Source", "Kind", "OldOwner", "NewOwner":
+			value = r.FieldByName(field).String()
+		case "PartitionID":
+			value = r.FieldByName(field).Uint()
+		default:
+			return nil, fmt.Errorf("invalid field: %s", field)
+		}
+		return value, nil
+	})
+}
+
+// RoutingTableUpdatedEvent is published by a member when it applies a new routing table.
+type RoutingTableUpdatedEvent struct {
+	Kind        string `json:"kind"`
+	Source      string `json:"source"`
+	Coordinator string `json:"coordinator"`
+	Signature   uint64 `json:"signature"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+func (rt *RoutingTableUpdatedEvent) Encode() (string, error) {
+	fields := []string{
+		"Timestamp",
+		"Source",
+		"Kind",
+		"Coordinator",
+		"Signature",
+	}
+	return encodeEvent(rt, fields, func(r reflect.Value, field string) (interface{}, error) {
+		var value interface{}
+		switch field {
+		case "Timestamp":
+			value = r.FieldByName(field).Int()
+		case "Source", "Kind", "Coordinator":
+			value = r.FieldByName(field).String()
+		case "Signature":
+			value = r.FieldByName(field).Uint()
+		default:
+			return nil, fmt.Errorf("invalid field: %s", field)
+		}
+		return value, nil
+	})
+}
+
+// DMapCreatedEvent is published by a member when it creates its local DMap
+// instance. The members create the instances lazily, with the first request
+// for the DMap that they serve, including the requests that are routed from
+// the other members. So a DMap that is used on N members produces N events,
+// one for each Source, and a member publishes it again after a restart.
+type DMapCreatedEvent struct {
+	Kind      string `json:"kind"`
+	Source    string `json:"source"`
+	DMap      string `json:"dmap"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (d *DMapCreatedEvent) Encode() (string, error) {
+	fields := []string{
+		"Timestamp",
+		"Source",
+		"Kind",
+		"DMap",
+	}
+	return encodeEvent(d, fields, func(r reflect.Value, field string) (interface{}, error) {
+		var value interface{}
+		switch field {
+		case "Timestamp":
+			value = r.FieldByName(field).Int()
+		case "Source", "Kind", "DMap":
+			value = r.FieldByName(field).String()
+		default:
+			return nil, fmt.Errorf("invalid field: %s", field)
+		}
+		return value, nil
+	})
+}
+
+// DMapDestroyedEvent is published by the member that destroys a DMap on the cluster.
+type DMapDestroyedEvent struct {
+	Kind      string `json:"kind"`
+	Source    string `json:"source"`
+	DMap      string `json:"dmap"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (d *DMapDestroyedEvent) Encode() (string, error) {
+	fields := []string{
+		"Timestamp",
+		"Source",
+		"Kind",
+		"DMap",
+	}
+	return encodeEvent(d, fields, func(r reflect.Value, field string) (interface{}, error) {
+		var value interface{}
+		switch field {
+		case "Timestamp":
+			value = r.FieldByName(field).Int()
+		case "Source", "Kind", "DMap":
+			value = r.FieldByName(field).String()
+		default:
+			return nil, fmt.Errorf("invalid field: %s", field)
+		}
+		return value, nil
+	})
+}
+
+// BalancerStartedEvent is published by a member when the balancer starts to work on a new routing table.
+type BalancerStartedEvent struct {
+	Kind      string `json:"kind"`
+	Source    string `json:"source"`
+	Signature uint64 `json:"signature"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (b *BalancerStartedEvent) Encode() (string, error) {
+	fields := []string{
+		"Timestamp",
+		"Source",
+		"Kind",
+		"Signature",
+	}
+	return encodeEvent(b, fields, func(r reflect.Value, field string) (interface{}, error) {
+		var value interface{}
+		switch field {
+		case "Timestamp":
+			value = r.FieldByName(field).Int()
+		case "Source", "Kind":
+			value = r.FieldByName(field).String()
+		case "Signature":
+			value = r.FieldByName(field).Uint()
+		default:
+			return nil, fmt.Errorf("invalid field: %s", field)
+		}
+		return value, nil
+	})
+}
+
+// BalancerFinishedEvent is published by a member when the balancer finishes. Aborted is true if the routing table has changed in the meantime.
+type BalancerFinishedEvent struct {
+	Kind      string `json:"kind"`
+	Source    string `json:"source"`
+	Signature uint64 `json:"signature"`
+	Duration  int64  `json:"duration"`
+	Aborted   bool   `json:"aborted"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (b *BalancerFinishedEvent) Encode() (string, error) {
+	fields := []string{
+		"Timestamp",
+		"Source",
+		"Kind",
+		"Signature",
+		"Duration",
+		"Aborted",
+	}
+	return encodeEvent(b, fields, func(r reflect.Value, field string) (interface{}, error) {
+		var value interface{}
+		switch field {
+		case "Timestamp", "Duration":
+			value = r.FieldByName(field).Int()
+		case "Source", "Kind":
+			value = r.FieldByName(field).String()
+		case "Signature":
+			value = r.FieldByName(field).Uint()
+		case "Aborted":
+			value = r.FieldByName(field).Bool()
+		default:
+			return nil, fmt.Errorf("invalid field: %s", field)
+		}
+		return value, nil
+	})
+}
+
+// MemberSuspectEvent is published by a member when the failure detector suspects another member.
+type MemberSuspectEvent struct {
+	Kind      string `json:"kind"`
+	Source    string `json:"source"`
+	Member    string `json:"member"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (m *MemberSuspectEvent) Encode() (string, error) {
+	fields := []string{
+		"Timestamp",
+		"Source",
+		"Kind",
+		"Member",
+	}
+	return encodeEvent(m, fields, func(r reflect.Value, field string) (interface{}, error) {
+		var value interface{}
+		switch field {
+		case "Timestamp":
+			value = r.FieldByName(field).Int()
+		case "Source", "Kind", "Member":
+			value = r.FieldByName(field).String()
+		default:
+			return nil, fmt.Errorf("invalid field: %s", field)
+		}
+		return value, nil
+	})
+}
+
+// MemberAliveEvent is published by a member when a suspected member is alive again.
+type MemberAliveEvent struct {
+	Kind      string `json:"kind"`
+	Source    string `json:"source"`
+	Member    string `json:"member"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (m *MemberAliveEvent) Encode() (string, error) {
+	fields := []string{
+		"Timestamp",
+		"Source",
+		"Kind",
+		"Member",
+	}
+	return encodeEvent(m, fields, func(r reflect.Value, field string) (interface{}, error) {
+		var value interface{}
+		switch field {
+		case "Timestamp":
+			value = r.FieldByName(field).Int()
+		case "Source", "Kind", "Member":
+			value = r.FieldByName(field).String()
+		default:
+			return nil, fmt.Errorf("invalid field: %s", field)
+		}
+		return value, nil
+	})
+}
+
+// QuorumLostEvent is published by a member when the member count falls below MemberCountQuorum.
+type QuorumLostEvent struct {
+	Kind              string `json:"kind"`
+	Source            string `json:"source"`
+	MemberCount       int32  `json:"member_count"`
+	MemberCountQuorum int32  `json:"member_count_quorum"`
+	Timestamp         int64  `json:"timestamp"`
+}
+
+func (q *QuorumLostEvent) Encode() (string, error) {
+	fields := []string{
+		"Timestamp",
+		"Source",
+		"Kind",
+		"MemberCount",
+		"MemberCountQuorum",
+	}
+	return encodeEvent(q, fields, func(r reflect.Value, field string) (interface{}, error) {
+		var value interface{}
+		switch field {
+		case "Timestamp", "MemberCount", "MemberCountQuorum":
+			value = r.FieldByName(field).Int()
+		case "Source", "Kind":
+			value = r.FieldByName(field).String()
+		default:
+			return nil, fmt.Errorf("invalid field: %s", field)
+		}
+		return value, nil
+	})
+}
+
+// QuorumRegainedEvent is published by a member when the member count satisfies MemberCountQuorum again.
+type QuorumRegainedEvent struct {
+	Kind              string `json:"kind"`
+	Source            string `json:"source"`
+	MemberCount       int32  `json:"member_count"`
+	MemberCountQuorum int32  `json:"member_count_quorum"`
+	Timestamp         int64  `json:"timestamp"`
+}
+
+func (q *QuorumRegainedEvent) Encode() (string, error) {
+	fields := []string{
+		"Timestamp",
+		"Source",
+		"Kind",
+		"MemberCount",
+		"MemberCountQuorum",
+	}
+	return encodeEvent(q, fields, func(r reflect.Value, field string) (interface{}, error) {
+		var value interface{}
+		switch field {
+		case "Timestamp", "MemberCount", "MemberCountQuorum":
+			value = r.FieldByName(field).Int()
+		case "Source", "Kind":
+			value = r.FieldByName(field).String()
+		default:
+			return nil, fmt.Errorf("invalid field: %s", field)
+		}
+		return value, nil
+	})
+}
+
+// EvictionPressureEvent is published by a member when the LRU eviction starts to remove keys to satisfy MaxKeys or MaxInuse. It's published at most once in a while for every DMap.
+type EvictionPressureEvent struct {
+	Kind        string `json:"kind"`
+	Source      string `json:"source"`
+	DMap        string `json:"dmap"`
+	PartitionID uint64 `json:"partition_id"`
+	Reason      string `json:"reason"`
+	Length      int    `json:"length"`
+	Inuse       int    `json:"inuse"`
+	Limit       int    `json:"limit"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+func (e *EvictionPressureEvent) Encode() (string, error) {
+	fields := []string{
+		"Timestamp",
+		"Source",
+		"Kind",
+		"DMap",
+		"PartitionID",
+		"Reason",
+		"Length",
+		"Inuse",
+		"Limit",
+	}
+	return encodeEvent(e, fields, func(r reflect.Value, field string) (interface{}, error) {
+		var value interface{}
+		switch field {
+		case "Timestamp", "Length", "Inuse", "Limit":
+			value = r.FieldByName(field).Int()
+		case "Source", "Kind", "DMap", "Reason":
+			value = r.FieldByName(field).String()
+		case "PartitionID":
+			value = r.FieldByName(field).Uint()
+		default:
+			return nil, fmt.Errorf("invalid field: %s", field)
+		}
+		return value, nil
+	})
+}
@@ -82,3 +82,53 @@ func TestClusterEvents_FragmentReceivedEvent(t *testing.T) {
 	expected := `{"timestamp":585199808000,"source":"127.0.0.1:3423","kind":"fragment-received-event","data_structure":"dmap","partition_id":123,"identifier":"mydmap","is_backup":false,"length":1234}`
 	require.Equal(t, expected, result)
 }
+
+func TestClusterEvents_PartitionOwnershipChangedEvent(t *testing.T) {
+	var timestamp int64 = 585199808000 // Author's birthdate!
+	n := PartitionOwnershipChangedEvent{
+		Kind:        KindPartitionOwnershipChangedEvent,
+		Source:      "127.0.0.1:3423",
+		PartitionID: 123,
+		OldOwner:    "127.0.0.1:3423",
+		NewOwner:    "127.0.0.1:3576",
+		Timestamp:   timestamp,
+	}
+	result, err := n.Encode()
+	require.NoError(t, err)
+	expected := `{"timestamp":585199808000,"source":"127.0.0.1:3423","kind":"partition-ownership-changed-event","partition_id":123,"old_owner":"127.0.0.1:3423","new_owner":"127.0.0.1:3576"}`
+	require.Equal(t, expected, result)
+}
+
+func TestClusterEvents_BalancerFinishedEvent(t *testing.T) {
+	var timestamp int64 = 585199808000 // Author's birthdate!
+	n := BalancerFinishedEvent{
+		Kind:      KindBalancerFinishedEvent,
+		Source:    "127.0.0.1:3423",
+		Signature: 42,
+		Duration:  1500,
+		Timestamp: timestamp,
+	}
+	result, err := n.Encode()
+	require.NoError(t, err)
+	expected := `{"timestamp":585199808000,"source":"127.0.0.1:3423","kind":"balancer-finished-event","signature":42,"duration":1500,"aborted":false}`
+	require.Equal(t, expected, result)
+}
+
+func TestClusterEvents_EvictionPressureEvent(t *testing.T) {
+	var timestamp int64 = 585199808000 // Author's birthdate!
+	n := EvictionPressureEvent{
+		Kind:        KindEvictionPressureEvent,
+		Source:      "127.0.0.1:3423",
+		DMap:        "mydmap",
+		PartitionID: 123,
+		Reason:      "max-inuse",
+		Length:      10,
+		Inuse:       4096,
+		Limit:       2048,
+		Timestamp:   timestamp,
+	}
+	result, err := n.Encode()
+	require.NoError(t, err)
+	expected := `{"timestamp":585199808000,"source":"127.0.0.1:3423","kind":"eviction-pressure-event","dmap":"mydmap","partition_id":123,"reason":"max-inuse","length":10,"inuse":4096,"limit":2048}`
+	require.Equal(t, expected, result)
+}
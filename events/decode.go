@@ -0,0 +1,80 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnknownKind denotes an event with an unknown kind.
+var ErrUnknownKind = errors.New("unknown event kind")
+
+// registry maps the event kinds to the event types.
+var registry = map[string]func() Event{
+	KindNodeJoinEvent:          func() Event { return &NodeJoinEvent{} },
+	KindNodeLeftEvent:          func() Event { return &NodeLeftEvent{} },
+	KindFragmentMigrationEvent: func() Event { return &FragmentMigrationEvent{} },
+	KindFragmentReceivedEvent:  func() Event { return &FragmentReceivedEvent{} },
+
+	KindPartitionOwnershipChangedEvent: func() Event { return &PartitionOwnershipChangedEvent{} },
+	KindRoutingTableUpdatedEvent:       func() Event { return &RoutingTableUpdatedEvent{} },
+	KindDMapCreatedEvent:               func() Event { return &DMapCreatedEvent{} },
+	KindDMapDestroyedEvent:             func() Event { return &DMapDestroyedEvent{} },
+	KindBalancerStartedEvent:           func() Event { return &BalancerStartedEvent{} },
+	KindBalancerFinishedEvent:          func() Event { return &BalancerFinishedEvent{} },
+	KindMemberSuspectEvent:             func() Event { return &MemberSuspectEvent{} },
+	KindMemberAliveEvent:               func() Event { return &MemberAliveEvent{} },
+	KindQuorumLostEvent:                func() Event { return &QuorumLostEvent{} },
+	KindQuorumRegainedEvent:            func() Event { return &QuorumRegainedEvent{} },
+	KindEvictionPressureEvent:          func() Event { return &EvictionPressureEvent{} },
+}
+
+// Decode decodes the JSON representation of an event, as published to
+// ClusterEventsChannel, into its typed value. The returned value is a pointer
+// to one of the event types, e.g. *NodeJoinEvent.
+func Decode(data string) (Event, error) {
+	var header struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal([]byte(data), &header); err != nil {
+		return nil, err
+	}
+
+	newEvent, ok := registry[header.Kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, header.Kind)
+	}
+	e := newEvent()
+	if err := json.Unmarshal([]byte(data), e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// KindOf returns the kind of an event.
+func KindOf(e Event) string {
+	r := reflect.Indirect(reflect.ValueOf(e))
+	if r.Kind() != reflect.Struct {
+		return ""
+	}
+	kind := r.FieldByName("Kind")
+	if !kind.IsValid() || kind.Kind() != reflect.String {
+		return ""
+	}
+	return kind.String()
+}
@@ -0,0 +1,128 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterEvents_Decode(t *testing.T) {
+	var timestamp int64 = 585199808000
+	tests := []Event{
+		&NodeJoinEvent{
+			Kind:      KindNodeJoinEvent,
+			Source:    "127.0.0.1:3423",
+			NodeJoin:  "127.0.0.1:3576",
+			Timestamp: timestamp,
+		},
+		&NodeLeftEvent{
+			Kind:      KindNodeLeftEvent,
+			Source:    "127.0.0.1:3423",
+			NodeLeft:  "127.0.0.1:3576",
+			Timestamp: timestamp,
+		},
+		&FragmentMigrationEvent{
+			Kind:          KindFragmentMigrationEvent,
+			Source:        "127.0.0.1:3423",
+			Target:        "127.0.0.1:3576",
+			Identifier:    "mydmap",
+			PartitionID:   123,
+			DataStructure: "dmap",
+			Length:        1234,
+			IsBackup:      true,
+			Timestamp:     timestamp,
+		},
+		&FragmentReceivedEvent{
+			Kind:          KindFragmentReceivedEvent,
+			Source:        "127.0.0.1:3423",
+			Identifier:    "mydmap",
+			PartitionID:   123,
+			DataStructure: "dmap",
+			Length:        1234,
+			Timestamp:     timestamp,
+		},
+		&PartitionOwnershipChangedEvent{
+			Kind:        KindPartitionOwnershipChangedEvent,
+			Source:      "127.0.0.1:3423",
+			PartitionID: 123,
+			OldOwner:    "127.0.0.1:3423",
+			NewOwner:    "127.0.0.1:3576",
+			Timestamp:   timestamp,
+		},
+		&RoutingTableUpdatedEvent{
+			Kind:        KindRoutingTableUpdatedEvent,
+			Source:      "127.0.0.1:3576",
+			Coordinator: "127.0.0.1:3423",
+			Signature:   18446744073709551615,
+			Timestamp:   timestamp,
+		},
+		&DMapCreatedEvent{Kind: KindDMapCreatedEvent, Source: "127.0.0.1:3423", DMap: "mydmap", Timestamp: timestamp},
+		&DMapDestroyedEvent{Kind: KindDMapDestroyedEvent, Source: "127.0.0.1:3423", DMap: "mydmap", Timestamp: timestamp},
+		&BalancerStartedEvent{Kind: KindBalancerStartedEvent, Source: "127.0.0.1:3423", Signature: 42, Timestamp: timestamp},
+		&BalancerFinishedEvent{
+			Kind:      KindBalancerFinishedEvent,
+			Source:    "127.0.0.1:3423",
+			Signature: 42,
+			Duration:  1500,
+			Aborted:   true,
+			Timestamp: timestamp,
+		},
+		&MemberSuspectEvent{Kind: KindMemberSuspectEvent, Source: "127.0.0.1:3423", Member: "127.0.0.1:3576", Timestamp: timestamp},
+		&MemberAliveEvent{Kind: KindMemberAliveEvent, Source: "127.0.0.1:3423", Member: "127.0.0.1:3576", Timestamp: timestamp},
+		&QuorumLostEvent{
+			Kind:              KindQuorumLostEvent,
+			Source:            "127.0.0.1:3423",
+			MemberCount:       1,
+			MemberCountQuorum: 2,
+			Timestamp:         timestamp,
+		},
+		&QuorumRegainedEvent{
+			Kind:              KindQuorumRegainedEvent,
+			Source:            "127.0.0.1:3423",
+			MemberCount:       2,
+			MemberCountQuorum: 2,
+			Timestamp:         timestamp,
+		},
+		&EvictionPressureEvent{
+			Kind:        KindEvictionPressureEvent,
+			Source:      "127.0.0.1:3423",
+			DMap:        "mydmap",
+			PartitionID: 123,
+			Reason:      "max-keys",
+			Length:      100,
+			Inuse:       4096,
+			Limit:       100,
+			Timestamp:   timestamp,
+		},
+	}
+
+	for _, expected := range tests {
+		data, err := expected.Encode()
+		require.NoError(t, err)
+
+		e, err := Decode(data)
+		require.NoError(t, err)
+		require.Equal(t, expected, e)
+		require.Equal(t, KindOf(expected), KindOf(e))
+	}
+}
+
+func TestClusterEvents_Decode_Unknown_Kind(t *testing.T) {
+	_, err := Decode(`{"kind":"foobar-event"}`)
+	require.True(t, errors.Is(err, ErrUnknownKind))
+}
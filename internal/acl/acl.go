@@ -0,0 +1,330 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*Package acl implements the access control lists of the users and their rules.*/
+package acl
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/tidwall/match"
+)
+
+// Permission is the access level on a DMap.
+type Permission uint8
+
+const (
+	// Read allows the commands that read from a DMap.
+	Read Permission = 1 << iota
+
+	// Write allows the commands that modify a DMap.
+	Write
+
+	// ReadWrite allows all the commands on a DMap.
+	ReadWrite = Read | Write
+)
+
+// keyPattern is a glob pattern of DMap names with an access level.
+type keyPattern struct {
+	pattern    string
+	permission Permission
+}
+
+// User is a user with hashed passwords and permissions.
+type User struct {
+	name      string
+	passwords [][]byte
+	rules     []string
+
+	// unrestricted users can run all the commands without any checks.
+	unrestricted bool
+	commands     map[string]bool
+	dmaps        []keyPattern
+	channels     []string
+}
+
+// NewUser creates a user with the given hex encoded SHA-256 password hashes and ACL rules.
+// The rules are applied in order, and a user has no permissions without them.
+func NewUser(name string, passwords, rules []string) (*User, error) {
+	u := &User{
+		name:     name,
+		rules:    rules,
+		commands: make(map[string]bool),
+	}
+	for _, password := range passwords {
+		hash, err := hex.DecodeString(password)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("invalid password hash of user %s", name)
+		}
+		u.passwords = append(u.passwords, hash)
+	}
+	for _, rule := range rules {
+		if err := u.applyRule(rule); err != nil {
+			return nil, fmt.Errorf("invalid rule of user %s: '%s': %w", name, rule, err)
+		}
+	}
+	return u, nil
+}
+
+// newDefaultUser creates the default user. It has all the permissions.
+func newDefaultUser(passwords ...string) *User {
+	u := &User{
+		name:         config.DefaultUser,
+		rules:        []string{"allcommands", "allkeys", "allchannels"},
+		unrestricted: true,
+	}
+	for _, password := range passwords {
+		hash := sha256.Sum256([]byte(password))
+		u.passwords = append(u.passwords, hash[:])
+	}
+	return u
+}
+
+// Name returns the name of the user.
+func (u *User) Name() string {
+	return u.name
+}
+
+func (u *User) setCommands(allowed bool, f func(c command) bool) {
+	for name, c := range commands {
+		if f(c) {
+			u.commands[name] = allowed
+		}
+	}
+}
+
+func (u *User) applyRule(rule string) error {
+	switch rule {
+	case "allcommands":
+		rule = "+@all"
+	case "nocommands":
+		rule = "-@all"
+	case "allkeys":
+		rule = "~*"
+	case "allchannels":
+		rule = "&*"
+	case "resetkeys":
+		u.dmaps = nil
+		return nil
+	case "resetchannels":
+		u.channels = nil
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(rule, "+") || strings.HasPrefix(rule, "-"):
+		allowed := rule[0] == '+'
+		name := strings.ToLower(rule[1:])
+		if strings.HasPrefix(name, "@") {
+			category := name[1:]
+			if !isCategory(category) {
+				return fmt.Errorf("unknown category")
+			}
+			u.setCommands(allowed, func(c command) bool {
+				// -@all denies the internal commands, too.
+				return c.hasCategory(category) || (!allowed && category == CategoryAll)
+			})
+			return nil
+		}
+		// Subcommands are written as "slowlog|get", like Redis does.
+		name = strings.ReplaceAll(name, "|", " ")
+		if _, ok := commands[name]; !ok {
+			return fmt.Errorf("unknown command")
+		}
+		u.commands[name] = allowed
+	case strings.HasPrefix(rule, "&"):
+		u.channels = append(u.channels, rule[1:])
+	case strings.HasPrefix(rule, "~"):
+		u.dmaps = append(u.dmaps, keyPattern{pattern: rule[1:], permission: ReadWrite})
+	case strings.HasPrefix(rule, "%"):
+		i := strings.Index(rule, "~")
+		if i < 0 {
+			return fmt.Errorf("missing DMap pattern")
+		}
+		var permission Permission
+		for _, p := range strings.ToUpper(rule[1:i]) {
+			switch p {
+			case 'R':
+				permission |= Read
+			case 'W':
+				permission |= Write
+			default:
+				return fmt.Errorf("unknown permission: %c", p)
+			}
+		}
+		if permission == 0 {
+			return fmt.Errorf("missing permission")
+		}
+		u.dmaps = append(u.dmaps, keyPattern{pattern: rule[i+1:], permission: permission})
+	default:
+		return fmt.Errorf("syntax error")
+	}
+	return nil
+}
+
+// checkPassword compares the hash of the password with the hashes of the user.
+func (u *User) checkPassword(password string) bool {
+	hash := sha256.Sum256([]byte(password))
+	var ok bool
+	for _, h := range u.passwords {
+		// Check all the hashes to not leak which one matches.
+		if subtle.ConstantTimeCompare(h, hash[:]) == 1 {
+			ok = true
+		}
+	}
+	return ok
+}
+
+func (u *User) canAccessDMap(name string, permission Permission) bool {
+	for _, p := range u.dmaps {
+		if p.permission&permission == permission && match.Match(name, p.pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *User) canAccessChannel(channel string, pattern bool) bool {
+	for _, p := range u.channels {
+		// A pattern subscription is only allowed if the user can access all the
+		// channels, or the same pattern is in the rules.
+		if pattern && (p == "*" || p == channel) {
+			return true
+		}
+		if !pattern && match.Match(channel, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// ACL holds the users and checks their permissions. It's safe for concurrent use.
+type ACL struct {
+	mtx      sync.RWMutex
+	users    map[string]*User
+	dmapName func(key string) string
+}
+
+// New creates an ACL from the authentication configuration. The default user
+// authenticates with c.Password. dmapName returns the DMap name of a key of the
+// Redis compatibility layer.
+func New(c *config.Authentication, dmapName func(key string) string) (*ACL, error) {
+	a := &ACL{
+		users:    make(map[string]*User),
+		dmapName: dmapName,
+	}
+	passwords := []string{c.Password}
+	if c.SecondaryPassword != "" {
+		passwords = append(passwords, c.SecondaryPassword)
+	}
+	a.users[config.DefaultUser] = newDefaultUser(passwords...)
+	for _, cu := range c.Users {
+		u, err := NewUser(cu.Name, cu.Passwords, cu.Rules)
+		if err != nil {
+			return nil, err
+		}
+		a.users[u.name] = u
+	}
+	return a, nil
+}
+
+// SetDefaultPasswords replaces the passwords of the default user. The
+// connections that are already authenticated are not affected.
+func (a *ACL) SetDefaultPasswords(passwords ...string) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.users[config.DefaultUser] = newDefaultUser(passwords...)
+}
+
+func (a *ACL) user(username string) (*User, bool) {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	u, ok := a.users[username]
+	return u, ok
+}
+
+// Authenticate returns true if the password belongs to the user.
+func (a *ACL) Authenticate(username, password string) bool {
+	u, ok := a.user(username)
+	if !ok {
+		return false
+	}
+	return u.checkPassword(password)
+}
+
+// Check returns an error that wraps protocol.ErrNoPerm if the user is not
+// allowed to run the command with the given arguments.
+func (a *ACL) Check(username, name string, args [][]byte) error {
+	if _, ok := alwaysAllowed[name]; ok {
+		return nil
+	}
+
+	u, ok := a.user(username)
+	if !ok {
+		return fmt.Errorf("%w: unknown user '%s'", protocol.ErrNoPerm, username)
+	}
+	if u.unrestricted {
+		return nil
+	}
+
+	c, ok := commands[name]
+	if !ok || !u.commands[name] {
+		return fmt.Errorf("%w: user '%s' has no permissions to run the '%s' command", protocol.ErrNoPerm, username, name)
+	}
+
+	if c.dmaps != nil {
+		for _, dmap := range c.dmaps(a.dmapName, args) {
+			if !u.canAccessDMap(dmap, c.permission) {
+				return fmt.Errorf("%w: user '%s' has no permissions to access the '%s' DMap", protocol.ErrNoPerm, username, dmap)
+			}
+		}
+	}
+
+	if c.channels != nil {
+		for _, channel := range c.channels(args) {
+			if !u.canAccessChannel(channel, c.patterns) {
+				return fmt.Errorf("%w: user '%s' has no permissions to access the '%s' channel", protocol.ErrNoPerm, username, channel)
+			}
+		}
+	}
+	return nil
+}
+
+// List returns the users and their rules in the format of ACL LIST, sorted by name.
+func (a *ACL) List() []string {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+
+	var result []string
+	for _, u := range a.users {
+		fields := []string{"user", u.name}
+		for _, h := range u.passwords {
+			fields = append(fields, "#"+hex.EncodeToString(h))
+		}
+		fields = append(fields, u.rules...)
+		result = append(result, strings.Join(fields, " "))
+	}
+	sort.Strings(result)
+	return result
+}
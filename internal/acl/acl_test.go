@@ -0,0 +1,192 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func args(command string) [][]byte {
+	var result [][]byte
+	for _, arg := range strings.Fields(command) {
+		result = append(result, []byte(arg))
+	}
+	return result
+}
+
+func newTestACL(t *testing.T, rules ...string) *ACL {
+	c := &config.Authentication{
+		Password: "secret",
+		Users: []config.User{{
+			Name:      "alice",
+			Passwords: []string{config.HashPassword("alice-password")},
+			Rules:     rules,
+		}},
+	}
+	rc := &config.RedisCompat{DefaultDMap: "default", KeyPrefixSeparator: ":"}
+	a, err := New(c, rc.DMapName)
+	require.NoError(t, err)
+	return a
+}
+
+func TestACL_Authenticate(t *testing.T) {
+	a := newTestACL(t)
+
+	require.True(t, a.Authenticate(config.DefaultUser, "secret"))
+	require.True(t, a.Authenticate("alice", "alice-password"))
+	require.False(t, a.Authenticate("alice", "secret"))
+	require.False(t, a.Authenticate("bob", "alice-password"))
+}
+
+func TestACL_Check_DefaultUser(t *testing.T) {
+	a := newTestACL(t)
+
+	require.NoError(t, a.Check(config.DefaultUser, protocol.DMap.Destroy, args("dm.destroy mydmap")))
+	require.NoError(t, a.Check(config.DefaultUser, protocol.DMap.PutEntry, args("dm.putentry mydmap key value")))
+}
+
+func TestACL_Check_Commands(t *testing.T) {
+	a := newTestACL(t, "+@all", "-dm.destroy", "-slowlog|reset", "allkeys")
+
+	require.NoError(t, a.Check("alice", protocol.DMap.Put, args("dm.put mydmap key value")))
+	require.NoError(t, a.Check("alice", protocol.Generic.SlowLogGet, args("slowlog get")))
+	require.ErrorIs(t, a.Check("alice", protocol.DMap.Destroy, args("dm.destroy mydmap")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.Generic.SlowLogReset, args("slowlog reset")), protocol.ErrNoPerm)
+
+	// Always allowed
+	require.NoError(t, a.Check("alice", protocol.Generic.ACLWhoAmI, args("acl whoami")))
+}
+
+func TestACL_Check_Internal_Commands(t *testing.T) {
+	a := newTestACL(t, "+@all", "allkeys", "allchannels")
+
+	require.ErrorIs(t, a.Check("alice", protocol.Internal.UpdateRouting, args("internal.node.updaterouting 1")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.DMap.PutEntry, args("dm.putentry mydmap key value")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.PubSub.PublishInternal, args("publish.internal events message")), protocol.ErrNoPerm)
+
+	a = newTestACL(t, "+@all", "+@internal", "allkeys")
+	require.NoError(t, a.Check("alice", protocol.Internal.UpdateRouting, args("internal.node.updaterouting 1")))
+
+	// -@all denies the internal commands, too.
+	a = newTestACL(t, "+@internal", "-@all")
+	require.ErrorIs(t, a.Check("alice", protocol.Internal.UpdateRouting, args("internal.node.updaterouting 1")), protocol.ErrNoPerm)
+}
+
+func TestACL_Check_No_Permissions(t *testing.T) {
+	a := newTestACL(t)
+
+	require.ErrorIs(t, a.Check("alice", protocol.DMap.Get, args("dm.get mydmap key")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.Generic.Ping, args("ping")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("bob", protocol.Generic.Ping, args("ping")), protocol.ErrNoPerm)
+}
+
+func TestACL_Check_DMaps(t *testing.T) {
+	a := newTestACL(t, "+@dmap", "%R~sessions:*", "~cache.*")
+
+	require.NoError(t, a.Check("alice", protocol.DMap.Get, args("dm.get sessions:eu key")))
+	require.NoError(t, a.Check("alice", protocol.DMap.Scan, args("dm.scan 0 sessions:eu 0")))
+	require.ErrorIs(t, a.Check("alice", protocol.DMap.Put, args("dm.put sessions:eu key value")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.DMap.Incr, args("dm.incr sessions:eu key 1")), protocol.ErrNoPerm)
+
+	require.NoError(t, a.Check("alice", protocol.DMap.Put, args("dm.put cache.users key value")))
+	require.NoError(t, a.Check("alice", protocol.DMap.Incr, args("dm.incr cache.users key 1")))
+
+	require.ErrorIs(t, a.Check("alice", protocol.DMap.Get, args("dm.get users key")), protocol.ErrNoPerm)
+}
+
+func TestACL_Check_Write_Only(t *testing.T) {
+	a := newTestACL(t, "+@dmap", "%W~logs")
+
+	require.NoError(t, a.Check("alice", protocol.DMap.Put, args("dm.put logs key value")))
+	require.ErrorIs(t, a.Check("alice", protocol.DMap.Get, args("dm.get logs key")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.DMap.GetPut, args("dm.getput logs key value")), protocol.ErrNoPerm)
+}
+
+func TestACL_Check_Redis_Keys(t *testing.T) {
+	a := newTestACL(t, "+@redis", "%R~users", "%RW~default")
+
+	require.NoError(t, a.Check("alice", protocol.Redis.Get, args("get users:42")))
+	require.NoError(t, a.Check("alice", protocol.Redis.Set, args("set counter 1")))
+	require.NoError(t, a.Check("alice", protocol.Redis.MGet, args("mget users:1 users:2 counter")))
+	require.NoError(t, a.Check("alice", protocol.Redis.Scan, args("scan 0 MATCH users:*")))
+	require.ErrorIs(t, a.Check("alice", protocol.Redis.Set, args("set users:42 value")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.Redis.Del, args("del counter users:42")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.Redis.MGet, args("mget users:1 orders:1")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.Redis.Scan, args("scan 0 MATCH orders:*")), protocol.ErrNoPerm)
+}
+
+func TestACL_Check_Channels(t *testing.T) {
+	a := newTestACL(t, "+@pubsub", "&events.*", "&orders")
+
+	require.NoError(t, a.Check("alice", protocol.PubSub.Publish, args("publish events.user message")))
+	require.NoError(t, a.Check("alice", protocol.PubSub.Subscribe, args("subscribe events.user orders")))
+	require.NoError(t, a.Check("alice", protocol.PubSub.PSubscribe, args("psubscribe events.*")))
+	require.NoError(t, a.Check("alice", protocol.PubSub.PubSubResume, args("pubsub resume orders 10 events.user 5")))
+
+	require.ErrorIs(t, a.Check("alice", protocol.PubSub.Publish, args("publish payments message")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.PubSub.Subscribe, args("subscribe orders payments")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.PubSub.PSubscribe, args("psubscribe *")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.PubSub.PubSubResume, args("pubsub resume payments 10")), protocol.ErrNoPerm)
+}
+
+func TestACL_Check_Reset(t *testing.T) {
+	a := newTestACL(t, "allcommands", "allkeys", "allchannels", "resetkeys", "resetchannels", "-@all", "+dm.get", "~mydmap")
+
+	require.NoError(t, a.Check("alice", protocol.DMap.Get, args("dm.get mydmap key")))
+	require.ErrorIs(t, a.Check("alice", protocol.DMap.Get, args("dm.get other key")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.DMap.Put, args("dm.put mydmap key value")), protocol.ErrNoPerm)
+	require.ErrorIs(t, a.Check("alice", protocol.PubSub.Publish, args("publish events message")), protocol.ErrNoPerm)
+}
+
+func TestACL_Invalid_Rules(t *testing.T) {
+	hash := config.HashPassword("alice-password")
+	for _, rule := range []string{"+unknown", "+@unknown", "%X~mydmap", "%~mydmap", "%R", "mydmap"} {
+		t.Run(rule, func(t *testing.T) {
+			_, err := NewUser("alice", []string{hash}, []string{rule})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestACL_List(t *testing.T) {
+	a := newTestACL(t, "+@read", "%R~sessions:*")
+
+	require.Equal(t, []string{
+		"user alice #" + config.HashPassword("alice-password") + " +@read %R~sessions:*",
+		"user default #" + config.HashPassword("secret") + " allcommands allkeys allchannels",
+	}, a.List())
+}
+
+func TestACL_SetDefaultPasswords(t *testing.T) {
+	c := &config.Authentication{
+		Password:          "secret",
+		SecondaryPassword: "new-secret",
+	}
+	a, err := New(c, (&config.RedisCompat{}).DMapName)
+	require.NoError(t, err)
+
+	require.True(t, a.Authenticate(config.DefaultUser, "secret"))
+	require.True(t, a.Authenticate(config.DefaultUser, "new-secret"))
+
+	a.SetDefaultPasswords("new-secret")
+	require.False(t, a.Authenticate(config.DefaultUser, "secret"))
+	require.True(t, a.Authenticate(config.DefaultUser, "new-secret"))
+	require.NoError(t, a.Check(config.DefaultUser, protocol.DMap.Destroy, args("dm.destroy mydmap")))
+}
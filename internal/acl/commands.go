@@ -0,0 +1,243 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"strings"
+
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/util"
+)
+
+// Command categories. A rule such as "+@read" allows all the commands in the category.
+const (
+	CategoryAll       = "all"
+	CategoryRead      = "read"
+	CategoryWrite     = "write"
+	CategoryDMap      = "dmap"
+	CategoryPubSub    = "pubsub"
+	CategoryStream    = "stream"
+	CategoryRedis     = "redis"
+	CategoryCluster   = "cluster"
+	CategoryAdmin     = "admin"
+	CategoryDangerous = "dangerous"
+	CategoryInternal  = "internal"
+)
+
+// command describes the permissions required to run a command.
+type command struct {
+	categories []string
+
+	// permission is required on the DMaps accessed by the command.
+	permission Permission
+
+	// dmaps returns the DMaps accessed by the command. dmapName returns the
+	// DMap name of a key of the Redis compatibility layer.
+	dmaps func(dmapName func(key string) string, args [][]byte) []string
+
+	// channels returns the Pub/Sub channels, or the patterns, accessed by the command.
+	channels func(args [][]byte) []string
+
+	// patterns is true if the command subscribes to channel patterns.
+	patterns bool
+}
+
+// alwaysAllowed are the commands that every authenticated user can run.
+var alwaysAllowed = map[string]struct{}{
+	protocol.Generic.Auth:      {},
+	protocol.Generic.Hello:     {},
+	protocol.Generic.ACLWhoAmI: {},
+}
+
+// argAt returns the argument at index i.
+func argAt(i int) func(args [][]byte) []string {
+	return func(args [][]byte) []string {
+		if len(args) <= i {
+			return nil
+		}
+		return []string{util.BytesToString(args[i])}
+	}
+}
+
+// argsFrom returns every step-th argument, starting at index i.
+func argsFrom(i, step int) func(args [][]byte) []string {
+	return func(args [][]byte) []string {
+		var result []string
+		for j := i; j < len(args); j += step {
+			result = append(result, util.BytesToString(args[j]))
+		}
+		return result
+	}
+}
+
+// dmaps returns the DMap names extracted by f.
+func dmaps(f func(args [][]byte) []string) func(dmapName func(key string) string, args [][]byte) []string {
+	return func(_ func(key string) string, args [][]byte) []string {
+		return f(args)
+	}
+}
+
+// redisKeys returns the DMaps of the Redis keys extracted by f.
+func redisKeys(f func(args [][]byte) []string) func(dmapName func(key string) string, args [][]byte) []string {
+	return func(dmapName func(key string) string, args [][]byte) []string {
+		var result []string
+		for _, key := range f(args) {
+			result = append(result, dmapName(key))
+		}
+		return result
+	}
+}
+
+// redisScanDMap returns the DMap chosen by the literal prefix of the MATCH pattern.
+// It's the same DMap that the Redis compatibility layer scans.
+func redisScanDMap(dmapName func(key string) string, args [][]byte) []string {
+	var pattern string
+	for i := 2; i+1 < len(args); i += 2 {
+		if strings.EqualFold(util.BytesToString(args[i]), "match") {
+			pattern = util.BytesToString(args[i+1])
+		}
+	}
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		pattern = pattern[:i]
+	}
+	return []string{dmapName(pattern)}
+}
+
+var commands = map[string]command{
+	// Generic
+	protocol.Generic.Ping:         {categories: []string{CategoryCluster}},
+	protocol.Generic.Stats:        {categories: []string{CategoryAdmin}},
+	protocol.Generic.SlowLogGet:   {categories: []string{CategoryAdmin}},
+	protocol.Generic.SlowLogLen:   {categories: []string{CategoryAdmin}},
+	protocol.Generic.SlowLogReset: {categories: []string{CategoryAdmin, CategoryDangerous}},
+	protocol.Generic.ACLList:      {categories: []string{CategoryAdmin}},
+
+	// Cluster
+	protocol.Cluster.RoutingTable:    {categories: []string{CategoryCluster}},
+	protocol.Cluster.Members:         {categories: []string{CategoryCluster}},
+	protocol.Cluster.KeyringInstall:  {categories: []string{CategoryAdmin, CategoryDangerous}},
+	protocol.Cluster.KeyringUse:      {categories: []string{CategoryAdmin, CategoryDangerous}},
+	protocol.Cluster.KeyringRemove:   {categories: []string{CategoryAdmin, CategoryDangerous}},
+	protocol.Cluster.KeyringList:     {categories: []string{CategoryAdmin}},
+	protocol.Cluster.PasswordAdd:     {categories: []string{CategoryAdmin, CategoryDangerous}},
+	protocol.Cluster.PasswordPromote: {categories: []string{CategoryAdmin, CategoryDangerous}},
+	protocol.Cluster.PasswordRetire:  {categories: []string{CategoryAdmin, CategoryDangerous}},
+
+	// Distributed Map
+	protocol.DMap.Get:            {categories: []string{CategoryDMap, CategoryRead}, permission: Read, dmaps: dmaps(argAt(1))},
+	protocol.DMap.Put:            {categories: []string{CategoryDMap, CategoryWrite}, permission: Write, dmaps: dmaps(argAt(1))},
+	protocol.DMap.Del:            {categories: []string{CategoryDMap, CategoryWrite}, permission: Write, dmaps: dmaps(argAt(1))},
+	protocol.DMap.Expire:         {categories: []string{CategoryDMap, CategoryWrite}, permission: Write, dmaps: dmaps(argAt(1))},
+	protocol.DMap.PExpire:        {categories: []string{CategoryDMap, CategoryWrite}, permission: Write, dmaps: dmaps(argAt(1))},
+	protocol.DMap.Destroy:        {categories: []string{CategoryDMap, CategoryWrite, CategoryDangerous}, permission: Write, dmaps: dmaps(argAt(1))},
+	protocol.DMap.Incr:           {categories: []string{CategoryDMap, CategoryWrite}, permission: ReadWrite, dmaps: dmaps(argAt(1))},
+	protocol.DMap.Decr:           {categories: []string{CategoryDMap, CategoryWrite}, permission: ReadWrite, dmaps: dmaps(argAt(1))},
+	protocol.DMap.GetPut:         {categories: []string{CategoryDMap, CategoryWrite}, permission: ReadWrite, dmaps: dmaps(argAt(1))},
+	protocol.DMap.CompareAndSwap: {categories: []string{CategoryDMap, CategoryWrite}, permission: ReadWrite, dmaps: dmaps(argAt(1))},
+	protocol.DMap.IncrByFloat:    {categories: []string{CategoryDMap, CategoryWrite}, permission: ReadWrite, dmaps: dmaps(argAt(1))},
+	protocol.DMap.Lock:           {categories: []string{CategoryDMap, CategoryWrite}, permission: Write, dmaps: dmaps(argAt(1))},
+	protocol.DMap.Unlock:         {categories: []string{CategoryDMap, CategoryWrite}, permission: Write, dmaps: dmaps(argAt(1))},
+	protocol.DMap.LockLease:      {categories: []string{CategoryDMap, CategoryWrite}, permission: Write, dmaps: dmaps(argAt(1))},
+	protocol.DMap.PLockLease:     {categories: []string{CategoryDMap, CategoryWrite}, permission: Write, dmaps: dmaps(argAt(1))},
+	protocol.DMap.Scan:           {categories: []string{CategoryDMap, CategoryRead}, permission: Read, dmaps: dmaps(argAt(2))},
+	protocol.DMap.GetEntry:       {categories: []string{CategoryInternal}, permission: Read, dmaps: dmaps(argAt(1))},
+	protocol.DMap.PutEntry:       {categories: []string{CategoryInternal}, permission: Write, dmaps: dmaps(argAt(1))},
+	protocol.DMap.DelEntry:       {categories: []string{CategoryInternal}, permission: Write, dmaps: dmaps(argAt(1))},
+
+	// Pub/Sub
+	protocol.PubSub.Publish:         {categories: []string{CategoryPubSub}, channels: argAt(1)},
+	protocol.PubSub.SPublish:        {categories: []string{CategoryPubSub}, channels: argAt(1)},
+	protocol.PubSub.Subscribe:       {categories: []string{CategoryPubSub}, channels: argsFrom(1, 1)},
+	protocol.PubSub.SSubscribe:      {categories: []string{CategoryPubSub}, channels: argsFrom(1, 1)},
+	protocol.PubSub.PSubscribe:      {categories: []string{CategoryPubSub}, channels: argsFrom(1, 1), patterns: true},
+	protocol.PubSub.PubSubResume:    {categories: []string{CategoryPubSub}, channels: argsFrom(2, 2)},
+	protocol.PubSub.PubSubChannels:  {categories: []string{CategoryPubSub}},
+	protocol.PubSub.PubSubNumpat:    {categories: []string{CategoryPubSub}},
+	protocol.PubSub.PubSubNumsub:    {categories: []string{CategoryPubSub}},
+	protocol.PubSub.PublishInternal: {categories: []string{CategoryInternal}, channels: argAt(1)},
+
+	// Streams
+	protocol.Stream.XAdd:         {categories: []string{CategoryStream, CategoryWrite}},
+	protocol.Stream.XLen:         {categories: []string{CategoryStream, CategoryRead}},
+	protocol.Stream.XInfo:        {categories: []string{CategoryStream, CategoryRead}},
+	protocol.Stream.XRange:       {categories: []string{CategoryStream, CategoryRead}},
+	protocol.Stream.XRead:        {categories: []string{CategoryStream, CategoryRead}},
+	protocol.Stream.XPending:     {categories: []string{CategoryStream, CategoryRead}},
+	protocol.Stream.XGroupCreate: {categories: []string{CategoryStream, CategoryWrite}},
+	protocol.Stream.XReadGroup:   {categories: []string{CategoryStream, CategoryWrite}},
+	protocol.Stream.XAck:         {categories: []string{CategoryStream, CategoryWrite}},
+	protocol.Stream.XClaim:       {categories: []string{CategoryStream, CategoryWrite}},
+	protocol.Stream.XAutoClaim:   {categories: []string{CategoryStream, CategoryWrite}},
+	protocol.Stream.Replicate:    {categories: []string{CategoryInternal}},
+
+	// Redis compatibility layer
+	protocol.Redis.Get:    {categories: []string{CategoryRedis, CategoryRead}, permission: Read, dmaps: redisKeys(argAt(1))},
+	protocol.Redis.TTL:    {categories: []string{CategoryRedis, CategoryRead}, permission: Read, dmaps: redisKeys(argAt(1))},
+	protocol.Redis.Exists: {categories: []string{CategoryRedis, CategoryRead}, permission: Read, dmaps: redisKeys(argsFrom(1, 1))},
+	protocol.Redis.MGet:   {categories: []string{CategoryRedis, CategoryRead}, permission: Read, dmaps: redisKeys(argsFrom(1, 1))},
+	protocol.Redis.Scan:   {categories: []string{CategoryRedis, CategoryRead}, permission: Read, dmaps: redisScanDMap},
+	protocol.Redis.Set:    {categories: []string{CategoryRedis, CategoryWrite}, permission: Write, dmaps: redisKeys(argAt(1))},
+	protocol.Redis.Del:    {categories: []string{CategoryRedis, CategoryWrite}, permission: Write, dmaps: redisKeys(argsFrom(1, 1))},
+	protocol.Redis.Expire: {categories: []string{CategoryRedis, CategoryWrite}, permission: Write, dmaps: redisKeys(argAt(1))},
+	protocol.Redis.Incr:   {categories: []string{CategoryRedis, CategoryWrite}, permission: ReadWrite, dmaps: redisKeys(argAt(1))},
+
+	// Internal
+	protocol.Internal.MoveFragment:  {categories: []string{CategoryInternal}},
+	protocol.Internal.UpdateRouting: {categories: []string{CategoryInternal}},
+	protocol.Internal.LengthOfPart:  {categories: []string{CategoryInternal}},
+	protocol.Internal.MoveStream:    {categories: []string{CategoryInternal}},
+}
+
+// DMaps returns the DMaps accessed by a command, e.g. to record them in the
+// audit log. dmapName returns the DMap name of a key of the Redis compatibility
+// layer. The DMaps of the Redis commands are not returned if it's nil.
+func DMaps(name string, args [][]byte, dmapName func(key string) string) []string {
+	c, ok := commands[name]
+	if !ok || c.dmaps == nil {
+		return nil
+	}
+	if dmapName == nil && c.hasCategory(CategoryRedis) {
+		return nil
+	}
+	return c.dmaps(dmapName, args)
+}
+
+// hasCategory returns true if the command is in the category. The internal
+// commands are not in CategoryAll, they are only allowed with +@internal or
+// one by one.
+func (c command) hasCategory(category string) bool {
+	if category == CategoryAll {
+		return !c.hasCategory(CategoryInternal)
+	}
+	for _, cat := range c.categories {
+		if cat == category {
+			return true
+		}
+	}
+	return false
+}
+
+// isCategory returns true if the category is known.
+func isCategory(category string) bool {
+	if category == CategoryAll {
+		return true
+	}
+	for _, c := range commands {
+		if c.hasCategory(category) {
+			return true
+		}
+	}
+	return false
+}
@@ -21,10 +21,12 @@ import (
 	"time"
 
 	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/events"
 	"github.com/olric-data/olric/internal/cluster/partitions"
 	"github.com/olric-data/olric/internal/cluster/routingtable"
 	"github.com/olric-data/olric/internal/discovery"
 	"github.com/olric-data/olric/internal/environment"
+	"github.com/olric-data/olric/internal/server"
 	"github.com/olric-data/olric/internal/service"
 	"github.com/olric-data/olric/pkg/flog"
 )
@@ -37,9 +39,14 @@ type Balancer struct {
 	primary *partitions.Partitions
 	backup  *partitions.Partitions
 	rt      *routingtable.RoutingTable
+	client  *server.Client
 	wg      sync.WaitGroup
 	ctx     context.Context
 	cancel  context.CancelFunc
+
+	// lastSignature is the routing table signature of the last balancing run.
+	// Guarded by the embedded mutex.
+	lastSignature uint64
 }
 
 func New(e *environment.Environment) *Balancer {
@@ -51,6 +58,7 @@ func New(e *environment.Environment) *Balancer {
 		primary: e.Get("primary").(*partitions.Partitions),
 		backup:  e.Get("backup").(*partitions.Partitions),
 		rt:      e.Get("routingtable").(*routingtable.RoutingTable),
+		client:  e.Get("client").(*server.Client),
 		log:     log,
 		ctx:     ctx,
 		cancel:  cancel,
@@ -193,6 +201,37 @@ func (b *Balancer) triggerBalancer() {
 		return
 	}
 
+	sign := b.rt.Signature()
+	if !b.config.EnableClusterEventsChannel || sign == b.lastSignature {
+		b.lastSignature = sign
+		b.moveCopies()
+		return
+	}
+	b.lastSignature = sign
+
+	b.wg.Add(1)
+	go b.publishEvent(&events.BalancerStartedEvent{
+		Kind:      events.KindBalancerStartedEvent,
+		Source:    b.rt.This().String(),
+		Signature: sign,
+		Timestamp: time.Now().UnixNano(),
+	})
+
+	start := time.Now()
+	b.moveCopies()
+
+	b.wg.Add(1)
+	go b.publishEvent(&events.BalancerFinishedEvent{
+		Kind:      events.KindBalancerFinishedEvent,
+		Source:    b.rt.This().String(),
+		Signature: sign,
+		Duration:  time.Since(start).Nanoseconds(),
+		Aborted:   b.breakLoop(sign),
+		Timestamp: time.Now().UnixNano(),
+	})
+}
+
+func (b *Balancer) moveCopies() {
 	b.primaryCopies()
 
 	if b.config.ReplicaCount > config.MinimumReplicaCount {
@@ -200,6 +239,22 @@ func (b *Balancer) triggerBalancer() {
 	}
 }
 
+func (b *Balancer) publishEvent(e events.Event) {
+	defer b.wg.Done()
+
+	kind := events.KindOf(e)
+	rc := b.client.Get(b.rt.This().String())
+	data, err := e.Encode()
+	if err != nil {
+		b.log.V(3).Printf("[ERROR] Failed to encode %s: %v", kind, err)
+		return
+	}
+	err = rc.Publish(b.ctx, events.ClusterEventsChannel, data).Err()
+	if err != nil {
+		b.log.V(3).Printf("[ERROR] Failed to publish %s to %s: %v", kind, events.ClusterEventsChannel, err)
+	}
+}
+
 func (b *Balancer) BalanceEagerly() {
 	b.triggerBalancer()
 }
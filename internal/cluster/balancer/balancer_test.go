@@ -25,14 +25,17 @@ import (
 	"time"
 
 	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/events"
 	"github.com/olric-data/olric/internal/cluster/partitions"
 	"github.com/olric-data/olric/internal/cluster/routingtable"
 	"github.com/olric-data/olric/internal/discovery"
 	"github.com/olric-data/olric/internal/environment"
+	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/server"
 	"github.com/olric-data/olric/internal/testutil"
 	"github.com/olric-data/olric/internal/testutil/mockfragment"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/redcon"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -295,3 +298,55 @@ func TestBalance_Backup_Move(t *testing.T) {
 		require.Equal(t, []discovery.Member{b2.rt.This()}, r[partID].Owners)
 	}
 }
+
+func TestBalance_ClusterEvents(t *testing.T) {
+	cluster := newMockCluster(t)
+	defer cluster.shutdown()
+
+	c := testutil.NewConfig()
+	c.EnableClusterEventsChannel = true
+	e := newTestEnvironment(c)
+
+	result := make(chan events.Event, 16)
+	b := cluster.addNode(e)
+	srv := e.Get("server").(*server.Server)
+	srv.ServeMux().HandleFunc(protocol.PubSub.Publish, func(conn redcon.Conn, cmd redcon.Command) {
+		publishCmd, err := protocol.ParsePublishCommand(cmd)
+		require.NoError(t, err)
+
+		ev, err := events.Decode(publishCmd.Message)
+		require.NoError(t, err)
+		switch ev.(type) {
+		case *events.BalancerStartedEvent, *events.BalancerFinishedEvent:
+			result <- ev
+		}
+
+		conn.WriteInt(1)
+	})
+
+	// The balancer publishes its events once per routing table signature.
+	// Force a new run on the current table.
+	b.Lock()
+	b.lastSignature = 0
+	b.Unlock()
+	b.BalanceEagerly()
+
+	var started, finished bool
+	for !started || !finished {
+		select {
+		case ev := <-result:
+			switch ev := ev.(type) {
+			case *events.BalancerStartedEvent:
+				require.Equal(t, b.rt.Signature(), ev.Signature)
+				require.Equal(t, b.rt.This().String(), ev.Source)
+				started = true
+			case *events.BalancerFinishedEvent:
+				require.Equal(t, b.rt.Signature(), ev.Signature)
+				require.False(t, ev.Aborted)
+				finished = true
+			}
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "Balancer events could not be received")
+		}
+	}
+}
@@ -15,8 +15,10 @@
 package routingtable
 
 import (
+	"sync/atomic"
 	"time"
 
+	"github.com/hashicorp/memberlist"
 	"github.com/olric-data/olric/events"
 	"github.com/olric-data/olric/internal/discovery"
 )
@@ -62,3 +64,155 @@ func (r *RoutingTable) publishNodeLeftEvent(m *discovery.Member) {
 		r.log.V(3).Printf("[ERROR] Failed to publish NodeLeftEvent to %s: %v", events.ClusterEventsChannel, err)
 	}
 }
+
+// memberStateInterval is the polling interval of the memberlist state watcher.
+const memberStateInterval = time.Second
+
+func (r *RoutingTable) publishEvent(e events.Event) {
+	defer r.wg.Done()
+
+	kind := events.KindOf(e)
+	rc := r.client.Get(r.this.String())
+	data, err := e.Encode()
+	if err != nil {
+		r.log.V(3).Printf("[ERROR] Failed to encode %s: %v", kind, err)
+		return
+	}
+	err = rc.Publish(r.ctx, events.ClusterEventsChannel, data).Err()
+	if err != nil {
+		r.log.V(3).Printf("[ERROR] Failed to publish %s to %s: %v", kind, events.ClusterEventsChannel, err)
+	}
+}
+
+// publishRoutingTableEvents announces a new routing table and the partitions that
+// changed hands. It's only called by the coordinator, after the table has been
+// pushed to the cluster. Periodic pushes of the same table are not announced.
+func (r *RoutingTable) publishRoutingTableEvents(previous, current map[uint64]*route) {
+	sign := r.Signature()
+	if sign == r.publishedSignature {
+		return
+	}
+	r.publishedSignature = sign
+
+	r.wg.Add(1)
+	go r.publishEvent(&events.RoutingTableUpdatedEvent{
+		Kind:        events.KindRoutingTableUpdatedEvent,
+		Source:      r.this.String(),
+		Coordinator: r.this.String(),
+		Signature:   sign,
+		Timestamp:   time.Now().UnixNano(),
+	})
+
+	if previous == nil {
+		// The first routing table of the cluster. There is no previous owner.
+		return
+	}
+
+	for partID, rt := range current {
+		if len(rt.Owners) == 0 {
+			continue
+		}
+		newOwner := rt.Owners[len(rt.Owners)-1]
+
+		old, ok := previous[partID]
+		if !ok || len(old.Owners) == 0 {
+			continue
+		}
+		oldOwner := old.Owners[len(old.Owners)-1]
+		if oldOwner.CompareByName(newOwner) {
+			continue
+		}
+
+		r.wg.Add(1)
+		go r.publishEvent(&events.PartitionOwnershipChangedEvent{
+			Kind:        events.KindPartitionOwnershipChangedEvent,
+			Source:      r.this.String(),
+			PartitionID: partID,
+			OldOwner:    oldOwner.String(),
+			NewOwner:    newOwner.String(),
+			Timestamp:   time.Now().UnixNano(),
+		})
+	}
+}
+
+// checkQuorumTransition publishes QuorumLostEvent or QuorumRegainedEvent if the
+// member count quorum has changed since the last membership event.
+func (r *RoutingTable) checkQuorumTransition() {
+	var lost int32
+	if r.CheckMemberCountQuorum() != nil {
+		lost = 1
+	}
+	if atomic.SwapInt32(&r.quorumLost, lost) == lost {
+		return
+	}
+
+	if lost == 1 {
+		r.wg.Add(1)
+		go r.publishEvent(&events.QuorumLostEvent{
+			Kind:              events.KindQuorumLostEvent,
+			Source:            r.this.String(),
+			MemberCount:       r.NumMembers(),
+			MemberCountQuorum: r.config.MemberCountQuorum,
+			Timestamp:         time.Now().UnixNano(),
+		})
+		return
+	}
+
+	r.wg.Add(1)
+	go r.publishEvent(&events.QuorumRegainedEvent{
+		Kind:              events.KindQuorumRegainedEvent,
+		Source:            r.this.String(),
+		MemberCount:       r.NumMembers(),
+		MemberCountQuorum: r.config.MemberCountQuorum,
+		Timestamp:         time.Now().UnixNano(),
+	})
+}
+
+// processMemberStates compares the memberlist states with the previous snapshot
+// and publishes MemberSuspectEvent and MemberAliveEvent for the transitions.
+func (r *RoutingTable) processMemberStates(previous, current map[string]memberlist.NodeStateType) {
+	for name, state := range current {
+		switch {
+		case state == memberlist.StateSuspect && previous[name] == memberlist.StateAlive:
+			r.wg.Add(1)
+			go r.publishEvent(&events.MemberSuspectEvent{
+				Kind:      events.KindMemberSuspectEvent,
+				Source:    r.this.String(),
+				Member:    name,
+				Timestamp: time.Now().UnixNano(),
+			})
+		case state == memberlist.StateAlive && previous[name] == memberlist.StateSuspect:
+			r.wg.Add(1)
+			go r.publishEvent(&events.MemberAliveEvent{
+				Kind:      events.KindMemberAliveEvent,
+				Source:    r.this.String(),
+				Member:    name,
+				Timestamp: time.Now().UnixNano(),
+			})
+		}
+	}
+}
+
+// watchMemberStates polls the failure detector to catch suspect and alive
+// transitions. memberlist doesn't deliver them as node events. Only the
+// coordinator publishes them to avoid duplicates.
+func (r *RoutingTable) watchMemberStates() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(memberStateInterval)
+	defer ticker.Stop()
+
+	previous := r.discovery.MemberStates()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			current := r.discovery.MemberStates()
+			if r.discovery.IsCoordinator() {
+				r.processMemberStates(previous, current)
+			}
+			previous = current
+		}
+	}
+}
@@ -17,9 +17,11 @@ package routingtable
 import (
 	"context"
 	"encoding/json"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/memberlist"
 	"github.com/olric-data/olric/events"
 	"github.com/olric-data/olric/internal/discovery"
 	"github.com/olric-data/olric/internal/protocol"
@@ -92,3 +94,143 @@ func TestRoutingTable_publishNodeLeftEvent(t *testing.T) {
 	<-ctx.Done()
 	require.ErrorIs(t, context.Canceled, ctx.Err())
 }
+
+func captureClusterEvents(t *testing.T, rt *RoutingTable) chan events.Event {
+	result := make(chan events.Event, 16)
+	rt.server.ServeMux().HandleFunc(protocol.PubSub.Publish, func(conn redcon.Conn, cmd redcon.Command) {
+		publishCmd, err := protocol.ParsePublishCommand(cmd)
+		require.NoError(t, err)
+		require.Equal(t, events.ClusterEventsChannel, publishCmd.Channel)
+
+		ev, err := events.Decode(publishCmd.Message)
+		require.NoError(t, err)
+		result <- ev
+
+		conn.WriteInt(1)
+	})
+	return result
+}
+
+func receiveClusterEvent(t *testing.T, result chan events.Event) events.Event {
+	select {
+	case ev := <-result:
+		return ev
+	case <-time.After(time.Second):
+		require.Fail(t, "No cluster event received")
+	}
+	return nil
+}
+
+func TestRoutingTable_publishRoutingTableEvents(t *testing.T) {
+	cluster := newTestCluster()
+	defer cluster.cancel()
+
+	c := testutil.NewConfig()
+	rt, err := cluster.addNode(c)
+	require.NoError(t, err)
+	result := captureClusterEvents(t, rt)
+
+	c2 := testutil.NewConfig()
+	c2.MemberlistConfig.Name = "127.0.0.1:0"
+	other := discovery.NewMember(c2)
+
+	previous := map[uint64]*route{
+		0: {Owners: []discovery.Member{rt.this}},
+		1: {Owners: []discovery.Member{rt.this}},
+	}
+	current := map[uint64]*route{
+		0: {Owners: []discovery.Member{rt.this}},
+		1: {Owners: []discovery.Member{rt.this, other}},
+	}
+
+	rt.Lock()
+	rt.publishRoutingTableEvents(previous, current)
+	rt.Unlock()
+
+	var updated, changed bool
+	for !updated || !changed {
+		switch ev := receiveClusterEvent(t, result).(type) {
+		case *events.RoutingTableUpdatedEvent:
+			require.Equal(t, rt.this.String(), ev.Coordinator)
+			require.Equal(t, rt.Signature(), ev.Signature)
+			updated = true
+		case *events.PartitionOwnershipChangedEvent:
+			require.Equal(t, uint64(1), ev.PartitionID)
+			require.Equal(t, rt.this.String(), ev.OldOwner)
+			require.Equal(t, other.String(), ev.NewOwner)
+			changed = true
+		}
+	}
+
+	// The same routing table is not announced twice.
+	rt.Lock()
+	rt.publishRoutingTableEvents(current, current)
+	rt.Unlock()
+
+	select {
+	case ev := <-result:
+		require.Failf(t, "unexpected cluster event", "%v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRoutingTable_checkQuorumTransition(t *testing.T) {
+	cluster := newTestCluster()
+	defer cluster.cancel()
+
+	c := testutil.NewConfig()
+	c.MemberCountQuorum = 2
+	rt, err := cluster.addNode(c)
+	require.NoError(t, err)
+	result := captureClusterEvents(t, rt)
+
+	rt.SetNumMembersEagerly(1)
+	atomic.StoreInt32(&rt.quorumLost, 0)
+	rt.checkQuorumTransition()
+
+	lost, ok := receiveClusterEvent(t, result).(*events.QuorumLostEvent)
+	require.True(t, ok)
+	require.Equal(t, int32(1), lost.MemberCount)
+	require.Equal(t, int32(2), lost.MemberCountQuorum)
+
+	rt.SetNumMembersEagerly(2)
+	rt.checkQuorumTransition()
+
+	regained, ok := receiveClusterEvent(t, result).(*events.QuorumRegainedEvent)
+	require.True(t, ok)
+	require.Equal(t, int32(2), regained.MemberCount)
+
+	// No transition, no event.
+	rt.checkQuorumTransition()
+	select {
+	case ev := <-result:
+		require.Failf(t, "unexpected cluster event", "%v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRoutingTable_processMemberStates(t *testing.T) {
+	cluster := newTestCluster()
+	defer cluster.cancel()
+
+	c := testutil.NewConfig()
+	rt, err := cluster.addNode(c)
+	require.NoError(t, err)
+	result := captureClusterEvents(t, rt)
+
+	rt.processMemberStates(
+		map[string]memberlist.NodeStateType{"node-1": memberlist.StateAlive},
+		map[string]memberlist.NodeStateType{"node-1": memberlist.StateSuspect},
+	)
+	suspect, ok := receiveClusterEvent(t, result).(*events.MemberSuspectEvent)
+	require.True(t, ok)
+	require.Equal(t, "node-1", suspect.Member)
+
+	rt.processMemberStates(
+		map[string]memberlist.NodeStateType{"node-1": memberlist.StateSuspect},
+		map[string]memberlist.NodeStateType{"node-1": memberlist.StateAlive},
+	)
+	alive, ok := receiveClusterEvent(t, result).(*events.MemberAliveEvent)
+	require.True(t, ok)
+	require.Equal(t, "node-1", alive.Member)
+}
@@ -52,25 +52,31 @@ type RoutingTable struct {
 	signature           uint64
 	// numMembers is used to check cluster quorum.
 	numMembers int32
+	// quorumLost is set when the member count drops below MemberCountQuorum.
+	// It's only used to publish quorum transitions to the cluster events channel.
+	quorumLost int32
 
 	// These values is useful to control operation status.
 	bootstrapped int32
 
 	updateRoutingMtx sync.Mutex
 	table            map[uint64]*route
-	consistent       *consistent.Consistent
-	this             discovery.Member
-	members          *Members
-	config           *config.Config
-	log              *flog.Logger
-	primary          *partitions.Partitions
-	backup           *partitions.Partitions
-	client           *server.Client
-	server           *server.Server
-	discovery        *discovery.Discovery
-	callbacks        []func()
-	callbackMtx      sync.Mutex
-	pushPeriod       time.Duration
+	// publishedSignature is the signature of the last routing table announced on
+	// the cluster events channel by the coordinator. Guarded by routingMtx.
+	publishedSignature uint64
+	consistent         *consistent.Consistent
+	this               discovery.Member
+	members            *Members
+	config             *config.Config
+	log                *flog.Logger
+	primary            *partitions.Partitions
+	backup             *partitions.Partitions
+	client             *server.Client
+	server             *server.Server
+	discovery          *discovery.Discovery
+	callbacks          []func()
+	callbackMtx        sync.Mutex
+	pushPeriod         time.Duration
 	// The command handlers of the routing table service should wait for the cluster join event.
 	joined chan struct{}
 	ctx    context.Context
@@ -78,6 +84,12 @@ type RoutingTable struct {
 	wg     sync.WaitGroup
 }
 
+// The errors are registered at init, the clients convert them without a
+// routing table.
+func init() {
+	registerErrors()
+}
+
 func registerErrors() {
 	protocol.SetError("CLUSTERQUORUM", ErrClusterQuorum)
 	protocol.SetError("CLUSTERJOIN", ErrClusterJoin)
@@ -114,7 +126,6 @@ func New(e *environment.Environment) *RoutingTable {
 		ctx:        ctx,
 		cancel:     cancel,
 	}
-	registerErrors()
 	rt.RegisterHandlers()
 	return rt
 }
@@ -250,12 +261,16 @@ func (r *RoutingTable) updateRouting() {
 		return
 	}
 
+	previous := r.table
 	r.fillRoutingTable()
 	reports, err := r.updateRoutingTableOnCluster()
 	if err != nil {
 		r.log.V(2).Printf("[ERROR] Failed to update routing table on cluster: %v", err)
 		return
 	}
+	if r.config.EnableClusterEventsChannel {
+		r.publishRoutingTableEvents(previous, r.table)
+	}
 	r.processLeftOverDataReports(reports)
 }
 
@@ -316,6 +331,10 @@ func (r *RoutingTable) processClusterEvent(event *discovery.ClusterEvent) {
 	// Store the current number of members in the member list.
 	// We need this to implement a simple split-brain protection algorithm.
 	r.setNumMembers()
+
+	if r.config.EnableClusterEventsChannel {
+		r.checkQuorumTransition()
+	}
 }
 
 func (r *RoutingTable) listenClusterEvents(eventCh chan *discovery.ClusterEvent) {
@@ -387,6 +406,9 @@ func (r *RoutingTable) Start() error {
 	// Store the current number of members in the member list.
 	// We need this to implement a simple split-brain protection algorithm.
 	r.setNumMembers()
+	if r.CheckMemberCountQuorum() != nil {
+		atomic.StoreInt32(&r.quorumLost, 1)
+	}
 
 	r.wg.Add(1)
 	go r.listenClusterEvents(r.discovery.ClusterEvents)
@@ -422,6 +444,11 @@ func (r *RoutingTable) Start() error {
 	r.wg.Add(1)
 	go r.pushPeriodically()
 
+	if r.config.EnableClusterEventsChannel {
+		r.wg.Add(1)
+		go r.watchMemberStates()
+	}
+
 	if r.config.MemberlistInterface != "" {
 		r.log.V(2).Printf("[INFO] Memberlist uses interface: %s", r.config.MemberlistInterface)
 	}
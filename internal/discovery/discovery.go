@@ -224,6 +224,15 @@ func (d *Discovery) GetMembers() []Member {
 	return members
 }
 
+// MemberStates returns the memberlist state of the known nodes, keyed by node name.
+func (d *Discovery) MemberStates() map[string]memberlist.NodeStateType {
+	states := make(map[string]memberlist.NodeStateType)
+	for _, node := range d.memberlist.Members() {
+		states[node.Name] = node.State
+	}
+	return states
+}
+
 func (d *Discovery) NumMembers() int {
 	return d.memberlist.NumMembers()
 }
@@ -270,6 +279,12 @@ func (d *Discovery) LocalNode() *memberlist.Node {
 	return d.memberlist.LocalNode()
 }
 
+// Keyring returns the keyring of the gossip encryption keys. It's nil if the
+// encryption is not enabled.
+func (d *Discovery) Keyring() *memberlist.Keyring {
+	return d.config.MemberlistConfig.Keyring
+}
+
 // Shutdown will stop any background maintenance of network activity
 // for this memberlist, causing it to appear "dead". A leave message
 // will not be broadcasted prior, so the cluster being left will have
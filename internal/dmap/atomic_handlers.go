@@ -15,20 +15,21 @@
 package dmap
 
 import (
-	"strconv"
+	"context"
 	"time"
 
 	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
 	"github.com/tidwall/redcon"
 )
 
-func (s *Service) incrDecrCommon(cmd, dmap, key string, delta int) (int, error) {
+func (s *Service) incrDecrCommon(ctx context.Context, cmd, dmap, key string, delta int) (int, error) {
 	dm, err := s.getOrCreateDMap(dmap)
 	if err != nil {
 		return 0, err
 	}
 
-	e := newEnv(s.ctx)
+	e := newEnv(ctx)
 	e.dmap = dm.name
 	e.key = key
 	return dm.atomicIncrDecr(cmd, e, delta)
@@ -40,7 +41,7 @@ func (s *Service) incrCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		protocol.WriteError(conn, err)
 		return
 	}
-	latest, err := s.incrDecrCommon(protocol.DMap.Incr, incrCmd.DMap, incrCmd.Key, incrCmd.Delta)
+	latest, err := s.incrDecrCommon(server.RequestContext(s.ctx, conn), protocol.DMap.Incr, incrCmd.DMap, incrCmd.Key, incrCmd.Delta)
 	if err != nil {
 		protocol.WriteError(conn, err)
 		return
@@ -54,7 +55,7 @@ func (s *Service) decrCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		protocol.WriteError(conn, err)
 		return
 	}
-	latest, err := s.incrDecrCommon(protocol.DMap.Decr, decrCmd.DMap, decrCmd.Key, decrCmd.Delta)
+	latest, err := s.incrDecrCommon(server.RequestContext(s.ctx, conn), protocol.DMap.Decr, decrCmd.DMap, decrCmd.Key, decrCmd.Delta)
 	if err != nil {
 		protocol.WriteError(conn, err)
 		return
@@ -74,7 +75,7 @@ func (s *Service) getPutCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		return
 	}
 
-	e := newEnv(s.ctx)
+	e := newEnv(server.RequestContext(s.ctx, conn))
 	e.dmap = getPutCmd.DMap
 	e.key = getPutCmd.Key
 	e.value = getPutCmd.Value
@@ -85,7 +86,7 @@ func (s *Service) getPutCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 	}
 
 	if old == nil {
-		conn.WriteNull()
+		server.WriteNull(conn)
 		return
 	}
 
@@ -126,7 +127,7 @@ func (s *Service) compareAndSwapCommandHandler(conn redcon.Conn, cmd redcon.Comm
 		pc.PXAT = time.Duration(casCmd.PXAT * int64(time.Millisecond))
 	}
 
-	e := newEnv(s.ctx)
+	e := newEnv(server.RequestContext(s.ctx, conn))
 	e.dmap = casCmd.DMap
 	e.key = casCmd.Key
 	e.value = casCmd.Value
@@ -145,7 +146,7 @@ func (s *Service) compareAndSwapCommandHandler(conn redcon.Conn, cmd redcon.Comm
 		conn.WriteInt(0)
 	}
 	if current == nil {
-		conn.WriteNull()
+		server.WriteNull(conn)
 	} else {
 		conn.WriteBulk(current.Encode())
 	}
@@ -164,7 +165,7 @@ func (s *Service) incrByFloatCommandHandler(conn redcon.Conn, cmd redcon.Command
 		return
 	}
 
-	e := newEnv(s.ctx)
+	e := newEnv(server.RequestContext(s.ctx, conn))
 	e.dmap = dm.name
 	e.key = incrCmd.Key
 	latest, err := dm.atomicIncrByFloat(e, incrCmd.Delta)
@@ -173,5 +174,5 @@ func (s *Service) incrByFloatCommandHandler(conn redcon.Conn, cmd redcon.Command
 		return
 	}
 
-	conn.WriteBulkString(strconv.FormatFloat(latest, 'f', -1, 64))
+	server.WriteDouble(conn, latest)
 }
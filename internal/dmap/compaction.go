@@ -107,11 +107,11 @@ func (s *Service) triggerCompaction() {
 func (s *Service) compactionWorker() {
 	defer s.wg.Done()
 
-	timer := time.NewTimer(s.config.DMaps.TriggerCompactionInterval)
+	timer := time.NewTimer(s.getDMapsConfig().TriggerCompactionInterval)
 	defer timer.Stop()
 
 	for {
-		timer.Reset(s.config.DMaps.TriggerCompactionInterval)
+		timer.Reset(s.getDMapsConfig().TriggerCompactionInterval)
 		select {
 		case <-timer.C:
 			s.triggerCompaction()
@@ -16,9 +16,11 @@ package dmap
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/olric-data/olric/config"
+	"github.com/tidwall/match"
 )
 
 // dmapConfig keeps DMap config control parameters and access-log for keys in a dmap.
@@ -30,6 +32,54 @@ type dmapConfig struct {
 	maxInuse        int
 	lruSamples      int
 	evictionPolicy  config.EvictionPolicy
+
+	// rule is the key of DMaps.Custom that configures the DMap. It's empty
+	// if the DMap uses the global configuration.
+	rule string
+}
+
+// isPrefixRule returns true if the key of DMaps.Custom is a prefix, like
+// "sessions:*". A prefix ends with the only wildcard of the key, and it's not
+// empty. "*" is a glob pattern that matches every DMap.
+func isPrefixRule(key string) bool {
+	return len(key) > 1 && strings.HasSuffix(key, "*") && !match.IsPattern(key[:len(key)-1])
+}
+
+// findCustom returns the key of DMaps.Custom that matches the DMap. An exact
+// name has the highest precedence, then the longest prefix, then the glob
+// patterns. If more than one glob pattern matches, the longest one wins, and
+// the ties are broken by the lexical order to make the result deterministic.
+func findCustom(custom map[string]config.DMap, name string) (string, bool) {
+	if _, ok := custom[name]; ok {
+		return name, true
+	}
+
+	var prefix, glob string
+	for key := range custom {
+		if !match.IsPattern(key) {
+			continue
+		}
+		if isPrefixRule(key) {
+			if strings.HasPrefix(name, key[:len(key)-1]) && len(key) > len(prefix) {
+				prefix = key
+			}
+			continue
+		}
+		if !match.Match(name, key) {
+			continue
+		}
+		if len(key) > len(glob) || (len(key) == len(glob) && key < glob) {
+			glob = key
+		}
+	}
+
+	if prefix != "" {
+		return prefix, true
+	}
+	if glob != "" {
+		return glob, true
+	}
+	return "", false
 }
 
 func (c *dmapConfig) load(dc *config.DMaps, name string) error {
@@ -44,8 +94,10 @@ func (c *dmapConfig) load(dc *config.DMaps, name string) error {
 
 	if dc.Custom != nil {
 		// config.DMap struct can be used for fine-grained control.
-		cs, ok := dc.Custom[name]
+		rule, ok := findCustom(dc.Custom, name)
 		if ok {
+			cs := dc.Custom[rule]
+			c.rule = rule
 			if c.maxIdleDuration != cs.MaxIdleDuration {
 				c.maxIdleDuration = cs.MaxIdleDuration
 			}
@@ -85,3 +137,15 @@ func (c *dmapConfig) load(dc *config.DMaps, name string) error {
 	}
 	return nil
 }
+
+// ConfigRule returns the key of DMaps.Custom that configures the DMap. It's
+// empty if the DMap uses the global configuration. The DMaps that are not
+// created on this member yet are resolved with the current configuration.
+func (s *Service) ConfigRule(name string) string {
+	dm, err := s.getDMap(name)
+	if err == nil {
+		return dm.getConfig().rule
+	}
+	rule, _ := findCustom(s.getDMapsConfig().Custom, name)
+	return rule
+}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/testcluster"
 	"github.com/olric-data/olric/internal/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -76,3 +77,93 @@ func TestDMap_Config(t *testing.T) {
 		require.Equal(t, c.DMaps.Custom["foobar"].Engine, dcc.engine)
 	})
 }
+
+func TestDMap_Config_Patterns(t *testing.T) {
+	c := config.New("local")
+	c.DMaps.MaxKeys = 100
+	c.DMaps.Custom = map[string]config.DMap{
+		"sessions:tenant42": {MaxKeys: 1},
+		"sessions:*":        {MaxKeys: 2},
+		"sessions:tenant*":  {MaxKeys: 3},
+		"*:tenant*":         {MaxKeys: 4},
+		"cache:v?:*":        {MaxKeys: 5},
+		"cache:*:users":     {MaxKeys: 6},
+	}
+
+	tests := []struct {
+		name    string
+		rule    string
+		maxKeys int
+	}{
+		{name: "sessions:tenant42", rule: "sessions:tenant42", maxKeys: 1},
+		{name: "sessions:tenant7", rule: "sessions:tenant*", maxKeys: 3},
+		{name: "sessions:admin", rule: "sessions:*", maxKeys: 2},
+		{name: "events:tenant42", rule: "*:tenant*", maxKeys: 4},
+		// Both of the globs match, the longest one wins.
+		{name: "cache:v3:users", rule: "cache:*:users", maxKeys: 6},
+		{name: "cache:v3:orders", rule: "cache:v?:*", maxKeys: 5},
+		{name: "mydmap", rule: "", maxKeys: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := dmapConfig{}
+			require.NoError(t, dc.load(c.DMaps, tt.name))
+			require.Equal(t, tt.rule, dc.rule)
+			require.Equal(t, tt.maxKeys, dc.maxKeys)
+		})
+	}
+}
+
+func TestDMap_Config_Patterns_Tie(t *testing.T) {
+	custom := map[string]config.DMap{
+		"a?c*": {},
+		"a*c?": {},
+	}
+	for i := 0; i < 10; i++ {
+		rule, ok := findCustom(custom, "abcd")
+		require.True(t, ok)
+		require.Equal(t, "a*c?", rule)
+	}
+}
+
+func TestDMap_Config_Patterns_Catch_All(t *testing.T) {
+	custom := map[string]config.DMap{
+		"*":              {},
+		"cache:v?:users": {},
+		"sessions:*":     {},
+	}
+
+	// "*" is not a prefix rule, it has the lowest precedence of the globs.
+	tests := map[string]string{
+		"cache:v3:users": "cache:v?:users",
+		"sessions:eu":    "sessions:*",
+		"mydmap":         "*",
+	}
+	for name, expected := range tests {
+		rule, ok := findCustom(custom, name)
+		require.True(t, ok)
+		require.Equal(t, expected, rule)
+	}
+}
+
+func TestDMap_ConfigRule(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	c := testutil.NewConfig()
+	c.DMaps.Custom = map[string]config.DMap{
+		"sessions:*": {MaxKeys: 10},
+	}
+	e := testcluster.NewEnvironment(c)
+	s := cluster.AddMember(e).(*Service)
+	defer cluster.Shutdown()
+
+	// The DMap is not created yet.
+	require.Equal(t, "sessions:*", s.ConfigRule("sessions:tenant42"))
+
+	dm, err := s.NewDMap("sessions:tenant42")
+	require.NoError(t, err)
+	require.Equal(t, "sessions:*", s.ConfigRule(dm.Name()))
+	require.Equal(t, 10, dm.getConfig().maxKeys)
+
+	require.Equal(t, "", s.ConfigRule("mydmap"))
+}
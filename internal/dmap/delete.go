@@ -51,13 +51,14 @@ func (dm *DMap) deleteFromFragment(key string, kind partitions.Kind) error {
 	return f.storage.Delete(hkey)
 }
 
-func (dm *DMap) deleteFromPreviousOwners(key string, owners []discovery.Member) error {
+func (dm *DMap) deleteFromPreviousOwners(ctx context.Context, key string, owners []discovery.Member) error {
+	ctx = dm.rpcContext(ctx)
 	// Traverse in reverse order. Except from the latest host, this one.
 	for i := len(owners) - 2; i >= 0; i-- {
 		owner := owners[i]
-		cmd := protocol.NewDelEntry(dm.name, key).Command(dm.s.ctx)
+		cmd := protocol.NewDelEntry(dm.name, key).Command(ctx)
 		rc := dm.s.client.Get(owner.String())
-		err := rc.Process(dm.s.ctx, cmd)
+		err := rc.Process(ctx, cmd)
 		if err != nil {
 			return protocol.ConvertError(err)
 		}
@@ -69,15 +70,16 @@ func (dm *DMap) deleteFromPreviousOwners(key string, owners []discovery.Member)
 	return nil
 }
 
-func (dm *DMap) deleteBackupOnCluster(hkey uint64, key string) error {
+func (dm *DMap) deleteBackupOnCluster(ctx context.Context, hkey uint64, key string) error {
+	ctx = dm.rpcContext(ctx)
 	owners := dm.s.backup.PartitionOwnersByHKey(hkey)
 	var g errgroup.Group
 	for _, owner := range owners {
 		mem := owner
 		g.Go(func() error {
-			cmd := protocol.NewDelEntry(dm.name, key).SetReplica().Command(dm.s.ctx)
+			cmd := protocol.NewDelEntry(dm.name, key).SetReplica().Command(ctx)
 			rc := dm.s.client.Get(mem.String())
-			err := rc.Process(dm.s.ctx, cmd)
+			err := rc.Process(ctx, cmd)
 			if err != nil {
 				dm.s.log.V(3).Printf("[ERROR] Failed to delete replica key/value on %s: %s", dm.name, err)
 				return protocol.ConvertError(err)
@@ -89,19 +91,19 @@ func (dm *DMap) deleteBackupOnCluster(hkey uint64, key string) error {
 }
 
 // deleteOnCluster is not a thread-safe function
-func (dm *DMap) deleteOnCluster(hkey uint64, key string, f *fragment) error {
+func (dm *DMap) deleteOnCluster(ctx context.Context, hkey uint64, key string, f *fragment) error {
 	owners := dm.s.primary.PartitionOwnersByHKey(hkey)
 	if len(owners) == 0 {
 		panic("partition owners list cannot be empty")
 	}
 
-	err := dm.deleteFromPreviousOwners(key, owners)
+	err := dm.deleteFromPreviousOwners(ctx, key, owners)
 	if err != nil {
 		return err
 	}
 
 	if dm.s.config.ReplicaCount != 0 {
-		err := dm.deleteBackupOnCluster(hkey, key)
+		err := dm.deleteBackupOnCluster(ctx, hkey, key)
 		if err != nil {
 			return err
 		}
@@ -118,12 +120,14 @@ func (dm *DMap) deleteOnCluster(hkey uint64, key string, f *fragment) error {
 	return nil
 }
 
-func (dm *DMap) deleteKey(key string) error {
+// deleteKey deletes the key on the cluster. It reports whether the key was
+// found in the primary owner.
+func (dm *DMap) deleteKey(ctx context.Context, key string) (bool, error) {
 	hkey := partitions.HKey(dm.name, key)
 	part := dm.getPartitionByHKey(hkey, partitions.PRIMARY)
 	f, err := dm.loadOrCreateFragment(part)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	f.Lock()
@@ -133,10 +137,10 @@ func (dm *DMap) deleteKey(key string) error {
 	if !f.storage.Check(hkey) {
 		// DeleteMisses is the number of deletions reqs for missing keys
 		DeleteMisses.Increase(1)
-		return nil
+		return false, nil
 	}
 
-	return dm.deleteOnCluster(hkey, key, f)
+	return true, dm.deleteOnCluster(ctx, hkey, key, f)
 }
 
 func (dm *DMap) deleteKeys(ctx context.Context, keys ...string) (int, error) {
@@ -150,12 +154,12 @@ func (dm *DMap) deleteKeys(ctx context.Context, keys ...string) (int, error) {
 	for member, distributedKeys := range members {
 		if member.CompareByName(dm.s.rt.This()) {
 			for _, key := range distributedKeys {
-				if err := dm.deleteKey(key); err != nil {
+				if _, err := dm.deleteKey(ctx, key); err != nil {
 					return 0, err
 				}
 			}
 		} else {
-			cmd := protocol.NewDel(dm.name, distributedKeys...).Command(dm.s.ctx)
+			cmd := protocol.NewDel(dm.name, distributedKeys...).Command(ctx)
 			rc := dm.s.client.Get(member.String())
 			err := rc.Process(ctx, cmd)
 			if err != nil {
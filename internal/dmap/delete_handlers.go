@@ -17,6 +17,7 @@ package dmap
 import (
 	"github.com/olric-data/olric/internal/cluster/partitions"
 	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
 	"github.com/tidwall/redcon"
 )
 
@@ -32,7 +33,7 @@ func (s *Service) delCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		return
 	}
 
-	count, err := dm.deleteKeys(s.ctx, delCmd.Keys...)
+	count, err := dm.deleteKeys(server.RequestContext(s.ctx, conn), delCmd.Keys...)
 	if err != nil {
 		protocol.WriteError(conn, err)
 		return
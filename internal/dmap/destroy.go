@@ -17,7 +17,9 @@ package dmap
 import (
 	"context"
 	"runtime"
+	"time"
 
+	"github.com/olric-data/olric/events"
 	"github.com/olric-data/olric/internal/discovery"
 	"github.com/olric-data/olric/internal/protocol"
 	"golang.org/x/sync/errgroup"
@@ -70,5 +72,18 @@ func (dm *DMap) destroyOnCluster(ctx context.Context) error {
 // is no global lock on DMaps. So if you call Put, Put with EX and Destroy methods
 // concurrently on the cluster, Put and Put with EX calls may set new values to the DMap.
 func (dm *DMap) Destroy(ctx context.Context) error {
-	return dm.destroyOnCluster(ctx)
+	if err := dm.destroyOnCluster(ctx); err != nil {
+		return err
+	}
+
+	if dm.s.config.EnableClusterEventsChannel {
+		dm.s.wg.Add(1)
+		go dm.s.publishEvent(&events.DMapDestroyedEvent{
+			Kind:      events.KindDMapDestroyedEvent,
+			Source:    dm.s.rt.This().String(),
+			DMap:      dm.name,
+			Timestamp: time.Now().UnixNano(),
+		})
+	}
+	return nil
 }
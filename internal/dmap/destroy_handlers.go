@@ -20,6 +20,7 @@ import (
 	"github.com/olric-data/olric/config"
 	"github.com/olric-data/olric/internal/cluster/partitions"
 	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
 	"github.com/tidwall/redcon"
 )
 
@@ -85,7 +86,7 @@ func (s *Service) destroyCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 	if destroyCmd.Local {
 		err = s.destroyLocalDMap(destroyCmd.DMap)
 	} else {
-		err = dm.destroyOnCluster(s.ctx)
+		err = dm.destroyOnCluster(server.RequestContext(s.ctx, conn))
 	}
 
 	if err != nil {
@@ -17,11 +17,14 @@ package dmap
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/olric-data/olric/events"
 	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/testcluster"
 	"github.com/olric-data/olric/internal/testutil"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/redcon"
 )
 
 func TestDMap_Destroy_Standalone(t *testing.T) {
@@ -125,3 +128,106 @@ func TestDMap_Destroy_destroyOperation(t *testing.T) {
 		require.ErrorIs(t, err, ErrKeyNotFound)
 	}
 }
+
+func TestDMap_Destroy_ClusterEvents(t *testing.T) {
+	c := testutil.NewConfig()
+	c.EnableClusterEventsChannel = true
+	e := testcluster.NewEnvironment(c)
+
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(e).(*Service)
+	defer cluster.Shutdown()
+
+	result := make(chan events.Event, 16)
+	s.server.ServeMux().HandleFunc(protocol.PubSub.Publish, func(conn redcon.Conn, cmd redcon.Command) {
+		publishCmd, err := protocol.ParsePublishCommand(cmd)
+		require.NoError(t, err)
+
+		ev, err := events.Decode(publishCmd.Message)
+		require.NoError(t, err)
+		result <- ev
+
+		conn.WriteInt(1)
+	})
+
+	dm, err := s.NewDMap("mymap")
+	require.NoError(t, err)
+	require.NoError(t, dm.Destroy(context.Background()))
+
+	var created, destroyed bool
+	for !created || !destroyed {
+		select {
+		case ev := <-result:
+			switch ev := ev.(type) {
+			case *events.DMapCreatedEvent:
+				require.Equal(t, "mymap", ev.DMap)
+				require.Equal(t, s.rt.This().String(), ev.Source)
+				created = true
+			case *events.DMapDestroyedEvent:
+				require.Equal(t, "mymap", ev.DMap)
+				destroyed = true
+			}
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "DMap events could not be received")
+		}
+	}
+}
+
+func TestDMap_Created_ClusterEvents_PerMember(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	var services []*Service
+	for i := 0; i < 2; i++ {
+		c := testutil.NewConfig()
+		c.EnableClusterEventsChannel = true
+		services = append(services, cluster.AddMember(testcluster.NewEnvironment(c)).(*Service))
+	}
+	defer cluster.Shutdown()
+
+	result := make(chan *events.DMapCreatedEvent, 16)
+	for _, s := range services {
+		s.server.ServeMux().HandleFunc(protocol.PubSub.Publish, func(conn redcon.Conn, cmd redcon.Command) {
+			publishCmd, err := protocol.ParsePublishCommand(cmd)
+			require.NoError(t, err)
+
+			ev, err := events.Decode(publishCmd.Message)
+			require.NoError(t, err)
+			if created, ok := ev.(*events.DMapCreatedEvent); ok {
+				result <- created
+			}
+			conn.WriteInt(1)
+		})
+	}
+
+	s1, s2 := services[0], services[1]
+	dm, err := s1.NewDMap("mymap")
+	require.NoError(t, err)
+	// The instance is cached, it doesn't publish the event again.
+	_, err = s1.NewDMap("mymap")
+	require.NoError(t, err)
+
+	// Some of the keys are owned by s2, the routed requests create the DMap on s2.
+	for i := 0; i < 100; i++ {
+		require.NoError(t, dm.Put(context.Background(), testutil.ToKey(i), testutil.ToVal(i), nil))
+	}
+
+	sources := make(map[string]int)
+	for len(sources) < 2 {
+		select {
+		case ev := <-result:
+			require.Equal(t, "mymap", ev.DMap)
+			sources[ev.Source]++
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "DMapCreatedEvent could not be received")
+		}
+	}
+
+	select {
+	case ev := <-result:
+		require.Failf(t, "Unexpected DMapCreatedEvent", "source: %s", ev.Source)
+	case <-time.After(250 * time.Millisecond):
+	}
+	require.Equal(t, map[string]int{
+		s1.rt.This().String(): 1,
+		s2.rt.This().String(): 1,
+	}, sources)
+}
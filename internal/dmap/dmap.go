@@ -15,12 +15,16 @@
 package dmap
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/olric-data/olric/events"
 	"github.com/olric-data/olric/internal/cluster/partitions"
 	"github.com/olric-data/olric/pkg/storage"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const nilTimeout = 0 * time.Second
@@ -38,7 +42,17 @@ type DMap struct {
 	fragmentName string
 	s            *Service
 	engine       storage.Engine
-	config       *dmapConfig
+	// config holds a *dmapConfig. It's replaced when the configuration is reloaded.
+	config atomic.Value
+
+	// lastEvictionPressureEvent is the UnixNano timestamp of the last
+	// EvictionPressureEvent published for this DMap.
+	lastEvictionPressureEvent int64
+}
+
+// getConfig loads the configuration of the DMap from atomic.Value and returns.
+func (dm *DMap) getConfig() *dmapConfig {
+	return dm.config.Load().(*dmapConfig)
 }
 
 // Name exposes name of the DMap.
@@ -88,18 +102,31 @@ func (s *Service) NewDMap(name string) (*DMap, error) {
 	}
 
 	dm = &DMap{
-		config:       &dmapConfig{},
 		name:         name,
 		fragmentName: s.fragmentName(name),
 		s:            s,
 	}
-	if err := dm.config.load(s.config.DMaps, name); err != nil {
+	dc := &dmapConfig{}
+	if err := dc.load(s.getDMapsConfig(), name); err != nil {
 		return nil, err
 	}
+	dm.config.Store(dc)
 
 	// It's a shortcut.
-	dm.engine = dm.config.engine.Implementation
+	dm.engine = dc.engine.Implementation
 	s.dmaps[name] = dm
+
+	// The event is published once for every member that creates the DMap,
+	// there is no cluster-wide record of the DMaps.
+	if s.config.EnableClusterEventsChannel {
+		s.wg.Add(1)
+		go s.publishEvent(&events.DMapCreatedEvent{
+			Kind:      events.KindDMapCreatedEvent,
+			Source:    s.rt.This().String(),
+			DMap:      name,
+			Timestamp: time.Now().UnixNano(),
+		})
+	}
 	return dm, nil
 }
 
@@ -112,6 +139,17 @@ func (s *Service) getOrCreateDMap(name string) (*DMap, error) {
 	return dm, err
 }
 
+// rpcContext returns the service context with the span context of ctx. The requests
+// sent to the other members become a part of the caller's trace, but they are
+// not canceled with the caller's context.
+func (dm *DMap) rpcContext(ctx context.Context) context.Context {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return dm.s.ctx
+	}
+	return trace.ContextWithSpanContext(dm.s.ctx, sc)
+}
+
 func (dm *DMap) getPartitionByHKey(hkey uint64, kind partitions.Kind) *partitions.Partition {
 	var part *partitions.Partition
 	switch {
@@ -30,11 +30,8 @@ import (
 
 // isKeyIdleOnFragment is not a thread-safe function. It accesses underlying fragment for the given hkey.
 func (dm *DMap) isKeyIdleOnFragment(hkey uint64, f *fragment) bool {
-	if dm.config == nil {
-		return false
-	}
-
-	if dm.config.maxIdleDuration.Nanoseconds() == 0 {
+	maxIdleDuration := dm.getConfig().maxIdleDuration
+	if maxIdleDuration.Nanoseconds() == 0 {
 		return false
 	}
 	// Maximum time in seconds for each entry to stay idle in the map.
@@ -46,7 +43,7 @@ func (dm *DMap) isKeyIdleOnFragment(hkey uint64, f *fragment) bool {
 		return false
 	}
 	//TODO: Handle other errors.
-	ttl := (dm.config.maxIdleDuration.Nanoseconds() + lastAccess) / 1000000
+	ttl := (maxIdleDuration.Nanoseconds() + lastAccess) / 1000000
 	return isKeyExpired(ttl)
 }
 
@@ -70,8 +67,8 @@ func (s *Service) evictKeysAtBackground() {
 	defer s.wg.Done()
 
 	num := int64(runtime.NumCPU())
-	if s.config.DMaps != nil && s.config.DMaps.NumEvictionWorkers != 0 {
-		num = s.config.DMaps.NumEvictionWorkers
+	if dc := s.getDMapsConfig(); dc != nil && dc.NumEvictionWorkers != 0 {
+		num = dc.NumEvictionWorkers
 	}
 	sem := semaphore.NewWeighted(num)
 	for {
@@ -103,6 +100,10 @@ func (s *Service) evictKeys() {
 	partID := uint64(rand.Intn(int(s.config.PartitionCount)))
 	part := s.primary.PartitionByID(partID)
 	part.Map().Range(func(name, tmp interface{}) bool {
+		if !strings.HasPrefix(name.(string), "dmap.") {
+			// Skip fragments of the other data structures.
+			return true
+		}
 		dmapName := strings.TrimPrefix(name.(string), "dmap.")
 		f := tmp.(*fragment)
 		s.scanFragmentForEviction(partID, dmapName, f)
@@ -157,7 +158,7 @@ func (s *Service) scanFragmentForEviction(partID uint64, name string, f *fragmen
 			}
 
 			if isKeyExpired(ttl) || dm.isKeyIdleOnFragment(hkey, f) {
-				err = dm.deleteOnCluster(hkey, key, f)
+				err = dm.deleteOnCluster(dm.s.ctx, hkey, key, f)
 				if err != nil {
 					// It will be tried again.
 					dm.s.log.V(3).Printf("[ERROR] Failed to delete expired key: %s on DMap: %s: %v",
@@ -207,12 +208,13 @@ type lruItem struct {
 func (dm *DMap) evictKeyWithLRU(e *env) error {
 	var idx = 1
 	var items []lruItem
+	lruSamples := dm.getConfig().lruSamples
 
 	// Warning: fragment is already locked by DMap.Put. Be sure about that before editing this function.
 
 	// Pick random items from the distributed map and sort them by accessedAt.
 	e.fragment.storage.Range(func(hkey uint64, e storage.Entry) bool {
-		if idx >= dm.config.lruSamples {
+		if idx >= lruSamples {
 			return false
 		}
 		idx++
@@ -243,7 +245,7 @@ func (dm *DMap) evictKeyWithLRU(e *env) error {
 	if dm.s.log.V(6).Ok() {
 		dm.s.log.V(6).Printf("[DEBUG] Evicted item on DMap: %s, key: %s with LRU", e.dmap, key)
 	}
-	err = dm.deleteOnCluster(item.HKey, key, e.fragment)
+	err = dm.deleteOnCluster(e.ctx, item.HKey, key, e.fragment)
 	if err != nil {
 		return err
 	}
@@ -20,9 +20,12 @@ import (
 	"time"
 
 	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/events"
+	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/testcluster"
 	"github.com/olric-data/olric/internal/testutil"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/redcon"
 )
 
 func TestDMap_Eviction_TTL(t *testing.T) {
@@ -211,3 +214,59 @@ func TestDMap_Eviction_LRU_Config_MaxInuse(t *testing.T) {
 
 	require.NotEqual(t, 100, length)
 }
+
+func TestDMap_Eviction_LRU_ClusterEvents(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	c := testutil.NewConfig()
+	c.EnableClusterEventsChannel = true
+	c.DMaps = &config.DMaps{
+		MaxKeys:        70,
+		EvictionPolicy: config.LRUEviction,
+		Engine:         config.NewEngine(),
+	}
+	require.NoError(t, c.DMaps.Engine.Sanitize())
+
+	e := testcluster.NewEnvironment(c)
+	s := cluster.AddMember(e).(*Service)
+	defer cluster.Shutdown()
+
+	result := make(chan *events.EvictionPressureEvent, 16)
+	s.server.ServeMux().HandleFunc(protocol.PubSub.Publish, func(conn redcon.Conn, cmd redcon.Command) {
+		publishCmd, err := protocol.ParsePublishCommand(cmd)
+		require.NoError(t, err)
+
+		ev, err := events.Decode(publishCmd.Message)
+		require.NoError(t, err)
+		if ev, ok := ev.(*events.EvictionPressureEvent); ok {
+			result <- ev
+		}
+
+		conn.WriteInt(1)
+	})
+
+	dm, err := s.NewDMap("mydmap")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 1000; i++ {
+		err = dm.Put(ctx, testutil.ToKey(i), testutil.ToVal(i), nil)
+		require.NoError(t, err)
+	}
+
+	select {
+	case ev := <-result:
+		require.Equal(t, "mydmap", ev.DMap)
+		require.Equal(t, "max-keys", ev.Reason)
+		require.Equal(t, 70/int(s.rt.OwnedPartitionCount()), ev.Limit)
+		require.GreaterOrEqual(t, ev.Length, ev.Limit)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "EvictionPressureEvent could not be received")
+	}
+
+	// Rate-limited per DMap.
+	select {
+	case ev := <-result:
+		require.Failf(t, "unexpected EvictionPressureEvent", "%v", ev)
+	case <-time.After(250 * time.Millisecond):
+	}
+}
@@ -16,6 +16,7 @@ package dmap
 
 import (
 	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
 	"github.com/tidwall/redcon"
 )
 
@@ -36,7 +37,7 @@ func (s *Service) expireCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		OnlyUpdateTTL: true,
 	}
 
-	e := newEnv(s.ctx)
+	e := newEnv(server.RequestContext(s.ctx, conn))
 	e.putConfig = pc
 	e.dmap = expireCmd.DMap
 	e.key = expireCmd.Key
@@ -66,7 +67,7 @@ func (s *Service) pexpireCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		OnlyUpdateTTL: true,
 	}
 
-	e := newEnv(s.ctx)
+	e := newEnv(server.RequestContext(s.ctx, conn))
 	e.putConfig = pc
 	e.dmap = pexpireCmd.DMap
 	e.key = pexpireCmd.Key
@@ -129,7 +129,7 @@ func (f *fragment) Move(part *partitions.Partition, name string, owners []discov
 }
 
 func (dm *DMap) newFragment() (*fragment, error) {
-	c := storage.NewConfig(dm.config.engine.Config)
+	c := storage.NewConfig(dm.getConfig().engine.Config)
 	engine, err := dm.engine.Fork(c)
 	if err != nil {
 		return nil, err
@@ -86,10 +86,11 @@ func (dm *DMap) getOnFragment(e *env) (storage.Entry, error) {
 
 // lookupOnPreviousOwner retrieves the version of a key from a previous owner in the cluster.
 // It communicates with the specified owner node and decodes the value into a version object.
-func (dm *DMap) lookupOnPreviousOwner(owner *discovery.Member, key string) (*version, error) {
-	cmd := protocol.NewGetEntry(dm.name, key).Command(dm.s.ctx)
+func (dm *DMap) lookupOnPreviousOwner(ctx context.Context, owner *discovery.Member, key string) (*version, error) {
+	ctx = dm.rpcContext(ctx)
+	cmd := protocol.NewGetEntry(dm.name, key).Command(ctx)
 	rc := dm.s.client.Get(owner.String())
-	err := rc.Process(dm.s.ctx, cmd)
+	err := rc.Process(ctx, cmd)
 	if err != nil {
 		return nil, protocol.ConvertError(err)
 	}
@@ -148,7 +149,7 @@ func (dm *DMap) lookupOnThisNode(hkey uint64, key string) *version {
 
 // lookupOnOwners collects versions of a key/value pair on the partition owner
 // by including previous partition owners.
-func (dm *DMap) lookupOnOwners(hkey uint64, key string) []*version {
+func (dm *DMap) lookupOnOwners(ctx context.Context, hkey uint64, key string) []*version {
 	owners := dm.s.primary.PartitionOwnersByHKey(hkey)
 	if len(owners) == 0 {
 		panic("partition owners list cannot be empty")
@@ -170,7 +171,7 @@ func (dm *DMap) lookupOnOwners(hkey uint64, key string) []*version {
 		go func(member *discovery.Member) {
 			defer wg.Done()
 
-			v, err := dm.lookupOnPreviousOwner(member, key)
+			v, err := dm.lookupOnPreviousOwner(ctx, member, key)
 			if err != nil {
 				if dm.s.log.V(6).Ok() {
 					dm.s.log.V(6).Printf("[ERROR] Failed to call get on a previous "+
@@ -219,7 +220,9 @@ func (dm *DMap) sanitizeAndSortVersions(versions []*version) []*version {
 
 // lookupOnReplicas retrieves data from replica nodes for the given hash key and
 // key, returning a list of versioned entries.
-func (dm *DMap) lookupOnReplicas(hkey uint64, key string) []*version {
+func (dm *DMap) lookupOnReplicas(ctx context.Context, hkey uint64, key string) []*version {
+	ctx = dm.rpcContext(ctx)
+
 	// Check replicas
 	var (
 		wg  sync.WaitGroup
@@ -233,9 +236,9 @@ func (dm *DMap) lookupOnReplicas(hkey uint64, key string) []*version {
 		go func(host *discovery.Member) {
 			defer wg.Done()
 
-			cmd := protocol.NewGetEntry(dm.name, key).SetReplica().Command(dm.s.ctx)
+			cmd := protocol.NewGetEntry(dm.name, key).SetReplica().Command(ctx)
 			rc := dm.s.client.Get(host.String())
-			err := rc.Process(dm.s.ctx, cmd)
+			err := rc.Process(ctx, cmd)
 			err = protocol.ConvertError(err)
 			if err != nil {
 				if dm.s.log.V(6).Ok() {
@@ -327,13 +330,13 @@ func (dm *DMap) readRepair(winner *version, versions []*version) {
 // getOnCluster retrieves the storage.Entry for a given hashed key and key string
 // from cluster nodes with read quorum. It ensures data consistency via read repair
 // and returns ErrKeyNotFound or ErrReadQuorum if conditions aren't met.
-func (dm *DMap) getOnCluster(hkey uint64, key string) (storage.Entry, error) {
+func (dm *DMap) getOnCluster(ctx context.Context, hkey uint64, key string) (storage.Entry, error) {
 	// RUnlock should not be called with a defer statement here because
 	//  the readRepair function may call putOnFragment function which needs a write
 	// lock. Please remember calling RUnlock before returning here.
-	versions := dm.lookupOnOwners(hkey, key)
+	versions := dm.lookupOnOwners(ctx, hkey, key)
 	if dm.s.config.ReadQuorum >= config.MinimumReplicaCount {
-		v := dm.lookupOnReplicas(hkey, key)
+		v := dm.lookupOnReplicas(ctx, hkey, key)
 		versions = append(versions, v...)
 	}
 
@@ -374,7 +377,7 @@ func (dm *DMap) Get(ctx context.Context, key string) (storage.Entry, error) {
 
 	// We are on the partition owner
 	if member.CompareByName(dm.s.rt.This()) {
-		entry, err := dm.getOnCluster(hkey, key)
+		entry, err := dm.getOnCluster(ctx, hkey, key)
 		if errors.Is(err, ErrKeyNotFound) {
 			GetMisses.Increase(1)
 		}
@@ -389,7 +392,7 @@ func (dm *DMap) Get(ctx context.Context, key string) (storage.Entry, error) {
 	}
 
 	// Redirect to the partition owner
-	cmd := protocol.NewGet(dm.name, key).SetRaw().Command(dm.s.ctx)
+	cmd := protocol.NewGet(dm.name, key).SetRaw().Command(ctx)
 	rc := dm.s.client.Get(member.String())
 	err := rc.Process(ctx, cmd)
 	if err != nil {
@@ -17,6 +17,7 @@ package dmap
 import (
 	"github.com/olric-data/olric/internal/cluster/partitions"
 	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
 	"github.com/tidwall/redcon"
 )
 
@@ -32,7 +33,7 @@ func (s *Service) getCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		return
 	}
 
-	raw, err := dm.Get(s.ctx, getCmd.Key)
+	raw, err := dm.Get(server.RequestContext(s.ctx, conn), getCmd.Key)
 	if err != nil {
 		protocol.WriteError(conn, err)
 		return
@@ -62,7 +63,7 @@ func (s *Service) getEntryCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		kind = partitions.BACKUP
 	}
 
-	e := newEnv(s.ctx)
+	e := newEnv(server.RequestContext(s.ctx, conn))
 	e.dmap = getEntryCmd.DMap
 	e.key = getEntryCmd.Key
 	e.hkey = partitions.HKey(getEntryCmd.DMap, getEntryCmd.Key)
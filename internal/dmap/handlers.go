@@ -39,4 +39,8 @@ func (s *Service) RegisterHandlers() {
 	s.server.ServeMux().HandleFunc(protocol.DMap.LockLease, s.lockLeaseCommandHandler)
 	s.server.ServeMux().HandleFunc(protocol.DMap.PLockLease, s.plockLeaseCommandHandler)
 	s.server.ServeMux().HandleFunc(protocol.Internal.MoveFragment, s.moveFragmentCommandHandler)
+
+	if s.config.RedisCompat.Enabled {
+		s.registerRedisHandlers()
+	}
 }
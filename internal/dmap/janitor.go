@@ -81,11 +81,11 @@ func (s *Service) deleteEmptyFragments() {
 
 func (s *Service) janitorWorker() {
 	defer s.wg.Done()
-	timer := time.NewTimer(s.config.DMaps.CheckEmptyFragmentsInterval)
+	timer := time.NewTimer(s.getDMapsConfig().CheckEmptyFragmentsInterval)
 	defer timer.Stop()
 
 	for {
-		timer.Reset(s.config.DMaps.CheckEmptyFragmentsInterval)
+		timer.Reset(s.getDMapsConfig().CheckEmptyFragmentsInterval)
 		select {
 		case <-timer.C:
 			s.deleteEmptyFragments()
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
 	"github.com/tidwall/redcon"
 )
 
@@ -40,7 +41,7 @@ func (s *Service) unlockCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		return
 	}
 
-	err = dm.Unlock(s.ctx, unlockCmd.Key, token)
+	err = dm.Unlock(server.RequestContext(s.ctx, conn), unlockCmd.Key, token)
 	if err != nil {
 		protocol.WriteError(conn, err)
 		return
@@ -71,7 +72,7 @@ func (s *Service) lockCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 	}
 
 	var deadline = time.Duration(lockCmd.Deadline * float64(time.Second))
-	token, err := dm.Lock(s.ctx, lockCmd.Key, timeout, deadline)
+	token, err := dm.Lock(server.RequestContext(s.ctx, conn), lockCmd.Key, timeout, deadline)
 	if err != nil {
 		protocol.WriteError(conn, err)
 		return
@@ -99,7 +100,7 @@ func (s *Service) lockLeaseCommandHandler(conn redcon.Conn, cmd redcon.Command)
 		protocol.WriteError(conn, err)
 		return
 	}
-	err = dm.Lease(s.ctx, lockLeaseCmd.Key, token, timeout)
+	err = dm.Lease(server.RequestContext(s.ctx, conn), lockLeaseCmd.Key, token, timeout)
 	if err != nil {
 		protocol.WriteError(conn, err)
 		return
@@ -126,7 +127,7 @@ func (s *Service) plockLeaseCommandHandler(conn redcon.Conn, cmd redcon.Command)
 		protocol.WriteError(conn, err)
 		return
 	}
-	err = dm.Lease(s.ctx, plockLeaseCmd.Key, token, timeout)
+	err = dm.Lease(server.RequestContext(s.ctx, conn), plockLeaseCmd.Key, token, timeout)
 	if err != nil {
 		protocol.WriteError(conn, err)
 		return
@@ -18,9 +18,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/events"
 	"github.com/olric-data/olric/internal/bufpool"
 	"github.com/olric-data/olric/internal/cluster/partitions"
 	"github.com/olric-data/olric/internal/discovery"
@@ -33,6 +35,10 @@ import (
 
 var pool = bufpool.New()
 
+// evictionPressureEventInterval is the minimum interval between two
+// EvictionPressureEvents of the same DMap.
+const evictionPressureEventInterval = 10 * time.Second
+
 // EntriesTotal is the total number of entries(including replicas)
 // stored during the life of this instance.
 var EntriesTotal = stats.NewInt64Counter()
@@ -134,8 +140,9 @@ func (dm *DMap) asyncPutOnBackup(e *env, data []byte, owner discovery.Member) {
 	defer dm.s.wg.Done()
 
 	rc := dm.s.client.Get(owner.String())
-	cmd := protocol.NewPutEntry(e.dmap, e.key, data).Command(dm.s.ctx)
-	err := rc.Process(dm.s.ctx, cmd)
+	ctx := dm.rpcContext(e.ctx)
+	cmd := protocol.NewPutEntry(e.dmap, e.key, data).Command(ctx)
+	err := rc.Process(ctx, cmd)
 	if err != nil {
 		if dm.s.log.V(3).Ok() {
 			dm.s.log.V(3).Printf("[ERROR] Failed to create replica in async mode: %v", err)
@@ -180,8 +187,9 @@ func (dm *DMap) syncPutOnCluster(e *env, nt storage.Entry) error {
 	owners := dm.s.backup.PartitionOwnersByHKey(e.hkey)
 	for _, owner := range owners {
 		rc := dm.s.client.Get(owner.String())
-		cmd := protocol.NewPutEntry(dm.name, e.key, encodedEntry).Command(dm.s.ctx)
-		err := rc.Process(dm.s.ctx, cmd)
+		ctx := dm.rpcContext(e.ctx)
+		cmd := protocol.NewPutEntry(dm.name, e.key, encodedEntry).Command(ctx)
+		err := rc.Process(ctx, cmd)
 		if err != nil {
 			return protocol.ConvertError(err)
 		}
@@ -208,6 +216,37 @@ func (dm *DMap) syncPutOnCluster(e *env, nt storage.Entry) error {
 	return ErrWriteQuorum
 }
 
+// publishEvictionPressureEvent publishes an EvictionPressureEvent when a partition
+// hits its share of MaxKeys or MaxInuse. The events are rate-limited per DMap.
+func (dm *DMap) publishEvictionPressureEvent(e *env, reason string, length, inuse, limit int) {
+	if !dm.s.config.EnableClusterEventsChannel {
+		return
+	}
+
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&dm.lastEvictionPressureEvent)
+	if now-last < int64(evictionPressureEventInterval) {
+		return
+	}
+	if !atomic.CompareAndSwapInt64(&dm.lastEvictionPressureEvent, last, now) {
+		// Another goroutine has just published it.
+		return
+	}
+
+	dm.s.wg.Add(1)
+	go dm.s.publishEvent(&events.EvictionPressureEvent{
+		Kind:        events.KindEvictionPressureEvent,
+		Source:      dm.s.rt.This().String(),
+		DMap:        dm.name,
+		PartitionID: dm.s.primary.PartitionIDByHKey(e.hkey),
+		Reason:      reason,
+		Length:      length,
+		Inuse:       inuse,
+		Limit:       limit,
+		Timestamp:   now,
+	})
+}
+
 func (dm *DMap) setLRUEvictionStats(e *env) error {
 	// Try to make room for the new item, if it's required.
 	// MaxKeys and MaxInuse properties of LRU can be used in the same time.
@@ -225,12 +264,15 @@ func (dm *DMap) setLRUEvictionStats(e *env) error {
 		return nil
 	}
 
-	if dm.config.maxKeys > 0 {
+	dc := dm.getConfig()
+	if dc.maxKeys > 0 {
 		// MaxKeys controls maximum key count owned by this node.
 		// We need ownedPartitionCount property because every partition
 		// manages itself independently. So if you set MaxKeys=70 and
 		// your partition count is 7, every partition 10 keys at maximum.
-		if st.Length > 0 && st.Length >= dm.config.maxKeys/int(ownedPartitionCount) {
+		limit := dc.maxKeys / int(ownedPartitionCount)
+		if st.Length > 0 && st.Length >= limit {
+			dm.publishEvictionPressureEvent(e, "max-keys", st.Length, st.Inuse, limit)
 			err := dm.evictKeyWithLRU(e)
 			if err != nil {
 				return err
@@ -238,13 +280,15 @@ func (dm *DMap) setLRUEvictionStats(e *env) error {
 		}
 	}
 
-	if dm.config.maxInuse > 0 {
+	if dc.maxInuse > 0 {
 		// MaxInuse controls maximum in-use memory of partitions on this node.
 		// We need ownedPartitionCount property because every partition
 		// manages itself independently. So if you set MaxInuse=70M(in bytes) and
 		// your partition count is 7, every partition consumes 10M in-use space at maximum.
 		// WARNING: Actual allocated memory can be different.
-		if st.Inuse > 0 && st.Inuse >= dm.config.maxInuse/int(ownedPartitionCount) {
+		limit := dc.maxInuse / int(ownedPartitionCount)
+		if st.Inuse > 0 && st.Inuse >= limit {
+			dm.publishEvictionPressureEvent(e, "max-inuse", st.Length, st.Inuse, limit)
 			err := dm.evictKeyWithLRU(e)
 			if err != nil {
 				return err
@@ -304,14 +348,13 @@ func (dm *DMap) putOnCluster(e *env) error {
 		return err
 	}
 
-	if dm.config != nil {
-		if dm.config.ttlDuration.Seconds() != 0 && e.timeout.Seconds() == 0 {
-			e.timeout = dm.config.ttlDuration
-		}
-		if dm.config.evictionPolicy == config.LRUEviction {
-			if err = dm.setLRUEvictionStats(e); err != nil {
-				return err
-			}
+	dc := dm.getConfig()
+	if dc.ttlDuration.Seconds() != 0 && e.timeout.Seconds() == 0 {
+		e.timeout = dc.ttlDuration
+	}
+	if dc.evictionPolicy == config.LRUEviction {
+		if err = dm.setLRUEvictionStats(e); err != nil {
+			return err
 		}
 	}
 
@@ -354,7 +397,7 @@ func (dm *DMap) writePutCommand(e *env) (*redis.StatusCmd, error) {
 		cmd.SetXX()
 	}
 
-	return cmd.Command(dm.s.ctx), nil
+	return cmd.Command(dm.rpcContext(e.ctx)), nil
 }
 
 // put controls every write operation in Olric. It redirects the requests to its owner,
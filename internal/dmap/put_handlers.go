@@ -19,6 +19,7 @@ import (
 
 	"github.com/olric-data/olric/internal/cluster/partitions"
 	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
 	"github.com/tidwall/redcon"
 )
 
@@ -57,7 +58,7 @@ func (s *Service) putCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		pc.PXAT = time.Duration(putCmd.PXAT * int64(time.Millisecond))
 	}
 
-	e := newEnv(s.ctx)
+	e := newEnv(server.RequestContext(s.ctx, conn))
 	e.putConfig = &pc
 	e.dmap = putCmd.DMap
 	e.key = putCmd.Key
@@ -83,7 +84,7 @@ func (s *Service) putEntryCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		return
 	}
 
-	e := newEnv(s.ctx)
+	e := newEnv(server.RequestContext(s.ctx, conn))
 	e.hkey = partitions.HKey(putEntryCmd.DMap, putEntryCmd.Key)
 	e.dmap = putEntryCmd.DMap
 	e.key = putEntryCmd.Key
@@ -0,0 +1,441 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmap
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olric-data/olric/internal/cluster/partitions"
+	"github.com/olric-data/olric/internal/discovery"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
+	"github.com/olric-data/olric/pkg/storage"
+	"github.com/tidwall/match"
+	"github.com/tidwall/redcon"
+)
+
+func (s *Service) registerRedisHandlers() {
+	s.server.ServeMux().HandleFunc(protocol.Redis.Get, s.redisGetCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Redis.Set, s.redisSetCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Redis.Del, s.redisDelCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Redis.Expire, s.redisExpireCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Redis.TTL, s.redisTTLCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Redis.Incr, s.redisIncrCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Redis.Scan, s.redisScanCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Redis.Exists, s.redisExistsCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Redis.MGet, s.redisMGetCommandHandler)
+}
+
+// checkRedisKeys checks the partition owners of the keys. The Redis
+// compatibility layer doesn't forward the requests to the other members,
+// the clients follow the MOVED redirects like they do for a Redis Cluster.
+// It writes an error to the connection and returns false, if the keys are
+// not owned by this member.
+func (s *Service) checkRedisKeys(conn redcon.Conn, keys ...string) bool {
+	if err := s.rt.CheckBootstrap(); err != nil {
+		protocol.WriteError(conn, err)
+		return false
+	}
+
+	var local, remote int
+	var movedPartID uint64
+	var movedOwner discovery.Member
+	for _, key := range keys {
+		hkey := partitions.HKey(s.config.RedisCompat.DMapName(key), key)
+		part := s.primary.PartitionByHKey(hkey)
+		owner := part.Owner()
+		if owner.CompareByName(s.rt.This()) {
+			local++
+			continue
+		}
+		if remote == 0 {
+			movedPartID, movedOwner = part.ID(), owner
+		} else if !owner.CompareByName(movedOwner) {
+			protocol.WriteError(conn, protocol.ErrCrossSlot)
+			return false
+		}
+		remote++
+	}
+
+	if remote == 0 {
+		return true
+	}
+	if local > 0 {
+		protocol.WriteError(conn, protocol.ErrCrossSlot)
+		return false
+	}
+	protocol.WriteMoved(conn, movedPartID, movedOwner.String())
+	return false
+}
+
+func (s *Service) getRedisDMap(key string) (*DMap, error) {
+	return s.getOrCreateDMap(s.config.RedisCompat.DMapName(key))
+}
+
+// redisLookup returns nil, if the key doesn't exist.
+func (s *Service) redisLookup(ctx context.Context, key string) (storage.Entry, error) {
+	dm, err := s.getRedisDMap(key)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := dm.Get(ctx, key)
+	if err == ErrKeyNotFound {
+		return nil, nil
+	}
+	return entry, err
+}
+
+func (s *Service) redisGetCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	getCmd, err := protocol.ParseRedisKeyCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	if !s.checkRedisKeys(conn, getCmd.Key) {
+		return
+	}
+
+	entry, err := s.redisLookup(server.RequestContext(s.ctx, conn), getCmd.Key)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	if entry == nil {
+		server.WriteNull(conn)
+		return
+	}
+	conn.WriteBulk(entry.Value())
+}
+
+func (s *Service) redisSetCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	setCmd, err := protocol.ParseRedisSetCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	if !s.checkRedisKeys(conn, setCmd.Key) {
+		return
+	}
+
+	dm, err := s.getRedisDMap(setCmd.Key)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	var pc PutConfig
+	switch {
+	case setCmd.NX:
+		pc.HasNX = true
+	case setCmd.XX:
+		pc.HasXX = true
+	}
+
+	switch {
+	case setCmd.EX != 0:
+		pc.HasEX = true
+		pc.EX = time.Duration(setCmd.EX) * time.Second
+	case setCmd.PX != 0:
+		pc.HasPX = true
+		pc.PX = time.Duration(setCmd.PX) * time.Millisecond
+	}
+
+	e := newEnv(server.RequestContext(s.ctx, conn))
+	e.putConfig = &pc
+	e.dmap = dm.name
+	e.key = setCmd.Key
+	e.value = setCmd.Value
+	err = dm.put(e)
+	if err == ErrKeyFound || err == ErrKeyNotFound {
+		// The NX or XX condition is not met.
+		server.WriteNull(conn)
+		return
+	}
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteString(protocol.StatusOK)
+}
+
+func (s *Service) redisDelCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	delCmd, err := protocol.ParseRedisKeysCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	if !s.checkRedisKeys(conn, delCmd.Keys...) {
+		return
+	}
+
+	ctx := server.RequestContext(s.ctx, conn)
+	var deleted int
+	for _, key := range delCmd.Keys {
+		dm, err := s.getRedisDMap(key)
+		if err != nil {
+			protocol.WriteError(conn, err)
+			return
+		}
+		found, err := dm.deleteKey(ctx, key)
+		if err != nil {
+			protocol.WriteError(conn, err)
+			return
+		}
+		if found {
+			deleted++
+		}
+	}
+	conn.WriteInt(deleted)
+}
+
+func (s *Service) redisExpireCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	expireCmd, err := protocol.ParseRedisExpireCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	if !s.checkRedisKeys(conn, expireCmd.Key) {
+		return
+	}
+
+	dm, err := s.getRedisDMap(expireCmd.Key)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	ctx := server.RequestContext(s.ctx, conn)
+	if expireCmd.Seconds <= 0 {
+		// A non-positive timeout deletes the key, like Redis does.
+		found, err := dm.deleteKey(ctx, expireCmd.Key)
+		if err != nil {
+			protocol.WriteError(conn, err)
+			return
+		}
+		if !found {
+			conn.WriteInt(0)
+			return
+		}
+		conn.WriteInt(1)
+		return
+	}
+
+	err = dm.Expire(ctx, expireCmd.Key, time.Duration(expireCmd.Seconds)*time.Second)
+	if err == ErrKeyNotFound {
+		conn.WriteInt(0)
+		return
+	}
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteInt(1)
+}
+
+func (s *Service) redisTTLCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	ttlCmd, err := protocol.ParseRedisKeyCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	if !s.checkRedisKeys(conn, ttlCmd.Key) {
+		return
+	}
+
+	entry, err := s.redisLookup(server.RequestContext(s.ctx, conn), ttlCmd.Key)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	if entry == nil {
+		conn.WriteInt(-2)
+		return
+	}
+	if entry.TTL() == 0 {
+		conn.WriteInt(-1)
+		return
+	}
+
+	remaining := time.Until(time.UnixMilli(entry.TTL())).Milliseconds()
+	if remaining < 0 {
+		remaining = 0
+	}
+	conn.WriteInt64((remaining + 500) / 1000)
+}
+
+func (s *Service) redisIncrCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	incrCmd, err := protocol.ParseRedisKeyCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	if !s.checkRedisKeys(conn, incrCmd.Key) {
+		return
+	}
+
+	latest, err := s.incrDecrCommon(
+		server.RequestContext(s.ctx, conn),
+		protocol.DMap.Incr,
+		s.config.RedisCompat.DMapName(incrCmd.Key),
+		incrCmd.Key,
+		1,
+	)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteInt(latest)
+}
+
+func (s *Service) redisExistsCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	existsCmd, err := protocol.ParseRedisKeysCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	if !s.checkRedisKeys(conn, existsCmd.Keys...) {
+		return
+	}
+
+	ctx := server.RequestContext(s.ctx, conn)
+	var count int
+	for _, key := range existsCmd.Keys {
+		entry, err := s.redisLookup(ctx, key)
+		if err != nil {
+			protocol.WriteError(conn, err)
+			return
+		}
+		if entry != nil {
+			count++
+		}
+	}
+	conn.WriteInt(count)
+}
+
+func (s *Service) redisMGetCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	mgetCmd, err := protocol.ParseRedisKeysCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	if !s.checkRedisKeys(conn, mgetCmd.Keys...) {
+		return
+	}
+
+	ctx := server.RequestContext(s.ctx, conn)
+	entries := make([]storage.Entry, 0, len(mgetCmd.Keys))
+	for _, key := range mgetCmd.Keys {
+		entry, err := s.redisLookup(ctx, key)
+		if err != nil {
+			protocol.WriteError(conn, err)
+			return
+		}
+		entries = append(entries, entry)
+	}
+
+	conn.WriteArray(len(entries))
+	for _, entry := range entries {
+		if entry == nil {
+			server.WriteNull(conn)
+			continue
+		}
+		conn.WriteBulk(entry.Value())
+	}
+}
+
+// globPrefix returns the literal prefix of a glob-style pattern.
+func globPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+// redisScanCommandHandler iterates the keys in the primary partitions owned by
+// this member, like SCAN does on a Redis Cluster node. The cursor encodes the
+// partition ID and the cursor of the storage engine in that partition.
+//
+// The keys are read from the default DMap, or from the DMap chosen by the
+// literal prefix of the MATCH pattern.
+func (s *Service) redisScanCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	scanCmd, err := protocol.ParseRedisScanCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	dm, err := s.getRedisDMap(globPrefix(scanCmd.Match))
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	partitionCount := s.config.PartitionCount
+	partID := scanCmd.Cursor % partitionCount
+	cursor := scanCmd.Cursor / partitionCount
+
+	sc := &ScanConfig{
+		HasCount: true,
+		Count:    scanCmd.Count,
+	}
+
+	var keys []string
+	for ; partID < partitionCount; partID++ {
+		owner := s.primary.PartitionByID(partID).Owner()
+		if !owner.CompareByName(s.rt.This()) {
+			cursor = 0
+			continue
+		}
+
+		for {
+			var result []string
+			result, cursor, err = dm.Scan(partID, cursor, sc)
+			if err != nil {
+				protocol.WriteError(conn, err)
+				return
+			}
+			for _, key := range result {
+				if scanCmd.Match == "" || match.Match(key, scanCmd.Match) {
+					keys = append(keys, key)
+				}
+			}
+			if cursor == 0 || len(keys) >= scanCmd.Count {
+				break
+			}
+		}
+
+		if len(keys) >= scanCmd.Count {
+			break
+		}
+	}
+
+	var next uint64
+	if cursor == 0 {
+		partID++
+	}
+	if partID < partitionCount {
+		next = cursor*partitionCount + partID
+	}
+
+	conn.WriteArray(2)
+	conn.WriteBulkString(strconv.FormatUint(next, 10))
+	conn.WriteArray(len(keys))
+	for _, key := range keys {
+		conn.WriteBulkString(key)
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/internal/cluster/partitions"
+	"github.com/olric-data/olric/internal/testcluster"
+	"github.com/olric-data/olric/internal/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedisCompatService(cluster *testcluster.TestCluster, separator string) *Service {
+	c := testutil.NewConfig()
+	c.RedisCompat.Enabled = true
+	c.RedisCompat.KeyPrefixSeparator = separator
+	e := testcluster.NewEnvironment(c)
+	return cluster.AddMember(e).(*Service)
+}
+
+func TestDMap_Redis_Disabled(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	rc := s.client.Get(s.rt.This().String())
+	err := rc.Set(ctx, "mykey", "myvalue", 0).Err()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown command")
+}
+
+func TestDMap_Redis_Commands(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := newRedisCompatService(cluster, "")
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	rc := s.client.Get(s.rt.This().String())
+
+	require.NoError(t, rc.Set(ctx, "mykey", "myvalue", 0).Err())
+	value, err := rc.Get(ctx, "mykey").Result()
+	require.NoError(t, err)
+	require.Equal(t, "myvalue", value)
+
+	// The keys are stored in the default DMap.
+	dm, err := s.getOrCreateDMap("default")
+	require.NoError(t, err)
+	entry, err := dm.Get(ctx, "mykey")
+	require.NoError(t, err)
+	require.Equal(t, []byte("myvalue"), entry.Value())
+
+	_, err = rc.Get(ctx, "missing").Result()
+	require.ErrorIs(t, err, redis.Nil)
+
+	ok, err := rc.SetNX(ctx, "mykey", "other", 0).Result()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = rc.SetXX(ctx, "missing", "other", 0).Result()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	count, err := rc.Exists(ctx, "mykey", "missing").Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	values, err := rc.MGet(ctx, "mykey", "missing").Result()
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"myvalue", nil}, values)
+
+	for i := 1; i <= 3; i++ {
+		latest, err := rc.Incr(ctx, "counter").Result()
+		require.NoError(t, err)
+		require.Equal(t, int64(i), latest)
+	}
+
+	count, err = rc.Del(ctx, "mykey", "counter", "missing").Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	_, err = rc.Get(ctx, "mykey").Result()
+	require.ErrorIs(t, err, redis.Nil)
+}
+
+func TestDMap_Redis_TTL(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := newRedisCompatService(cluster, "")
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	rc := s.client.Get(s.rt.This().String())
+
+	ttl, err := rc.TTL(ctx, "mykey").Result()
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(-2), ttl)
+
+	require.NoError(t, rc.Set(ctx, "mykey", "myvalue", 0).Err())
+	ttl, err = rc.TTL(ctx, "mykey").Result()
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(-1), ttl)
+
+	ok, err := rc.Expire(ctx, "mykey", 100*time.Second).Result()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ttl, err = rc.TTL(ctx, "mykey").Result()
+	require.NoError(t, err)
+	require.Equal(t, 100*time.Second, ttl)
+
+	require.NoError(t, rc.Set(ctx, "mykey", "myvalue", 10*time.Second).Err())
+	ttl, err = rc.TTL(ctx, "mykey").Result()
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, ttl)
+
+	ok, err = rc.Expire(ctx, "missing", time.Second).Result()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// A non-positive timeout deletes the key.
+	ok, err = rc.Do(ctx, "expire", "mykey", 0).Bool()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = rc.Get(ctx, "mykey").Result()
+	require.ErrorIs(t, err, redis.Nil)
+}
+
+func TestDMap_Redis_KeyPrefix(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := newRedisCompatService(cluster, ":")
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	rc := s.client.Get(s.rt.This().String())
+
+	require.NoError(t, rc.Set(ctx, "users:42", "alice", 0).Err())
+	require.NoError(t, rc.Set(ctx, "nokey", "value", 0).Err())
+
+	dm, err := s.getOrCreateDMap("users")
+	require.NoError(t, err)
+	entry, err := dm.Get(ctx, "users:42")
+	require.NoError(t, err)
+	require.Equal(t, []byte("alice"), entry.Value())
+
+	dm, err = s.getOrCreateDMap("default")
+	require.NoError(t, err)
+	_, err = dm.Get(ctx, "nokey")
+	require.NoError(t, err)
+	_, err = dm.Get(ctx, "users:42")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestDMap_Redis_Scan(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := newRedisCompatService(cluster, ":")
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	rc := s.client.Get(s.rt.This().String())
+
+	allKeys := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("users:%d", i)
+		require.NoError(t, rc.Set(ctx, key, i, 0).Err())
+		allKeys[key] = false
+		require.NoError(t, rc.Set(ctx, testutil.ToKey(i), i, 0).Err())
+	}
+
+	var cursor uint64
+	for {
+		var keys []string
+		var err error
+		keys, cursor, err = rc.Scan(ctx, cursor, "users:*", 7).Result()
+		require.NoError(t, err)
+		for _, key := range keys {
+			require.True(t, strings.HasPrefix(key, "users:"))
+			allKeys[key] = true
+		}
+		if cursor == 0 {
+			break
+		}
+	}
+
+	for key, found := range allKeys {
+		require.True(t, found, key)
+	}
+
+	var total int
+	iter := rc.Scan(ctx, 0, "", 0).Iterator()
+	for iter.Next(ctx) {
+		require.False(t, strings.HasPrefix(iter.Val(), "users:"))
+		total++
+	}
+	require.NoError(t, iter.Err())
+	require.Equal(t, 100, total)
+}
+
+func TestDMap_Redis_Moved(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s1 := newRedisCompatService(cluster, "")
+	s2 := newRedisCompatService(cluster, "")
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	rc := s1.client.Get(s1.rt.This().String())
+
+	var localKey, remoteKey string
+	var remotePartID uint64
+	for i := 0; localKey == "" || remoteKey == ""; i++ {
+		key := testutil.ToKey(i)
+		hkey := partitions.HKey("default", key)
+		part := s1.primary.PartitionByHKey(hkey)
+		if part.Owner().CompareByName(s1.rt.This()) {
+			localKey = key
+		} else {
+			remoteKey = key
+			remotePartID = part.ID()
+		}
+	}
+
+	require.NoError(t, rc.Set(ctx, localKey, "value", 0).Err())
+
+	err := rc.Set(ctx, remoteKey, "value", 0).Err()
+	require.Error(t, err)
+	require.Equal(t, fmt.Sprintf("MOVED %d %s", remotePartID, s2.rt.This().String()), err.Error())
+
+	err = rc.MGet(ctx, localKey, remoteKey).Err()
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), "CROSSSLOT"))
+
+	// Follow the redirect.
+	rc2 := s2.client.Get(s2.rt.This().String())
+	require.NoError(t, rc2.Set(ctx, remoteKey, "value", 0).Err())
+	value, err := rc2.Get(ctx, remoteKey).Result()
+	require.NoError(t, err)
+	require.Equal(t, "value", value)
+}
@@ -0,0 +1,50 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmap
+
+import (
+	"fmt"
+
+	"github.com/olric-data/olric/config"
+)
+
+// Reload applies a new DMap configuration to the running service. The existing
+// DMaps use the new configuration immediately, and the new ones are created
+// with it. The storage engines cannot be changed without a restart, so the
+// running ones are kept. If the configuration is invalid for any existing
+// DMap, nothing is changed.
+func (s *Service) Reload(c *config.DMaps) error {
+	s.Lock()
+	defer s.Unlock()
+
+	c.Engine = s.getDMapsConfig().Engine
+
+	configs := make(map[string]*dmapConfig, len(s.dmaps))
+	for name, dm := range s.dmaps {
+		dc := &dmapConfig{}
+		if err := dc.load(c, name); err != nil {
+			return fmt.Errorf("invalid configuration for DMap: %s: %w", name, err)
+		}
+		// The fragments of the DMap are created by the running engine.
+		dc.engine = dm.getConfig().engine
+		configs[name] = dc
+	}
+
+	for name, dc := range configs {
+		s.dmaps[name].config.Store(dc)
+	}
+	s.dmapsConfig.Store(c)
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/testcluster"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDMap_Reload(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	dm, err := s.NewDMap("mydmap")
+	require.NoError(t, err)
+	engine := dm.getConfig().engine
+
+	c := &config.DMaps{
+		TTLDuration:               time.Minute,
+		TriggerCompactionInterval: time.Second,
+		Custom: map[string]config.DMap{
+			"mydmap": {
+				TTLDuration:    time.Hour,
+				MaxKeys:        1000,
+				EvictionPolicy: config.LRUEviction,
+			},
+		},
+	}
+	require.NoError(t, s.Reload(c))
+
+	dc := dm.getConfig()
+	require.Equal(t, time.Hour, dc.ttlDuration)
+	require.Equal(t, 1000, dc.maxKeys)
+	require.Equal(t, config.LRUEviction, dc.evictionPolicy)
+	require.Equal(t, config.DefaultLRUSamples, dc.lruSamples)
+	require.Same(t, engine, dc.engine)
+	require.Equal(t, time.Second, s.getDMapsConfig().TriggerCompactionInterval)
+
+	// The new DMaps are created with the new configuration.
+	other, err := s.NewDMap("other")
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, other.getConfig().ttlDuration)
+	require.Same(t, engine, other.getConfig().engine)
+}
+
+func TestDMap_Reload_Invalid(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	dm, err := s.NewDMap("mydmap")
+	require.NoError(t, err)
+	previous := s.getDMapsConfig()
+
+	// LRU eviction requires MaxKeys or MaxInuse.
+	c := &config.DMaps{
+		TTLDuration:    time.Minute,
+		EvictionPolicy: config.LRUEviction,
+	}
+	require.Error(t, s.Reload(c))
+
+	require.Equal(t, time.Duration(0), dm.getConfig().ttlDuration)
+	require.Same(t, previous, s.getDMapsConfig())
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"reflect"
 	"sync"
+	"sync/atomic"
 
 	"github.com/olric-data/olric/config"
 	"github.com/olric-data/olric/events"
@@ -53,9 +54,18 @@ type Service struct {
 	locker  *locker.Locker
 	dmaps   map[string]*DMap
 	storage *storageMap
-	wg      sync.WaitGroup
-	ctx     context.Context
-	cancel  context.CancelFunc
+	// dmapsConfig holds the current *config.DMaps. It's replaced when the
+	// configuration is reloaded.
+	dmapsConfig atomic.Value
+	wg          sync.WaitGroup
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// The clients need the prefixes of the DMap errors, even if there is no
+// Service in the process.
+func init() {
+	registerErrors()
 }
 
 func registerErrors() {
@@ -89,11 +99,16 @@ func NewService(e *environment.Environment) (service.Service, error) {
 		ctx:    ctx,
 		cancel: cancel,
 	}
-	registerErrors()
+	s.dmapsConfig.Store(s.config.DMaps)
 	s.RegisterHandlers()
 	return s, nil
 }
 
+// getDMapsConfig loads the current DMap configuration from atomic.Value and returns.
+func (s *Service) getDMapsConfig() *config.DMaps {
+	return s.dmapsConfig.Load().(*config.DMaps)
+}
+
 func (s *Service) isAlive() bool {
 	select {
 	case <-s.ctx.Done():
@@ -0,0 +1,155 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*Package metrics implements the Prometheus text exposition format.*/
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Type denotes the type of metric family.
+type Type string
+
+const (
+	// Counter is a monotonically increasing value.
+	Counter Type = "counter"
+
+	// Gauge is a value that can arbitrarily go up and down.
+	Gauge Type = "gauge"
+
+	// Histogram samples observations and counts them in buckets.
+	Histogram Type = "histogram"
+)
+
+// Label is a name-value pair that identifies a sample of a metric family.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single value of a metric family.
+type Sample struct {
+	// Suffix is appended to the name of the family, e.g. _bucket, _sum and
+	// _count for histograms.
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+// Family is a group of samples with the same name, help text and type.
+type Family struct {
+	Name    string
+	Help    string
+	Type    Type
+	Samples []Sample
+}
+
+// NewFamily returns a new, empty metric family.
+func NewFamily(name, help string, typ Type) *Family {
+	return &Family{
+		Name: name,
+		Help: help,
+		Type: typ,
+	}
+}
+
+// Add appends a new sample to the family.
+func (f *Family) Add(value float64, labels ...Label) {
+	f.Samples = append(f.Samples, Sample{
+		Labels: labels,
+		Value:  value,
+	})
+}
+
+// AddWithSuffix appends a new sample whose name is the family name followed by suffix.
+func (f *Family) AddWithSuffix(suffix string, value float64, labels ...Label) {
+	f.Samples = append(f.Samples, Sample{
+		Suffix: suffix,
+		Labels: labels,
+		Value:  value,
+	})
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// FormatFloat formats a sample value as required by the text exposition format.
+func FormatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func writeSample(w *bufio.Writer, name string, s Sample) {
+	w.WriteString(name)
+	w.WriteString(s.Suffix)
+	if len(s.Labels) > 0 {
+		w.WriteByte('{')
+		for i, label := range s.Labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(label.Name)
+			w.WriteString(`="`)
+			labelEscaper.WriteString(w, label.Value)
+			w.WriteByte('"')
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(FormatFloat(s.Value))
+	w.WriteByte('\n')
+}
+
+// Write encodes the metric families in the text exposition format. Families
+// without samples are skipped.
+func Write(w io.Writer, families []*Family) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		if len(f.Samples) == 0 {
+			continue
+		}
+
+		bw.WriteString("# HELP ")
+		bw.WriteString(f.Name)
+		bw.WriteByte(' ')
+		helpEscaper.WriteString(bw, f.Help)
+		bw.WriteString("\n# TYPE ")
+		bw.WriteString(f.Name)
+		bw.WriteByte(' ')
+		bw.WriteString(string(f.Type))
+		bw.WriteByte('\n')
+
+		for _, s := range f.Samples {
+			writeSample(bw, f.Name, s)
+		}
+	}
+	return bw.Flush()
+}
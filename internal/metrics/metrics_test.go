@@ -0,0 +1,75 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_Write(t *testing.T) {
+	commands := NewFamily("olric_commands_total", "Total number of commands.", Counter)
+	commands.Add(42, Label{Name: "member", Value: "127.0.0.1:3320"})
+
+	length := NewFamily("olric_dmap_length", "Number of keys.", Gauge)
+	length.Add(10,
+		Label{Name: "member", Value: "127.0.0.1:3320"},
+		Label{Name: "dmap", Value: `my"dmap`},
+		Label{Name: "partition", Value: "1"},
+	)
+
+	empty := NewFamily("olric_empty", "Empty family.", Gauge)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, []*Family{commands, empty, length}))
+
+	expected := `# HELP olric_commands_total Total number of commands.
+# TYPE olric_commands_total counter
+olric_commands_total{member="127.0.0.1:3320"} 42
+# HELP olric_dmap_length Number of keys.
+# TYPE olric_dmap_length gauge
+olric_dmap_length{member="127.0.0.1:3320",dmap="my\"dmap",partition="1"} 10
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestMetrics_Write_Suffix(t *testing.T) {
+	h := NewFamily("olric_latency_seconds", "Latency.", Histogram)
+	h.AddWithSuffix("_bucket", 1, Label{Name: "le", Value: FormatFloat(math.Inf(1))})
+	h.AddWithSuffix("_sum", 0.25)
+	h.AddWithSuffix("_count", 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, []*Family{h}))
+
+	expected := `# HELP olric_latency_seconds Latency.
+# TYPE olric_latency_seconds histogram
+olric_latency_seconds_bucket{le="+Inf"} 1
+olric_latency_seconds_sum 0.25
+olric_latency_seconds_count 1
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestMetrics_FormatFloat(t *testing.T) {
+	require.Equal(t, "+Inf", FormatFloat(math.Inf(1)))
+	require.Equal(t, "-Inf", FormatFloat(math.Inf(-1)))
+	require.Equal(t, "NaN", FormatFloat(math.NaN()))
+	require.Equal(t, "1.5", FormatFloat(1.5))
+	require.Equal(t, "1e+06", FormatFloat(1000000))
+}
@@ -16,7 +16,9 @@ package protocol
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/olric-data/olric/internal/util"
 	"github.com/redis/go-redis/v9"
 	"github.com/tidwall/redcon"
 )
@@ -62,3 +64,264 @@ func ParseClusterMembers(cmd redcon.Command) (*ClusterMembers, error) {
 	c := NewClusterMembers()
 	return c, nil
 }
+
+// parseClusterArgCommand parses the cluster-wide commands that take a single
+// argument, e.g. a gossip encryption key, and an optional LC argument to run
+// the command only on the receiving member.
+func parseClusterArgCommand(cmd redcon.Command) (string, bool, error) {
+	if len(cmd.Args) < 2 || len(cmd.Args) > 3 {
+		return "", false, errWrongNumber(cmd.Args)
+	}
+
+	var local bool
+	if len(cmd.Args) == 3 {
+		arg := util.BytesToString(cmd.Args[2])
+		if arg != "LC" {
+			return "", false, fmt.Errorf("%w: %s", ErrInvalidArgument, arg)
+		}
+		local = true
+	}
+	return util.BytesToString(cmd.Args[1]), local, nil
+}
+
+type ClusterKeyringInstall struct {
+	Key   string
+	Local bool
+}
+
+func NewClusterKeyringInstall(key string) *ClusterKeyringInstall {
+	return &ClusterKeyringInstall{
+		Key: key,
+	}
+}
+
+func (c *ClusterKeyringInstall) SetLocal() *ClusterKeyringInstall {
+	c.Local = true
+	return c
+}
+
+func (c *ClusterKeyringInstall) Command(ctx context.Context) *redis.StatusCmd {
+	var args []interface{}
+	args = append(args, Cluster.KeyringInstall)
+	args = append(args, c.Key)
+	if c.Local {
+		args = append(args, "LC")
+	}
+	return redis.NewStatusCmd(ctx, args...)
+}
+
+func ParseClusterKeyringInstall(cmd redcon.Command) (*ClusterKeyringInstall, error) {
+	key, local, err := parseClusterArgCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterKeyringInstall{Key: key, Local: local}, nil
+}
+
+type ClusterKeyringUse struct {
+	Key   string
+	Local bool
+}
+
+func NewClusterKeyringUse(key string) *ClusterKeyringUse {
+	return &ClusterKeyringUse{
+		Key: key,
+	}
+}
+
+func (c *ClusterKeyringUse) SetLocal() *ClusterKeyringUse {
+	c.Local = true
+	return c
+}
+
+func (c *ClusterKeyringUse) Command(ctx context.Context) *redis.StatusCmd {
+	var args []interface{}
+	args = append(args, Cluster.KeyringUse)
+	args = append(args, c.Key)
+	if c.Local {
+		args = append(args, "LC")
+	}
+	return redis.NewStatusCmd(ctx, args...)
+}
+
+func ParseClusterKeyringUse(cmd redcon.Command) (*ClusterKeyringUse, error) {
+	key, local, err := parseClusterArgCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterKeyringUse{Key: key, Local: local}, nil
+}
+
+type ClusterKeyringRemove struct {
+	Key   string
+	Local bool
+}
+
+func NewClusterKeyringRemove(key string) *ClusterKeyringRemove {
+	return &ClusterKeyringRemove{
+		Key: key,
+	}
+}
+
+func (c *ClusterKeyringRemove) SetLocal() *ClusterKeyringRemove {
+	c.Local = true
+	return c
+}
+
+func (c *ClusterKeyringRemove) Command(ctx context.Context) *redis.StatusCmd {
+	var args []interface{}
+	args = append(args, Cluster.KeyringRemove)
+	args = append(args, c.Key)
+	if c.Local {
+		args = append(args, "LC")
+	}
+	return redis.NewStatusCmd(ctx, args...)
+}
+
+func ParseClusterKeyringRemove(cmd redcon.Command) (*ClusterKeyringRemove, error) {
+	key, local, err := parseClusterArgCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterKeyringRemove{Key: key, Local: local}, nil
+}
+
+type ClusterKeyringList struct {
+	Local bool
+}
+
+func NewClusterKeyringList() *ClusterKeyringList {
+	return &ClusterKeyringList{}
+}
+
+func (c *ClusterKeyringList) SetLocal() *ClusterKeyringList {
+	c.Local = true
+	return c
+}
+
+func (c *ClusterKeyringList) Command(ctx context.Context) *redis.SliceCmd {
+	var args []interface{}
+	args = append(args, Cluster.KeyringList)
+	if c.Local {
+		args = append(args, "LC")
+	}
+	return redis.NewSliceCmd(ctx, args...)
+}
+
+func ParseClusterKeyringList(cmd redcon.Command) (*ClusterKeyringList, error) {
+	if len(cmd.Args) > 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	c := NewClusterKeyringList()
+	if len(cmd.Args) == 2 {
+		arg := util.BytesToString(cmd.Args[1])
+		if arg != "LC" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, arg)
+		}
+		c.SetLocal()
+	}
+	return c, nil
+}
+
+type ClusterPasswordAdd struct {
+	Password string
+	Local    bool
+}
+
+func NewClusterPasswordAdd(password string) *ClusterPasswordAdd {
+	return &ClusterPasswordAdd{
+		Password: password,
+	}
+}
+
+func (c *ClusterPasswordAdd) SetLocal() *ClusterPasswordAdd {
+	c.Local = true
+	return c
+}
+
+func (c *ClusterPasswordAdd) Command(ctx context.Context) *redis.StatusCmd {
+	var args []interface{}
+	args = append(args, Cluster.PasswordAdd)
+	args = append(args, c.Password)
+	if c.Local {
+		args = append(args, "LC")
+	}
+	return redis.NewStatusCmd(ctx, args...)
+}
+
+func ParseClusterPasswordAdd(cmd redcon.Command) (*ClusterPasswordAdd, error) {
+	password, local, err := parseClusterArgCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterPasswordAdd{Password: password, Local: local}, nil
+}
+
+type ClusterPasswordPromote struct {
+	Password string
+	Local    bool
+}
+
+func NewClusterPasswordPromote(password string) *ClusterPasswordPromote {
+	return &ClusterPasswordPromote{
+		Password: password,
+	}
+}
+
+func (c *ClusterPasswordPromote) SetLocal() *ClusterPasswordPromote {
+	c.Local = true
+	return c
+}
+
+func (c *ClusterPasswordPromote) Command(ctx context.Context) *redis.StatusCmd {
+	var args []interface{}
+	args = append(args, Cluster.PasswordPromote)
+	args = append(args, c.Password)
+	if c.Local {
+		args = append(args, "LC")
+	}
+	return redis.NewStatusCmd(ctx, args...)
+}
+
+func ParseClusterPasswordPromote(cmd redcon.Command) (*ClusterPasswordPromote, error) {
+	password, local, err := parseClusterArgCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterPasswordPromote{Password: password, Local: local}, nil
+}
+
+type ClusterPasswordRetire struct {
+	Password string
+	Local    bool
+}
+
+func NewClusterPasswordRetire(password string) *ClusterPasswordRetire {
+	return &ClusterPasswordRetire{
+		Password: password,
+	}
+}
+
+func (c *ClusterPasswordRetire) SetLocal() *ClusterPasswordRetire {
+	c.Local = true
+	return c
+}
+
+func (c *ClusterPasswordRetire) Command(ctx context.Context) *redis.StatusCmd {
+	var args []interface{}
+	args = append(args, Cluster.PasswordRetire)
+	args = append(args, c.Password)
+	if c.Local {
+		args = append(args, "LC")
+	}
+	return redis.NewStatusCmd(ctx, args...)
+}
+
+func ParseClusterPasswordRetire(cmd redcon.Command) (*ClusterPasswordRetire, error) {
+	password, local, err := parseClusterArgCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterPasswordRetire{Password: password, Local: local}, nil
+}
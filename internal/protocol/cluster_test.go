@@ -18,6 +18,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,3 +49,149 @@ func TestProtocol_ClusterMembers(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestProtocol_ClusterKeyringInstall(t *testing.T) {
+	installCmd := NewClusterKeyringInstall("cg8StVXbQJ0gPvMd9o7yrg==")
+
+	cmd := stringToCommand(installCmd.Command(context.Background()).String())
+	parsed, err := ParseClusterKeyringInstall(cmd)
+	require.NoError(t, err)
+	require.Equal(t, "cg8StVXbQJ0gPvMd9o7yrg==", parsed.Key)
+	require.False(t, parsed.Local)
+
+	t.Run("CLUSTER.KEYRING.INSTALL with LC", func(t *testing.T) {
+		cmd := stringToCommand(installCmd.SetLocal().Command(context.Background()).String())
+		parsed, err := ParseClusterKeyringInstall(cmd)
+		require.NoError(t, err)
+		require.True(t, parsed.Local)
+	})
+
+	t.Run("CLUSTER.KEYRING.INSTALL invalid argument", func(t *testing.T) {
+		cmd := stringToCommand("cluster.keyring.install cg8StVXbQJ0gPvMd9o7yrg== foobar")
+		_, err := ParseClusterKeyringInstall(cmd)
+		require.ErrorIs(t, err, ErrInvalidArgument)
+	})
+}
+
+func TestProtocol_ClusterKeyringUse(t *testing.T) {
+	useCmd := NewClusterKeyringUse("cg8StVXbQJ0gPvMd9o7yrg==").SetLocal()
+
+	cmd := stringToCommand(useCmd.Command(context.Background()).String())
+	parsed, err := ParseClusterKeyringUse(cmd)
+	require.NoError(t, err)
+	require.Equal(t, "cg8StVXbQJ0gPvMd9o7yrg==", parsed.Key)
+	require.True(t, parsed.Local)
+
+	t.Run("CLUSTER.KEYRING.USE without key", func(t *testing.T) {
+		cmd := stringToCommand("cluster.keyring.use")
+		_, err := ParseClusterKeyringUse(cmd)
+		require.Error(t, err)
+	})
+}
+
+func TestProtocol_ClusterKeyringRemove(t *testing.T) {
+	removeCmd := NewClusterKeyringRemove("cg8StVXbQJ0gPvMd9o7yrg==")
+
+	cmd := stringToCommand(removeCmd.Command(context.Background()).String())
+	parsed, err := ParseClusterKeyringRemove(cmd)
+	require.NoError(t, err)
+	require.Equal(t, "cg8StVXbQJ0gPvMd9o7yrg==", parsed.Key)
+	require.False(t, parsed.Local)
+}
+
+func TestProtocol_ClusterKeyringList(t *testing.T) {
+	listCmd := NewClusterKeyringList()
+
+	cmd := stringToCommand(listCmd.Command(context.Background()).String())
+	parsed, err := ParseClusterKeyringList(cmd)
+	require.NoError(t, err)
+	require.False(t, parsed.Local)
+
+	cmd = stringToCommand(listCmd.SetLocal().Command(context.Background()).String())
+	parsed, err = ParseClusterKeyringList(cmd)
+	require.NoError(t, err)
+	require.True(t, parsed.Local)
+
+	t.Run("CLUSTER.KEYRING.LIST invalid argument", func(t *testing.T) {
+		cmd := stringToCommand("cluster.keyring.list foobar")
+		_, err := ParseClusterKeyringList(cmd)
+		require.ErrorIs(t, err, ErrInvalidArgument)
+	})
+}
+
+func TestProtocol_ClusterPasswordAdd(t *testing.T) {
+	addCmd := NewClusterPasswordAdd("new-password")
+
+	cmd := stringToCommand(addCmd.Command(context.Background()).String())
+	parsed, err := ParseClusterPasswordAdd(cmd)
+	require.NoError(t, err)
+	require.Equal(t, "new-password", parsed.Password)
+	require.False(t, parsed.Local)
+
+	cmd = stringToCommand(addCmd.SetLocal().Command(context.Background()).String())
+	parsed, err = ParseClusterPasswordAdd(cmd)
+	require.NoError(t, err)
+	require.True(t, parsed.Local)
+}
+
+func TestProtocol_ClusterPasswordPromote(t *testing.T) {
+	promoteCmd := NewClusterPasswordPromote("new-password").SetLocal()
+
+	cmd := stringToCommand(promoteCmd.Command(context.Background()).String())
+	parsed, err := ParseClusterPasswordPromote(cmd)
+	require.NoError(t, err)
+	require.Equal(t, "new-password", parsed.Password)
+	require.True(t, parsed.Local)
+
+	t.Run("CLUSTER.PASSWORD.PROMOTE without password", func(t *testing.T) {
+		cmd := stringToCommand("cluster.password.promote")
+		_, err := ParseClusterPasswordPromote(cmd)
+		require.Error(t, err)
+	})
+}
+
+func TestProtocol_ClusterPasswordRetire(t *testing.T) {
+	retireCmd := NewClusterPasswordRetire("old-password")
+
+	cmd := stringToCommand(retireCmd.Command(context.Background()).String())
+	parsed, err := ParseClusterPasswordRetire(cmd)
+	require.NoError(t, err)
+	require.Equal(t, "old-password", parsed.Password)
+	require.False(t, parsed.Local)
+
+	t.Run("CLUSTER.PASSWORD.RETIRE invalid argument", func(t *testing.T) {
+		cmd := stringToCommand("cluster.password.retire old-password foobar")
+		_, err := ParseClusterPasswordRetire(cmd)
+		require.ErrorIs(t, err, ErrInvalidArgument)
+	})
+}
+
+func TestProtocol_IsInternalCommand_LocalVariants(t *testing.T) {
+	key := "cg8StVXbQJ0gPvMd9o7yrg=="
+	ctx := context.Background()
+	commands := []struct {
+		cluster redis.Cmder
+		local   redis.Cmder
+	}{
+		{NewClusterKeyringInstall(key).Command(ctx), NewClusterKeyringInstall(key).SetLocal().Command(ctx)},
+		{NewClusterKeyringUse(key).Command(ctx), NewClusterKeyringUse(key).SetLocal().Command(ctx)},
+		{NewClusterKeyringRemove(key).Command(ctx), NewClusterKeyringRemove(key).SetLocal().Command(ctx)},
+		{NewClusterKeyringList().Command(ctx), NewClusterKeyringList().SetLocal().Command(ctx)},
+		{NewClusterPasswordAdd("secret").Command(ctx), NewClusterPasswordAdd("secret").SetLocal().Command(ctx)},
+		{NewClusterPasswordPromote("secret").Command(ctx), NewClusterPasswordPromote("secret").SetLocal().Command(ctx)},
+		{NewClusterPasswordRetire("secret").Command(ctx), NewClusterPasswordRetire("secret").SetLocal().Command(ctx)},
+	}
+	for _, c := range commands {
+		cmd := stringToCommand(c.cluster.String())
+		require.False(t, IsInternalCommand(c.cluster.Name(), cmd.Args), c.cluster.String())
+
+		cmd = stringToCommand(c.local.String())
+		require.True(t, IsInternalCommand(c.local.Name(), cmd.Args), c.local.String())
+	}
+
+	// A key or a password that reads LC is not the LC argument.
+	cmd := stringToCommand("cluster.keyring.install LC")
+	require.False(t, IsInternalCommand(Cluster.KeyringInstall, cmd.Args))
+	cmd = stringToCommand("cluster.password.add LC")
+	require.False(t, IsInternalCommand(Cluster.PasswordAdd, cmd.Args))
+}
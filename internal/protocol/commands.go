@@ -17,13 +17,27 @@ package protocol
 const StatusOK = "OK"
 
 type ClusterCommands struct {
-	RoutingTable string
-	Members      string
+	RoutingTable    string
+	Members         string
+	KeyringInstall  string
+	KeyringUse      string
+	KeyringRemove   string
+	KeyringList     string
+	PasswordAdd     string
+	PasswordPromote string
+	PasswordRetire  string
 }
 
 var Cluster = &ClusterCommands{
-	RoutingTable: "cluster.routingtable",
-	Members:      "cluster.members",
+	RoutingTable:    "cluster.routingtable",
+	Members:         "cluster.members",
+	KeyringInstall:  "cluster.keyring.install",
+	KeyringUse:      "cluster.keyring.use",
+	KeyringRemove:   "cluster.keyring.remove",
+	KeyringList:     "cluster.keyring.list",
+	PasswordAdd:     "cluster.password.add",
+	PasswordPromote: "cluster.password.promote",
+	PasswordRetire:  "cluster.password.retire",
 }
 
 type InternalCommands struct {
@@ -31,24 +45,103 @@ type InternalCommands struct {
 	UpdateRouting       string
 	LengthOfPart        string
 	ClusterRoutingTable string
+	MoveStream          string
 }
 
 var Internal = &InternalCommands{
 	MoveFragment:  "internal.node.movefragment",
 	UpdateRouting: "internal.node.updaterouting",
 	LengthOfPart:  "internal.node.lengthofpart",
+	MoveStream:    "internal.node.movestream",
+}
+
+// internalCommands are only sent by the cluster members to each other.
+var internalCommands = map[string]struct{}{
+	Internal.MoveFragment:  {},
+	Internal.UpdateRouting: {},
+	Internal.LengthOfPart:  {},
+	Internal.MoveStream:    {},
+	DMap.GetEntry:          {},
+	DMap.PutEntry:          {},
+	DMap.DelEntry:          {},
+	PubSub.PublishInternal: {},
+	Stream.Replicate:       {},
+}
+
+// localVariants are the cluster-wide commands that have an LC variant. The
+// member that receives a command sends its LC variant to the other members, and
+// the LC variant only runs on the receiving member. The value is the position of
+// the LC argument.
+var localVariants = map[string]int{
+	Cluster.KeyringInstall:  2,
+	Cluster.KeyringUse:      2,
+	Cluster.KeyringRemove:   2,
+	Cluster.KeyringList:     1,
+	Cluster.PasswordAdd:     2,
+	Cluster.PasswordPromote: 2,
+	Cluster.PasswordRetire:  2,
+}
+
+// IsInternalCommand returns true if the command is only sent by the cluster
+// members to each other. args are the arguments of the command, including
+// the command itself.
+func IsInternalCommand(command string, args [][]byte) bool {
+	if _, ok := internalCommands[command]; ok {
+		return true
+	}
+	// An LC variant changes a single member, it would leave the members
+	// in different states if a client could send it.
+	i, ok := localVariants[command]
+	return ok && len(args) == i+1 && string(args[i]) == "LC"
+}
+
+// secretCommands carry a password or an encryption key in their arguments.
+var secretCommands = map[string]struct{}{
+	Generic.Auth:            {},
+	Generic.Hello:           {},
+	Cluster.KeyringInstall:  {},
+	Cluster.KeyringUse:      {},
+	Cluster.KeyringRemove:   {},
+	Cluster.PasswordAdd:     {},
+	Cluster.PasswordPromote: {},
+	Cluster.PasswordRetire:  {},
+}
+
+// HasSecret returns true if the arguments of the command carry a secret, so
+// they must not be recorded.
+func HasSecret(command string) bool {
+	_, ok := secretCommands[command]
+	return ok
 }
 
 type GenericCommands struct {
-	Ping  string
-	Stats string
-	Auth  string
+	Ping         string
+	Stats        string
+	Auth         string
+	Hello        string
+	Trace        string
+	SlowLog      string
+	SlowLogGet   string
+	SlowLogLen   string
+	SlowLogReset string
+	ACL          string
+	ACLWhoAmI    string
+	ACLList      string
 }
 
 var Generic = &GenericCommands{
-	Ping:  "ping",
-	Stats: "stats",
-	Auth:  "auth",
+	Ping:         "ping",
+	Stats:        "stats",
+	Auth:         "auth",
+	Hello:        "hello",
+	Trace:        "trace",
+	SlowLog:      "slowlog",
+	SlowLogGet:   "slowlog get",
+	SlowLogLen:   "slowlog len",
+	SlowLogReset: "slowlog reset",
+	ACL:          "acl",
+	ACLWhoAmI:    "acl whoami",
+	ACLList:      "acl list",
 }
 
 type DMapCommands struct {
@@ -102,9 +195,12 @@ type PubSubCommands struct {
 	PublishInternal string
 	Subscribe       string
 	PSubscribe      string
+	SPublish        string
+	SSubscribe      string
 	PubSubChannels  string
 	PubSubNumpat    string
 	PubSubNumsub    string
+	PubSubResume    string
 }
 
 var PubSub = &PubSubCommands{
@@ -113,7 +209,66 @@ var PubSub = &PubSubCommands{
 	PublishInternal: "publish.internal",
 	Subscribe:       "subscribe",
 	PSubscribe:      "psubscribe",
+	SPublish:        "spublish",
+	SSubscribe:      "ssubscribe",
 	PubSubChannels:  "pubsub channels",
 	PubSubNumpat:    "pubsub numpat",
 	PubSubNumsub:    "pubsub numsub",
+	PubSubResume:    "pubsub resume",
+}
+
+type StreamCommands struct {
+	XAdd         string
+	XLen         string
+	XInfo        string
+	XRange       string
+	XRead        string
+	XGroupCreate string
+	XReadGroup   string
+	XAck         string
+	XPending     string
+	XClaim       string
+	XAutoClaim   string
+	Replicate    string
+}
+
+var Stream = &StreamCommands{
+	XAdd:         "stream.xadd",
+	XLen:         "stream.xlen",
+	XInfo:        "stream.xinfo",
+	XRange:       "stream.xrange",
+	XRead:        "stream.xread",
+	XGroupCreate: "stream.xgroupcreate",
+	XReadGroup:   "stream.xreadgroup",
+	XAck:         "stream.xack",
+	XPending:     "stream.xpending",
+	XClaim:       "stream.xclaim",
+	XAutoClaim:   "stream.xautoclaim",
+	Replicate:    "stream.replicate",
+}
+
+// RedisCommands are the standard Redis commands served by the Redis
+// compatibility layer. They are only registered if it's enabled.
+type RedisCommands struct {
+	Get    string
+	Set    string
+	Del    string
+	Expire string
+	TTL    string
+	Incr   string
+	Scan   string
+	Exists string
+	MGet   string
+}
+
+var Redis = &RedisCommands{
+	Get:    "get",
+	Set:    "set",
+	Del:    "del",
+	Expire: "expire",
+	TTL:    "ttl",
+	Incr:   "incr",
+	Scan:   "scan",
+	Exists: "exists",
+	MGet:   "mget",
 }
@@ -25,6 +25,17 @@ import (
 
 var ErrInvalidArgument = errors.New("invalid argument")
 
+// ErrMoved means that the partition of a key or a sharded channel is owned
+// by another cluster member. The reply is in Redis Cluster format:
+// MOVED <partition-id> <owner>
+var ErrMoved = errors.New("moved")
+
+// ErrNoProto means that the protocol version requested by HELLO is not supported.
+var ErrNoProto = errors.New("unsupported protocol version")
+
+// ErrNoPerm means that the connection has no permissions to run the command.
+var ErrNoPerm = errors.New("no permissions to run the command")
+
 var GenericError = "ERR"
 
 var errorWithPrefix = struct {
@@ -38,6 +49,9 @@ var errorWithPrefix = struct {
 
 func init() {
 	SetError("INVALIDARGUMENT", ErrInvalidArgument)
+	SetError("MOVED", ErrMoved)
+	SetError("NOPROTO", ErrNoProto)
+	SetError("NOPERM", ErrNoPerm)
 }
 
 func SetError(prefix string, err error) {
@@ -100,6 +114,11 @@ func WriteError(conn redcon.Conn, err error) {
 	conn.WriteError(fmt.Sprintf("%s %s", prefix, err.Error()))
 }
 
+// WriteMoved writes a MOVED error that points to the owner of the partition.
+func WriteMoved(conn redcon.Conn, partID uint64, owner string) {
+	conn.WriteError(fmt.Sprintf("MOVED %d %s", partID, owner))
+}
+
 func errWrongNumber(args [][]byte) error {
 	sb := strings.Builder{}
 	for {
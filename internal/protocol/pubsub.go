@@ -16,6 +16,9 @@ package protocol
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/olric-data/olric/internal/util"
 	"github.com/redis/go-redis/v9"
@@ -54,8 +57,9 @@ func ParsePublishCommand(cmd redcon.Command) (*Publish, error) {
 }
 
 type PublishInternal struct {
-	Channel string
-	Message string
+	Channel  string
+	Message  string
+	Sequence uint64
 }
 
 func NewPublishInternal(channel, message string) *PublishInternal {
@@ -65,11 +69,19 @@ func NewPublishInternal(channel, message string) *PublishInternal {
 	}
 }
 
+func (p *PublishInternal) SetSequence(sequence uint64) *PublishInternal {
+	p.Sequence = sequence
+	return p
+}
+
 func (p *PublishInternal) Command(ctx context.Context) *redis.IntCmd {
 	var args []interface{}
 	args = append(args, PubSub.PublishInternal)
 	args = append(args, p.Channel)
 	args = append(args, p.Message)
+	if p.Sequence != 0 {
+		args = append(args, "SEQ", p.Sequence)
+	}
 	return redis.NewIntCmd(ctx, args...)
 }
 
@@ -78,10 +90,29 @@ func ParsePublishInternalCommand(cmd redcon.Command) (*PublishInternal, error) {
 		return nil, errWrongNumber(cmd.Args)
 	}
 
-	return NewPublishInternal(
+	p := NewPublishInternal(
 		util.BytesToString(cmd.Args[1]), // Channel
 		util.BytesToString(cmd.Args[2]), // Message
-	), nil
+	)
+
+	args := cmd.Args[3:]
+	for len(args) > 0 {
+		switch arg := strings.ToUpper(util.BytesToString(args[0])); arg {
+		case "SEQ":
+			if len(args) < 2 {
+				return nil, errWrongNumber(cmd.Args)
+			}
+			sequence, err := strconv.ParseUint(util.BytesToString(args[1]), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			p.SetSequence(sequence)
+			args = args[2:]
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, arg)
+		}
+	}
+	return p, nil
 }
 
 type Subscribe struct {
@@ -152,6 +183,71 @@ func ParsePSubscribeCommand(cmd redcon.Command) (*PSubscribe, error) {
 	return NewPSubscribe(patterns...), nil
 }
 
+type SPublish struct {
+	Channel string
+	Message string
+}
+
+func NewSPublish(channel, message string) *SPublish {
+	return &SPublish{
+		Channel: channel,
+		Message: message,
+	}
+}
+
+func (p *SPublish) Command(ctx context.Context) *redis.IntCmd {
+	var args []interface{}
+	args = append(args, PubSub.SPublish)
+	args = append(args, p.Channel)
+	args = append(args, p.Message)
+	return redis.NewIntCmd(ctx, args...)
+}
+
+func ParseSPublishCommand(cmd redcon.Command) (*SPublish, error) {
+	if len(cmd.Args) < 3 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	return NewSPublish(
+		util.BytesToString(cmd.Args[1]), // Channel
+		util.BytesToString(cmd.Args[2]), // Message
+	), nil
+}
+
+type SSubscribe struct {
+	Channels []string
+}
+
+func NewSSubscribe(channels ...string) *SSubscribe {
+	return &SSubscribe{
+		Channels: channels,
+	}
+}
+
+func (s *SSubscribe) Command(ctx context.Context) *redis.SliceCmd {
+	var args []interface{}
+	args = append(args, PubSub.SSubscribe)
+	for _, channel := range s.Channels {
+		args = append(args, channel)
+	}
+	return redis.NewSliceCmd(ctx, args...)
+}
+
+func ParseSSubscribeCommand(cmd redcon.Command) (*SSubscribe, error) {
+	if len(cmd.Args) < 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	var channels []string
+	args := cmd.Args[1:]
+	for len(args) > 0 {
+		arg := util.BytesToString(args[0])
+		channels = append(channels, arg)
+		args = args[1:]
+	}
+	return NewSSubscribe(channels...), nil
+}
+
 type PubSubChannels struct {
 	Pattern string
 }
@@ -239,3 +335,61 @@ func ParsePubSubNumsubCommand(cmd redcon.Command) (*PubSubNumsub, error) {
 	}
 	return NewPubSubNumsub(channels...), nil
 }
+
+// LatestSequence resumes a channel from its latest message.
+const LatestSequence int64 = -1
+
+type PubSubResume struct {
+	Channels  []string
+	Sequences []int64
+}
+
+func NewPubSubResume() *PubSubResume {
+	return &PubSubResume{}
+}
+
+// Add adds a channel with its last seen sequence ID. LatestSequence means
+// the latest message of the channel.
+func (ps *PubSubResume) Add(channel string, sequence int64) *PubSubResume {
+	ps.Channels = append(ps.Channels, channel)
+	ps.Sequences = append(ps.Sequences, sequence)
+	return ps
+}
+
+func (ps *PubSubResume) Command(ctx context.Context) *redis.IntSliceCmd {
+	var args []interface{}
+	args = append(args, PubSub.PubSub, "resume")
+	for i, channel := range ps.Channels {
+		args = append(args, channel)
+		if ps.Sequences[i] == LatestSequence {
+			args = append(args, "$")
+		} else {
+			args = append(args, ps.Sequences[i])
+		}
+	}
+	return redis.NewIntSliceCmd(ctx, args...)
+}
+
+func ParsePubSubResumeCommand(cmd redcon.Command) (*PubSubResume, error) {
+	if len(cmd.Args) < 2 || len(cmd.Args)%2 != 0 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	ps := NewPubSubResume()
+	args := cmd.Args[2:]
+	for len(args) > 0 {
+		channel := util.BytesToString(args[0])
+		rawSequence := util.BytesToString(args[1])
+		if rawSequence == "$" {
+			ps.Add(channel, LatestSequence)
+		} else {
+			sequence, err := strconv.ParseInt(rawSequence, 10, 64)
+			if err != nil || sequence < 0 {
+				return nil, fmt.Errorf("%w: invalid sequence: %s", ErrInvalidArgument, rawSequence)
+			}
+			ps.Add(channel, sequence)
+		}
+		args = args[2:]
+	}
+	return ps, nil
+}
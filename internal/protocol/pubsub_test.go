@@ -51,6 +51,28 @@ func TestProtocol_ParsePSubscribeCommand(t *testing.T) {
 	require.Equal(t, patterns, parsed.Patterns)
 }
 
+func TestProtocol_ParseSPublishCommand(t *testing.T) {
+	spublishCmd := NewSPublish("my-pubsub", "my-message")
+
+	cmd := stringToCommand(spublishCmd.Command(context.Background()).String())
+	parsed, err := ParseSPublishCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, "my-pubsub", parsed.Channel)
+	require.Equal(t, "my-message", parsed.Message)
+}
+
+func TestProtocol_ParseSSubscribeCommand(t *testing.T) {
+	ssubscribeCmd := NewSSubscribe("channel-1", "channel-2")
+
+	cmd := stringToCommand(ssubscribeCmd.Command(context.Background()).String())
+	parsed, err := ParseSSubscribeCommand(cmd)
+	require.NoError(t, err)
+
+	channels := []string{"channel-1", "channel-2"}
+	require.Equal(t, channels, parsed.Channels)
+}
+
 func TestProtocol_PubSubChannels(t *testing.T) {
 	pubsubChannelsCmd := NewPubSubChannels()
 
@@ -89,3 +111,28 @@ func TestProtocol_PubSubNumsub(t *testing.T) {
 	channels := []string{"channel-1", "channel-2", "channel-3"}
 	require.Equal(t, channels, parsed.Channels)
 }
+
+func TestProtocol_ParsePublishInternalCommand_Sequence(t *testing.T) {
+	publishIntCmd := NewPublishInternal("my-pubsub", "my-message").SetSequence(42)
+
+	cmd := stringToCommand(publishIntCmd.Command(context.Background()).String())
+	parsed, err := ParsePublishInternalCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, "my-pubsub", parsed.Channel)
+	require.Equal(t, "my-message", parsed.Message)
+	require.Equal(t, uint64(42), parsed.Sequence)
+}
+
+func TestProtocol_PubSubResume(t *testing.T) {
+	pubsubResumeCmd := NewPubSubResume().
+		Add("channel-1", 10).
+		Add("channel-2", LatestSequence)
+
+	cmd := stringToCommand(pubsubResumeCmd.Command(context.Background()).String())
+	parsed, err := ParsePubSubResumeCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"channel-1", "channel-2"}, parsed.Channels)
+	require.Equal(t, []int64{10, LatestSequence}, parsed.Sequences)
+}
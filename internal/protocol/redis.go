@@ -0,0 +1,194 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/olric-data/olric/internal/util"
+	"github.com/tidwall/redcon"
+)
+
+// ErrCrossSlot means that the keys of a multi-key command don't belong to the
+// same cluster member.
+var ErrCrossSlot = errors.New("keys in request don't hash to the same member")
+
+func init() {
+	SetError("CROSSSLOT", ErrCrossSlot)
+}
+
+// RedisKey is a single key command of the Redis compatibility layer: GET,
+// TTL and INCR.
+type RedisKey struct {
+	Key string
+}
+
+func ParseRedisKeyCommand(cmd redcon.Command) (*RedisKey, error) {
+	if len(cmd.Args) != 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	return &RedisKey{
+		Key: util.BytesToString(cmd.Args[1]),
+	}, nil
+}
+
+// RedisKeys is a multi-key command of the Redis compatibility layer: DEL,
+// EXISTS and MGET.
+type RedisKeys struct {
+	Keys []string
+}
+
+func ParseRedisKeysCommand(cmd redcon.Command) (*RedisKeys, error) {
+	if len(cmd.Args) < 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	k := &RedisKeys{}
+	for _, key := range cmd.Args[1:] {
+		k.Keys = append(k.Keys, util.BytesToString(key))
+	}
+	return k, nil
+}
+
+type RedisSet struct {
+	Key   string
+	Value []byte
+	EX    int64
+	PX    int64
+	NX    bool
+	XX    bool
+}
+
+func ParseRedisSetCommand(cmd redcon.Command) (*RedisSet, error) {
+	if len(cmd.Args) < 3 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	s := &RedisSet{
+		Key:   util.BytesToString(cmd.Args[1]),
+		Value: cmd.Args[2],
+	}
+
+	args := cmd.Args[3:]
+	for len(args) > 0 {
+		switch arg := strings.ToUpper(util.BytesToString(args[0])); arg {
+		case "NX":
+			s.NX = true
+			args = args[1:]
+			continue
+		case "XX":
+			s.XX = true
+			args = args[1:]
+			continue
+		case "EX", "PX":
+			if len(args) < 2 {
+				return nil, errors.New("syntax error")
+			}
+			ttl, err := strconv.ParseInt(util.BytesToString(args[1]), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			if ttl <= 0 {
+				return nil, errors.New("invalid expire time in 'set' command")
+			}
+			if arg == "EX" {
+				s.EX = ttl
+			} else {
+				s.PX = ttl
+			}
+			args = args[2:]
+			continue
+		default:
+			return nil, errors.New("syntax error")
+		}
+	}
+
+	if (s.NX && s.XX) || (s.EX != 0 && s.PX != 0) {
+		return nil, errors.New("syntax error")
+	}
+
+	return s, nil
+}
+
+type RedisExpire struct {
+	Key     string
+	Seconds int64
+}
+
+func ParseRedisExpireCommand(cmd redcon.Command) (*RedisExpire, error) {
+	if len(cmd.Args) != 3 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	seconds, err := strconv.ParseInt(util.BytesToString(cmd.Args[2]), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisExpire{
+		Key:     util.BytesToString(cmd.Args[1]),
+		Seconds: seconds,
+	}, nil
+}
+
+type RedisScan struct {
+	Cursor uint64
+	Match  string
+	Count  int
+}
+
+func ParseRedisScanCommand(cmd redcon.Command) (*RedisScan, error) {
+	if len(cmd.Args) < 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	cursor, err := strconv.ParseUint(util.BytesToString(cmd.Args[1]), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &RedisScan{
+		Cursor: cursor,
+		Count:  DefaultScanCount,
+	}
+
+	args := cmd.Args[2:]
+	for len(args) > 0 {
+		if len(args) < 2 {
+			return nil, errors.New("syntax error")
+		}
+		switch arg := strings.ToUpper(util.BytesToString(args[0])); arg {
+		case "MATCH":
+			s.Match = util.BytesToString(args[1])
+		case "COUNT":
+			count, err := strconv.Atoi(util.BytesToString(args[1]))
+			if err != nil {
+				return nil, err
+			}
+			if count < 1 {
+				return nil, errors.New("syntax error")
+			}
+			s.Count = count
+		default:
+			return nil, errors.New("syntax error")
+		}
+		args = args[2:]
+	}
+
+	return s, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocol_ParseRedisKeyCommand(t *testing.T) {
+	parsed, err := ParseRedisKeyCommand(stringToCommand("get my-key"))
+	require.NoError(t, err)
+	require.Equal(t, "my-key", parsed.Key)
+
+	_, err = ParseRedisKeyCommand(stringToCommand("get my-key other-key"))
+	require.Error(t, err)
+}
+
+func TestProtocol_ParseRedisKeysCommand(t *testing.T) {
+	parsed, err := ParseRedisKeysCommand(stringToCommand("mget key-1 key-2 key-3"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"key-1", "key-2", "key-3"}, parsed.Keys)
+
+	_, err = ParseRedisKeysCommand(stringToCommand("del"))
+	require.Error(t, err)
+}
+
+func TestProtocol_ParseRedisSetCommand(t *testing.T) {
+	parsed, err := ParseRedisSetCommand(stringToCommand("set my-key my-value"))
+	require.NoError(t, err)
+	require.Equal(t, "my-key", parsed.Key)
+	require.Equal(t, []byte("my-value"), parsed.Value)
+
+	parsed, err = ParseRedisSetCommand(stringToCommand("set my-key my-value ex 10 nx"))
+	require.NoError(t, err)
+	require.Equal(t, int64(10), parsed.EX)
+	require.True(t, parsed.NX)
+
+	parsed, err = ParseRedisSetCommand(stringToCommand("set my-key my-value PX 100 XX"))
+	require.NoError(t, err)
+	require.Equal(t, int64(100), parsed.PX)
+	require.True(t, parsed.XX)
+}
+
+func TestProtocol_ParseRedisSetCommand_Syntax_Error(t *testing.T) {
+	for _, s := range []string{
+		"set my-key my-value NX XX",
+		"set my-key my-value EX 10 PX 100",
+		"set my-key my-value EX 0",
+		"set my-key my-value EX",
+		"set my-key my-value KEEPTTL",
+	} {
+		_, err := ParseRedisSetCommand(stringToCommand(s))
+		require.Error(t, err, s)
+	}
+}
+
+func TestProtocol_ParseRedisExpireCommand(t *testing.T) {
+	parsed, err := ParseRedisExpireCommand(stringToCommand("expire my-key 10"))
+	require.NoError(t, err)
+	require.Equal(t, "my-key", parsed.Key)
+	require.Equal(t, int64(10), parsed.Seconds)
+}
+
+func TestProtocol_ParseRedisScanCommand(t *testing.T) {
+	parsed, err := ParseRedisScanCommand(stringToCommand("scan 0"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), parsed.Cursor)
+	require.Equal(t, DefaultScanCount, parsed.Count)
+
+	parsed, err = ParseRedisScanCommand(stringToCommand("scan 42 MATCH users:* COUNT 100"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), parsed.Cursor)
+	require.Equal(t, "users:*", parsed.Match)
+	require.Equal(t, 100, parsed.Count)
+
+	_, err = ParseRedisScanCommand(stringToCommand("scan 0 COUNT"))
+	require.Error(t, err)
+}
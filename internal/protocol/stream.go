@@ -0,0 +1,660 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olric-data/olric/internal/util"
+	"github.com/redis/go-redis/v9"
+	"github.com/tidwall/redcon"
+)
+
+type XAdd struct {
+	Stream string
+	ID     string
+	Fields map[string]string
+	MaxLen int64
+}
+
+func NewXAdd(stream, id string, fields map[string]string) *XAdd {
+	return &XAdd{
+		Stream: stream,
+		ID:     id,
+		Fields: fields,
+	}
+}
+
+func (x *XAdd) SetMaxLen(maxLen int64) *XAdd {
+	x.MaxLen = maxLen
+	return x
+}
+
+func (x *XAdd) Command(ctx context.Context) *redis.StringCmd {
+	var args []interface{}
+	args = append(args, Stream.XAdd)
+	args = append(args, x.Stream)
+	if x.MaxLen != 0 {
+		args = append(args, "MAXLEN")
+		args = append(args, x.MaxLen)
+	}
+	args = append(args, x.ID)
+
+	// Sort the field names to produce the same command for the same input.
+	fields := make([]string, 0, len(x.Fields))
+	for field := range x.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		args = append(args, field)
+		args = append(args, x.Fields[field])
+	}
+	return redis.NewStringCmd(ctx, args...)
+}
+
+func ParseXAddCommand(cmd redcon.Command) (*XAdd, error) {
+	if len(cmd.Args) < 5 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	x := NewXAdd(
+		util.BytesToString(cmd.Args[1]), // Stream
+		"",
+		make(map[string]string),
+	)
+
+	args := cmd.Args[2:]
+	if strings.ToUpper(util.BytesToString(args[0])) == "MAXLEN" {
+		if len(args) < 2 {
+			return nil, errWrongNumber(cmd.Args)
+		}
+		maxLen, err := strconv.ParseInt(util.BytesToString(args[1]), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		x.SetMaxLen(maxLen)
+		args = args[2:]
+	}
+
+	if len(args) < 3 || len(args)%2 == 0 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+	x.ID = util.BytesToString(args[0])
+
+	args = args[1:]
+	for len(args) > 0 {
+		x.Fields[string(args[0])] = string(args[1])
+		args = args[2:]
+	}
+	return x, nil
+}
+
+type XLen struct {
+	Stream string
+}
+
+func NewXLen(stream string) *XLen {
+	return &XLen{
+		Stream: stream,
+	}
+}
+
+func (x *XLen) Command(ctx context.Context) *redis.IntCmd {
+	var args []interface{}
+	args = append(args, Stream.XLen)
+	args = append(args, x.Stream)
+	return redis.NewIntCmd(ctx, args...)
+}
+
+func ParseXLenCommand(cmd redcon.Command) (*XLen, error) {
+	if len(cmd.Args) < 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	return NewXLen(util.BytesToString(cmd.Args[1])), nil
+}
+
+type XInfo struct {
+	Stream string
+}
+
+func NewXInfo(stream string) *XInfo {
+	return &XInfo{
+		Stream: stream,
+	}
+}
+
+func (x *XInfo) Command(ctx context.Context) *redis.SliceCmd {
+	var args []interface{}
+	args = append(args, Stream.XInfo)
+	args = append(args, x.Stream)
+	return redis.NewSliceCmd(ctx, args...)
+}
+
+func ParseXInfoCommand(cmd redcon.Command) (*XInfo, error) {
+	if len(cmd.Args) < 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	return NewXInfo(util.BytesToString(cmd.Args[1])), nil
+}
+
+type XRange struct {
+	Stream string
+	Start  string
+	End    string
+	Count  int
+}
+
+func NewXRange(stream, start, end string) *XRange {
+	return &XRange{
+		Stream: stream,
+		Start:  start,
+		End:    end,
+	}
+}
+
+func (x *XRange) SetCount(count int) *XRange {
+	x.Count = count
+	return x
+}
+
+func (x *XRange) Command(ctx context.Context) *redis.SliceCmd {
+	var args []interface{}
+	args = append(args, Stream.XRange)
+	args = append(args, x.Stream)
+	args = append(args, x.Start)
+	args = append(args, x.End)
+	if x.Count != 0 {
+		args = append(args, "COUNT")
+		args = append(args, x.Count)
+	}
+	return redis.NewSliceCmd(ctx, args...)
+}
+
+func ParseXRangeCommand(cmd redcon.Command) (*XRange, error) {
+	if len(cmd.Args) < 4 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	x := NewXRange(
+		util.BytesToString(cmd.Args[1]), // Stream
+		util.BytesToString(cmd.Args[2]), // Start
+		util.BytesToString(cmd.Args[3]), // End
+	)
+
+	args := cmd.Args[4:]
+	for len(args) > 0 {
+		switch arg := strings.ToUpper(util.BytesToString(args[0])); arg {
+		case "COUNT":
+			if len(args) < 2 {
+				return nil, fmt.Errorf("%w: %s needs a numerical argument", ErrInvalidArgument, arg)
+			}
+			count, err := strconv.Atoi(util.BytesToString(args[1]))
+			if err != nil {
+				return nil, err
+			}
+			x.SetCount(count)
+			args = args[2:]
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, arg)
+		}
+	}
+
+	return x, nil
+}
+
+type XRead struct {
+	Stream string
+	ID     string
+	Count  int
+	Block  int64
+}
+
+func NewXRead(stream, id string) *XRead {
+	return &XRead{
+		Stream: stream,
+		ID:     id,
+	}
+}
+
+func (x *XRead) SetCount(count int) *XRead {
+	x.Count = count
+	return x
+}
+
+// SetBlock sets the blocking timeout in milliseconds.
+func (x *XRead) SetBlock(block int64) *XRead {
+	x.Block = block
+	return x
+}
+
+func (x *XRead) Command(ctx context.Context) *redis.SliceCmd {
+	var args []interface{}
+	args = append(args, Stream.XRead)
+	args = append(args, x.Stream)
+	args = append(args, x.ID)
+	if x.Count != 0 {
+		args = append(args, "COUNT")
+		args = append(args, x.Count)
+	}
+	if x.Block != 0 {
+		args = append(args, "BLOCK")
+		args = append(args, x.Block)
+	}
+	return redis.NewSliceCmd(ctx, args...)
+}
+
+func ParseXReadCommand(cmd redcon.Command) (*XRead, error) {
+	if len(cmd.Args) < 3 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	x := NewXRead(
+		util.BytesToString(cmd.Args[1]), // Stream
+		util.BytesToString(cmd.Args[2]), // ID
+	)
+
+	count, block, err := parseCountAndBlock(cmd.Args[3:])
+	if err != nil {
+		return nil, err
+	}
+	x.SetCount(count)
+	x.SetBlock(block)
+	return x, nil
+}
+
+type XGroupCreate struct {
+	Stream string
+	Group  string
+	ID     string
+}
+
+func NewXGroupCreate(stream, group, id string) *XGroupCreate {
+	return &XGroupCreate{
+		Stream: stream,
+		Group:  group,
+		ID:     id,
+	}
+}
+
+func (x *XGroupCreate) Command(ctx context.Context) *redis.StatusCmd {
+	var args []interface{}
+	args = append(args, Stream.XGroupCreate)
+	args = append(args, x.Stream)
+	args = append(args, x.Group)
+	args = append(args, x.ID)
+	return redis.NewStatusCmd(ctx, args...)
+}
+
+func ParseXGroupCreateCommand(cmd redcon.Command) (*XGroupCreate, error) {
+	if len(cmd.Args) < 4 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	return NewXGroupCreate(
+		util.BytesToString(cmd.Args[1]), // Stream
+		util.BytesToString(cmd.Args[2]), // Group
+		util.BytesToString(cmd.Args[3]), // ID
+	), nil
+}
+
+type XReadGroup struct {
+	Stream   string
+	Group    string
+	Consumer string
+	ID       string
+	Count    int
+	Block    int64
+}
+
+func NewXReadGroup(stream, group, consumer, id string) *XReadGroup {
+	return &XReadGroup{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		ID:       id,
+	}
+}
+
+func (x *XReadGroup) SetCount(count int) *XReadGroup {
+	x.Count = count
+	return x
+}
+
+// SetBlock sets the blocking timeout in milliseconds.
+func (x *XReadGroup) SetBlock(block int64) *XReadGroup {
+	x.Block = block
+	return x
+}
+
+func (x *XReadGroup) Command(ctx context.Context) *redis.SliceCmd {
+	var args []interface{}
+	args = append(args, Stream.XReadGroup)
+	args = append(args, x.Stream)
+	args = append(args, x.Group)
+	args = append(args, x.Consumer)
+	args = append(args, x.ID)
+	if x.Count != 0 {
+		args = append(args, "COUNT")
+		args = append(args, x.Count)
+	}
+	if x.Block != 0 {
+		args = append(args, "BLOCK")
+		args = append(args, x.Block)
+	}
+	return redis.NewSliceCmd(ctx, args...)
+}
+
+func ParseXReadGroupCommand(cmd redcon.Command) (*XReadGroup, error) {
+	if len(cmd.Args) < 5 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	x := NewXReadGroup(
+		util.BytesToString(cmd.Args[1]), // Stream
+		util.BytesToString(cmd.Args[2]), // Group
+		util.BytesToString(cmd.Args[3]), // Consumer
+		util.BytesToString(cmd.Args[4]), // ID
+	)
+
+	count, block, err := parseCountAndBlock(cmd.Args[5:])
+	if err != nil {
+		return nil, err
+	}
+	x.SetCount(count)
+	x.SetBlock(block)
+	return x, nil
+}
+
+type XAck struct {
+	Stream string
+	Group  string
+	IDs    []string
+}
+
+func NewXAck(stream, group string, ids ...string) *XAck {
+	return &XAck{
+		Stream: stream,
+		Group:  group,
+		IDs:    ids,
+	}
+}
+
+func (x *XAck) Command(ctx context.Context) *redis.IntCmd {
+	var args []interface{}
+	args = append(args, Stream.XAck)
+	args = append(args, x.Stream)
+	args = append(args, x.Group)
+	for _, id := range x.IDs {
+		args = append(args, id)
+	}
+	return redis.NewIntCmd(ctx, args...)
+}
+
+func ParseXAckCommand(cmd redcon.Command) (*XAck, error) {
+	if len(cmd.Args) < 4 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	var ids []string
+	for _, arg := range cmd.Args[3:] {
+		ids = append(ids, util.BytesToString(arg))
+	}
+	return NewXAck(
+		util.BytesToString(cmd.Args[1]), // Stream
+		util.BytesToString(cmd.Args[2]), // Group
+		ids...,
+	), nil
+}
+
+type XPending struct {
+	Stream string
+	Group  string
+}
+
+func NewXPending(stream, group string) *XPending {
+	return &XPending{
+		Stream: stream,
+		Group:  group,
+	}
+}
+
+func (x *XPending) Command(ctx context.Context) *redis.SliceCmd {
+	var args []interface{}
+	args = append(args, Stream.XPending)
+	args = append(args, x.Stream)
+	args = append(args, x.Group)
+	return redis.NewSliceCmd(ctx, args...)
+}
+
+func ParseXPendingCommand(cmd redcon.Command) (*XPending, error) {
+	if len(cmd.Args) < 3 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	return NewXPending(
+		util.BytesToString(cmd.Args[1]), // Stream
+		util.BytesToString(cmd.Args[2]), // Group
+	), nil
+}
+
+type XClaim struct {
+	Stream   string
+	Group    string
+	Consumer string
+	MinIdle  int64
+	IDs      []string
+}
+
+// NewXClaim creates a new XClaim command. minIdle is in milliseconds.
+func NewXClaim(stream, group, consumer string, minIdle int64, ids ...string) *XClaim {
+	return &XClaim{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		IDs:      ids,
+	}
+}
+
+func (x *XClaim) Command(ctx context.Context) *redis.SliceCmd {
+	var args []interface{}
+	args = append(args, Stream.XClaim)
+	args = append(args, x.Stream)
+	args = append(args, x.Group)
+	args = append(args, x.Consumer)
+	args = append(args, x.MinIdle)
+	for _, id := range x.IDs {
+		args = append(args, id)
+	}
+	return redis.NewSliceCmd(ctx, args...)
+}
+
+func ParseXClaimCommand(cmd redcon.Command) (*XClaim, error) {
+	if len(cmd.Args) < 6 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	minIdle, err := strconv.ParseInt(util.BytesToString(cmd.Args[4]), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, arg := range cmd.Args[5:] {
+		ids = append(ids, util.BytesToString(arg))
+	}
+	return NewXClaim(
+		util.BytesToString(cmd.Args[1]), // Stream
+		util.BytesToString(cmd.Args[2]), // Group
+		util.BytesToString(cmd.Args[3]), // Consumer
+		minIdle,
+		ids...,
+	), nil
+}
+
+type XAutoClaim struct {
+	Stream   string
+	Group    string
+	Consumer string
+	MinIdle  int64
+	Start    string
+	Count    int
+}
+
+// NewXAutoClaim creates a new XAutoClaim command. minIdle is in milliseconds.
+func NewXAutoClaim(stream, group, consumer string, minIdle int64, start string) *XAutoClaim {
+	return &XAutoClaim{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    start,
+	}
+}
+
+func (x *XAutoClaim) SetCount(count int) *XAutoClaim {
+	x.Count = count
+	return x
+}
+
+func (x *XAutoClaim) Command(ctx context.Context) *redis.SliceCmd {
+	var args []interface{}
+	args = append(args, Stream.XAutoClaim)
+	args = append(args, x.Stream)
+	args = append(args, x.Group)
+	args = append(args, x.Consumer)
+	args = append(args, x.MinIdle)
+	args = append(args, x.Start)
+	if x.Count != 0 {
+		args = append(args, "COUNT")
+		args = append(args, x.Count)
+	}
+	return redis.NewSliceCmd(ctx, args...)
+}
+
+func ParseXAutoClaimCommand(cmd redcon.Command) (*XAutoClaim, error) {
+	if len(cmd.Args) < 6 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	minIdle, err := strconv.ParseInt(util.BytesToString(cmd.Args[4]), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	x := NewXAutoClaim(
+		util.BytesToString(cmd.Args[1]), // Stream
+		util.BytesToString(cmd.Args[2]), // Group
+		util.BytesToString(cmd.Args[3]), // Consumer
+		minIdle,
+		util.BytesToString(cmd.Args[5]), // Start
+	)
+
+	count, _, err := parseCountAndBlock(cmd.Args[6:])
+	if err != nil {
+		return nil, err
+	}
+	x.SetCount(count)
+	return x, nil
+}
+
+type StreamReplicate struct {
+	Payload []byte
+}
+
+func NewStreamReplicate(payload []byte) *StreamReplicate {
+	return &StreamReplicate{
+		Payload: payload,
+	}
+}
+
+func (s *StreamReplicate) Command(ctx context.Context) *redis.StatusCmd {
+	var args []interface{}
+	args = append(args, Stream.Replicate)
+	args = append(args, s.Payload)
+	return redis.NewStatusCmd(ctx, args...)
+}
+
+func ParseStreamReplicateCommand(cmd redcon.Command) (*StreamReplicate, error) {
+	if len(cmd.Args) < 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	return NewStreamReplicate(cmd.Args[1]), nil
+}
+
+type MoveStream struct {
+	Payload []byte
+}
+
+func NewMoveStream(payload []byte) *MoveStream {
+	return &MoveStream{
+		Payload: payload,
+	}
+}
+
+func (m *MoveStream) Command(ctx context.Context) *redis.StatusCmd {
+	var args []interface{}
+	args = append(args, Internal.MoveStream)
+	args = append(args, m.Payload)
+	return redis.NewStatusCmd(ctx, args...)
+}
+
+func ParseMoveStreamCommand(cmd redcon.Command) (*MoveStream, error) {
+	if len(cmd.Args) < 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	return NewMoveStream(cmd.Args[1]), nil
+}
+
+// parseCountAndBlock parses the optional COUNT and BLOCK arguments of the
+// stream read commands.
+func parseCountAndBlock(args [][]byte) (int, int64, error) {
+	var (
+		count int
+		block int64
+		err   error
+	)
+	for len(args) > 0 {
+		arg := strings.ToUpper(util.BytesToString(args[0]))
+		if len(args) < 2 {
+			return 0, 0, fmt.Errorf("%w: %s needs a numerical argument", ErrInvalidArgument, arg)
+		}
+		switch arg {
+		case "COUNT":
+			count, err = strconv.Atoi(util.BytesToString(args[1]))
+			if err != nil {
+				return 0, 0, err
+			}
+		case "BLOCK":
+			block, err = strconv.ParseInt(util.BytesToString(args[1]), 10, 64)
+			if err != nil {
+				return 0, 0, err
+			}
+		default:
+			return 0, 0, fmt.Errorf("%w: %s", ErrInvalidArgument, arg)
+		}
+		args = args[2:]
+	}
+	return count, block, nil
+}
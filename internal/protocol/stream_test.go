@@ -0,0 +1,131 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocol_ParseXAddCommand(t *testing.T) {
+	fields := map[string]string{"field-1": "value-1", "field-2": "value-2"}
+	xaddCmd := NewXAdd("my-stream", "*", fields)
+
+	cmd := stringToCommand(xaddCmd.Command(context.Background()).String())
+	parsed, err := ParseXAddCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, "my-stream", parsed.Stream)
+	require.Equal(t, "*", parsed.ID)
+	require.Equal(t, fields, parsed.Fields)
+	require.Equal(t, int64(0), parsed.MaxLen)
+}
+
+func TestProtocol_ParseXAddCommand_MaxLen(t *testing.T) {
+	xaddCmd := NewXAdd("my-stream", "1-1", map[string]string{"field": "value"})
+	xaddCmd.SetMaxLen(100)
+
+	cmd := stringToCommand(xaddCmd.Command(context.Background()).String())
+	parsed, err := ParseXAddCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, "1-1", parsed.ID)
+	require.Equal(t, int64(100), parsed.MaxLen)
+}
+
+func TestProtocol_ParseXRangeCommand(t *testing.T) {
+	xrangeCmd := NewXRange("my-stream", "-", "+")
+	xrangeCmd.SetCount(10)
+
+	cmd := stringToCommand(xrangeCmd.Command(context.Background()).String())
+	parsed, err := ParseXRangeCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, "my-stream", parsed.Stream)
+	require.Equal(t, "-", parsed.Start)
+	require.Equal(t, "+", parsed.End)
+	require.Equal(t, 10, parsed.Count)
+}
+
+func TestProtocol_ParseXReadCommand(t *testing.T) {
+	xreadCmd := NewXRead("my-stream", "$")
+	xreadCmd.SetCount(10)
+	xreadCmd.SetBlock(1000)
+
+	cmd := stringToCommand(xreadCmd.Command(context.Background()).String())
+	parsed, err := ParseXReadCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, "my-stream", parsed.Stream)
+	require.Equal(t, "$", parsed.ID)
+	require.Equal(t, 10, parsed.Count)
+	require.Equal(t, int64(1000), parsed.Block)
+}
+
+func TestProtocol_ParseXReadGroupCommand(t *testing.T) {
+	xreadGroupCmd := NewXReadGroup("my-stream", "my-group", "my-consumer", ">")
+	xreadGroupCmd.SetBlock(1000)
+
+	cmd := stringToCommand(xreadGroupCmd.Command(context.Background()).String())
+	parsed, err := ParseXReadGroupCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, "my-stream", parsed.Stream)
+	require.Equal(t, "my-group", parsed.Group)
+	require.Equal(t, "my-consumer", parsed.Consumer)
+	require.Equal(t, ">", parsed.ID)
+	require.Equal(t, 0, parsed.Count)
+	require.Equal(t, int64(1000), parsed.Block)
+}
+
+func TestProtocol_ParseXAckCommand(t *testing.T) {
+	xackCmd := NewXAck("my-stream", "my-group", "1-1", "1-2")
+
+	cmd := stringToCommand(xackCmd.Command(context.Background()).String())
+	parsed, err := ParseXAckCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, "my-stream", parsed.Stream)
+	require.Equal(t, "my-group", parsed.Group)
+	require.Equal(t, []string{"1-1", "1-2"}, parsed.IDs)
+}
+
+func TestProtocol_ParseXClaimCommand(t *testing.T) {
+	xclaimCmd := NewXClaim("my-stream", "my-group", "my-consumer", 5000, "1-1", "1-2")
+
+	cmd := stringToCommand(xclaimCmd.Command(context.Background()).String())
+	parsed, err := ParseXClaimCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, "my-consumer", parsed.Consumer)
+	require.Equal(t, int64(5000), parsed.MinIdle)
+	require.Equal(t, []string{"1-1", "1-2"}, parsed.IDs)
+}
+
+func TestProtocol_ParseXAutoClaimCommand(t *testing.T) {
+	xautoClaimCmd := NewXAutoClaim("my-stream", "my-group", "my-consumer", 5000, "0-0")
+	xautoClaimCmd.SetCount(25)
+
+	cmd := stringToCommand(xautoClaimCmd.Command(context.Background()).String())
+	parsed, err := ParseXAutoClaimCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, "my-consumer", parsed.Consumer)
+	require.Equal(t, int64(5000), parsed.MinIdle)
+	require.Equal(t, "0-0", parsed.Start)
+	require.Equal(t, 25, parsed.Count)
+}
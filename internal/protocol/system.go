@@ -16,8 +16,10 @@ package protocol
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/olric-data/olric/internal/util"
 	"github.com/redis/go-redis/v9"
@@ -58,6 +60,28 @@ func ParsePingCommand(cmd redcon.Command) (*Ping, error) {
 	return p, nil
 }
 
+// WrapTrace wraps the arguments of a command in a TRACE envelope that carries the
+// W3C trace context of the caller. The wire format is: TRACE <traceparent> <command> [<args>...]
+func WrapTrace(traceParent string, args []interface{}) []interface{} {
+	wrapped := make([]interface{}, 0, len(args)+2)
+	wrapped = append(wrapped, Generic.Trace)
+	wrapped = append(wrapped, traceParent)
+	return append(wrapped, args...)
+}
+
+// ParseTraceCommand parses a TRACE envelope. It returns the trace context and the wrapped command.
+func ParseTraceCommand(cmd redcon.Command) (string, redcon.Command, error) {
+	if len(cmd.Args) < 3 {
+		return "", redcon.Command{}, errWrongNumber(cmd.Args)
+	}
+
+	inner := redcon.Command{
+		Raw:  cmd.Raw,
+		Args: cmd.Args[2:],
+	}
+	return util.BytesToString(cmd.Args[1]), inner, nil
+}
+
 type MoveFragment struct {
 	Payload []byte
 }
@@ -176,13 +200,15 @@ func (s *Stats) SetCollectRuntime() *Stats {
 	return s
 }
 
-func (s *Stats) Command(ctx context.Context) *redis.StringCmd {
+// Command constructs a STATS command. The reply is a JSON document on RESP2
+// connections and a map on RESP3 connections.
+func (s *Stats) Command(ctx context.Context) *redis.Cmd {
 	var args []interface{}
 	args = append(args, Generic.Stats)
 	if s.CollectRuntime {
 		args = append(args, "CR")
 	}
-	return redis.NewStringCmd(ctx, args...)
+	return redis.NewCmd(ctx, args...)
 }
 
 func ParseStatsCommand(cmd redcon.Command) (*Stats, error) {
@@ -203,8 +229,10 @@ func ParseStatsCommand(cmd redcon.Command) (*Stats, error) {
 	return s, nil
 }
 
-// Auth represents a structure for authentication containing a password.
+// Auth represents a structure for authentication containing a password and
+// an optional username. An empty Username means the default user.
 type Auth struct {
+	Username string
 	Password string
 }
 
@@ -215,23 +243,285 @@ func NewAuth(password string) *Auth {
 	}
 }
 
+// SetUsername sets the name of the user to authenticate.
+func (a *Auth) SetUsername(username string) *Auth {
+	a.Username = username
+	return a
+}
+
 // Command constructs a Redis AUTH command using the provided authentication password from the Auth instance.
 func (a *Auth) Command(ctx context.Context) *redis.StatusCmd {
 	var args []interface{}
 
 	args = append(args, Generic.Auth)
+	if a.Username != "" {
+		args = append(args, a.Username)
+	}
 	args = append(args, a.Password)
 
 	return redis.NewStatusCmd(ctx, args...)
 }
 
 // ParseAuthCommand parses a redcon.Command to create an Auth instance and validates command arguments.
+// It accepts both AUTH password and AUTH username password.
 func ParseAuthCommand(cmd redcon.Command) (*Auth, error) {
-	if len(cmd.Args) != 2 {
+	if len(cmd.Args) < 2 || len(cmd.Args) > 3 {
 		return nil, errWrongNumber(cmd.Args)
 	}
 
+	if len(cmd.Args) == 3 {
+		return NewAuth(
+			util.BytesToString(cmd.Args[2]),
+		).SetUsername(util.BytesToString(cmd.Args[1])), nil
+	}
+
 	return NewAuth(
 		util.BytesToString(cmd.Args[1]),
 	), nil
 }
+
+// ACLWhoAmI represents the ACL WHOAMI command. It returns the name of the user
+// that the connection is authenticated as.
+type ACLWhoAmI struct{}
+
+// NewACLWhoAmI creates and returns a new ACLWhoAmI instance.
+func NewACLWhoAmI() *ACLWhoAmI {
+	return &ACLWhoAmI{}
+}
+
+// Command constructs an ACL WHOAMI command.
+func (a *ACLWhoAmI) Command(ctx context.Context) *redis.StringCmd {
+	var args []interface{}
+	args = append(args, Generic.ACL)
+	args = append(args, "whoami")
+	return redis.NewStringCmd(ctx, args...)
+}
+
+// ParseACLWhoAmICommand parses a redcon.Command to create an ACLWhoAmI instance.
+func ParseACLWhoAmICommand(cmd redcon.Command) (*ACLWhoAmI, error) {
+	if len(cmd.Args) != 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+	return NewACLWhoAmI(), nil
+}
+
+// ACLList represents the ACL LIST command. It returns the users and their rules.
+type ACLList struct{}
+
+// NewACLList creates and returns a new ACLList instance.
+func NewACLList() *ACLList {
+	return &ACLList{}
+}
+
+// Command constructs an ACL LIST command.
+func (a *ACLList) Command(ctx context.Context) *redis.StringSliceCmd {
+	var args []interface{}
+	args = append(args, Generic.ACL)
+	args = append(args, "list")
+	return redis.NewStringSliceCmd(ctx, args...)
+}
+
+// ParseACLListCommand parses a redcon.Command to create an ACLList instance.
+func ParseACLListCommand(cmd redcon.Command) (*ACLList, error) {
+	if len(cmd.Args) != 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+	return NewACLList(), nil
+}
+
+const (
+	// RESP2 is the default protocol version of the connections.
+	RESP2 = 2
+
+	// RESP3 is the protocol version that the clients negotiate with HELLO 3.
+	RESP3 = 3
+)
+
+// Hello represents the HELLO command. It negotiates the protocol version of the
+// connection and optionally authenticates it. A zero ProtocolVersion means that
+// the version is not changed.
+type Hello struct {
+	ProtocolVersion int
+	Username        string
+	Password        string
+	ClientName      string
+}
+
+// NewHello creates and returns a new Hello instance with the given protocol version.
+func NewHello(protocolVersion int) *Hello {
+	return &Hello{
+		ProtocolVersion: protocolVersion,
+	}
+}
+
+// SetAuth sets the credentials of the connection.
+func (h *Hello) SetAuth(username, password string) *Hello {
+	h.Username = username
+	h.Password = password
+	return h
+}
+
+// SetClientName sets the name of the connection.
+func (h *Hello) SetClientName(name string) *Hello {
+	h.ClientName = name
+	return h
+}
+
+// Command constructs a HELLO command. The reply is a map of the server properties.
+func (h *Hello) Command(ctx context.Context) *redis.MapStringInterfaceCmd {
+	var args []interface{}
+	args = append(args, Generic.Hello)
+	if h.ProtocolVersion != 0 {
+		args = append(args, h.ProtocolVersion)
+	}
+	if h.Password != "" {
+		args = append(args, "AUTH")
+		args = append(args, h.Username)
+		args = append(args, h.Password)
+	}
+	if h.ClientName != "" {
+		args = append(args, "SETNAME")
+		args = append(args, h.ClientName)
+	}
+	return redis.NewMapStringInterfaceCmd(ctx, args...)
+}
+
+// ParseHelloCommand parses a redcon.Command to create a Hello instance. It returns
+// ErrNoProto if the protocol version is not supported.
+func ParseHelloCommand(cmd redcon.Command) (*Hello, error) {
+	if len(cmd.Args) < 1 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	h := NewHello(0)
+	if len(cmd.Args) == 1 {
+		return h, nil
+	}
+
+	version, err := strconv.Atoi(util.BytesToString(cmd.Args[1]))
+	if err != nil {
+		return nil, errors.New("Protocol version is not an integer or out of range")
+	}
+	if version != RESP2 && version != RESP3 {
+		return nil, ErrNoProto
+	}
+	h.ProtocolVersion = version
+
+	args := cmd.Args[2:]
+	for len(args) > 0 {
+		switch arg := strings.ToUpper(util.BytesToString(args[0])); arg {
+		case "AUTH":
+			if len(args) < 3 {
+				return nil, errors.New("syntax error")
+			}
+			h.SetAuth(util.BytesToString(args[1]), util.BytesToString(args[2]))
+			args = args[3:]
+		case "SETNAME":
+			if len(args) < 2 {
+				return nil, errors.New("syntax error")
+			}
+			h.SetClientName(util.BytesToString(args[1]))
+			args = args[2:]
+		default:
+			return nil, errors.New("syntax error")
+		}
+	}
+
+	return h, nil
+}
+
+// DefaultSlowLogGetCount is the default number of records returned by SLOWLOG GET.
+const DefaultSlowLogGetCount = 10
+
+// SlowLogGet represents the SLOWLOG GET command. It returns the latest records
+// of the slow log. A negative Count returns all the records.
+type SlowLogGet struct {
+	Count int64
+}
+
+// NewSlowLogGet creates and returns a new SlowLogGet instance with the default count.
+func NewSlowLogGet() *SlowLogGet {
+	return &SlowLogGet{
+		Count: DefaultSlowLogGetCount,
+	}
+}
+
+// SetCount sets the number of records to return.
+func (s *SlowLogGet) SetCount(count int64) *SlowLogGet {
+	s.Count = count
+	return s
+}
+
+// Command constructs a SLOWLOG GET command. The reply has the same format as Redis.
+func (s *SlowLogGet) Command(ctx context.Context) *redis.SlowLogCmd {
+	var args []interface{}
+	args = append(args, Generic.SlowLog)
+	args = append(args, "get")
+	args = append(args, s.Count)
+	return redis.NewSlowLogCmd(ctx, args...)
+}
+
+// ParseSlowLogGetCommand parses a redcon.Command to create a SlowLogGet instance.
+func ParseSlowLogGetCommand(cmd redcon.Command) (*SlowLogGet, error) {
+	if len(cmd.Args) < 2 || len(cmd.Args) > 3 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+
+	s := NewSlowLogGet()
+	if len(cmd.Args) == 3 {
+		count, err := strconv.ParseInt(util.BytesToString(cmd.Args[2]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, err)
+		}
+		s.SetCount(count)
+	}
+	return s, nil
+}
+
+// SlowLogLen represents the SLOWLOG LEN command. It returns the number of records in the slow log.
+type SlowLogLen struct{}
+
+// NewSlowLogLen creates and returns a new SlowLogLen instance.
+func NewSlowLogLen() *SlowLogLen {
+	return &SlowLogLen{}
+}
+
+// Command constructs a SLOWLOG LEN command.
+func (s *SlowLogLen) Command(ctx context.Context) *redis.IntCmd {
+	var args []interface{}
+	args = append(args, Generic.SlowLog)
+	args = append(args, "len")
+	return redis.NewIntCmd(ctx, args...)
+}
+
+// ParseSlowLogLenCommand parses a redcon.Command to create a SlowLogLen instance.
+func ParseSlowLogLenCommand(cmd redcon.Command) (*SlowLogLen, error) {
+	if len(cmd.Args) != 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+	return NewSlowLogLen(), nil
+}
+
+// SlowLogReset represents the SLOWLOG RESET command. It removes all the records of the slow log.
+type SlowLogReset struct{}
+
+// NewSlowLogReset creates and returns a new SlowLogReset instance.
+func NewSlowLogReset() *SlowLogReset {
+	return &SlowLogReset{}
+}
+
+// Command constructs a SLOWLOG RESET command.
+func (s *SlowLogReset) Command(ctx context.Context) *redis.StatusCmd {
+	var args []interface{}
+	args = append(args, Generic.SlowLog)
+	args = append(args, "reset")
+	return redis.NewStatusCmd(ctx, args...)
+}
+
+// ParseSlowLogResetCommand parses a redcon.Command to create a SlowLogReset instance.
+func ParseSlowLogResetCommand(cmd redcon.Command) (*SlowLogReset, error) {
+	if len(cmd.Args) != 2 {
+		return nil, errWrongNumber(cmd.Args)
+	}
+	return NewSlowLogReset(), nil
+}
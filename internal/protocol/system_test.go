@@ -18,6 +18,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 )
 
@@ -42,6 +43,28 @@ func TestProtocol_Ping_Message(t *testing.T) {
 	require.Equal(t, "message", parsed.Message)
 }
 
+func TestProtocol_Trace(t *testing.T) {
+	put := NewPut("mydmap", "mykey", []byte("myvalue"))
+	args := WrapTrace("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", put.Command(context.Background()).Args())
+
+	cmd := stringToCommand(redis.NewStatusCmd(context.Background(), args...).String())
+	traceParent, inner, err := ParseTraceCommand(cmd)
+	require.NoError(t, err)
+	require.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", traceParent)
+
+	parsed, err := ParsePutCommand(inner)
+	require.NoError(t, err)
+	require.Equal(t, "mydmap", parsed.DMap)
+	require.Equal(t, "mykey", parsed.Key)
+	require.Equal(t, []byte("myvalue"), parsed.Value)
+}
+
+func TestProtocol_Trace_WrongNumber(t *testing.T) {
+	cmd := stringToCommand(redis.NewStatusCmd(context.Background(), Generic.Trace, "traceparent").String())
+	_, _, err := ParseTraceCommand(cmd)
+	require.Error(t, err)
+}
+
 func TestProtocol_MoveFragment(t *testing.T) {
 	moveFragmentCmd := NewMoveFragment([]byte("payload"))
 
@@ -117,6 +140,17 @@ func TestProtocol_Auth(t *testing.T) {
 	require.Equal(t, "secret", parsed.Password)
 }
 
+func TestProtocol_Auth_Username(t *testing.T) {
+	auth := NewAuth("secret").SetUsername("alice")
+
+	cmd := stringToCommand(auth.Command(context.Background()).String())
+	parsed, err := ParseAuthCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, "alice", parsed.Username)
+	require.Equal(t, "secret", parsed.Password)
+}
+
 func TestProtocol_Auth_errWrongNumber(t *testing.T) {
 	cmd := stringToCommand("auth:")
 
@@ -124,3 +158,81 @@ func TestProtocol_Auth_errWrongNumber(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, "wrong number of arguments for 'auth' command", err.Error())
 }
+
+func TestProtocol_Hello(t *testing.T) {
+	hello := NewHello(RESP3).SetAuth("default", "secret").SetClientName("my-client")
+	require.Equal(t, []interface{}{"hello", 3, "AUTH", "default", "secret", "SETNAME", "my-client"},
+		hello.Command(context.Background()).Args())
+
+	cmd := stringToCommand("hello 3 AUTH default secret SETNAME my-client")
+	parsed, err := ParseHelloCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, RESP3, parsed.ProtocolVersion)
+	require.Equal(t, "default", parsed.Username)
+	require.Equal(t, "secret", parsed.Password)
+	require.Equal(t, "my-client", parsed.ClientName)
+}
+
+func TestProtocol_Hello_Without_Version(t *testing.T) {
+	parsed, err := ParseHelloCommand(stringToCommand("hello"))
+	require.NoError(t, err)
+	require.Equal(t, 0, parsed.ProtocolVersion)
+}
+
+func TestProtocol_Hello_NoProto(t *testing.T) {
+	_, err := ParseHelloCommand(stringToCommand("hello 4"))
+	require.ErrorIs(t, err, ErrNoProto)
+
+	_, err = ParseHelloCommand(stringToCommand("hello 3 AUTH default"))
+	require.Error(t, err)
+}
+
+func TestProtocol_SlowLogGet(t *testing.T) {
+	slowLogGetCmd := NewSlowLogGet()
+	slowLogGetCmd.SetCount(-1)
+
+	cmd := stringToCommand(slowLogGetCmd.Command(context.Background()).String())
+	parsed, err := ParseSlowLogGetCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(-1), parsed.Count)
+}
+
+func TestProtocol_SlowLogGet_Default_Count(t *testing.T) {
+	cmd := stringToCommand("slowlog get")
+	parsed, err := ParseSlowLogGetCommand(cmd)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(DefaultSlowLogGetCount), parsed.Count)
+}
+
+func TestProtocol_SlowLogGet_Invalid_Count(t *testing.T) {
+	cmd := stringToCommand("slowlog get foobar")
+	_, err := ParseSlowLogGetCommand(cmd)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestProtocol_SlowLogLen(t *testing.T) {
+	cmd := stringToCommand(NewSlowLogLen().Command(context.Background()).String())
+	_, err := ParseSlowLogLenCommand(cmd)
+	require.NoError(t, err)
+}
+
+func TestProtocol_SlowLogReset(t *testing.T) {
+	cmd := stringToCommand(NewSlowLogReset().Command(context.Background()).String())
+	_, err := ParseSlowLogResetCommand(cmd)
+	require.NoError(t, err)
+}
+
+func TestProtocol_ACLWhoAmI(t *testing.T) {
+	cmd := stringToCommand(NewACLWhoAmI().Command(context.Background()).String())
+	_, err := ParseACLWhoAmICommand(cmd)
+	require.NoError(t, err)
+}
+
+func TestProtocol_ACLList(t *testing.T) {
+	cmd := stringToCommand(NewACLList().Command(context.Background()).String())
+	_, err := ParseACLListCommand(cmd)
+	require.NoError(t, err)
+}
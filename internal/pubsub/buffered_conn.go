@@ -0,0 +1,56 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"github.com/tidwall/redcon"
+)
+
+// bufferedConn serves a command on a detached RESP3 connection. The reply is
+// buffered, so a slow command doesn't block the push messages while it's
+// running. The other methods are served by the detached connection.
+type bufferedConn struct {
+	redcon.DetachedConn
+	wr *redcon.Writer
+}
+
+func newBufferedConn(dconn redcon.DetachedConn) *bufferedConn {
+	return &bufferedConn{
+		DetachedConn: dconn,
+		wr:           redcon.NewWriter(nil),
+	}
+}
+
+func (c *bufferedConn) WriteError(msg string)       { c.wr.WriteError(msg) }
+func (c *bufferedConn) WriteString(str string)      { c.wr.WriteString(str) }
+func (c *bufferedConn) WriteBulk(bulk []byte)       { c.wr.WriteBulk(bulk) }
+func (c *bufferedConn) WriteBulkString(bulk string) { c.wr.WriteBulkString(bulk) }
+func (c *bufferedConn) WriteInt(num int)            { c.wr.WriteInt(num) }
+func (c *bufferedConn) WriteInt64(num int64)        { c.wr.WriteInt64(num) }
+func (c *bufferedConn) WriteUint64(num uint64)      { c.wr.WriteUint64(num) }
+func (c *bufferedConn) WriteArray(count int)        { c.wr.WriteArray(count) }
+func (c *bufferedConn) WriteNull()                  { c.wr.WriteNull() }
+func (c *bufferedConn) WriteRaw(data []byte)        { c.wr.WriteRaw(data) }
+func (c *bufferedConn) WriteAny(v interface{})      { c.wr.WriteAny(v) }
+
+// Detach panics, the connection is already detached.
+func (c *bufferedConn) Detach() redcon.DetachedConn {
+	panic("pubsub: connection is already detached")
+}
+
+// Buffer returns the buffered reply.
+func (c *bufferedConn) Buffer() []byte {
+	return c.wr.Buffer()
+}
@@ -15,7 +15,10 @@
 package pubsub
 
 import (
+	"fmt"
+
 	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
 	"github.com/tidwall/redcon"
 )
 
@@ -40,33 +43,78 @@ func (s *Service) publishCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		return
 	}
 
+	var total int
+	if h := s.pubsub.history(publishCmd.Channel); h != nil {
+		total, err = s.publishRetained(h, publishCmd.Channel, publishCmd.Message)
+	} else {
+		total, err = s.publish(publishCmd.Channel, publishCmd.Message, 0)
+	}
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteInt(total)
+}
+
+// publishRetained publishes a message to a retained channel. The partition
+// owner of the channel assigns the sequence IDs, so the other members forward
+// the message to the owner.
+func (s *Service) publishRetained(h *history, channel, message string) (int, error) {
+	if err := s.rt.CheckBootstrap(); err != nil {
+		return 0, err
+	}
+
+	_, owner, ok := s.shardOwner(channel)
+	if !ok {
+		if owner == "" {
+			return 0, fmt.Errorf("no partition owner found for channel: %s", channel)
+		}
+		cmd := protocol.NewPublish(channel, message).Command(s.ctx)
+		rc := s.client.Get(owner)
+		if err := rc.Process(s.ctx, cmd); err != nil {
+			return 0, protocol.ConvertError(err)
+		}
+		count, err := cmd.Result()
+		if err != nil {
+			return 0, protocol.ConvertError(err)
+		}
+		return int(count), nil
+	}
+
+	// All the members must receive the messages in the same order.
+	h.sequencer.Lock()
+	defer h.sequencer.Unlock()
+
+	return s.publish(channel, message, h.lastSequence()+1)
+}
+
+// publish sends a message to all the cluster members. It returns the total
+// number of subscribers that received the message.
+func (s *Service) publish(channel, message string, sequence uint64) (int, error) {
 	var total int
 	members := s.rt.Discovery().GetMembers()
 	for _, member := range members {
 		if member.CompareByID(s.rt.This()) {
-			count := s.pubsub.Publish(publishCmd.Channel, publishCmd.Message)
+			count := s.pubsub.PublishSequence(channel, message, sequence)
 			total += count
 			PublishedTotal.Increase(int64(count))
 			continue
 		}
 
-		pi := protocol.NewPublishInternal(publishCmd.Channel, publishCmd.Message).Command(s.ctx)
+		pi := protocol.NewPublishInternal(channel, message).SetSequence(sequence).Command(s.ctx)
 		rc := s.client.Get(member.String())
-		err = rc.Process(s.ctx, pi)
+		err := rc.Process(s.ctx, pi)
 		if err != nil {
-			protocol.WriteError(conn, err)
-			return
+			return 0, err
 		}
 		pcount, err := pi.Result()
 		if err != nil {
-			protocol.WriteError(conn, err)
-			return
+			return 0, err
 		}
 		total += int(pcount)
 		PublishedTotal.Increase(pcount)
 	}
-
-	conn.WriteInt(total)
+	return total, nil
 }
 
 func (s *Service) publishInternalCommandHandler(conn redcon.Conn, cmd redcon.Command) {
@@ -75,7 +123,7 @@ func (s *Service) publishInternalCommandHandler(conn redcon.Conn, cmd redcon.Com
 		protocol.WriteError(conn, err)
 		return
 	}
-	count := s.pubsub.Publish(publishInternalCmd.Channel, publishInternalCmd.Message)
+	count := s.pubsub.PublishSequence(publishInternalCmd.Channel, publishInternalCmd.Message, publishInternalCmd.Sequence)
 	conn.WriteInt(count)
 }
 
@@ -140,3 +188,87 @@ func (s *Service) pubsubNumsubCommandHandler(conn redcon.Conn, cmd redcon.Comman
 		conn.WriteInt(s.pubsub.Numsub(channel))
 	}
 }
+
+func (s *Service) ssubscribeCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	ssubscribeCmd, err := protocol.ParseSSubscribeCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	if err = s.rt.CheckBootstrap(); err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	// The clients must connect to the partition owner of the channels.
+	for _, channel := range ssubscribeCmd.Channels {
+		partID, owner, ok := s.pubsub.ShardOwner(channel)
+		if !ok {
+			protocol.WriteMoved(conn, partID, owner)
+			return
+		}
+	}
+
+	for _, channel := range ssubscribeCmd.Channels {
+		s.pubsub.Ssubscribe(conn, channel)
+		CurrentSSubscribers.Increase(1)
+		SSubscribersTotal.Increase(1)
+	}
+}
+
+func (s *Service) spublishCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	spublishCmd, err := protocol.ParseSPublishCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	if err = s.rt.CheckBootstrap(); err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	// Sharded messages are never forwarded to the other members.
+	partID, owner, ok := s.pubsub.ShardOwner(spublishCmd.Channel)
+	if !ok {
+		protocol.WriteMoved(conn, partID, owner)
+		return
+	}
+
+	count := s.pubsub.SPublish(spublishCmd.Channel, spublishCmd.Message)
+	PublishedTotal.Increase(int64(count))
+	conn.WriteInt(count)
+}
+
+// pubsubResumeCommandHandler enables sequenced delivery on the connection and
+// sets the last seen sequence IDs of the channels. The subsequent SUBSCRIBE
+// commands replay the retained messages after these sequences. It returns the
+// resolved sequence IDs, "$" is resolved to the latest message.
+func (s *Service) pubsubResumeCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	pubsubResumeCmd, err := protocol.ParsePubSubResumeCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	ctx, ok := conn.Context().(*server.ConnContext)
+	if !ok {
+		protocol.WriteError(conn, fmt.Errorf("connection context is not available"))
+		return
+	}
+
+	sequences := make(map[string]uint64)
+	conn.WriteArray(len(pubsubResumeCmd.Channels))
+	for i, channel := range pubsubResumeCmd.Channels {
+		var sequence uint64
+		if pubsubResumeCmd.Sequences[i] == protocol.LatestSequence {
+			sequence, _ = s.pubsub.LastSequence(channel)
+		} else {
+			sequence = uint64(pubsubResumeCmd.Sequences[i])
+		}
+		sequences[channel] = sequence
+		conn.WriteInt64(int64(sequence))
+	}
+	ctx.SetPubSubSequences(sequences)
+}
@@ -15,12 +15,19 @@
 package pubsub
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/testcluster"
+	"github.com/olric-data/olric/internal/testutil"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 )
@@ -392,3 +399,268 @@ L:
 
 	require.Equal(t, expected, consumed)
 }
+
+func TestPubSub_Handler_SSubscribe(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	rc := s.client.Get(s.rt.This().String())
+	ctx := context.Background()
+	ps := rc.SSubscribe(ctx, "my-channel")
+
+	// Wait for confirmation that subscription is created before publishing anything.
+	msgi, err := ps.ReceiveTimeout(ctx, time.Second)
+	require.NoError(t, err)
+
+	subs := msgi.(*redis.Subscription)
+	require.Equal(t, "ssubscribe", subs.Kind)
+	require.Equal(t, "my-channel", subs.Channel)
+	require.Equal(t, 1, subs.Count)
+
+	// A regular publish doesn't reach the sharded subscribers.
+	count, err := rc.Publish(ctx, "my-channel", "my-message").Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count)
+
+	count, err = rc.SPublish(ctx, "my-channel", "my-message").Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	msgi, err = ps.ReceiveTimeout(ctx, time.Second)
+	require.NoError(t, err)
+	msg := msgi.(*redis.Message)
+	require.Equal(t, "my-channel", msg.Channel)
+	require.Equal(t, "my-message", msg.Payload)
+
+	require.NoError(t, ps.SUnsubscribe(ctx, "my-channel"))
+	msgi, err = ps.ReceiveTimeout(ctx, time.Second)
+	require.NoError(t, err)
+	subs = msgi.(*redis.Subscription)
+	require.Equal(t, "sunsubscribe", subs.Kind)
+	require.Equal(t, 0, subs.Count)
+}
+
+func TestPubSub_Handler_SPublish_Moved(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s1 := cluster.AddMember(nil).(*Service)
+	s2 := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	// Find a channel that belongs to the second member.
+	var channel string
+	for i := 0; ; i++ {
+		channel = fmt.Sprintf("my-channel-%d", i)
+		if _, _, ok := s2.pubsub.ShardOwner(channel); ok {
+			break
+		}
+	}
+
+	ctx := context.Background()
+	rc1 := s1.client.Get(s1.rt.This().String())
+	err := rc1.SPublish(ctx, channel, "my-message").Err()
+	require.ErrorIs(t, protocol.ConvertError(err), protocol.ErrMoved)
+	require.Contains(t, err.Error(), s2.rt.This().String())
+
+	ps := rc1.SSubscribe(ctx, channel)
+	defer func() {
+		require.NoError(t, ps.Close())
+	}()
+	_, err = ps.ReceiveTimeout(ctx, time.Second)
+	require.ErrorIs(t, protocol.ConvertError(err), protocol.ErrMoved)
+
+	rc2 := s2.client.Get(s2.rt.This().String())
+	count, err := rc2.SPublish(ctx, channel, "my-message").Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count)
+}
+
+func TestPubSub_Handler_SSubscribe_Partition_Moved(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s1 := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	var channels []string
+	for i := 0; i < 20; i++ {
+		channels = append(channels, fmt.Sprintf("my-channel-%d", i))
+	}
+
+	rc := s1.client.Get(s1.rt.This().String())
+	ctx := context.Background()
+	ps := rc.SSubscribe(ctx, channels...)
+	defer func() {
+		require.NoError(t, ps.Close())
+	}()
+	for range channels {
+		_, err := ps.ReceiveTimeout(ctx, time.Second)
+		require.NoError(t, err)
+	}
+
+	// Some partitions are moved to the new member.
+	s2 := cluster.AddMember(nil).(*Service)
+
+	expected := make(map[string]struct{})
+	for _, channel := range channels {
+		if _, _, ok := s2.pubsub.ShardOwner(channel); ok {
+			expected[channel] = struct{}{}
+		}
+	}
+	require.NotEmpty(t, expected)
+
+	moved := make(map[string]struct{})
+	for len(moved) < len(expected) {
+		msgi, err := ps.ReceiveTimeout(ctx, 5*time.Second)
+		require.NoError(t, err)
+		subs := msgi.(*redis.Subscription)
+		require.Equal(t, "sunsubscribe", subs.Kind)
+		moved[subs.Channel] = struct{}{}
+	}
+	require.Equal(t, expected, moved)
+
+	// The remaining subscriptions are still active.
+	for _, channel := range channels {
+		if _, ok := expected[channel]; ok {
+			continue
+		}
+		count, err := rc.SPublish(ctx, channel, "my-message").Result()
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	}
+}
+
+func TestPubSub_Handler_PubSubResume(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	defer cluster.Shutdown()
+
+	newConfig := func() *config.Config {
+		c := testutil.NewConfig()
+		c.PubSub.Retention = map[string]config.ChannelRetention{
+			"my-channel": {MaxMessages: 100},
+		}
+		return c
+	}
+	s1 := cluster.AddMember(testcluster.NewEnvironment(newConfig())).(*Service)
+	s2 := cluster.AddMember(testcluster.NewEnvironment(newConfig())).(*Service)
+
+	ctx := context.Background()
+	rc2 := s2.client.Get(s2.rt.This().String())
+	for i := 1; i <= 5; i++ {
+		err := rc2.Publish(ctx, "my-channel", fmt.Sprintf("my-message-%d", i)).Err()
+		require.NoError(t, err)
+	}
+
+	// Both members have the same history.
+	for _, s := range []*Service{s1, s2} {
+		sequence, ok := s.pubsub.LastSequence("my-channel")
+		require.True(t, ok)
+		require.Equal(t, uint64(5), sequence)
+	}
+
+	opt := *s1.client.Get(s1.rt.This().String()).Options()
+	opt.OnConnect = func(ctx context.Context, cn *redis.Conn) error {
+		cmd := protocol.NewPubSubResume().Add("my-channel", 2).Command(ctx)
+		if err := cn.Process(ctx, cmd); err != nil {
+			return err
+		}
+		sequences, err := cmd.Result()
+		if err != nil {
+			return err
+		}
+		require.Equal(t, []int64{2}, sequences)
+		return nil
+	}
+	rc1 := redis.NewClient(&opt)
+	defer func() {
+		require.NoError(t, rc1.Close())
+	}()
+
+	ps := rc1.Subscribe(ctx, "my-channel")
+	defer func() {
+		require.NoError(t, ps.Close())
+	}()
+	msgi, err := ps.ReceiveTimeout(ctx, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "subscribe", msgi.(*redis.Subscription).Kind)
+
+	err = rc2.Publish(ctx, "my-channel", "my-message-6").Err()
+	require.NoError(t, err)
+
+	for i := 3; i <= 6; i++ {
+		msgi, err = ps.ReceiveTimeout(ctx, time.Second)
+		require.NoError(t, err)
+		msg := msgi.(*redis.Message)
+		require.Equal(t, "my-channel", msg.Channel)
+		require.Equal(t, []string{strconv.Itoa(i), fmt.Sprintf("my-message-%d", i)}, msg.PayloadSlice)
+	}
+}
+
+func TestPubSub_Handler_PubSubResume_Latest(t *testing.T) {
+	c := testutil.NewConfig()
+	c.PubSub.Retention = map[string]config.ChannelRetention{
+		"my-channel": {MaxMessages: 100},
+	}
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(testcluster.NewEnvironment(c)).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	rc := s.client.Get(s.rt.This().String())
+	for i := 0; i < 3; i++ {
+		require.NoError(t, rc.Publish(ctx, "my-channel", "my-message").Err())
+	}
+
+	cmd := protocol.NewPubSubResume().
+		Add("my-channel", protocol.LatestSequence).
+		Add("not-retained", protocol.LatestSequence).
+		Command(ctx)
+	require.NoError(t, rc.Process(ctx, cmd))
+	sequences, err := cmd.Result()
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 0}, sequences)
+}
+
+func TestPubSub_Handler_RESP3_Command_On_Subscribed_Connection(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	conn, err := net.Dial("tcp", s.rt.This().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	rd := bufio.NewReader(conn)
+	readLine := func() string {
+		line, err := rd.ReadString('\n')
+		require.NoError(t, err)
+		return strings.TrimSuffix(line, "\r\n")
+	}
+
+	_, err = conn.Write([]byte("HELLO 3\r\n"))
+	require.NoError(t, err)
+	require.Equal(t, "%5", readLine())
+	// Skip the server properties, the last one is the empty list of modules.
+	for readLine() != "*0" {
+	}
+
+	_, err = conn.Write([]byte("SUBSCRIBE my-channel\r\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{">3", "$9", "subscribe", "$10", "my-channel", ":1"},
+		[]string{readLine(), readLine(), readLine(), readLine(), readLine(), readLine()})
+
+	// Regular commands are allowed on a subscribed RESP3 connection. The reply of
+	// PUBLISH may be written before or after the message itself.
+	_, err = conn.Write([]byte("PUBLISH my-channel hello\r\n"))
+	require.NoError(t, err)
+
+	var lines []string
+	for i := 0; i < 8; i++ {
+		lines = append(lines, readLine())
+	}
+	message := []string{">3", "$7", "message", "$10", "my-channel", "$5", "hello"}
+	if lines[0] == ":1" {
+		require.Equal(t, message, lines[1:])
+	} else {
+		require.Equal(t, append(message, ":1"), lines)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"sync"
+	"time"
+
+	"github.com/olric-data/olric/config"
+)
+
+// historyEntry is a retained message.
+type historyEntry struct {
+	sequence  uint64
+	payload   string
+	timestamp int64
+}
+
+// history is the short-term message history of a channel. It's a ring buffer
+// that grows on demand. The oldest messages are discarded when MaxMessages or
+// MaxAge is exceeded.
+type history struct {
+	mu          sync.Mutex
+	maxMessages int
+	maxAge      time.Duration
+	entries     []historyEntry
+	head        int
+	length      int
+	last        uint64
+
+	// sequencer serializes the publishers on the member that assigns the
+	// sequence IDs, so all the members receive the messages in order.
+	sequencer sync.Mutex
+}
+
+func newHistory(retention config.ChannelRetention) *history {
+	size := retention.MaxMessages
+	if size <= 0 || size > 1024 {
+		size = 1024
+	}
+	return &history{
+		maxMessages: retention.MaxMessages,
+		maxAge:      retention.MaxAge,
+		entries:     make([]historyEntry, size),
+	}
+}
+
+// lastSequence returns the sequence ID of the latest message.
+func (h *history) lastSequence() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.last
+}
+
+// add appends a message to the history. If sequence is zero, the next
+// sequence ID is assigned. It returns the sequence ID of the message.
+func (h *history) add(sequence uint64, payload string, now time.Time) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if sequence == 0 {
+		sequence = h.last + 1
+	}
+	if sequence > h.last {
+		h.last = sequence
+	}
+
+	if h.maxMessages > 0 && h.length == h.maxMessages {
+		// Overwrite the oldest message.
+		h.head = (h.head + 1) % len(h.entries)
+		h.length--
+	}
+	if h.length == len(h.entries) {
+		h.grow()
+	}
+	idx := (h.head + h.length) % len(h.entries)
+	h.entries[idx] = historyEntry{
+		sequence:  sequence,
+		payload:   payload,
+		timestamp: now.UnixNano(),
+	}
+	h.length++
+	h.expire(now)
+	return sequence
+}
+
+// grow doubles the capacity of the ring buffer. The caller must hold h.mu.
+func (h *history) grow() {
+	size := 2 * len(h.entries)
+	if h.maxMessages > 0 && size > h.maxMessages {
+		size = h.maxMessages
+	}
+	entries := make([]historyEntry, size)
+	for i := 0; i < h.length; i++ {
+		entries[i] = h.entries[(h.head+i)%len(h.entries)]
+	}
+	h.entries = entries
+	h.head = 0
+}
+
+// expire discards the messages older than MaxAge. The caller must hold h.mu.
+func (h *history) expire(now time.Time) {
+	if h.maxAge <= 0 {
+		return
+	}
+	deadline := now.Add(-h.maxAge).UnixNano()
+	for h.length > 0 && h.entries[h.head].timestamp < deadline {
+		h.entries[h.head] = historyEntry{}
+		h.head = (h.head + 1) % len(h.entries)
+		h.length--
+	}
+}
+
+// since returns the messages whose sequence ID is greater than the given one.
+func (h *history) since(sequence uint64, now time.Time) []historyEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.expire(now)
+	var result []historyEntry
+	for i := 0; i < h.length; i++ {
+		entry := h.entries[(h.head+i)%len(h.entries)]
+		if entry.sequence > sequence {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// len returns the number of retained messages.
+func (h *history) len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.length
+}
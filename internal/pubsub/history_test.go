@@ -0,0 +1,82 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/config"
+	"github.com/stretchr/testify/require"
+)
+
+func historySequences(entries []historyEntry) []uint64 {
+	var sequences []uint64
+	for _, entry := range entries {
+		sequences = append(sequences, entry.sequence)
+	}
+	return sequences
+}
+
+func TestPubSub_History_MaxMessages(t *testing.T) {
+	h := newHistory(config.ChannelRetention{MaxMessages: 3})
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		require.Equal(t, uint64(i+1), h.add(0, strconv.Itoa(i), now))
+	}
+
+	require.Equal(t, 3, h.len())
+	require.Equal(t, uint64(5), h.lastSequence())
+	require.Equal(t, []uint64{3, 4, 5}, historySequences(h.since(0, now)))
+	require.Equal(t, []uint64{5}, historySequences(h.since(4, now)))
+	require.Empty(t, h.since(5, now))
+}
+
+func TestPubSub_History_MaxAge(t *testing.T) {
+	h := newHistory(config.ChannelRetention{MaxAge: time.Minute})
+	now := time.Now()
+	h.add(0, "old", now.Add(-2*time.Minute))
+	h.add(0, "new", now)
+
+	entries := h.since(0, now)
+	require.Len(t, entries, 1)
+	require.Equal(t, uint64(2), entries[0].sequence)
+	require.Equal(t, "new", entries[0].payload)
+
+	require.Empty(t, h.since(0, now.Add(2*time.Minute)))
+	require.Equal(t, uint64(2), h.lastSequence())
+}
+
+func TestPubSub_History_Grow(t *testing.T) {
+	h := newHistory(config.ChannelRetention{MaxAge: time.Hour})
+	now := time.Now()
+	for i := 0; i < 3000; i++ {
+		h.add(0, strconv.Itoa(i), now)
+	}
+	require.Equal(t, 3000, h.len())
+
+	entries := h.since(2997, now)
+	require.Equal(t, []uint64{2998, 2999, 3000}, historySequences(entries))
+	require.Equal(t, "2999", entries[2].payload)
+}
+
+func TestPubSub_History_Given_Sequence(t *testing.T) {
+	h := newHistory(config.ChannelRetention{MaxMessages: 10})
+	now := time.Now()
+	require.Equal(t, uint64(42), h.add(42, "my-message", now))
+	require.Equal(t, uint64(43), h.add(0, "my-message", now))
+	require.Equal(t, uint64(43), h.lastSequence())
+}
@@ -23,9 +23,15 @@ package pubsub
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
 	"github.com/tidwall/btree"
 	"github.com/tidwall/match"
 	"github.com/tidwall/redcon"
@@ -39,86 +45,379 @@ type PubSub struct {
 	chans  *btree.BTree
 	conns  map[redcon.Conn]*pubSubConn
 
+	// patterns indexes the PSUBSCRIBE entries. chans holds the SUBSCRIBE
+	// and SSUBSCRIBE entries.
+	patterns *patternTrie
+
+	// queueSize is the capacity of the outbound message queue of a
+	// subscriber. Zero means config.DefaultSubscriberQueueSize.
+	queueSize int
+
+	// overflowPolicy determines what happens when the queue of a subscriber
+	// is full. Empty means config.DefaultOverflowPolicy.
+	overflowPolicy config.OverflowPolicy
+
 	// callbacks
 	unsubscribeCallback  func()
 	punsubscribeCallback func()
+	sunsubscribeCallback func()
+	droppedCallback      func()
+
+	// shardOwner returns the partition and the owner of a sharded channel.
+	// ok is true if this member owns the partition. If it's nil, every
+	// channel is owned by this member.
+	shardOwner func(channel string) (partID uint64, owner string, ok bool)
+
+	// retention holds the history settings of the retained channels.
+	retention map[string]config.ChannelRetention
+
+	historyMu sync.Mutex
+	histories map[string]*history
+
+	// resumeFrom returns the last seen sequence IDs of a connection. ok is
+	// true if the connection asked for sequenced delivery with PUBSUB RESUME.
+	// If it's nil, sequenced delivery is disabled.
+	resumeFrom func(conn redcon.Conn) (sequences map[string]uint64, ok bool)
+
+	// handler serves the other commands on the subscribed RESP3 connections.
+	// If it's nil, only the Pub/Sub commands are allowed.
+	handler redcon.Handler
+
+	// authorize checks the permissions of the subscriptions on the subscribed
+	// connections, they don't go through the handler. If it's nil, all the
+	// subscriptions are allowed.
+	authorize func(conn redcon.Conn, cmd redcon.Command) bool
+}
+
+// entryKind denotes the kind of subscription.
+type entryKind uint8
+
+const (
+	// channelEntry is created by SUBSCRIBE.
+	channelEntry entryKind = iota
+
+	// patternEntry is created by PSUBSCRIBE.
+	patternEntry
+
+	// shardEntry is created by SSUBSCRIBE.
+	shardEntry
+)
+
+// subscribeReply returns the kind of reply to a subscribe command.
+func (k entryKind) subscribeReply() string {
+	switch k {
+	case patternEntry:
+		return "psubscribe"
+	case shardEntry:
+		return "ssubscribe"
+	default:
+		return "subscribe"
+	}
+}
+
+// unsubscribeReply returns the kind of reply to an unsubscribe command.
+func (k entryKind) unsubscribeReply() string {
+	switch k {
+	case patternEntry:
+		return "punsubscribe"
+	case shardEntry:
+		return "sunsubscribe"
+	default:
+		return "unsubscribe"
+	}
+}
+
+// SubscriberStats is a point-in-time snapshot of a subscriber connection.
+type SubscriberStats struct {
+	ID          uint64
+	RemoteAddr  string
+	QueueLength int
+	Dropped     int64
 }
 
 // Subscribe a connection to PubSub
 func (ps *PubSub) Subscribe(conn redcon.Conn, channel string) {
-	ps.subscribe(conn, false, channel)
+	ps.subscribe(conn, channelEntry, channel)
 }
 
 // Psubscribe a connection to PubSub
 func (ps *PubSub) Psubscribe(conn redcon.Conn, channel string) {
-	ps.subscribe(conn, true, channel)
+	ps.subscribe(conn, patternEntry, channel)
+}
+
+// Ssubscribe a connection to a sharded channel. The caller must check the
+// ownership of the channel.
+func (ps *PubSub) Ssubscribe(conn redcon.Conn, channel string) {
+	ps.subscribe(conn, shardEntry, channel)
 }
 
-// Publish a message to subscribers
-func (ps *PubSub) Publish(channel, message string) int {
+// Publish a message to subscribers. It doesn't wait for the network. Messages
+// are queued per subscriber and written by a background goroutine. It returns
+// the number of subscribers that the message is queued for.
+func (ps *PubSub) Publish(channel, msg string) int {
+	return ps.PublishSequence(channel, msg, 0)
+}
+
+// PublishSequence publishes a message with a sequence ID. If the channel is
+// retained, the message is added to the history. A zero sequence ID means the
+// next one in the history.
+func (ps *PubSub) PublishSequence(channel, msg string, sequence uint64) int {
 	ps.mu.RLock()
 	defer ps.mu.RUnlock()
+
+	// The history is updated while holding ps.mu, so a new subscriber cannot
+	// miss a message between the replay and the subscription.
+	if h := ps.history(channel); h != nil {
+		sequence = h.add(sequence, msg, time.Now())
+	}
 	if !ps.initd {
 		return 0
 	}
-	var sent int
 	// write messages to all clients that are subscribed on the channel
-	pivot := &pubSubEntry{pattern: false, channel: channel}
-	ps.chans.Ascend(pivot, func(item interface{}) bool {
-		entry := item.(*pubSubEntry)
-		if entry.channel != pivot.channel || entry.pattern != pivot.pattern {
-			return false
+	sent := ps.publish(channelEntry, channel, msg, sequence)
+
+	// match on and write all psubscribe clients
+	ps.patterns.match(channel, func(entry *pubSubEntry) {
+		m := &message{
+			kind:     patternEntry,
+			pchannel: entry.channel,
+			channel:  channel,
+			payload:  msg,
+		}
+		if ps.enqueue(entry.sconn, m) {
+			sent++
 		}
-		entry.sconn.writeMessage(entry.pattern, "", channel, message)
-		sent++
-		return true
 	})
 
-	// match on and write all psubscribe clients
-	pivot = &pubSubEntry{pattern: true}
+	return sent
+}
+
+// SPublish publishes a message to the subscribers of a sharded channel. The
+// caller must check the ownership of the channel.
+func (ps *PubSub) SPublish(channel, msg string) int {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	if !ps.initd {
+		return 0
+	}
+	return ps.publish(shardEntry, channel, msg, 0)
+}
+
+// publish queues a message for the subscribers of a channel. The caller must
+// hold ps.mu.
+func (ps *PubSub) publish(kind entryKind, channel, msg string, sequence uint64) int {
+	var sent int
+	pivot := &pubSubEntry{kind: kind, channel: channel}
 	ps.chans.Ascend(pivot, func(item interface{}) bool {
 		entry := item.(*pubSubEntry)
-		if match.Match(channel, entry.channel) {
-			entry.sconn.writeMessage(entry.pattern, entry.channel, channel,
-				message)
+		if entry.channel != pivot.channel || entry.kind != pivot.kind {
+			return false
+		}
+		m := &message{kind: kind, channel: channel, payload: msg, sequence: sequence}
+		if ps.enqueue(entry.sconn, m) {
+			sent++
 		}
-		sent++
 		return true
 	})
-
 	return sent
 }
 
+// enqueue puts a message into the outbound queue of a subscriber. If the queue
+// is full, the overflow policy is applied. It returns false if the message is
+// dropped.
+func (ps *PubSub) enqueue(sconn *pubSubConn, m *message) bool {
+	for {
+		select {
+		case sconn.queue <- m:
+			return true
+		default:
+		}
+
+		switch ps.overflowPolicy {
+		case config.DropNewest:
+			ps.drop(sconn)
+			return false
+		case config.Disconnect:
+			ps.drop(sconn)
+			sconn.disconnect()
+			return false
+		default:
+			// DropOldest: make room for the new message and try again.
+			select {
+			case <-sconn.queue:
+				ps.drop(sconn)
+			default:
+			}
+		}
+	}
+}
+
+func (ps *PubSub) drop(sconn *pubSubConn) {
+	sconn.dropped.Add(1)
+	if ps.droppedCallback != nil {
+		ps.droppedCallback()
+	}
+}
+
+// history returns the history of a channel. It returns nil if the channel is
+// not retained.
+func (ps *PubSub) history(channel string) *history {
+	retention, ok := ps.retention[channel]
+	if !ok {
+		return nil
+	}
+
+	ps.historyMu.Lock()
+	defer ps.historyMu.Unlock()
+
+	if ps.histories == nil {
+		ps.histories = make(map[string]*history)
+	}
+	h, ok := ps.histories[channel]
+	if !ok {
+		h = newHistory(retention)
+		ps.histories[channel] = h
+	}
+	return h
+}
+
+// LastSequence returns the sequence ID of the latest message of a channel.
+// ok is false if the channel is not retained.
+func (ps *PubSub) LastSequence(channel string) (sequence uint64, ok bool) {
+	h := ps.history(channel)
+	if h == nil {
+		return 0, false
+	}
+	return h.lastSequence(), true
+}
+
+// replay queues the retained messages whose sequence ID is greater than the
+// given one. The caller must hold ps.mu.
+func (ps *PubSub) replay(sconn *pubSubConn, channel string, sequence uint64) {
+	h := ps.history(channel)
+	if h == nil {
+		return
+	}
+	for _, entry := range h.since(sequence, time.Now()) {
+		ps.enqueue(sconn, &message{
+			kind:     channelEntry,
+			channel:  channel,
+			payload:  entry.payload,
+			sequence: entry.sequence,
+		})
+	}
+}
+
+// Subscribers returns a snapshot of the current subscriber connections.
+func (ps *PubSub) Subscribers() []SubscriberStats {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	if !ps.initd {
+		return nil
+	}
+
+	var result []SubscriberStats
+	for _, sconn := range ps.conns {
+		result = append(result, SubscriberStats{
+			ID:          sconn.id,
+			RemoteAddr:  sconn.conn.RemoteAddr(),
+			QueueLength: len(sconn.queue),
+			Dropped:     sconn.dropped.Load(),
+		})
+	}
+	return result
+}
+
+type message struct {
+	kind     entryKind
+	pchannel string
+	channel  string
+	payload  string
+	sequence uint64
+}
+
 type pubSubConn struct {
-	id      uint64
-	mu      sync.Mutex
-	conn    redcon.Conn
-	dconn   redcon.DetachedConn
-	entries map[*pubSubEntry]bool
+	id        uint64
+	mu        sync.Mutex
+	conn      redcon.Conn
+	dconn     redcon.DetachedConn
+	entries   map[*pubSubEntry]bool
+	queue     chan *message
+	done      chan struct{}
+	dropped   atomic.Int64
+	closeOnce sync.Once
+
+	// sequenced is true if the messages are delivered with their sequence IDs.
+	sequenced bool
 }
 
 type pubSubEntry struct {
-	pattern bool
+	kind    entryKind
 	sconn   *pubSubConn
 	channel string
 }
 
-func (sconn *pubSubConn) writeMessage(pat bool, pchan, channel, msg string) {
-	sconn.mu.Lock()
-	defer sconn.mu.Unlock()
-	if pat {
-		sconn.dconn.WriteArray(4)
+// writeMessage writes a message to the connection buffer. The caller must hold
+// sconn.mu and flush the buffer.
+func (sconn *pubSubConn) writeMessage(m *message) {
+	switch m.kind {
+	case patternEntry:
+		server.WritePush(sconn.dconn, 4)
 		sconn.dconn.WriteBulkString("pmessage")
-		sconn.dconn.WriteBulkString(pchan)
-		sconn.dconn.WriteBulkString(channel)
-		sconn.dconn.WriteBulkString(msg)
-	} else {
-		sconn.dconn.WriteArray(3)
+		sconn.dconn.WriteBulkString(m.pchannel)
+		sconn.dconn.WriteBulkString(m.channel)
+		sconn.dconn.WriteBulkString(m.payload)
+	case shardEntry:
+		server.WritePush(sconn.dconn, 3)
+		sconn.dconn.WriteBulkString("smessage")
+		sconn.dconn.WriteBulkString(m.channel)
+		sconn.dconn.WriteBulkString(m.payload)
+	default:
+		server.WritePush(sconn.dconn, 3)
 		sconn.dconn.WriteBulkString("message")
-		sconn.dconn.WriteBulkString(channel)
-		sconn.dconn.WriteBulkString(msg)
+		sconn.dconn.WriteBulkString(m.channel)
+		if sconn.sequenced {
+			// A sequenced message is a [sequence, payload] pair.
+			sconn.dconn.WriteArray(2)
+			sconn.dconn.WriteBulkString(strconv.FormatUint(m.sequence, 10))
+		}
+		sconn.dconn.WriteBulkString(m.payload)
 	}
-	sconn.dconn.Flush()
+}
+
+// writer runs in the background and writes the queued messages to the
+// detached client. It writes all the available messages before flushing
+// the buffer.
+func (sconn *pubSubConn) writer() {
+	for {
+		select {
+		case m := <-sconn.queue:
+			sconn.mu.Lock()
+			sconn.writeMessage(m)
+		DRAIN:
+			for {
+				select {
+				case m = <-sconn.queue:
+					sconn.writeMessage(m)
+				default:
+					break DRAIN
+				}
+			}
+			sconn.dconn.Flush()
+			sconn.mu.Unlock()
+		case <-sconn.done:
+			return
+		}
+	}
+}
+
+// disconnect closes the underlying network connection. bgrunner notices it
+// and cleans up the subscriptions.
+func (sconn *pubSubConn) disconnect() {
+	sconn.closeOnce.Do(func() {
+		_ = sconn.dconn.NetConn().Close()
+	})
 }
 
 // bgrunner runs in the background and reads incoming commands from the
@@ -130,9 +429,10 @@ func (sconn *pubSubConn) bgrunner(ps *PubSub) {
 		ps.mu.Lock()
 		defer ps.mu.Unlock()
 		for entry := range sconn.entries {
-			ps.chans.Delete(entry)
+			ps.deleteEntry(entry)
 		}
 		delete(ps.conns, sconn.conn)
+		close(sconn.done)
 		sconn.mu.Lock()
 		defer sconn.mu.Unlock()
 		sconn.dconn.Close()
@@ -146,7 +446,7 @@ func (sconn *pubSubConn) bgrunner(ps *PubSub) {
 			continue
 		}
 		switch strings.ToLower(string(cmd.Args[0])) {
-		case "psubscribe", "subscribe":
+		case "psubscribe", "subscribe", "ssubscribe":
 			if len(cmd.Args) < 2 {
 				func() {
 					sconn.mu.Lock()
@@ -158,21 +458,40 @@ func (sconn *pubSubConn) bgrunner(ps *PubSub) {
 				continue
 			}
 			command := strings.ToLower(string(cmd.Args[0]))
+			// The command doesn't go through the mux, so the permissions
+			// are checked here.
+			if ps.authorize != nil && !sconn.authorize(ps.authorize, cmd) {
+				continue
+			}
+			if command == "ssubscribe" && !sconn.checkShardOwnership(ps, cmd.Args[1:]) {
+				continue
+			}
 			for i := 1; i < len(cmd.Args); i++ {
-				if command == "psubscribe" {
+				switch command {
+				case "psubscribe":
 					ps.Psubscribe(sconn.conn, string(cmd.Args[i]))
-				} else {
+				case "ssubscribe":
+					ps.Ssubscribe(sconn.conn, string(cmd.Args[i]))
+				default:
 					ps.Subscribe(sconn.conn, string(cmd.Args[i]))
 				}
 			}
-		case "unsubscribe", "punsubscribe":
-			pattern := strings.ToLower(string(cmd.Args[0])) == "punsubscribe"
+		case "unsubscribe", "punsubscribe", "sunsubscribe":
+			var kind entryKind
+			switch strings.ToLower(string(cmd.Args[0])) {
+			case "punsubscribe":
+				kind = patternEntry
+			case "sunsubscribe":
+				kind = shardEntry
+			default:
+				kind = channelEntry
+			}
 			if len(cmd.Args) == 1 {
-				ps.unsubscribe(sconn.conn, pattern, true, "")
+				ps.unsubscribe(sconn.conn, kind, true, "")
 			} else {
 				for i := 1; i < len(cmd.Args); i++ {
 					channel := string(cmd.Args[i])
-					ps.unsubscribe(sconn.conn, pattern, false, channel)
+					ps.unsubscribe(sconn.conn, kind, false, channel)
 				}
 			}
 		case "quit":
@@ -203,17 +522,32 @@ func (sconn *pubSubConn) bgrunner(ps *PubSub) {
 			func() {
 				sconn.mu.Lock()
 				defer sconn.mu.Unlock()
-				sconn.dconn.WriteArray(2)
-				sconn.dconn.WriteBulkString("pong")
-				sconn.dconn.WriteBulkString(msg)
+				if server.ProtocolVersion(sconn.dconn) == protocol.RESP3 {
+					// PING works as usual on RESP3 connections.
+					if len(cmd.Args) == 1 {
+						sconn.dconn.WriteString("PONG")
+					} else {
+						sconn.dconn.WriteBulkString(msg)
+					}
+				} else {
+					sconn.dconn.WriteArray(2)
+					sconn.dconn.WriteBulkString("pong")
+					sconn.dconn.WriteBulkString(msg)
+				}
 				sconn.dconn.Flush()
 			}()
 		default:
+			if ps.handler != nil && server.ProtocolVersion(sconn.dconn) == protocol.RESP3 {
+				// The replies and the push messages are distinguished by their
+				// types on RESP3 connections, so any command can be served.
+				sconn.serve(ps.handler, cmd)
+				continue
+			}
 			func() {
 				sconn.mu.Lock()
 				defer sconn.mu.Unlock()
 				sconn.dconn.WriteError(fmt.Sprintf("ERR Can't execute '%s': "+
-					"only (P)SUBSCRIBE / (P)UNSUBSCRIBE / PING / QUIT are "+
+					"only (P|S)SUBSCRIBE / (P|S)UNSUBSCRIBE / PING / QUIT are "+
 					"allowed in this context", cmd.Args[0]))
 				sconn.dconn.Flush()
 			}()
@@ -221,17 +555,146 @@ func (sconn *pubSubConn) bgrunner(ps *PubSub) {
 	}
 }
 
+// authorize checks the permissions of a command. It writes the error and
+// returns false if the command is denied.
+func (sconn *pubSubConn) authorize(authorize func(redcon.Conn, redcon.Command) bool, cmd redcon.Command) bool {
+	bconn := newBufferedConn(sconn.dconn)
+	if authorize(bconn, cmd) {
+		return true
+	}
+
+	sconn.mu.Lock()
+	defer sconn.mu.Unlock()
+	sconn.dconn.WriteRaw(bconn.Buffer())
+	sconn.dconn.Flush()
+	return false
+}
+
+// serve runs a command on the detached connection and writes the reply.
+func (sconn *pubSubConn) serve(handler redcon.Handler, cmd redcon.Command) {
+	bconn := newBufferedConn(sconn.dconn)
+	handler.ServeRESP(bconn, cmd)
+
+	sconn.mu.Lock()
+	defer sconn.mu.Unlock()
+	sconn.dconn.WriteRaw(bconn.Buffer())
+	sconn.dconn.Flush()
+}
+
+// setEntry adds an entry to the channels btree or the patterns trie. The caller
+// must hold ps.mu.
+func (ps *PubSub) setEntry(entry *pubSubEntry) {
+	if entry.kind == patternEntry {
+		ps.patterns.insert(entry)
+		return
+	}
+	ps.chans.Set(entry)
+}
+
+// deleteEntry removes an entry from the channels btree or the patterns trie.
+// The caller must hold ps.mu.
+func (ps *PubSub) deleteEntry(entry *pubSubEntry) {
+	if entry.kind == patternEntry {
+		ps.patterns.remove(entry)
+		return
+	}
+	ps.chans.Delete(entry)
+}
+
+// UnsubscribeMovedShards removes the sharded subscriptions whose partition is
+// no longer owned by this member. The subscribers receive a SUNSUBSCRIBE
+// message for every removed channel, so they can resubscribe to the new owner.
+// It returns the number of removed subscriptions.
+func (ps *PubSub) UnsubscribeMovedShards() int {
+	type notification struct {
+		sconn   *pubSubConn
+		channel string
+		count   int
+	}
+
+	ps.mu.Lock()
+	if !ps.initd {
+		ps.mu.Unlock()
+		return 0
+	}
+
+	var moved []*pubSubEntry
+	pivot := &pubSubEntry{kind: shardEntry}
+	ps.chans.Ascend(pivot, func(item interface{}) bool {
+		entry := item.(*pubSubEntry)
+		if entry.kind != shardEntry {
+			return false
+		}
+		if _, _, ok := ps.ShardOwner(entry.channel); !ok {
+			moved = append(moved, entry)
+		}
+		return true
+	})
+
+	var notifications []notification
+	for _, entry := range moved {
+		ps.deleteEntry(entry)
+		sconn := entry.sconn
+		sconn.mu.Lock()
+		delete(sconn.entries, entry)
+		count := sconn.count(shardEntry)
+		sconn.mu.Unlock()
+		ps.runUnsubscribeCallback(shardEntry)
+		notifications = append(notifications, notification{
+			sconn:   sconn,
+			channel: entry.channel,
+			count:   count,
+		})
+	}
+	ps.mu.Unlock()
+
+	// Don't block the publishers while writing to the network.
+	for _, n := range notifications {
+		n.sconn.mu.Lock()
+		server.WritePush(n.sconn.dconn, 3)
+		n.sconn.dconn.WriteBulkString(shardEntry.unsubscribeReply())
+		n.sconn.dconn.WriteBulkString(n.channel)
+		n.sconn.dconn.WriteInt(n.count)
+		n.sconn.dconn.Flush()
+		n.sconn.mu.Unlock()
+	}
+	return len(moved)
+}
+
+// checkShardOwnership writes a MOVED error and returns false if any of the
+// sharded channels is owned by another member.
+func (sconn *pubSubConn) checkShardOwnership(ps *PubSub, channels [][]byte) bool {
+	for _, channel := range channels {
+		partID, owner, ok := ps.ShardOwner(string(channel))
+		if ok {
+			continue
+		}
+		sconn.mu.Lock()
+		protocol.WriteMoved(sconn.dconn, partID, owner)
+		sconn.dconn.Flush()
+		sconn.mu.Unlock()
+		return false
+	}
+	return true
+}
+
+// ShardOwner returns the partition and the owner of a sharded channel. ok is
+// true if this member owns the partition.
+func (ps *PubSub) ShardOwner(channel string) (partID uint64, owner string, ok bool) {
+	if ps.shardOwner == nil {
+		return 0, "", true
+	}
+	return ps.shardOwner(channel)
+}
+
 // byEntry is a "less" function that sorts the entries in a btree. The tree
-// is sorted be (pattern, channel, conn.id). All pattern=true entries are at
-// the end (right) of the tree.
+// is sorted be (kind, channel, conn.id). All the entries of a kind are
+// adjacent in the tree.
 func byEntry(a, b interface{}) bool {
 	aa := a.(*pubSubEntry)
 	bb := b.(*pubSubEntry)
-	if !aa.pattern && bb.pattern {
-		return true
-	}
-	if aa.pattern && !bb.pattern {
-		return false
+	if aa.kind != bb.kind {
+		return aa.kind < bb.kind
 	}
 	if aa.channel < bb.channel {
 		return true
@@ -250,7 +713,7 @@ func byEntry(a, b interface{}) bool {
 	return aid < bid
 }
 
-func (ps *PubSub) subscribe(conn redcon.Conn, pattern bool, channel string) {
+func (ps *PubSub) subscribe(conn redcon.Conn, kind entryKind, channel string) {
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
 
@@ -258,6 +721,7 @@ func (ps *PubSub) subscribe(conn redcon.Conn, pattern bool, channel string) {
 	if !ps.initd {
 		ps.conns = make(map[redcon.Conn]*pubSubConn)
 		ps.chans = btree.New(byEntry)
+		ps.patterns = newPatternTrie()
 		ps.initd = true
 	}
 
@@ -268,11 +732,20 @@ func (ps *PubSub) subscribe(conn redcon.Conn, pattern bool, channel string) {
 		// and attach it to the PubSub channels/conn btree
 		ps.nextid++
 		dconn := conn.Detach()
+		queueSize := ps.queueSize
+		if queueSize <= 0 {
+			queueSize = config.DefaultSubscriberQueueSize
+		}
 		sconn = &pubSubConn{
 			id:      ps.nextid,
 			conn:    conn,
 			dconn:   dconn,
 			entries: make(map[*pubSubEntry]bool),
+			queue:   make(chan *message, queueSize),
+			done:    make(chan struct{}),
+		}
+		if ps.resumeFrom != nil {
+			_, sconn.sequenced = ps.resumeFrom(conn)
 		}
 		ps.conns[conn] = sconn
 	}
@@ -281,37 +754,64 @@ func (ps *PubSub) subscribe(conn redcon.Conn, pattern bool, channel string) {
 
 	// add an entry to the pubsub btree
 	entry := &pubSubEntry{
-		pattern: pattern,
+		kind:    kind,
 		channel: channel,
 		sconn:   sconn,
 	}
-	ps.chans.Set(entry)
+	ps.setEntry(entry)
 	sconn.entries[entry] = true
 
 	// send a message to the client
-	sconn.dconn.WriteArray(3)
-	if pattern {
-		sconn.dconn.WriteBulkString("psubscribe")
-	} else {
-		sconn.dconn.WriteBulkString("subscribe")
-	}
+	server.WritePush(sconn.dconn, 3)
+	sconn.dconn.WriteBulkString(kind.subscribeReply())
 	sconn.dconn.WriteBulkString(channel)
-	var count int
-	for ient := range sconn.entries {
-		if ient.pattern == pattern {
-			count++
-		}
-	}
+	count := sconn.count(kind)
 	sconn.dconn.WriteInt(count)
 	sconn.dconn.Flush()
 
+	// replay the retained messages after the last seen sequence
+	if kind == channelEntry && sconn.sequenced {
+		sequences, _ := ps.resumeFrom(conn)
+		if sequence, ok := sequences[channel]; ok {
+			ps.replay(sconn, channel, sequence)
+		}
+	}
+
 	// start the background client operation
 	if !ok {
+		go sconn.writer()
 		go sconn.bgrunner(ps)
 	}
 }
 
-func (ps *PubSub) unsubscribe(conn redcon.Conn, pattern, all bool, channel string) {
+// count returns the number of subscriptions of a kind. The caller must hold
+// sconn.mu.
+func (sconn *pubSubConn) count(kind entryKind) int {
+	var count int
+	for ient := range sconn.entries {
+		if ient.kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+func (ps *PubSub) runUnsubscribeCallback(kind entryKind) {
+	var callback func()
+	switch kind {
+	case patternEntry:
+		callback = ps.punsubscribeCallback
+	case shardEntry:
+		callback = ps.sunsubscribeCallback
+	default:
+		callback = ps.unsubscribeCallback
+	}
+	if callback != nil {
+		callback()
+	}
+}
+
+func (ps *PubSub) unsubscribe(conn redcon.Conn, kind entryKind, all bool, channel string) {
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
 	// fetch the pubSubConn. This must exist
@@ -321,39 +821,24 @@ func (ps *PubSub) unsubscribe(conn redcon.Conn, pattern, all bool, channel strin
 
 	removeEntry := func(entry *pubSubEntry) {
 		if entry != nil {
-			ps.chans.Delete(entry)
+			ps.deleteEntry(entry)
 			delete(sconn.entries, entry)
 		}
-		sconn.dconn.WriteArray(3)
-		if pattern {
-			if ps.punsubscribeCallback != nil {
-				ps.punsubscribeCallback()
-			}
-			sconn.dconn.WriteBulkString("punsubscribe")
-		} else {
-			if ps.unsubscribeCallback != nil {
-				ps.unsubscribeCallback()
-			}
-			sconn.dconn.WriteBulkString("unsubscribe")
-		}
+		ps.runUnsubscribeCallback(kind)
+		server.WritePush(sconn.dconn, 3)
+		sconn.dconn.WriteBulkString(kind.unsubscribeReply())
 		if entry != nil {
 			sconn.dconn.WriteBulkString(entry.channel)
 		} else {
-			sconn.dconn.WriteNull()
-		}
-		var count int
-		for ient := range sconn.entries {
-			if ient.pattern == pattern {
-				count++
-			}
+			server.WriteNull(sconn.dconn)
 		}
-		sconn.dconn.WriteInt(count)
+		sconn.dconn.WriteInt(sconn.count(kind))
 	}
 	if all {
-		// unsubscribe from all (p)subscribe entries
+		// unsubscribe from all (p|s)subscribe entries
 		var entries []*pubSubEntry
 		for ient := range sconn.entries {
-			if ient.pattern == pattern {
+			if ient.kind == kind {
 				entries = append(entries, ient)
 			}
 		}
@@ -365,10 +850,10 @@ func (ps *PubSub) unsubscribe(conn redcon.Conn, pattern, all bool, channel strin
 			}
 		}
 	} else {
-		// unsubscribe single channel from (p)subscribe.
+		// unsubscribe single channel from (p|s)subscribe.
 		var entry *pubSubEntry
 		for ient := range sconn.entries {
-			if ient.pattern == pattern && ient.channel == channel {
+			if ient.kind == kind && ient.channel == channel {
 				entry = ient
 				break
 			}
@@ -390,7 +875,7 @@ func (ps *PubSub) Channels() []string {
 	for _, sconn := range ps.conns {
 		sconn.mu.Lock()
 		for ient := range sconn.entries {
-			if !ient.pattern {
+			if ient.kind == channelEntry {
 				channels = append(channels, ient.channel)
 			}
 		}
@@ -412,7 +897,7 @@ func (ps *PubSub) ChannelsWithPatterns(pattern string) []string {
 	for _, sconn := range ps.conns {
 		sconn.mu.Lock()
 		for ient := range sconn.entries {
-			if match.Match(ient.channel, pattern) {
+			if ient.kind != shardEntry && match.Match(ient.channel, pattern) {
 				channels = append(channels, ient.channel)
 			}
 		}
@@ -434,7 +919,7 @@ func (ps *PubSub) Numpat() int {
 	for _, sconn := range ps.conns {
 		sconn.mu.Lock()
 		for ient := range sconn.entries {
-			if ient.pattern {
+			if ient.kind == patternEntry {
 				set[ient.channel] = struct{}{}
 			}
 		}
@@ -456,7 +941,7 @@ func (ps *PubSub) Numsub(channel string) int {
 	for _, sconn := range ps.conns {
 		sconn.mu.Lock()
 		for ient := range sconn.entries {
-			if ient.channel == channel {
+			if ient.kind != shardEntry && ient.channel == channel {
 				result++
 			}
 		}
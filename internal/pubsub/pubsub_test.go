@@ -31,7 +31,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/olric-data/olric/config"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/btree"
 	"github.com/tidwall/redcon"
 )
 
@@ -52,6 +54,9 @@ func testPubSubServer(addr string, done chan bool) {
 						if ps.chans.Len() != 0 {
 							panic("chans not empty")
 						}
+						if ps.patterns.len() != 0 {
+							panic("patterns not empty")
+						}
 						empty = true
 					}
 					ps.mu.Unlock()
@@ -221,3 +226,148 @@ func TestPubSub(t *testing.T) {
 	// stop the timeout
 	final <- true
 }
+
+func testEnqueue(t *testing.T, policy config.OverflowPolicy) (*PubSub, *pubSubConn) {
+	ps := &PubSub{overflowPolicy: policy}
+	sconn := &pubSubConn{queue: make(chan *message, 2)}
+	for i := 0; i < 3; i++ {
+		ps.enqueue(sconn, &message{channel: "my-channel", payload: strconv.Itoa(i)})
+	}
+	require.Equal(t, int64(1), sconn.dropped.Load())
+	return ps, sconn
+}
+
+func TestPubSub_Enqueue_DropOldest(t *testing.T) {
+	_, sconn := testEnqueue(t, config.DropOldest)
+	require.Equal(t, "1", (<-sconn.queue).payload)
+	require.Equal(t, "2", (<-sconn.queue).payload)
+}
+
+func TestPubSub_Enqueue_DropNewest(t *testing.T) {
+	_, sconn := testEnqueue(t, config.DropNewest)
+	require.Equal(t, "0", (<-sconn.queue).payload)
+	require.Equal(t, "1", (<-sconn.queue).payload)
+}
+
+func testSlowSubscriber(t *testing.T, ps *PubSub) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := redcon.NewServer("", func(conn redcon.Conn, cmd redcon.Command) {
+		ps.Subscribe(conn, string(cmd.Args[1]))
+	}, nil, nil)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(func() {
+		_ = srv.Close()
+	})
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	_, err = fmt.Fprintf(conn, "subscribe my-channel\r\n")
+	require.NoError(t, err)
+
+	// Wait for the confirmation and never read again.
+	rd := bufio.NewReader(conn)
+	var buf []byte
+	for {
+		line, err := rd.ReadBytes('\n')
+		require.NoError(t, err)
+		buf = append(buf, line...)
+		if n, _ := redcon.ReadNextRESP(buf); n != 0 {
+			break
+		}
+	}
+}
+
+func TestPubSub_Slow_Subscriber(t *testing.T) {
+	var dropped int
+	ps := &PubSub{
+		queueSize:      8,
+		overflowPolicy: config.DropOldest,
+		droppedCallback: func() {
+			dropped++
+		},
+	}
+	testSlowSubscriber(t, ps)
+
+	// Large enough to fill the socket buffers quickly. The publisher must
+	// not be blocked by the subscriber.
+	payload := strings.Repeat("x", 1<<16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			ps.Publish("my-channel", payload)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "publisher is blocked by a slow subscriber")
+	}
+
+	subscribers := ps.Subscribers()
+	require.Len(t, subscribers, 1)
+	require.Greater(t, subscribers[0].Dropped, int64(0))
+	require.Equal(t, int64(dropped), subscribers[0].Dropped)
+	require.LessOrEqual(t, subscribers[0].QueueLength, 8)
+}
+
+func TestPubSub_Slow_Subscriber_Disconnect(t *testing.T) {
+	ps := &PubSub{
+		queueSize:      8,
+		overflowPolicy: config.Disconnect,
+	}
+	testSlowSubscriber(t, ps)
+
+	payload := strings.Repeat("x", 1<<16)
+	for i := 0; i < 1000; i++ {
+		ps.Publish("my-channel", payload)
+	}
+
+	require.Eventually(t, func() bool {
+		return len(ps.Subscribers()) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func BenchmarkPubSub_Publish_Patterns(b *testing.B) {
+	for _, n := range patternCounts {
+		b.Run(fmt.Sprintf("patterns=%d", n), func(b *testing.B) {
+			ps := &PubSub{
+				chans:    btree.New(byEntry),
+				patterns: newPatternTrie(),
+				initd:    true,
+			}
+			sconn := &pubSubConn{
+				queue: make(chan *message, config.DefaultSubscriberQueueSize),
+				done:  make(chan struct{}),
+			}
+			defer close(sconn.done)
+			go func() {
+				for {
+					select {
+					case <-sconn.queue:
+					case <-sconn.done:
+						return
+					}
+				}
+			}()
+
+			for _, pattern := range benchmarkPatterns(n) {
+				ps.setEntry(&pubSubEntry{kind: patternEntry, channel: pattern, sconn: sconn})
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ps.Publish("news.7.sports", "message")
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"context"
 	"sync"
 
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/cluster/partitions"
 	"github.com/olric-data/olric/internal/cluster/routingtable"
 	"github.com/olric-data/olric/internal/environment"
 	"github.com/olric-data/olric/internal/protocol"
@@ -25,6 +27,7 @@ import (
 	"github.com/olric-data/olric/internal/service"
 	"github.com/olric-data/olric/internal/stats"
 	"github.com/olric-data/olric/pkg/flog"
+	"github.com/tidwall/redcon"
 )
 
 var (
@@ -39,55 +42,118 @@ var (
 
 	CurrentPSubscribers = stats.NewInt64Gauge()
 	PSubscribersTotal   = stats.NewInt64Counter()
+
+	// CurrentSSubscribers is the current number of listeners of sharded channels.
+	CurrentSSubscribers = stats.NewInt64Gauge()
+
+	// SSubscribersTotal is the total number of registered listeners of sharded channels during the life of this instance.
+	SSubscribersTotal = stats.NewInt64Counter()
+
+	// DroppedMessagesTotal is the total number of messages dropped because of slow subscribers.
+	DroppedMessagesTotal = stats.NewInt64Counter()
 )
 
 type Service struct {
 	sync.RWMutex
 
-	log    *flog.Logger
-	pubsub *PubSub
-	rt     *routingtable.RoutingTable
-	server *server.Server
-	client *server.Client
-	wg     sync.WaitGroup
-	ctx    context.Context
-	cancel context.CancelFunc
+	log     *flog.Logger
+	pubsub  *PubSub
+	rt      *routingtable.RoutingTable
+	primary *partitions.Partitions
+	server  *server.Server
+	client  *server.Client
+	wg      sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
 }
 
 func (s *Service) RegisterHandlers() {
 	s.server.ServeMux().HandleFunc(protocol.PubSub.Subscribe, s.subscribeCommandHandler)
 	s.server.ServeMux().HandleFunc(protocol.PubSub.PSubscribe, s.psubscribeCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.PubSub.SSubscribe, s.ssubscribeCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.PubSub.SPublish, s.spublishCommandHandler)
 	s.server.ServeMux().HandleFunc(protocol.PubSub.Publish, s.publishCommandHandler)
 	s.server.ServeMux().HandleFunc(protocol.PubSub.PublishInternal, s.publishInternalCommandHandler)
 	s.server.ServeMux().HandleFunc(protocol.PubSub.PubSubChannels, s.pubsubChannelsCommandHandler)
 	s.server.ServeMux().HandleFunc(protocol.PubSub.PubSubNumpat, s.pubsubNumpatCommandHandler)
 	s.server.ServeMux().HandleFunc(protocol.PubSub.PubSubNumsub, s.pubsubNumsubCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.PubSub.PubSubResume, s.pubsubResumeCommandHandler)
 
 }
 
 func NewService(e *environment.Environment) (service.Service, error) {
+	c := e.Get("config").(*config.Config)
 	ctx, cancel := context.WithCancel(context.Background())
 	ps := &PubSub{
+		queueSize:      c.PubSub.SubscriberQueueSize,
+		overflowPolicy: c.PubSub.OverflowPolicy,
+		retention:      c.PubSub.Retention,
+		resumeFrom:     resumeFrom,
 		unsubscribeCallback: func() {
 			CurrentSubscribers.Decrease(1)
 		},
 		punsubscribeCallback: func() {
 			CurrentPSubscribers.Decrease(1)
 		},
+		sunsubscribeCallback: func() {
+			CurrentSSubscribers.Decrease(1)
+		},
+		droppedCallback: func() {
+			DroppedMessagesTotal.Increase(1)
+		},
 	}
 	s := &Service{
-		log:    e.Get("logger").(*flog.Logger),
-		rt:     e.Get("routingtable").(*routingtable.RoutingTable),
-		server: e.Get("server").(*server.Server),
-		client: e.Get("client").(*server.Client),
-		pubsub: ps,
-		ctx:    ctx,
-		cancel: cancel,
+		log:     e.Get("logger").(*flog.Logger),
+		rt:      e.Get("routingtable").(*routingtable.RoutingTable),
+		primary: e.Get("primary").(*partitions.Partitions),
+		server:  e.Get("server").(*server.Server),
+		client:  e.Get("client").(*server.Client),
+		pubsub:  ps,
+		ctx:     ctx,
+		cancel:  cancel,
 	}
+	ps.shardOwner = s.shardOwner
+	ps.handler = s.server.ServeMux()
+	ps.authorize = s.server.ServeMux().Authorize
+	s.rt.AddCallback(s.unsubscribeMovedShards)
 	s.RegisterHandlers()
 	return s, nil
 }
 
+// shardOwner returns the partition and the owner of a sharded channel. The
+// partition is found by hashing the channel name just like a DMap key
+// without a DMap name, so the clients can find the owner with their copy of
+// the routing table.
+func (s *Service) shardOwner(channel string) (uint64, string, bool) {
+	part := s.primary.PartitionByHKey(partitions.HKey("", channel))
+	if part.OwnerCount() == 0 {
+		return part.ID(), "", false
+	}
+	owner := part.Owner()
+	return part.ID(), owner.String(), owner.CompareByName(s.rt.This())
+}
+
+// resumeFrom returns the last seen sequence IDs of a connection.
+func resumeFrom(conn redcon.Conn) (map[string]uint64, bool) {
+	ctx, ok := conn.Context().(*server.ConnContext)
+	if !ok {
+		return nil, false
+	}
+	return ctx.PubSubSequences()
+}
+
+// unsubscribeMovedShards runs after every routing table update.
+func (s *Service) unsubscribeMovedShards() {
+	if n := s.pubsub.UnsubscribeMovedShards(); n > 0 {
+		s.log.V(3).Printf("[INFO] %d sharded Pub/Sub subscriptions have been moved", n)
+	}
+}
+
+// Subscribers returns a snapshot of the current subscriber connections.
+func (s *Service) Subscribers() []SubscriberStats {
+	return s.pubsub.Subscribers()
+}
+
 func (s *Service) Start() error {
 	// dummy implementation
 	return nil
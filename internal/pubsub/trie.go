@@ -0,0 +1,258 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import "unicode/utf8"
+
+// patternTrie indexes the patterns of PSUBSCRIBE entries by their characters.
+// Patterns share their common prefixes, and a channel is matched against all
+// the patterns in a single pass over its characters. So the cost of a
+// publish depends on the length of the channel name and the number of matching
+// patterns, not on the total number of patterns.
+//
+// The supported syntax is the same as github.com/tidwall/match: '*' matches
+// any sequence of characters, '?' matches any single character and '\\'
+// escapes the next character.
+type patternTrie struct {
+	root *trieNode
+
+	// dead holds the entries with an invalid pattern, such as a trailing
+	// backslash. These patterns never match.
+	dead map[*pubSubEntry]struct{}
+
+	length int
+}
+
+type trieNode struct {
+	literals map[rune]*trieNode
+	any      *trieNode // '?'
+	star     *trieNode // '*'
+
+	// wildcard is true if the node is reached by a '*'. Such a node consumes
+	// any character and remains active.
+	wildcard bool
+	entries  map[*pubSubEntry]struct{}
+}
+
+type tokenKind uint8
+
+const (
+	literalToken tokenKind = iota
+	anyToken
+	starToken
+)
+
+type token struct {
+	kind tokenKind
+	r    rune
+}
+
+func newPatternTrie() *patternTrie {
+	return &patternTrie{
+		root: &trieNode{},
+		dead: make(map[*pubSubEntry]struct{}),
+	}
+}
+
+// tokenize splits a pattern into tokens. It returns false if the pattern
+// cannot match anything.
+func tokenize(pattern string) ([]token, bool) {
+	var tokens []token
+	for len(pattern) > 0 {
+		r, size := utf8.DecodeRuneInString(pattern)
+		pattern = pattern[size:]
+		switch r {
+		case '*':
+			// Ignore repeating stars.
+			if len(tokens) > 0 && tokens[len(tokens)-1].kind == starToken {
+				continue
+			}
+			tokens = append(tokens, token{kind: starToken})
+		case '?':
+			tokens = append(tokens, token{kind: anyToken})
+		case '\\':
+			if len(pattern) == 0 {
+				return nil, false
+			}
+			r, size = utf8.DecodeRuneInString(pattern)
+			pattern = pattern[size:]
+			tokens = append(tokens, token{kind: literalToken, r: r})
+		default:
+			tokens = append(tokens, token{kind: literalToken, r: r})
+		}
+	}
+	return tokens, true
+}
+
+func (n *trieNode) child(t token) *trieNode {
+	switch t.kind {
+	case anyToken:
+		return n.any
+	case starToken:
+		return n.star
+	default:
+		return n.literals[t.r]
+	}
+}
+
+func (n *trieNode) addChild(t token) *trieNode {
+	child := &trieNode{}
+	switch t.kind {
+	case anyToken:
+		n.any = child
+	case starToken:
+		child.wildcard = true
+		n.star = child
+	default:
+		if n.literals == nil {
+			n.literals = make(map[rune]*trieNode)
+		}
+		n.literals[t.r] = child
+	}
+	return child
+}
+
+func (n *trieNode) removeChild(t token) {
+	switch t.kind {
+	case anyToken:
+		n.any = nil
+	case starToken:
+		n.star = nil
+	default:
+		delete(n.literals, t.r)
+	}
+}
+
+func (n *trieNode) isEmpty() bool {
+	return len(n.entries) == 0 && len(n.literals) == 0 && n.any == nil && n.star == nil
+}
+
+func (t *patternTrie) insert(entry *pubSubEntry) {
+	tokens, ok := tokenize(entry.channel)
+	if !ok {
+		if _, exists := t.dead[entry]; !exists {
+			t.dead[entry] = struct{}{}
+			t.length++
+		}
+		return
+	}
+
+	node := t.root
+	for _, tk := range tokens {
+		child := node.child(tk)
+		if child == nil {
+			child = node.addChild(tk)
+		}
+		node = child
+	}
+	if node.entries == nil {
+		node.entries = make(map[*pubSubEntry]struct{})
+	}
+	if _, exists := node.entries[entry]; !exists {
+		node.entries[entry] = struct{}{}
+		t.length++
+	}
+}
+
+func (t *patternTrie) remove(entry *pubSubEntry) {
+	tokens, ok := tokenize(entry.channel)
+	if !ok {
+		if _, exists := t.dead[entry]; exists {
+			delete(t.dead, entry)
+			t.length--
+		}
+		return
+	}
+
+	path := make([]*trieNode, 0, len(tokens)+1)
+	node := t.root
+	path = append(path, node)
+	for _, tk := range tokens {
+		node = node.child(tk)
+		if node == nil {
+			// Not found
+			return
+		}
+		path = append(path, node)
+	}
+	if _, exists := node.entries[entry]; !exists {
+		return
+	}
+	delete(node.entries, entry)
+	t.length--
+
+	// Remove the empty nodes from bottom to top.
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if !path[i+1].isEmpty() {
+			break
+		}
+		path[i].removeChild(tokens[i])
+	}
+}
+
+// activate adds the node and the nodes that can be reached without consuming
+// a character to the active set.
+func activate(active []*trieNode, seen map[*trieNode]struct{}, node *trieNode) []*trieNode {
+	for node != nil {
+		if _, ok := seen[node]; ok {
+			return active
+		}
+		seen[node] = struct{}{}
+		active = append(active, node)
+		// '*' matches an empty sequence too.
+		node = node.star
+	}
+	return active
+}
+
+// match calls f for every entry whose pattern matches the channel.
+func (t *patternTrie) match(channel string, f func(entry *pubSubEntry)) {
+	if t.length == 0 {
+		return
+	}
+
+	seen := make(map[*trieNode]struct{})
+	active := activate(nil, seen, t.root)
+	var next []*trieNode
+	for len(channel) > 0 && len(active) > 0 {
+		r, size := utf8.DecodeRuneInString(channel)
+		channel = channel[size:]
+
+		clear(seen)
+		next = next[:0]
+		for _, node := range active {
+			if node.wildcard {
+				next = activate(next, seen, node)
+			}
+			if child, ok := node.literals[r]; ok {
+				next = activate(next, seen, child)
+			}
+			if node.any != nil {
+				next = activate(next, seen, node.any)
+			}
+		}
+		active, next = next, active
+	}
+
+	for _, node := range active {
+		for entry := range node.entries {
+			f(entry)
+		}
+	}
+}
+
+func (t *patternTrie) len() int {
+	return t.length
+}
@@ -0,0 +1,191 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/match"
+)
+
+func trieMatches(t *patternTrie, channel string) []string {
+	var result []string
+	t.match(channel, func(entry *pubSubEntry) {
+		result = append(result, entry.channel)
+	})
+	sort.Strings(result)
+	return result
+}
+
+func linearMatches(patterns []string, channel string) []string {
+	var result []string
+	for _, pattern := range patterns {
+		if match.Match(channel, pattern) {
+			result = append(result, pattern)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestPatternTrie_Match(t *testing.T) {
+	patterns := []string{
+		"*",
+		"**",
+		"news.*",
+		"news.*.sports",
+		"news.?",
+		"news.??",
+		"n*s*",
+		"*.sports",
+		"h?llo",
+		"h*llo",
+		"h\\*llo",
+		"h\\?llo",
+		"hello",
+		"héllo*",
+		"a*b*c",
+		"trailing\\",
+		"",
+	}
+
+	trie := newPatternTrie()
+	for _, pattern := range patterns {
+		trie.insert(&pubSubEntry{kind: patternEntry, channel: pattern})
+	}
+	require.Equal(t, len(patterns), trie.len())
+
+	channels := []string{
+		"",
+		"news",
+		"news.",
+		"news.a",
+		"news.ab",
+		"news.abc",
+		"news.local.sports",
+		"news.sports",
+		"hello",
+		"hallo",
+		"heeello",
+		"h*llo",
+		"h?llo",
+		"héllo world",
+		"abc",
+		"aXbYc",
+		"aXbYcZ",
+		"trailing\\",
+		"trailing",
+	}
+	for _, channel := range channels {
+		t.Run(channel, func(t *testing.T) {
+			require.Equal(t, linearMatches(patterns, channel), trieMatches(trie, channel))
+		})
+	}
+}
+
+func TestPatternTrie_Match_Random(t *testing.T) {
+	const alphabet = "ab.*?\\"
+	randomString := func(r *rand.Rand, chars string) string {
+		b := make([]byte, r.Intn(8))
+		for i := range b {
+			b[i] = chars[r.Intn(len(chars))]
+		}
+		return string(b)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	trie := newPatternTrie()
+	var patterns []string
+	for i := 0; i < 500; i++ {
+		pattern := randomString(r, alphabet)
+		patterns = append(patterns, pattern)
+		trie.insert(&pubSubEntry{kind: patternEntry, channel: pattern})
+	}
+
+	for i := 0; i < 1000; i++ {
+		channel := randomString(r, "ab.")
+		require.Equal(t, linearMatches(patterns, channel), trieMatches(trie, channel), channel)
+	}
+}
+
+func TestPatternTrie_Remove(t *testing.T) {
+	trie := newPatternTrie()
+	a := &pubSubEntry{kind: patternEntry, channel: "news.*"}
+	b := &pubSubEntry{kind: patternEntry, channel: "news.*"}
+	c := &pubSubEntry{kind: patternEntry, channel: "news.*.sports"}
+	d := &pubSubEntry{kind: patternEntry, channel: "dead\\"}
+	for _, entry := range []*pubSubEntry{a, b, c, d} {
+		trie.insert(entry)
+	}
+	require.Equal(t, 4, trie.len())
+
+	trie.remove(a)
+	require.Equal(t, []string{"news.*"}, trieMatches(trie, "news.local"))
+	require.Equal(t, []string{"news.*", "news.*.sports"}, trieMatches(trie, "news.local.sports"))
+
+	// Removing twice is a no-op.
+	trie.remove(a)
+	require.Equal(t, 3, trie.len())
+
+	for _, entry := range []*pubSubEntry{b, c, d} {
+		trie.remove(entry)
+	}
+	require.Equal(t, 0, trie.len())
+	require.True(t, trie.root.isEmpty())
+	require.Empty(t, trieMatches(trie, "news.local.sports"))
+}
+
+func benchmarkPatterns(n int) []string {
+	patterns := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		patterns = append(patterns, fmt.Sprintf("news.%d.*", i))
+	}
+	return patterns
+}
+
+var patternCounts = []int{10, 100, 1000, 10000}
+
+func BenchmarkPatternTrie_Match(b *testing.B) {
+	for _, n := range patternCounts {
+		b.Run(fmt.Sprintf("patterns=%d", n), func(b *testing.B) {
+			trie := newPatternTrie()
+			for _, pattern := range benchmarkPatterns(n) {
+				trie.insert(&pubSubEntry{kind: patternEntry, channel: pattern})
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				trie.match("news.7.sports", func(*pubSubEntry) {})
+			}
+		})
+	}
+}
+
+func BenchmarkPatternLinear_Match(b *testing.B) {
+	for _, n := range patternCounts {
+		b.Run(fmt.Sprintf("patterns=%d", n), func(b *testing.B) {
+			patterns := benchmarkPatterns(n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, pattern := range patterns {
+					match.Match("news.7.sports", pattern)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/acl"
+	"github.com/olric-data/olric/pkg/flog"
+	"github.com/tidwall/redcon"
+)
+
+// Outcomes of the audited commands.
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+	AuditOutcomeDenied  = "denied"
+)
+
+// AuditRecord is a record of the audit log. It's written as a line of JSON.
+type AuditRecord struct {
+	// Timestamp is the time when the command was received.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Command is the name of the command.
+	Command string `json:"command"`
+
+	// DMap is the name of the DMap targeted by the command, if any. The names
+	// are separated by commas if the command accesses several DMaps, e.g. DEL
+	// with the keys of different DMaps.
+	DMap string `json:"dmap,omitempty"`
+
+	// NumArgs is the number of the arguments, excluding the command and the
+	// subcommand. Their values are redacted.
+	NumArgs int `json:"num_args"`
+
+	// ClientAddr is the address of the client.
+	ClientAddr string `json:"client_addr"`
+
+	// User is the name of the authenticated user. It's empty if the
+	// authentication is disabled.
+	User string `json:"user,omitempty"`
+
+	// Outcome is success, failure or denied. A denied command is rejected
+	// by the ACL before running.
+	Outcome string `json:"outcome"`
+
+	// Error is the error returned to the client, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditLog writes a record for every audited command. It's thread-safe.
+type AuditLog struct {
+	mtx sync.Mutex
+
+	w        io.Writer
+	closer   io.Closer
+	commands map[string]struct{}
+	log      *flog.Logger
+}
+
+// NewAuditLog opens the file of the audit log, if it's needed, and returns a
+// new AuditLog.
+func NewAuditLog(c *config.AuditLog, l *flog.Logger) (*AuditLog, error) {
+	a := &AuditLog{
+		commands: make(map[string]struct{}),
+		log:      l,
+	}
+	for _, command := range c.Commands {
+		a.commands[command] = struct{}{}
+	}
+
+	switch {
+	case c.Output != nil:
+		a.w = c.Output
+	case c.Path == "stdout":
+		a.w = os.Stdout
+	case c.Path == "stderr":
+		a.w = os.Stderr
+	default:
+		f, err := os.OpenFile(c.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the audit log: %w", err)
+		}
+		a.w = f
+		a.closer = f
+	}
+	return a, nil
+}
+
+// Audited returns true if the command is recorded by the audit log.
+func (a *AuditLog) Audited(command string) bool {
+	_, ok := a.commands[command]
+	return ok
+}
+
+// Write adds a record to the audit log.
+func (a *AuditLog) Write(record *AuditRecord) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		a.log.V(2).Printf("[ERROR] Failed to encode the audit record of %s: %v", record.Command, err)
+		return
+	}
+	data = append(data, '\n')
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if _, err = a.w.Write(data); err != nil {
+		a.log.V(2).Printf("[ERROR] Failed to write the audit record of %s: %v", record.Command, err)
+	}
+}
+
+// Close closes the file of the audit log. It doesn't close the custom writers
+// and the standard streams.
+func (a *AuditLog) Close() error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// auditConn wraps the connection of an audited command to capture the
+// first error written to the client.
+type auditConn struct {
+	redcon.Conn
+
+	record *AuditRecord
+	denied bool
+	err    string
+}
+
+// newAuditConn creates an auditConn. dmapName returns the DMap name of a key of
+// the Redis compatibility layer.
+func newAuditConn(command string, conn redcon.Conn, cmd redcon.Command, dmapName func(key string) string) *auditConn {
+	record := &AuditRecord{
+		Timestamp:  time.Now(),
+		Command:    command,
+		NumArgs:    len(cmd.Args) - len(strings.Fields(command)),
+		ClientAddr: conn.RemoteAddr(),
+	}
+	// The DMaps are found like the ACL does, the position of the name depends
+	// on the command.
+	var dmaps []string
+	for _, dmap := range acl.DMaps(command, cmd.Args, dmapName) {
+		if !slices.Contains(dmaps, dmap) {
+			dmaps = append(dmaps, dmap)
+		}
+	}
+	record.DMap = strings.Join(dmaps, ",")
+	return &auditConn{
+		Conn:   conn,
+		record: record,
+	}
+}
+
+// WriteError writes an error to the client and keeps it for the audit record.
+func (c *auditConn) WriteError(msg string) {
+	if c.err == "" {
+		c.err = msg
+	}
+	c.Conn.WriteError(msg)
+}
+
+// Record returns the audit record of the command. It should be called after
+// the command is served.
+func (c *auditConn) Record() *AuditRecord {
+	// The user is read after the command, AUTH may change it.
+	if cc, ok := c.Conn.Context().(*ConnContext); ok {
+		c.record.User = cc.User()
+	}
+
+	switch {
+	case c.denied:
+		c.record.Outcome = AuditOutcomeDenied
+	case c.err != "":
+		c.record.Outcome = AuditOutcomeFailure
+	default:
+		c.record.Outcome = AuditOutcomeSuccess
+	}
+	c.record.Error = c.err
+	return c.record
+}
@@ -0,0 +1,217 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/acl"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/redcon"
+)
+
+type auditBuffer struct {
+	mtx sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *auditBuffer) Write(p []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *auditBuffer) records(t *testing.T) []AuditRecord {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(bytes.NewReader(b.buf.Bytes()))
+	for scanner.Scan() {
+		var record AuditRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func TestAuditLog_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	c := &config.AuditLog{Path: path}
+	require.NoError(t, c.Sanitize())
+
+	a, err := NewAuditLog(c, newTestLogger())
+	require.NoError(t, err)
+	require.True(t, a.Audited(protocol.DMap.Destroy))
+	require.False(t, a.Audited(protocol.DMap.Get))
+
+	now := time.Now().UTC()
+	a.Write(&AuditRecord{
+		Timestamp:  now,
+		Command:    protocol.DMap.Destroy,
+		DMap:       "mydmap",
+		ClientAddr: "127.0.0.1:4242",
+		Outcome:    AuditOutcomeSuccess,
+	})
+	require.NoError(t, a.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var record AuditRecord
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(data), &record))
+	require.True(t, now.Equal(record.Timestamp))
+	require.Equal(t, protocol.DMap.Destroy, record.Command)
+	require.Equal(t, "mydmap", record.DMap)
+	require.Equal(t, "127.0.0.1:4242", record.ClientAddr)
+	require.Equal(t, AuditOutcomeSuccess, record.Outcome)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestMux_AuditLog(t *testing.T) {
+	a, err := acl.New(&config.Authentication{
+		Password: "secret",
+		Users: []config.User{{
+			Name:      "alice",
+			Passwords: []string{config.HashPassword("alice-password")},
+			Rules:     []string{"+@dmap", "~mydmap"},
+		}},
+	}, (&config.RedisCompat{DefaultDMap: config.DefaultRedisCompatDMap}).DMapName)
+	require.NoError(t, err)
+
+	output := &auditBuffer{}
+	c := &config.AuditLog{Output: output}
+	require.NoError(t, c.Sanitize())
+	auditLog, err := NewAuditLog(c, newTestLogger())
+	require.NoError(t, err)
+
+	s := newServer(t)
+	s.config.RequireAuth = true
+	s.config.ACL = a
+	s.config.AuditLog = auditLog
+	s.config.Authenticate = func(username, password string) error {
+		if !a.Authenticate(username, password) {
+			return errors.New("WRONGPASS invalid username-password pair or user is disabled")
+		}
+		return nil
+	}
+	s.ServeMux().HandleFunc(protocol.DMap.Get, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteString(protocol.StatusOK)
+	})
+	s.ServeMux().HandleFunc(protocol.DMap.Destroy, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteString(protocol.StatusOK)
+	})
+	s.ServeMux().HandleFunc(protocol.DMap.Unlock, func(conn redcon.Conn, cmd redcon.Command) {
+		protocol.WriteError(conn, errors.New("NOSUCHLOCK no such lock"))
+	})
+
+	<-s.StartedCtx.Done()
+
+	ctx := context.Background()
+	opt := defaultRedisOptions(s.config)
+	opt.Username = "alice"
+	opt.Password = "alice-password"
+	rdb := redis.NewClient(opt)
+
+	require.NoError(t, rdb.Do(ctx, protocol.DMap.Get, "mydmap", "mykey").Err())
+	require.NoError(t, rdb.Do(ctx, protocol.DMap.Destroy, "mydmap").Err())
+	require.Error(t, rdb.Do(ctx, protocol.DMap.Destroy, "other").Err())
+	require.Error(t, rdb.Do(ctx, protocol.DMap.Unlock, "mydmap", "mykey", "secret-token").Err())
+
+	records := output.records(t)
+	require.Len(t, records, 3)
+
+	require.Equal(t, protocol.DMap.Destroy, records[0].Command)
+	require.Equal(t, "mydmap", records[0].DMap)
+	require.Equal(t, "alice", records[0].User)
+	require.Equal(t, AuditOutcomeSuccess, records[0].Outcome)
+	require.Empty(t, records[0].Error)
+	require.NotEmpty(t, records[0].ClientAddr)
+	require.False(t, records[0].Timestamp.IsZero())
+
+	require.Equal(t, "other", records[1].DMap)
+	require.Equal(t, AuditOutcomeDenied, records[1].Outcome)
+	require.Contains(t, records[1].Error, "NOPERM")
+
+	require.Equal(t, protocol.DMap.Unlock, records[2].Command)
+	require.Equal(t, 3, records[2].NumArgs)
+	require.Equal(t, AuditOutcomeFailure, records[2].Outcome)
+	require.Contains(t, records[2].Error, "no such lock")
+
+	// The values of the arguments are redacted.
+	output.mtx.Lock()
+	defer output.mtx.Unlock()
+	require.NotContains(t, output.buf.String(), "mykey")
+	require.NotContains(t, output.buf.String(), "secret-token")
+}
+
+func TestMux_AuditLog_DMaps(t *testing.T) {
+	output := &auditBuffer{}
+	c := &config.AuditLog{
+		Output:   output,
+		Commands: []string{protocol.DMap.Scan, protocol.Redis.Set, protocol.Redis.Del},
+	}
+	require.NoError(t, c.Sanitize())
+	auditLog, err := NewAuditLog(c, newTestLogger())
+	require.NoError(t, err)
+
+	s := newServer(t)
+	s.config.AuditLog = auditLog
+	s.config.DMapName = (&config.RedisCompat{DefaultDMap: "default", KeyPrefixSeparator: ":"}).DMapName
+	for _, command := range c.Commands {
+		s.ServeMux().HandleFunc(command, func(conn redcon.Conn, cmd redcon.Command) {
+			conn.WriteString(protocol.StatusOK)
+		})
+	}
+
+	<-s.StartedCtx.Done()
+
+	ctx := context.Background()
+	rdb := redis.NewClient(defaultRedisOptions(s.config))
+
+	require.NoError(t, rdb.Do(ctx, protocol.NewScan(42, "mydmap", 0).Command(ctx).Args()...).Err())
+	require.NoError(t, rdb.Do(ctx, protocol.Redis.Set, "users:1", "value").Err())
+	require.NoError(t, rdb.Do(ctx, protocol.Redis.Del, "users:1", "sessions:1", "users:2").Err())
+
+	records := output.records(t)
+	require.Len(t, records, 3)
+
+	// The DMap of DM.SCAN follows the partition ID.
+	require.Equal(t, protocol.DMap.Scan, records[0].Command)
+	require.Equal(t, "mydmap", records[0].DMap)
+
+	// The DMaps of the Redis commands are found by the key prefix.
+	require.Equal(t, protocol.Redis.Set, records[1].Command)
+	require.Equal(t, "users", records[1].DMap)
+	require.Equal(t, protocol.Redis.Del, records[2].Command)
+	require.Equal(t, "users,sessions", records[2].DMap)
+}
@@ -19,9 +19,12 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/roundrobin"
+	"github.com/olric-data/olric/internal/tracing"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -31,6 +34,11 @@ type Client struct {
 	config     *config.Client
 	clients    map[string]*redis.Client
 	roundRobin *roundrobin.RoundRobin
+
+	// password is used by the new connections. It's changed by SetPassword
+	// during a credential rotation.
+	credentialsMtx sync.RWMutex
+	password       string
 }
 
 func NewClient(c *config.Client) *Client {
@@ -41,11 +49,32 @@ func NewClient(c *config.Client) *Client {
 			panic(fmt.Sprintf("failed to sanitize client config: %s", err))
 		}
 	}
-	return &Client{
+	cl := &Client{
 		config:     c,
 		clients:    make(map[string]*redis.Client),
 		roundRobin: roundrobin.New(nil),
 	}
+	if c.Authentication != nil {
+		cl.password = c.Authentication.Password
+	}
+	return cl
+}
+
+// SetPassword changes the password that the new connections authenticate with.
+// The connections in the pools are already authenticated, they are not affected.
+func (c *Client) SetPassword(password string) {
+	c.credentialsMtx.Lock()
+	defer c.credentialsMtx.Unlock()
+
+	c.password = password
+}
+
+// credentials returns the credentials of the new connections.
+func (c *Client) credentials() (string, string) {
+	c.credentialsMtx.RLock()
+	defer c.credentialsMtx.RUnlock()
+
+	return c.config.Authentication.Username, c.password
 }
 
 func (c *Client) Addresses() map[string]struct{} {
@@ -79,15 +108,28 @@ func (c *Client) Get(addr string) *redis.Client {
 		return rc
 	}
 
+	// The clients negotiate RESP3 with HELLO 3, and fall back to RESP2 if the
+	// member doesn't support it.
 	opt := c.config.RedisOptions()
-	opt.Protocol = 2
+	opt.Protocol = protocol.RESP3
 	opt.Addr = addr
+	if c.config.Authentication.Enabled() {
+		opt.CredentialsProvider = c.credentials
+	}
 	rc = redis.NewClient(opt)
+	if c.config.TracerProvider != nil {
+		rc.AddHook(tracing.NewHook(c.config.TracerProvider, addr))
+	}
 	c.clients[addr] = rc
 	c.roundRobin.Add(addr)
 	return rc
 }
 
+// ReadTimeout returns the socket read timeout of the clients.
+func (c *Client) ReadTimeout() time.Duration {
+	return c.config.ReadTimeout
+}
+
 func (c *Client) pickNodeRoundRobin() (string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
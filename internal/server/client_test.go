@@ -16,6 +16,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"net"
 	"strconv"
 	"testing"
@@ -141,3 +142,35 @@ func TestServer_Client_Shutdown(t *testing.T) {
 	require.Empty(t, cs.clients)
 	require.Equal(t, 0, cs.roundRobin.Length())
 }
+
+func TestServer_Client_SetPassword(t *testing.T) {
+	srv := newServer(t)
+	password := "secret"
+	srv.config.RequireAuth = true
+	srv.config.Authenticate = func(username, p string) error {
+		if username == config.DefaultUser && p == password {
+			return nil
+		}
+		return errors.New("WRONGPASS invalid username-password pair or user is disabled")
+	}
+	srv.ServeMux().HandleFunc(protocol.Generic.Ping, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteBulkString("pong")
+	})
+
+	<-srv.StartedCtx.Done()
+
+	addr := net.JoinHostPort(srv.config.BindAddr, strconv.Itoa(srv.config.BindPort))
+	c := config.NewClient()
+	c.Authentication = &config.Authentication{Password: "secret"}
+	require.NoError(t, c.Sanitize())
+
+	cs := NewClient(c)
+	ctx := context.Background()
+	require.NoError(t, cs.Get(addr).Process(ctx, protocol.NewPing().Command(ctx)))
+
+	// The server only accepts the new password. The new connections use it.
+	password = "new-secret"
+	cs.SetPassword("new-secret")
+	require.NoError(t, cs.Close(addr))
+	require.NoError(t, cs.Get(addr).Process(ctx, protocol.NewPing().Command(ctx)))
+}
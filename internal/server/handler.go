@@ -55,7 +55,8 @@ func (h Handler) ServeRESP(conn redcon.Conn, cmd redcon.Command) {
 	// Do not call precondition function for the following commands:
 	// * Internal.UpdateRouting
 	// * Generic.Auth
-	if command == protocol.Internal.UpdateRouting || command == protocol.Generic.Auth {
+	// * Generic.Hello
+	if command == protocol.Internal.UpdateRouting || command == protocol.Generic.Auth || command == protocol.Generic.Hello {
 		h.handler(conn, cmd)
 		return
 	}
@@ -71,6 +72,17 @@ func (h Handler) ServeRESP(conn redcon.Conn, cmd redcon.Command) {
 	}
 }
 
+// ServeRESP dispatches the command to the registered handlers.
+func (m *ServeMuxWrapper) ServeRESP(conn redcon.Conn, cmd redcon.Command) {
+	m.mux.ServeRESP(conn, cmd)
+}
+
+// Authorize checks the permissions of a command that is not dispatched by the
+// mux, see ServeMux.Authorize.
+func (m *ServeMuxWrapper) Authorize(conn redcon.Conn, cmd redcon.Command) bool {
+	return m.mux.Authorize(conn, cmd)
+}
+
 // HandleFunc registers the handler function for the given command.
 func (m *ServeMuxWrapper) HandleFunc(command string, handler func(conn redcon.Conn, cmd redcon.Command)) {
 	if handler == nil {
@@ -0,0 +1,69 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/tidwall/redcon"
+)
+
+// helloCommandHandler negotiates the protocol version of the connection and replies
+// with a map of the server properties. If the credentials are given, it also
+// authenticates the connection. SETNAME is accepted for compatibility, but the
+// name is not stored.
+func (m *ServeMux) helloCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	helloCmd, err := protocol.ParseHelloCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	ctx := conn.Context().(*ConnContext)
+	if helloCmd.Password != "" {
+		if m.config.Authenticate == nil {
+			protocol.WriteError(conn, errors.New("HELLO called with credentials, but authentication is not configured"))
+			return
+		}
+		if err = m.config.Authenticate(helloCmd.Username, helloCmd.Password); err != nil {
+			protocol.WriteError(conn, err)
+			return
+		}
+		ctx.SetAuthenticated(true)
+		ctx.SetUser(helloCmd.Username)
+	}
+
+	if m.config.RequireAuth && !ctx.IsAuthenticated() {
+		protocol.WriteError(conn, ErrAuthRequired)
+		return
+	}
+
+	if helloCmd.ProtocolVersion != 0 {
+		ctx.SetProtocolVersion(helloCmd.ProtocolVersion)
+	}
+
+	WriteMap(conn, 5)
+	conn.WriteBulkString("server")
+	conn.WriteBulkString("olric")
+	conn.WriteBulkString("version")
+	conn.WriteBulkString(m.config.Version)
+	conn.WriteBulkString("proto")
+	conn.WriteInt(ctx.ProtocolVersion())
+	conn.WriteBulkString("mode")
+	conn.WriteBulkString("cluster")
+	conn.WriteBulkString("modules")
+	conn.WriteArray(0)
+}
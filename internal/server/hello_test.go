@@ -0,0 +1,169 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/redcon"
+)
+
+func TestMux_Hello(t *testing.T) {
+	s := newServer(t)
+	s.config.Version = "0.7.0"
+
+	<-s.StartedCtx.Done()
+
+	rdb := redis.NewClient(defaultRedisOptions(s.config))
+	ctx := context.Background()
+
+	cmd := protocol.NewHello(protocol.RESP3).Command(ctx)
+	require.NoError(t, rdb.Process(ctx, cmd))
+
+	reply, err := cmd.Result()
+	require.NoError(t, err)
+	require.Equal(t, "olric", reply["server"])
+	require.Equal(t, "0.7.0", reply["version"])
+	require.Equal(t, int64(protocol.RESP3), reply["proto"])
+	require.Equal(t, "cluster", reply["mode"])
+}
+
+func TestMux_Hello_NoProto(t *testing.T) {
+	s := newServer(t)
+
+	<-s.StartedCtx.Done()
+
+	opt := defaultRedisOptions(s.config)
+	opt.Protocol = protocol.RESP2
+	rdb := redis.NewClient(opt)
+	ctx := context.Background()
+
+	cmd := redis.NewMapStringInterfaceCmd(ctx, protocol.Generic.Hello, 4)
+	err := rdb.Process(ctx, cmd)
+	require.ErrorIs(t, protocol.ConvertError(err), protocol.ErrNoProto)
+}
+
+func TestMux_Hello_Auth(t *testing.T) {
+	s := newServer(t)
+	s.config.RequireAuth = true
+	s.config.Authenticate = func(username, password string) error {
+		if username == "default" && password == "secret" {
+			return nil
+		}
+		return errors.New("WRONGPASS invalid username-password pair or user is disabled")
+	}
+	s.ServeMux().HandleFunc(protocol.Generic.Ping, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteString("PONG")
+	})
+
+	<-s.StartedCtx.Done()
+
+	ctx := context.Background()
+	t.Run("Wrong password", func(t *testing.T) {
+		opt := defaultRedisOptions(s.config)
+		opt.Protocol = protocol.RESP2
+		rdb := redis.NewClient(opt)
+		cmd := protocol.NewHello(protocol.RESP3).SetAuth("default", "wrong").Command(ctx)
+		err := rdb.Process(ctx, cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "WRONGPASS")
+	})
+
+	t.Run("Authenticated with HELLO", func(t *testing.T) {
+		opt := defaultRedisOptions(s.config)
+		opt.Username = "default"
+		opt.Password = "secret"
+		rdb := redis.NewClient(opt)
+		require.NoError(t, rdb.Ping(ctx).Err())
+	})
+
+	t.Run("HELLO without credentials", func(t *testing.T) {
+		opt := defaultRedisOptions(s.config)
+		opt.Protocol = protocol.RESP2
+		rdb := redis.NewClient(opt)
+		cmd := protocol.NewHello(protocol.RESP3).Command(ctx)
+		err := rdb.Process(ctx, cmd)
+		require.ErrorIs(t, protocol.ConvertError(err), ErrAuthRequired)
+	})
+}
+
+func TestMux_RESP3_Types(t *testing.T) {
+	s := newServer(t)
+	s.ServeMux().HandleFunc("resp3.types", func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteArray(4)
+		WriteNull(conn)
+		WriteDouble(conn, 3.14)
+		WriteBool(conn, true)
+		WriteMap(conn, 1)
+		conn.WriteBulkString("key")
+		conn.WriteInt(1)
+	})
+
+	<-s.StartedCtx.Done()
+
+	ctx := context.Background()
+	t.Run("RESP3", func(t *testing.T) {
+		opt := defaultRedisOptions(s.config)
+		opt.Protocol = protocol.RESP3
+		rdb := redis.NewClient(opt)
+		cmd := redis.NewSliceCmd(ctx, "resp3.types")
+		require.NoError(t, rdb.Process(ctx, cmd))
+		require.Equal(t, []interface{}{
+			nil,
+			3.14,
+			true,
+			map[interface{}]interface{}{"key": int64(1)},
+		}, cmd.Val())
+	})
+
+	t.Run("RESP2", func(t *testing.T) {
+		opt := defaultRedisOptions(s.config)
+		opt.Protocol = protocol.RESP2
+		rdb := redis.NewClient(opt)
+		cmd := redis.NewSliceCmd(ctx, "resp3.types")
+		require.NoError(t, rdb.Process(ctx, cmd))
+		require.Equal(t, []interface{}{
+			nil,
+			"3.14",
+			int64(1),
+			[]interface{}{"key", int64(1)},
+		}, cmd.Val())
+	})
+}
+
+func TestMux_WriteJSON(t *testing.T) {
+	s := newServer(t)
+	s.ServeMux().HandleFunc("resp3.json", func(conn redcon.Conn, cmd redcon.Command) {
+		require.NoError(t, WriteJSON(conn, []byte(`{"b":1.5,"a":[1,"x",true,null]}`)))
+	})
+
+	<-s.StartedCtx.Done()
+
+	ctx := context.Background()
+	opt := defaultRedisOptions(s.config)
+	opt.Protocol = protocol.RESP3
+	rdb := redis.NewClient(opt)
+	cmd := redis.NewCmd(ctx, "resp3.json")
+	require.NoError(t, rdb.Process(ctx, cmd))
+	require.Equal(t, map[interface{}]interface{}{
+		"a": []interface{}{int64(1), "x", true, nil},
+		"b": 1.5,
+	}, cmd.Val())
+}
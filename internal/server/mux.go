@@ -22,31 +22,110 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/olric-data/olric/config"
 	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/stats"
+	"github.com/olric-data/olric/internal/tracing"
 	"github.com/olric-data/olric/internal/util"
 	"github.com/tidwall/redcon"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// errAuthRequired represents an error indicating that authentication is required to access the requested resource or operation.
-var errAuthRequired = errors.New("Authentication required.")
+// ErrAuthRequired represents an error indicating that authentication is required to access the requested resource or operation.
+var ErrAuthRequired = errors.New("Authentication required.")
 
 // ServeMux is an RESP command multiplexer.
 type ServeMux struct {
-	config   *Config
-	handlers map[string]redcon.Handler
+	config    *Config
+	handlers  map[string]redcon.Handler
+	latencies *stats.HistogramVec
+	slowLog   *SlowLog
+	tracer    trace.Tracer
 }
 
 // NewServeMux allocates and returns a new ServeMux.
 func NewServeMux(c *Config) *ServeMux {
-	protocol.SetError("NOAUTH", errAuthRequired)
-	return &ServeMux{
-		config:   c,
-		handlers: make(map[string]redcon.Handler),
+	protocol.SetError("NOAUTH", ErrAuthRequired)
+	buckets := c.CommandLatencyBuckets
+	if len(buckets) == 0 {
+		buckets = config.DefaultCommandLatencyBuckets
 	}
+	threshold := c.SlowLogThreshold
+	if threshold == 0 {
+		threshold = config.DefaultSlowLogThreshold
+	}
+	maxLen := c.SlowLogMaxLen
+	if maxLen == 0 {
+		maxLen = config.DefaultSlowLogMaxLen
+	}
+	m := &ServeMux{
+		config:    c,
+		handlers:  make(map[string]redcon.Handler),
+		latencies: stats.NewHistogramVec(buckets),
+		slowLog:   NewSlowLog(threshold, maxLen),
+	}
+	if c.TracerProvider != nil {
+		m.tracer = tracing.Tracer(c.TracerProvider)
+	}
+	m.Handle(protocol.Generic.Hello, redcon.HandlerFunc(m.helloCommandHandler))
+	return m
+}
+
+// CommandLatencies returns the latency histograms of the commands, keyed by command name.
+func (m *ServeMux) CommandLatencies() *stats.HistogramVec {
+	return m.latencies
+}
+
+// SlowLog returns the slow log of the commands.
+func (m *ServeMux) SlowLog() *SlowLog {
+	return m.slowLog
+}
+
+// serve calls the handler, records the latency of the command and adds it to the slow log
+// if it's slow. If tracing is enabled, it also creates a server span that is a child of
+// the caller's span.
+func (m *ServeMux) serve(command, traceParent string, handler redcon.Handler, conn redcon.Conn, cmd redcon.Command) {
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start)
+		m.latencies.With(command).Observe(duration)
+		// AUTH, HELLO and the admin commands may carry a secret.
+		if !protocol.HasSecret(command) {
+			m.slowLog.Record(cmd, conn.RemoteAddr(), start, duration)
+		}
+	}()
+
+	cc, ok := conn.Context().(*ConnContext)
+	if m.tracer == nil || !ok || !tracing.Traced(command) {
+		handler.ServeRESP(conn, cmd)
+		return
+	}
+
+	ctx := context.Background()
+	if traceParent != "" {
+		ctx = tracing.Extract(ctx, traceParent)
+	}
+	addr := net.JoinHostPort(m.config.BindAddr, strconv.Itoa(m.config.BindPort))
+	_, span := m.tracer.Start(ctx, command,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(tracing.Attributes(command, addr)...),
+		trace.WithAttributes(attribute.String("client.address", conn.RemoteAddr())),
+	)
+	defer span.End()
+
+	cc.SetSpanContext(span.SpanContext())
+	defer cc.SetSpanContext(trace.SpanContext{})
+
+	handler.ServeRESP(conn, cmd)
 }
 
 // HandleFunc registers the handler function for the given command.
@@ -77,31 +156,94 @@ func (m *ServeMux) Handle(command string, handler redcon.Handler) {
 func (m *ServeMux) ServeRESP(conn redcon.Conn, cmd redcon.Command) {
 	command := strings.ToLower(util.BytesToString(cmd.Args[0]))
 
-	if m.config.RequireAuth && command != protocol.Generic.Auth {
+	var traceParent string
+	if command == protocol.Generic.Trace {
+		var err error
+		traceParent, cmd, err = protocol.ParseTraceCommand(cmd)
+		if err != nil {
+			protocol.WriteError(conn, err)
+			return
+		}
+		command = strings.ToLower(util.BytesToString(cmd.Args[0]))
+	}
+
+	// HELLO may carry the credentials, its handler checks the authentication.
+	if m.config.RequireAuth && command != protocol.Generic.Auth && command != protocol.Generic.Hello {
 		ctx := conn.Context().(*ConnContext)
 		if !ctx.IsAuthenticated() {
-			protocol.WriteError(conn, errAuthRequired)
+			protocol.WriteError(conn, ErrAuthRequired)
 			return
 		}
 	}
 
-	if handler, ok := m.handlers[command]; ok {
-		handler.ServeRESP(conn, cmd)
-		return
+	if m.config.ClusterRole != "" && protocol.IsInternalCommand(command, cmd.Args) {
+		if !hasClusterRole(conn, m.config.ClusterRole) {
+			protocol.WriteError(conn, fmt.Errorf("%w: '%s' requires a cluster certificate", protocol.ErrNoPerm, command))
+			return
+		}
 	}
 
-	if command == protocol.PubSub.PubSub {
+	handler, ok := m.handlers[command]
+	if !ok && (command == protocol.PubSub.PubSub || command == protocol.Generic.SlowLog || command == protocol.Generic.ACL) {
 		if len(cmd.Args) < 2 {
 			protocol.WriteError(conn, fmt.Errorf("wrong number of arguments for '%s' command", command))
 			return
 		}
-		command = fmt.Sprintf("%s %s", command, util.BytesToString(cmd.Args[1]))
+		command = fmt.Sprintf("%s %s", command, strings.ToLower(util.BytesToString(cmd.Args[1])))
+		handler, ok = m.handlers[command]
+	}
+	if !ok {
+		protocol.WriteError(conn, fmt.Errorf("unknown command '%s'", command))
+		return
 	}
 
-	if handler, ok := m.handlers[command]; ok {
-		handler.ServeRESP(conn, cmd)
+	var ac *auditConn
+	if m.config.AuditLog != nil && m.config.AuditLog.Audited(command) {
+		ac = newAuditConn(command, conn, cmd, m.config.DMapName)
+		defer func() {
+			m.config.AuditLog.Write(ac.Record())
+		}()
+		conn = ac
+	}
+
+	if !m.checkACL(conn, ac, command, cmd) {
 		return
 	}
 
-	protocol.WriteError(conn, fmt.Errorf("unknown command '%s'", command))
+	m.serve(command, traceParent, handler, conn, cmd)
+}
+
+// checkACL checks the permissions of the user of the connection. It writes the
+// error to conn and returns false if the command is denied.
+func (m *ServeMux) checkACL(conn redcon.Conn, ac *auditConn, command string, cmd redcon.Command) bool {
+	if m.config.ACL == nil {
+		return true
+	}
+	ctx := conn.Context().(*ConnContext)
+	if err := m.config.ACL.Check(ctx.User(), command, cmd.Args); err != nil {
+		if ac != nil {
+			ac.denied = true
+		}
+		protocol.WriteError(conn, err)
+		return false
+	}
+	return true
+}
+
+// Authorize checks the permissions of a command that is read from a detached
+// connection, e.g. a SUBSCRIBE on a connection that is already subscribed, and
+// records it in the audit log like ServeRESP does. It writes the error to conn
+// and returns false if the command is denied.
+func (m *ServeMux) Authorize(conn redcon.Conn, cmd redcon.Command) bool {
+	command := strings.ToLower(util.BytesToString(cmd.Args[0]))
+
+	var ac *auditConn
+	if m.config.AuditLog != nil && m.config.AuditLog.Audited(command) {
+		ac = newAuditConn(command, conn, cmd, m.config.DMapName)
+		defer func() {
+			m.config.AuditLog.Write(ac.Record())
+		}()
+		conn = ac
+	}
+	return m.checkACL(conn, ac, command, cmd)
 }
@@ -16,13 +16,27 @@ package server
 
 import (
 	"context"
+	"errors"
+	"log"
 	"math/rand"
+	"net"
+	"os"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/acl"
 	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/stats"
+	"github.com/olric-data/olric/pkg/flog"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 	"github.com/tidwall/redcon"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestMux_PubSub_Command(t *testing.T) {
@@ -52,3 +66,233 @@ func TestMux_PubSub_Command(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, int64(10), num)
 }
+
+func TestMux_CommandLatencies(t *testing.T) {
+	s := newServer(t)
+
+	s.ServeMux().HandleFunc(protocol.Generic.Ping, func(conn redcon.Conn, cmd redcon.Command) {
+		time.Sleep(2 * time.Millisecond)
+		conn.WriteString("PONG")
+	})
+	s.ServeMux().HandleFunc(protocol.PubSub.PubSubNumpat, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteInt(10)
+	})
+
+	<-s.StartedCtx.Done()
+
+	rdb := redis.NewClient(defaultRedisOptions(s.config))
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, rdb.Ping(ctx).Err())
+	}
+	require.NoError(t, rdb.Do(ctx, "pubsub", "numpat").Err())
+	require.Error(t, rdb.Do(ctx, "unknown-command").Err())
+
+	result := make(map[string]stats.HistogramSnapshot)
+	s.CommandLatencies().Range(func(command string, h *stats.Histogram) bool {
+		result[command] = h.Snapshot()
+		return true
+	})
+	// ping, pubsub numpat and hello, which is sent by go-redis to negotiate RESP3.
+	require.Len(t, result, 3)
+
+	ping := result[protocol.Generic.Ping]
+	require.Equal(t, int64(3), ping.Count)
+	require.GreaterOrEqual(t, ping.Sum, 6*time.Millisecond)
+	require.Equal(t, config.DefaultCommandLatencyBuckets, ping.Buckets)
+
+	require.Equal(t, int64(1), result[protocol.PubSub.PubSubNumpat].Count)
+}
+
+func newTracedServer(t *testing.T, tp trace.TracerProvider) *Server {
+	bindPort, err := getFreePort()
+	require.NoError(t, err)
+
+	c := &Config{
+		BindAddr:        "127.0.0.1",
+		BindPort:        bindPort,
+		KeepAlivePeriod: time.Second,
+		TracerProvider:  tp,
+	}
+	s := New(c, flog.New(log.New(os.Stdout, "server-test: ", log.LstdFlags)))
+
+	go func() {
+		if err := s.ListenAndServe(); err != nil {
+			t.Errorf("Expected nil. Got: %v", err)
+		}
+	}()
+
+	t.Cleanup(func() {
+		require.NoError(t, s.Shutdown(context.Background()))
+	})
+	return s
+}
+
+func TestMux_Tracing(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	s := newTracedServer(t, tp)
+
+	var mtx sync.Mutex
+	var handlerSpans []trace.SpanContext
+	s.ServeMux().HandleFunc(protocol.Generic.Ping, func(conn redcon.Conn, cmd redcon.Command) {
+		mtx.Lock()
+		sc := trace.SpanContextFromContext(RequestContext(context.Background(), conn))
+		handlerSpans = append(handlerSpans, sc)
+		mtx.Unlock()
+		conn.WriteString("PONG")
+	})
+
+	<-s.StartedCtx.Done()
+
+	c := config.NewClient()
+	c.TracerProvider = tp
+	rc := NewClient(c).Get(net.JoinHostPort(s.config.BindAddr, strconv.Itoa(s.config.BindPort)))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	require.NoError(t, rc.Ping(ctx).Err())
+	parent.End()
+
+	require.Eventually(t, func() bool {
+		return len(sr.Ended()) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	spans := make(map[trace.SpanKind]sdktrace.ReadOnlySpan)
+	for _, span := range sr.Ended() {
+		require.Equal(t, parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+		spans[span.SpanKind()] = span
+	}
+
+	client := spans[trace.SpanKindClient]
+	require.Equal(t, protocol.Generic.Ping, client.Name())
+	require.Equal(t, parent.SpanContext().SpanID(), client.Parent().SpanID())
+
+	srv := spans[trace.SpanKindServer]
+	require.Equal(t, protocol.Generic.Ping, srv.Name())
+	require.Equal(t, client.SpanContext().SpanID(), srv.Parent().SpanID())
+	require.True(t, srv.Parent().IsRemote())
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.Len(t, handlerSpans, 1)
+	require.Equal(t, srv.SpanContext().SpanID(), handlerSpans[0].SpanID())
+}
+
+func TestMux_Tracing_Pipeline(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	s := newTracedServer(t, tp)
+	s.ServeMux().HandleFunc(protocol.Generic.Ping, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteString("PONG")
+	})
+
+	<-s.StartedCtx.Done()
+
+	c := config.NewClient()
+	c.TracerProvider = tp
+	rc := NewClient(c).Get(net.JoinHostPort(s.config.BindAddr, strconv.Itoa(s.config.BindPort)))
+
+	ctx := context.Background()
+	cmds, err := rc.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Ping(ctx)
+		pipe.Ping(ctx)
+		return nil
+	})
+	require.NoError(t, err)
+	for _, cmd := range cmds {
+		require.Equal(t, "PONG", cmd.(*redis.StatusCmd).Val())
+	}
+
+	require.Eventually(t, func() bool {
+		return len(sr.Ended()) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	var pipeline sdktrace.ReadOnlySpan
+	var servers []sdktrace.ReadOnlySpan
+	for _, span := range sr.Ended() {
+		if span.SpanKind() == trace.SpanKindClient {
+			pipeline = span
+			continue
+		}
+		servers = append(servers, span)
+	}
+	require.NotNil(t, pipeline)
+	require.Equal(t, "pipeline", pipeline.Name())
+	require.Len(t, servers, 2)
+	for _, span := range servers {
+		require.Equal(t, protocol.Generic.Ping, span.Name())
+		require.Equal(t, pipeline.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+}
+
+func TestMux_Trace_Envelope_Without_Tracer(t *testing.T) {
+	s := newServer(t)
+	s.ServeMux().HandleFunc(protocol.Generic.Ping, func(conn redcon.Conn, cmd redcon.Command) {
+		require.False(t, trace.SpanContextFromContext(RequestContext(context.Background(), conn)).IsValid())
+		conn.WriteString("PONG")
+	})
+
+	<-s.StartedCtx.Done()
+
+	rdb := redis.NewClient(defaultRedisOptions(s.config))
+
+	ctx := context.Background()
+	args := protocol.WrapTrace("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", []interface{}{protocol.Generic.Ping})
+	res, err := rdb.Do(ctx, args...).Result()
+	require.NoError(t, err)
+	require.Equal(t, "PONG", res)
+
+	err = rdb.Do(ctx, protocol.Generic.Trace, "traceparent").Err()
+	require.Error(t, err)
+}
+
+func TestMux_ACL(t *testing.T) {
+	a, err := acl.New(&config.Authentication{
+		Password: "secret",
+		Users: []config.User{{
+			Name:      "alice",
+			Passwords: []string{config.HashPassword("alice-password")},
+			Rules:     []string{"+dm.get", "~mydmap"},
+		}},
+	}, (&config.RedisCompat{DefaultDMap: config.DefaultRedisCompatDMap}).DMapName)
+	require.NoError(t, err)
+
+	s := newServer(t)
+	s.config.RequireAuth = true
+	s.config.ACL = a
+	s.config.Authenticate = func(username, password string) error {
+		if !a.Authenticate(username, password) {
+			return errors.New("WRONGPASS invalid username-password pair or user is disabled")
+		}
+		return nil
+	}
+	s.ServeMux().HandleFunc(protocol.DMap.Get, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteString(protocol.StatusOK)
+	})
+	s.ServeMux().HandleFunc(protocol.DMap.Put, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteString(protocol.StatusOK)
+	})
+
+	<-s.StartedCtx.Done()
+
+	ctx := context.Background()
+	opt := defaultRedisOptions(s.config)
+	opt.Username = "alice"
+	opt.Password = "alice-password"
+	rdb := redis.NewClient(opt)
+
+	require.NoError(t, rdb.Do(ctx, protocol.DMap.Get, "mydmap", "mykey").Err())
+
+	err = rdb.Do(ctx, protocol.DMap.Get, "other", "mykey").Err()
+	require.ErrorIs(t, protocol.ConvertError(err), protocol.ErrNoPerm)
+
+	err = rdb.Do(ctx, protocol.DMap.Put, "mydmap", "mykey", "myvalue").Err()
+	require.ErrorIs(t, protocol.ConvertError(err), protocol.ErrNoPerm)
+
+	// Unknown commands are not hidden by the ACL.
+	err = rdb.Do(ctx, "unknown-command").Err()
+	require.EqualError(t, err, "ERR unknown command 'unknown-command'")
+}
@@ -0,0 +1,162 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/tidwall/redcon"
+)
+
+// ProtocolVersion returns the RESP version of the connection. It's RESP2 unless
+// the client negotiated RESP3 with HELLO.
+func ProtocolVersion(conn redcon.Conn) int {
+	cc, ok := conn.Context().(*ConnContext)
+	if !ok {
+		return protocol.RESP2
+	}
+	return cc.ProtocolVersion()
+}
+
+func isRESP3(conn redcon.Conn) bool {
+	return ProtocolVersion(conn) == protocol.RESP3
+}
+
+func writeHeader(conn redcon.Conn, prefix byte, count int) {
+	buf := make([]byte, 0, 16)
+	buf = append(buf, prefix)
+	buf = strconv.AppendInt(buf, int64(count), 10)
+	buf = append(buf, '\r', '\n')
+	conn.WriteRaw(buf)
+}
+
+// WriteMap writes the header of a map with count key-value pairs. The map is
+// written as a flat array of keys and values on RESP2 connections.
+func WriteMap(conn redcon.Conn, count int) {
+	if isRESP3(conn) {
+		writeHeader(conn, '%', count)
+		return
+	}
+	conn.WriteArray(count * 2)
+}
+
+// WritePush writes the header of a push message, e.g. a Pub/Sub message. It's an
+// array on RESP2 connections.
+func WritePush(conn redcon.Conn, count int) {
+	if isRESP3(conn) {
+		writeHeader(conn, '>', count)
+		return
+	}
+	conn.WriteArray(count)
+}
+
+// WriteNull writes a null. It's the typed null on RESP3 connections and a null
+// bulk string on RESP2 connections.
+func WriteNull(conn redcon.Conn) {
+	if isRESP3(conn) {
+		conn.WriteRaw([]byte("_\r\n"))
+		return
+	}
+	conn.WriteNull()
+}
+
+// WriteDouble writes a floating point number. It's a bulk string on RESP2 connections.
+func WriteDouble(conn redcon.Conn, f float64) {
+	if isRESP3(conn) {
+		buf := append([]byte{','}, strconv.FormatFloat(f, 'f', -1, 64)...)
+		conn.WriteRaw(append(buf, '\r', '\n'))
+		return
+	}
+	conn.WriteBulkString(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// WriteBool writes a boolean. It's an integer on RESP2 connections.
+func WriteBool(conn redcon.Conn, b bool) {
+	if isRESP3(conn) {
+		if b {
+			conn.WriteRaw([]byte("#t\r\n"))
+		} else {
+			conn.WriteRaw([]byte("#f\r\n"))
+		}
+		return
+	}
+	if b {
+		conn.WriteInt(1)
+	} else {
+		conn.WriteInt(0)
+	}
+}
+
+// WriteJSON writes a JSON document with the RESP3 types: objects are written as
+// maps, and the numbers are written as integers, big numbers or doubles. The
+// document is written as a bulk string on RESP2 connections.
+func WriteJSON(conn redcon.Conn, data []byte) error {
+	if !isRESP3(conn) {
+		conn.WriteBulk(data)
+		return nil
+	}
+
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return err
+	}
+	writeJSONValue(conn, value)
+	return nil
+}
+
+func writeJSONValue(conn redcon.Conn, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		WriteMap(conn, len(keys))
+		for _, key := range keys {
+			conn.WriteBulkString(key)
+			writeJSONValue(conn, v[key])
+		}
+	case []interface{}:
+		conn.WriteArray(len(v))
+		for _, item := range v {
+			writeJSONValue(conn, item)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			conn.WriteInt64(i)
+			return
+		}
+		if b, ok := new(big.Int).SetString(v.String(), 10); ok {
+			conn.WriteRaw([]byte("(" + b.String() + "\r\n"))
+			return
+		}
+		f, _ := v.Float64()
+		WriteDouble(conn, f)
+	case string:
+		conn.WriteBulkString(v)
+	case bool:
+		WriteBool(conn, v)
+	default:
+		WriteNull(conn)
+	}
+}
@@ -16,15 +16,19 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/olric-data/olric/internal/acl"
 	"github.com/olric-data/olric/internal/checkpoint"
+	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/stats"
 	"github.com/olric-data/olric/pkg/flog"
 	"github.com/tidwall/redcon"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -51,6 +55,53 @@ type Config struct {
 	KeepAlivePeriod time.Duration
 	IdleClose       time.Duration
 	RequireAuth     bool
+
+	// Authenticate checks the credentials given with HELLO <protover> AUTH.
+	// HELLO with credentials fails if it's nil.
+	Authenticate func(username, password string) error
+
+	// ACL checks the permissions of the authenticated users before running
+	// the commands. All the commands are allowed if it's nil.
+	ACL *acl.ACL
+
+	// Version is the server version in the reply of HELLO.
+	Version string
+
+	// CertificateReloader provides the certificates to serve the RESP protocol
+	// over TLS. The listener doesn't use TLS if it's nil.
+	CertificateReloader *CertificateReloader
+
+	// ClusterRole is the organizational unit in the certificates of the cluster
+	// members. If it's set, the internal commands are rejected unless the
+	// connection has a verified client certificate with this unit.
+	ClusterRole string
+
+	// CommandLatencyBuckets are the upper bounds of the command latency
+	// histograms. config.DefaultCommandLatencyBuckets is used if it's empty.
+	CommandLatencyBuckets []time.Duration
+
+	// SlowLogThreshold is the execution time that a command must exceed to be
+	// recorded by the slow log. A negative value disables the slow log.
+	// config.DefaultSlowLogThreshold is used if it's zero.
+	SlowLogThreshold time.Duration
+
+	// SlowLogMaxLen is the maximum number of records kept by the slow log.
+	// config.DefaultSlowLogMaxLen is used if it's zero.
+	SlowLogMaxLen int
+
+	// AuditLog records the audited commands and their outcomes. The audit
+	// log is disabled if it's nil.
+	AuditLog *AuditLog
+
+	// DMapName returns the DMap name of a key of the Redis compatibility
+	// layer. It's used to record the DMaps of the Redis commands in the audit
+	// log.
+	DMapName func(key string) string
+
+	// TracerProvider is used to create a server span for every command. The
+	// trace context sent by the clients is used as the parent. Tracing is
+	// disabled if it's nil.
+	TracerProvider trace.TracerProvider
 }
 
 // ConnContext represents the context for a connection with authentication state management.
@@ -59,6 +110,19 @@ type ConnContext struct {
 
 	// authenticated indicates whether the connection is successfully authenticated.
 	authenticated bool
+
+	// user is the name of the authenticated user.
+	user string
+
+	// sequences holds the last seen sequence IDs of the Pub/Sub channels. It's
+	// set by PUBSUB RESUME. A non-nil map enables sequenced delivery.
+	sequences map[string]uint64
+
+	// spanContext is the span context of the command being served.
+	spanContext trace.SpanContext
+
+	// protocolVersion is the RESP version negotiated with HELLO. Zero means RESP2.
+	protocolVersion int
 }
 
 // NewConnContext initializes and returns a new instance of ConnContext for managing connection states like authentication.
@@ -82,6 +146,89 @@ func (c *ConnContext) IsAuthenticated() bool {
 	return c.authenticated
 }
 
+// SetUser sets the name of the authenticated user. It is thread-safe.
+func (c *ConnContext) SetUser(user string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.user = user
+}
+
+// User returns the name of the authenticated user. It is thread-safe.
+func (c *ConnContext) User() string {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.user
+}
+
+// SetPubSubSequences sets the last seen sequence IDs of the Pub/Sub channels. It is thread-safe.
+func (c *ConnContext) SetPubSubSequences(sequences map[string]uint64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.sequences = sequences
+}
+
+// PubSubSequences returns the last seen sequence IDs of the Pub/Sub channels. It returns false if the
+// connection didn't ask for sequenced delivery. It is thread-safe.
+func (c *ConnContext) PubSubSequences() (map[string]uint64, bool) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.sequences, c.sequences != nil
+}
+
+// SetSpanContext sets the span context of the command being served. It is thread-safe.
+func (c *ConnContext) SetSpanContext(sc trace.SpanContext) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.spanContext = sc
+}
+
+// SpanContext returns the span context of the command being served. It is thread-safe.
+func (c *ConnContext) SpanContext() trace.SpanContext {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.spanContext
+}
+
+// SetProtocolVersion sets the RESP version of the connection. It is thread-safe.
+func (c *ConnContext) SetProtocolVersion(version int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.protocolVersion = version
+}
+
+// ProtocolVersion returns the RESP version of the connection. It is thread-safe.
+func (c *ConnContext) ProtocolVersion() int {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if c.protocolVersion == 0 {
+		return protocol.RESP2
+	}
+	return c.protocolVersion
+}
+
+// RequestContext returns a copy of ctx that carries the span context of the
+// command being served on conn. Handlers should use it for the requests sent
+// to the other members, so they become a part of the caller's trace.
+func RequestContext(ctx context.Context, conn redcon.Conn) context.Context {
+	cc, ok := conn.Context().(*ConnContext)
+	if !ok {
+		return ctx
+	}
+	sc := cc.SpanContext()
+	if !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithSpanContext(ctx, sc)
+}
+
 // ConnWrapper is a wrapper around net.Conn that enables tracking of read and written bytes.
 type ConnWrapper struct {
 	net.Conn
@@ -188,6 +335,16 @@ func (s *Server) ServeMux() *ServeMuxWrapper {
 	return s.wmux
 }
 
+// CommandLatencies returns the latency histograms of the commands served by this server.
+func (s *Server) CommandLatencies() *stats.HistogramVec {
+	return s.mux.CommandLatencies()
+}
+
+// SlowLog returns the slow log of the commands.
+func (s *Server) SlowLog() *SlowLog {
+	return s.mux.SlowLog()
+}
+
 // ListenAndServe starts the TCP server, initializes internal components, and begins accepting connections.
 func (s *Server) ListenAndServe() error {
 	addr := net.JoinHostPort(s.config.BindAddr, strconv.Itoa(s.config.BindPort))
@@ -204,6 +361,17 @@ func (s *Server) ListenAndServe() error {
 	defer close(s.stopped)
 	s.listener = lw
 
+	var ln net.Listener = lw
+	if s.config.CertificateReloader != nil {
+		ln = tls.NewListener(lw, s.config.CertificateReloader.ServerConfig())
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.config.CertificateReloader.Run(s.ctx)
+		}()
+	}
+
 	srv := redcon.NewServer(addr,
 		s.mux.ServeRESP,
 		func(conn redcon.Conn) bool {
@@ -225,7 +393,7 @@ func (s *Server) ListenAndServe() error {
 	// The TCP server has been started
 	s.started()
 	checkpoint.Pass()
-	return s.server.Serve(lw)
+	return s.server.Serve(ln)
 }
 
 // Shutdown gracefully shuts down the server without interrupting any active connections.
@@ -246,7 +414,7 @@ func (s *Server) Shutdown(ctx context.Context) error {
 
 	if s.server == nil {
 		// There is nothing to close.
-		return nil
+		return s.closeAuditLog()
 	}
 
 	var latestError error
@@ -275,5 +443,20 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	case <-done:
 	}
 
+	if err := s.closeAuditLog(); err != nil {
+		latestError = err
+	}
+
 	return latestError
 }
+
+func (s *Server) closeAuditLog() error {
+	if s.config.AuditLog == nil {
+		return nil
+	}
+	err := s.config.AuditLog.Close()
+	if err != nil {
+		s.log.V(2).Printf("[ERROR] Failed to close the audit log: %v", err)
+	}
+	return err
+}
@@ -47,22 +47,26 @@ func getFreePort() (int, error) {
 	return port, nil
 }
 
+func newTestLogger() *flog.Logger {
+	l := log.New(os.Stdout, "server-test: ", log.LstdFlags)
+	fl := flog.New(l)
+	fl.SetLevel(6)
+	fl.ShowLineNumber(1)
+	return fl
+}
+
 func newServerWithPreConditionFunc(t *testing.T, precond func(conn redcon.Conn, cmd redcon.Command) bool) *Server {
 	bindPort, err := getFreePort()
 	if err != nil {
 		t.Fatalf("Expected nil. Got: %v", err)
 	}
 
-	l := log.New(os.Stdout, "server-test: ", log.LstdFlags)
-	fl := flog.New(l)
-	fl.SetLevel(6)
-	fl.ShowLineNumber(1)
 	c := &Config{
 		BindAddr:        "127.0.0.1",
 		BindPort:        bindPort,
 		KeepAlivePeriod: time.Second,
 	}
-	s := New(c, fl)
+	s := New(c, newTestLogger())
 	s.SetPreConditionFunc(precond)
 
 	go func() {
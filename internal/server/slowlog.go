@@ -0,0 +1,154 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tidwall/redcon"
+)
+
+const (
+	// slowLogMaxArgs is the maximum number of arguments of a record.
+	slowLogMaxArgs = 32
+
+	// slowLogMaxArgLen is the maximum length of an argument of a record.
+	slowLogMaxArgLen = 128
+)
+
+// SlowLogEntry is a record of the slow log.
+type SlowLogEntry struct {
+	// ID is the unique, progressive identifier of the record.
+	ID uint64
+
+	// Timestamp is the time when the command was received.
+	Timestamp time.Time
+
+	// Duration is the execution time of the command.
+	Duration time.Duration
+
+	// Args are the command and its arguments. Long arguments and argument
+	// lists are truncated.
+	Args []string
+
+	// ClientAddr is the address of the client.
+	ClientAddr string
+}
+
+// SlowLog keeps the latest commands that take longer than a threshold to
+// execute. It's thread-safe.
+type SlowLog struct {
+	mtx sync.RWMutex
+
+	threshold time.Duration
+	maxLen    int
+	nextID    uint64
+	// entries is a ring buffer. head is the index of the oldest record.
+	entries []SlowLogEntry
+	head    int
+}
+
+// NewSlowLog returns a new SlowLog. A negative threshold disables the slow log.
+func NewSlowLog(threshold time.Duration, maxLen int) *SlowLog {
+	return &SlowLog{
+		threshold: threshold,
+		maxLen:    maxLen,
+	}
+}
+
+// truncateArgs copies the arguments of a command. It truncates long arguments and
+// argument lists like Redis does.
+func truncateArgs(args [][]byte) []string {
+	n := len(args)
+	if n > slowLogMaxArgs {
+		n = slowLogMaxArgs - 1
+	}
+
+	result := make([]string, 0, n+1)
+	for _, arg := range args[:n] {
+		if len(arg) > slowLogMaxArgLen {
+			result = append(result, fmt.Sprintf("%s... (%d more bytes)", arg[:slowLogMaxArgLen], len(arg)-slowLogMaxArgLen))
+			continue
+		}
+		result = append(result, string(arg))
+	}
+	if n < len(args) {
+		result = append(result, fmt.Sprintf("... (%d more arguments)", len(args)-n))
+	}
+	return result
+}
+
+// Record adds the command to the slow log if its execution time exceeds the threshold.
+func (s *SlowLog) Record(cmd redcon.Command, clientAddr string, start time.Time, duration time.Duration) {
+	if s.threshold < 0 || duration < s.threshold || s.maxLen == 0 {
+		return
+	}
+
+	entry := SlowLogEntry{
+		Timestamp:  start,
+		Duration:   duration,
+		Args:       truncateArgs(cmd.Args),
+		ClientAddr: clientAddr,
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	entry.ID = s.nextID
+	s.nextID++
+	if len(s.entries) < s.maxLen {
+		s.entries = append(s.entries, entry)
+		return
+	}
+	s.entries[s.head] = entry
+	s.head = (s.head + 1) % s.maxLen
+}
+
+// Get returns the latest count records, the newest first. It returns all the
+// records if count is negative.
+func (s *SlowLog) Get(count int) []SlowLogEntry {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	if count < 0 || count > len(s.entries) {
+		count = len(s.entries)
+	}
+
+	result := make([]SlowLogEntry, 0, count)
+	for i := 0; i < count; i++ {
+		idx := (s.head + len(s.entries) - 1 - i) % len(s.entries)
+		result = append(result, s.entries[idx])
+	}
+	return result
+}
+
+// Len returns the number of records.
+func (s *SlowLog) Len() int {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return len(s.entries)
+}
+
+// Reset removes all the records.
+func (s *SlowLog) Reset() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.entries = nil
+	s.head = 0
+}
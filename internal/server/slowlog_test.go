@@ -0,0 +1,125 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/redcon"
+)
+
+func slowLogCommand(args ...string) redcon.Command {
+	var cmd redcon.Command
+	for _, arg := range args {
+		cmd.Args = append(cmd.Args, []byte(arg))
+	}
+	return cmd
+}
+
+func TestSlowLog_Record(t *testing.T) {
+	s := NewSlowLog(10*time.Millisecond, 3)
+
+	start := time.Now()
+	s.Record(slowLogCommand("dm.get", "mydmap", "fast"), "127.0.0.1:1234", start, time.Millisecond)
+	require.Equal(t, 0, s.Len())
+
+	for i := 0; i < 5; i++ {
+		s.Record(slowLogCommand("dm.get", "mydmap", strconv.Itoa(i)), "127.0.0.1:1234", start, 10*time.Millisecond)
+	}
+	require.Equal(t, 3, s.Len())
+
+	entries := s.Get(-1)
+	require.Len(t, entries, 3)
+	for i, entry := range entries {
+		// The newest first
+		require.Equal(t, uint64(4-i), entry.ID)
+		require.Equal(t, []string{"dm.get", "mydmap", strconv.Itoa(4 - i)}, entry.Args)
+		require.Equal(t, "127.0.0.1:1234", entry.ClientAddr)
+		require.Equal(t, start, entry.Timestamp)
+		require.Equal(t, 10*time.Millisecond, entry.Duration)
+	}
+
+	entries = s.Get(1)
+	require.Len(t, entries, 1)
+	require.Equal(t, uint64(4), entries[0].ID)
+
+	s.Reset()
+	require.Equal(t, 0, s.Len())
+	require.Empty(t, s.Get(-1))
+
+	// IDs are not reset.
+	s.Record(slowLogCommand("dm.get", "mydmap", "mykey"), "127.0.0.1:1234", start, time.Second)
+	require.Equal(t, uint64(5), s.Get(1)[0].ID)
+}
+
+func TestSlowLog_Disabled(t *testing.T) {
+	s := NewSlowLog(-1, 3)
+	s.Record(slowLogCommand("dm.get", "mydmap", "mykey"), "127.0.0.1:1234", time.Now(), time.Hour)
+	require.Equal(t, 0, s.Len())
+}
+
+func TestSlowLog_Truncate(t *testing.T) {
+	s := NewSlowLog(0, 3)
+
+	args := []string{"dm.put", "mydmap", "mykey", strings.Repeat("a", slowLogMaxArgLen+10)}
+	for i := 0; i < slowLogMaxArgs; i++ {
+		args = append(args, "arg")
+	}
+	s.Record(slowLogCommand(args...), "127.0.0.1:1234", time.Now(), time.Millisecond)
+
+	entry := s.Get(1)[0]
+	require.Len(t, entry.Args, slowLogMaxArgs)
+	require.Equal(t, strings.Repeat("a", slowLogMaxArgLen)+"... (10 more bytes)", entry.Args[3])
+	require.Equal(t, "... (5 more arguments)", entry.Args[slowLogMaxArgs-1])
+}
+
+func TestMux_SlowLog(t *testing.T) {
+	s := newServer(t)
+
+	s.ServeMux().HandleFunc(protocol.Generic.Ping, func(conn redcon.Conn, cmd redcon.Command) {
+		time.Sleep(20 * time.Millisecond)
+		conn.WriteString("PONG")
+	})
+	s.ServeMux().HandleFunc(protocol.Generic.Auth, func(conn redcon.Conn, cmd redcon.Command) {
+		time.Sleep(20 * time.Millisecond)
+		conn.WriteString("OK")
+	})
+	s.ServeMux().HandleFunc(protocol.PubSub.PubSubNumpat, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteInt(10)
+	})
+
+	<-s.StartedCtx.Done()
+
+	rdb := redis.NewClient(defaultRedisOptions(s.config))
+
+	ctx := context.Background()
+	require.NoError(t, rdb.Ping(ctx).Err())
+	require.NoError(t, rdb.Do(ctx, "pubsub", "numpat").Err())
+	// AUTH carries a password.
+	require.NoError(t, rdb.Do(ctx, protocol.Generic.Auth, "secret").Err())
+
+	entries := s.SlowLog().Get(-1)
+	require.Len(t, entries, 1)
+	require.Equal(t, []string{"ping"}, entries[0].Args)
+	require.GreaterOrEqual(t, entries[0].Duration, 20*time.Millisecond)
+	require.NotEmpty(t, entries[0].ClientAddr)
+}
@@ -0,0 +1,233 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/pkg/flog"
+	"github.com/tidwall/redcon"
+)
+
+// hasClusterRole returns true if the client presented a verified certificate
+// with the given organizational unit.
+func hasClusterRole(conn redcon.Conn, role string) bool {
+	tlsConn, ok := conn.NetConn().(*tls.Conn)
+	if !ok {
+		return false
+	}
+	for _, chain := range tlsConn.ConnectionState().VerifiedChains {
+		if len(chain) == 0 {
+			continue
+		}
+		// The first certificate of a chain is the client certificate.
+		for _, unit := range chain[0].Subject.OrganizationalUnit {
+			if unit == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CertificateReloader keeps the certificate and the client CAs of the TLS
+// configuration in memory, and reloads them when the files change.
+type CertificateReloader struct {
+	mtx sync.RWMutex
+
+	config      *config.TLS
+	log         *flog.Logger
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	modTimes    map[string]time.Time
+}
+
+// NewCertificateReloader loads the files and returns a new CertificateReloader.
+func NewCertificateReloader(c *config.TLS, l *flog.Logger) (*CertificateReloader, error) {
+	r := &CertificateReloader{
+		config: c,
+		log:    l,
+	}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *CertificateReloader) files() []string {
+	files := []string{r.config.CertFile, r.config.KeyFile}
+	if r.config.ClientCAFile != "" {
+		files = append(files, r.config.ClientCAFile)
+	}
+	return files
+}
+
+// Reload loads the files again if any of them has changed since the last load.
+// It returns true if the certificates are reloaded. The previous certificates
+// are kept if the new ones are invalid.
+func (r *CertificateReloader) Reload() (bool, error) {
+	modTimes := make(map[string]time.Time)
+	changed := false
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, err
+		}
+		modTimes[file] = info.ModTime()
+
+		r.mtx.RLock()
+		modTime, ok := r.modTimes[file]
+		r.mtx.RUnlock()
+		if !ok || !modTime.Equal(info.ModTime()) {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load the certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.config.ClientCAFile != "" {
+		data, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return false, err
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(data) {
+			return false, fmt.Errorf("failed to parse the client CA certificates: %s", r.config.ClientCAFile)
+		}
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.certificate = &certificate
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	return true, nil
+}
+
+func (r *CertificateReloader) getCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	return r.certificate, nil
+}
+
+func (r *CertificateReloader) getClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	return r.certificate, nil
+}
+
+func (r *CertificateReloader) getConfigForClient(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+	return r.ServerConfig(), nil
+}
+
+// ServerConfig returns a TLS configuration for the listener. The clients have
+// to present a certificate signed by one of the client CAs, if it's set.
+func (r *CertificateReloader) ServerConfig() *tls.Config {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	tc := &tls.Config{
+		MinVersion:         r.config.TLSMinVersion(),
+		GetCertificate:     r.getCertificate,
+		GetConfigForClient: r.getConfigForClient,
+	}
+	if r.clientCAs != nil {
+		tc.ClientCAs = r.clientCAs
+		tc.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tc
+}
+
+// verifyConnection verifies the certificate of a member with the latest client
+// CAs. The system roots are used if the client CAs are not set.
+func (r *CertificateReloader) verifyConnection(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("no certificate presented by the server")
+	}
+
+	r.mtx.RLock()
+	roots := r.clientCAs
+	r.mtx.RUnlock()
+
+	opts := x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// ClientConfig returns a TLS configuration for the connections between the
+// members. The certificate of the member is presented as the client
+// certificate, and the client CAs are used to verify the other members.
+func (r *CertificateReloader) ClientConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:           r.config.TLSMinVersion(),
+		GetClientCertificate: r.getClientCertificate,
+		// The default verification is skipped because it cannot use the
+		// reloaded CAs. verifyConnection does the same with the latest ones.
+		InsecureSkipVerify: true,
+		VerifyConnection:   r.verifyConnection,
+	}
+}
+
+// Run checks the files periodically and reloads the certificates if they
+// change. It returns when ctx is canceled.
+func (r *CertificateReloader) Run(ctx context.Context) {
+	if r.config.ReloadInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.config.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.Reload()
+			if err != nil {
+				r.log.V(2).Printf("[ERROR] Failed to reload TLS certificates: %v", err)
+				continue
+			}
+			if reloaded {
+				r.log.V(2).Printf("[INFO] TLS certificates have been reloaded")
+			}
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/testutil/tlsfiles"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/redcon"
+)
+
+func newCertificateReloader(t *testing.T, c *config.TLS) *CertificateReloader {
+	require.NoError(t, c.Sanitize())
+	require.NoError(t, c.Validate())
+
+	r, err := NewCertificateReloader(c, newTestLogger())
+	require.NoError(t, err)
+	return r
+}
+
+func newTLSServer(t *testing.T, r *CertificateReloader) *Server {
+	bindPort, err := getFreePort()
+	require.NoError(t, err)
+
+	c := &Config{
+		BindAddr:            "127.0.0.1",
+		BindPort:            bindPort,
+		KeepAlivePeriod:     time.Second,
+		CertificateReloader: r,
+	}
+	s := New(c, newTestLogger())
+	s.ServeMux().HandleFunc(protocol.Generic.Ping, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteString("PONG")
+	})
+
+	go func() {
+		require.NoError(t, s.ListenAndServe())
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, s.Shutdown(context.Background()))
+	})
+
+	<-s.StartedCtx.Done()
+	return s
+}
+
+func newRootCAs(t *testing.T, files *tlsfiles.Files) *x509.CertPool {
+	data, err := os.ReadFile(files.CAFile)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(data))
+	return pool
+}
+
+func TestServer_TLS(t *testing.T) {
+	files := tlsfiles.New(t)
+	r := newCertificateReloader(t, &config.TLS{
+		CertFile: files.CertFile,
+		KeyFile:  files.KeyFile,
+	})
+	s := newTLSServer(t, r)
+	ctx := context.Background()
+
+	t.Run("TLS client", func(t *testing.T) {
+		opt := defaultRedisOptions(s.config)
+		opt.TLSConfig = &tls.Config{RootCAs: newRootCAs(t, files)}
+		rdb := redis.NewClient(opt)
+		defer rdb.Close()
+		require.NoError(t, rdb.Ping(ctx).Err())
+	})
+
+	t.Run("Plain client", func(t *testing.T) {
+		opt := defaultRedisOptions(s.config)
+		opt.MaxRetries = -1
+		opt.ReadTimeout = 250 * time.Millisecond
+		rdb := redis.NewClient(opt)
+		defer rdb.Close()
+		require.Error(t, rdb.Ping(ctx).Err())
+	})
+
+	t.Run("TLS 1.1 client", func(t *testing.T) {
+		addr := net.JoinHostPort(s.config.BindAddr, strconv.Itoa(s.config.BindPort))
+		_, err := tls.Dial("tcp", addr, &tls.Config{
+			RootCAs:    newRootCAs(t, files),
+			MaxVersion: tls.VersionTLS11,
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestServer_TLS_ClientCA(t *testing.T) {
+	files := tlsfiles.New(t)
+	r := newCertificateReloader(t, &config.TLS{
+		CertFile:     files.CertFile,
+		KeyFile:      files.KeyFile,
+		ClientCAFile: files.CAFile,
+	})
+	s := newTLSServer(t, r)
+	ctx := context.Background()
+
+	t.Run("Without client certificate", func(t *testing.T) {
+		opt := defaultRedisOptions(s.config)
+		opt.MaxRetries = -1
+		opt.TLSConfig = &tls.Config{RootCAs: newRootCAs(t, files)}
+		rdb := redis.NewClient(opt)
+		defer rdb.Close()
+		require.Error(t, rdb.Ping(ctx).Err())
+	})
+
+	t.Run("With client certificate", func(t *testing.T) {
+		opt := defaultRedisOptions(s.config)
+		opt.TLSConfig = r.ClientConfig()
+		rdb := redis.NewClient(opt)
+		defer rdb.Close()
+		require.NoError(t, rdb.Ping(ctx).Err())
+	})
+
+	t.Run("Untrusted server", func(t *testing.T) {
+		other := newCertificateReloader(t, &config.TLS{
+			CertFile:     files.CertFile,
+			KeyFile:      files.KeyFile,
+			ClientCAFile: tlsfiles.New(t).CAFile,
+		})
+		opt := defaultRedisOptions(s.config)
+		opt.MaxRetries = -1
+		opt.TLSConfig = other.ClientConfig()
+		rdb := redis.NewClient(opt)
+		defer rdb.Close()
+		require.Error(t, rdb.Ping(ctx).Err())
+	})
+}
+
+func TestCertificateReloader_Reload(t *testing.T) {
+	files := tlsfiles.New(t)
+	r := newCertificateReloader(t, &config.TLS{
+		CertFile:       files.CertFile,
+		KeyFile:        files.KeyFile,
+		ReloadInterval: 10 * time.Millisecond,
+	})
+	s := newTLSServer(t, r)
+
+	addr := net.JoinHostPort(s.config.BindAddr, strconv.Itoa(s.config.BindPort))
+	commonName := func() string {
+		conn, err := tls.Dial("tcp", addr, &tls.Config{RootCAs: newRootCAs(t, files)})
+		require.NoError(t, err)
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+	require.Equal(t, "olric-test", commonName())
+
+	reloaded, err := r.Reload()
+	require.NoError(t, err)
+	require.False(t, reloaded)
+
+	files.Renew(t, "olric-test-renewed")
+	// Make sure that the modification time is changed.
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(files.CertFile, future, future))
+
+	require.Eventually(t, func() bool {
+		return commonName() == "olric-test-renewed"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCertificateReloader_Invalid_Certificate(t *testing.T) {
+	files := tlsfiles.New(t)
+	r := newCertificateReloader(t, &config.TLS{
+		CertFile: files.CertFile,
+		KeyFile:  files.KeyFile,
+	})
+	before := r.ServerConfig()
+	cert, err := before.GetCertificate(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(files.CertFile, []byte("invalid"), 0600))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(files.CertFile, future, future))
+
+	_, err = r.Reload()
+	require.Error(t, err)
+
+	// The previous certificate is still in use.
+	current, err := r.ServerConfig().GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, cert, current)
+}
+
+func TestMux_ClusterRole(t *testing.T) {
+	files := tlsfiles.New(t, "olric-cluster")
+	r := newCertificateReloader(t, &config.TLS{
+		CertFile:     files.CertFile,
+		KeyFile:      files.KeyFile,
+		ClientCAFile: files.CAFile,
+		ClusterRole:  "olric-cluster",
+	})
+	s := newTLSServer(t, r)
+	s.config.ClusterRole = "olric-cluster"
+	s.ServeMux().HandleFunc(protocol.Internal.UpdateRouting, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteString(protocol.StatusOK)
+	})
+	s.ServeMux().HandleFunc(protocol.Cluster.KeyringUse, func(conn redcon.Conn, cmd redcon.Command) {
+		conn.WriteString(protocol.StatusOK)
+	})
+	key := "cg8StVXbQJ0gPvMd9o7yrg=="
+
+	ctx := context.Background()
+	newClient := func(t *testing.T, certFile, keyFile string) *redis.Client {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		require.NoError(t, err)
+
+		opt := defaultRedisOptions(s.config)
+		opt.TLSConfig = &tls.Config{
+			RootCAs:      newRootCAs(t, files),
+			Certificates: []tls.Certificate{certificate},
+		}
+		rdb := redis.NewClient(opt)
+		t.Cleanup(func() {
+			require.NoError(t, rdb.Close())
+		})
+		return rdb
+	}
+
+	t.Run("Cluster certificate", func(t *testing.T) {
+		rdb := newClient(t, files.CertFile, files.KeyFile)
+		require.NoError(t, rdb.Ping(ctx).Err())
+		require.NoError(t, rdb.Do(ctx, protocol.Internal.UpdateRouting).Err())
+		require.NoError(t, rdb.Process(ctx, protocol.NewClusterKeyringUse(key).SetLocal().Command(ctx)))
+	})
+
+	t.Run("Client certificate", func(t *testing.T) {
+		certFile, keyFile := files.NewCertificate(t, "olric-client", "olric-clients")
+		rdb := newClient(t, certFile, keyFile)
+		require.NoError(t, rdb.Ping(ctx).Err())
+
+		err := rdb.Do(ctx, protocol.Internal.UpdateRouting).Err()
+		require.ErrorIs(t, protocol.ConvertError(err), protocol.ErrNoPerm)
+
+		// The internal commands in a trace envelope are rejected, too.
+		err = rdb.Do(ctx, protocol.Generic.Trace, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", protocol.Internal.UpdateRouting).Err()
+		require.ErrorIs(t, protocol.ConvertError(err), protocol.ErrNoPerm)
+
+		// The LC variant of a cluster-wide command only changes this member,
+		// the members send it to each other.
+		err = rdb.Process(ctx, protocol.NewClusterKeyringUse(key).SetLocal().Command(ctx))
+		require.ErrorIs(t, protocol.ConvertError(err), protocol.ErrNoPerm)
+		require.NoError(t, rdb.Process(ctx, protocol.NewClusterKeyringUse(key).Command(ctx)))
+	})
+}
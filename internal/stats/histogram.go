@@ -0,0 +1,116 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Histogram samples durations and counts them in buckets. The buckets are
+// upper bounds, and there is an implicit +Inf bucket at the end.
+type Histogram struct {
+	buckets []time.Duration
+	counts  []int64
+	count   int64
+	sum     int64
+}
+
+// HistogramSnapshot is a point-in-time copy of a Histogram.
+type HistogramSnapshot struct {
+	// Buckets are the upper bounds of the buckets, without the +Inf bucket.
+	Buckets []time.Duration
+
+	// Counts holds the number of observations in each bucket. It's not
+	// cumulative, and the last item is the +Inf bucket.
+	Counts []int64
+
+	// Count is the total number of observations.
+	Count int64
+
+	// Sum is the sum of all observed durations.
+	Sum time.Duration
+}
+
+// NewHistogram returns a new Histogram. buckets must be sorted in increasing order.
+func NewHistogram(buckets []time.Duration) *Histogram {
+	return &Histogram{
+		buckets: buckets,
+		counts:  make([]int64, len(buckets)+1),
+	}
+}
+
+// Observe adds a single observation to the histogram.
+func (h *Histogram) Observe(d time.Duration) {
+	i := sort.Search(len(h.buckets), func(i int) bool {
+		return d <= h.buckets[i]
+	})
+	atomic.AddInt64(&h.counts[i], 1)
+	atomic.AddInt64(&h.count, 1)
+	atomic.AddInt64(&h.sum, int64(d))
+}
+
+// Snapshot returns a copy of the current state of the histogram.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Buckets: h.buckets,
+		Counts:  make([]int64, len(h.counts)),
+		Count:   atomic.LoadInt64(&h.count),
+		Sum:     time.Duration(atomic.LoadInt64(&h.sum)),
+	}
+	for i := range h.counts {
+		s.Counts[i] = atomic.LoadInt64(&h.counts[i])
+	}
+	return s
+}
+
+// Reset sets zero to all buckets of the histogram.
+func (h *Histogram) Reset() {
+	for i := range h.counts {
+		atomic.StoreInt64(&h.counts[i], 0)
+	}
+	atomic.StoreInt64(&h.count, 0)
+	atomic.StoreInt64(&h.sum, 0)
+}
+
+// HistogramVec is a set of histograms with the same buckets, keyed by name.
+type HistogramVec struct {
+	buckets    []time.Duration
+	histograms sync.Map
+}
+
+// NewHistogramVec returns a new HistogramVec. buckets must be sorted in increasing order.
+func NewHistogramVec(buckets []time.Duration) *HistogramVec {
+	return &HistogramVec{buckets: buckets}
+}
+
+// With returns the histogram for the given name, creating it if it doesn't exist.
+func (v *HistogramVec) With(name string) *Histogram {
+	h, ok := v.histograms.Load(name)
+	if ok {
+		return h.(*Histogram)
+	}
+	h, _ = v.histograms.LoadOrStore(name, NewHistogram(v.buckets))
+	return h.(*Histogram)
+}
+
+// Range calls f sequentially for each histogram. If f returns false, Range stops the iteration.
+func (v *HistogramVec) Range(f func(name string, h *Histogram) bool) {
+	v.histograms.Range(func(key, value interface{}) bool {
+		return f(key.(string), value.(*Histogram))
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]time.Duration{time.Millisecond, 10 * time.Millisecond})
+
+	h.Observe(500 * time.Microsecond)
+	h.Observe(time.Millisecond)
+	h.Observe(5 * time.Millisecond)
+	h.Observe(time.Second)
+
+	s := h.Snapshot()
+	require.Equal(t, []int64{2, 1, 1}, s.Counts)
+	require.Equal(t, int64(4), s.Count)
+	require.Equal(t, 1006500*time.Microsecond, s.Sum)
+
+	h.Reset()
+	s = h.Snapshot()
+	require.Equal(t, []int64{0, 0, 0}, s.Counts)
+	require.Equal(t, int64(0), s.Count)
+	require.Equal(t, time.Duration(0), s.Sum)
+}
+
+func TestHistogramVec(t *testing.T) {
+	v := NewHistogramVec([]time.Duration{time.Millisecond})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v.With("dm.get").Observe(time.Microsecond)
+		}()
+	}
+	wg.Wait()
+	v.With("dm.put").Observe(time.Second)
+
+	result := make(map[string]HistogramSnapshot)
+	v.Range(func(name string, h *Histogram) bool {
+		result[name] = h.Snapshot()
+		return true
+	})
+	require.Len(t, result, 2)
+	require.Equal(t, []int64{100, 0}, result["dm.get"].Counts)
+	require.Equal(t, []int64{0, 1}, result["dm.put"].Counts)
+}
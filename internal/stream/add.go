@@ -0,0 +1,171 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olric-data/olric/internal/protocol"
+)
+
+// AddConfig defines the options of the XAdd command.
+type AddConfig struct {
+	HasMaxLen bool
+	MaxLen    int64
+}
+
+// Info describes the current state of a stream.
+type Info struct {
+	Length int64
+	LastID ID
+	Groups int64
+}
+
+func (st *Stream) addOnFragment(rawID string, fields map[string]string, cfg *AddConfig) (ID, error) {
+	f := st.s.loadOrCreateFragment(st.primaryPartition(), st.fragmentName)
+
+	// Replication runs under the fragment lock to keep the operations
+	// in the same order on the backup owners.
+	f.Lock()
+	defer f.Unlock()
+
+	now := time.Now()
+	var id ID
+	if rawID == AutoID {
+		ms := uint64(now.UnixMilli())
+		if ms > f.lastID.Ms {
+			id = ID{Ms: ms}
+		} else {
+			id = f.lastID.next()
+		}
+	} else {
+		var err error
+		id, err = ParseID(rawID, 0)
+		if err != nil {
+			return ID{}, err
+		}
+		if !f.lastID.Less(id) {
+			return ID{}, ErrIDTooSmall
+		}
+	}
+
+	op := &operation{
+		Kind:      opAdd,
+		Stream:    st.name,
+		Entry:     Entry{ID: id, Fields: fields},
+		Timestamp: now.UnixNano(),
+	}
+	if cfg.HasMaxLen {
+		op.MaxLen = cfg.MaxLen
+	}
+	f.apply(op)
+
+	// total number of entries appended during the life of this instance.
+	EntriesTotal.Increase(1)
+
+	return id, st.replicate(op)
+}
+
+// XAdd appends a new entry to the stream and returns its ID. rawID is either
+// AutoID or an explicit ID that is greater than the latest ID in the stream.
+func (st *Stream) XAdd(ctx context.Context, rawID string, fields map[string]string, cfg *AddConfig) (ID, error) {
+	if cfg == nil {
+		cfg = &AddConfig{}
+	}
+
+	rc, ok := st.redirect()
+	if !ok {
+		return st.addOnFragment(rawID, fields, cfg)
+	}
+
+	// Redirect to the partition owner
+	xaddCmd := protocol.NewXAdd(st.name, rawID, fields)
+	if cfg.HasMaxLen {
+		xaddCmd.SetMaxLen(cfg.MaxLen)
+	}
+	cmd := xaddCmd.Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return ID{}, protocol.ConvertError(err)
+	}
+	raw, err := cmd.Result()
+	if err != nil {
+		return ID{}, protocol.ConvertError(err)
+	}
+	return ParseID(raw, 0)
+}
+
+// XLen returns the number of entries in the stream.
+func (st *Stream) XLen(ctx context.Context) (int64, error) {
+	rc, ok := st.redirect()
+	if ok {
+		cmd := protocol.NewXLen(st.name).Command(ctx)
+		err := rc.Process(ctx, cmd)
+		if err != nil {
+			return 0, protocol.ConvertError(err)
+		}
+		length, err := cmd.Result()
+		return length, protocol.ConvertError(err)
+	}
+
+	f, err := st.s.loadFragment(st.primaryPartition(), st.fragmentName)
+	if errors.Is(err, errFragmentNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	f.RLock()
+	defer f.RUnlock()
+
+	return int64(len(f.entries)), nil
+}
+
+// XInfo returns the length, the latest ID and the number of consumer groups of the stream.
+func (st *Stream) XInfo(ctx context.Context) (Info, error) {
+	rc, ok := st.redirect()
+	if ok {
+		cmd := protocol.NewXInfo(st.name).Command(ctx)
+		err := rc.Process(ctx, cmd)
+		if err != nil {
+			return Info{}, protocol.ConvertError(err)
+		}
+		items, err := cmd.Result()
+		if err != nil {
+			return Info{}, protocol.ConvertError(err)
+		}
+		return ParseInfo(items)
+	}
+
+	f, err := st.s.loadFragment(st.primaryPartition(), st.fragmentName)
+	if errors.Is(err, errFragmentNotFound) {
+		return Info{}, nil
+	}
+	if err != nil {
+		return Info{}, err
+	}
+
+	f.RLock()
+	defer f.RUnlock()
+
+	return Info{
+		Length: int64(len(f.entries)),
+		LastID: f.lastID,
+		Groups: int64(len(f.groups)),
+	}, nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/olric-data/olric/events"
+	"github.com/olric-data/olric/internal/cluster/partitions"
+	"github.com/olric-data/olric/internal/discovery"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/pkg/neterrors"
+	"github.com/tidwall/redcon"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type groupPack struct {
+	Name          string
+	LastDelivered ID
+	Pending       []pendingEntry
+}
+
+type fragmentPack struct {
+	PartID  uint64
+	Kind    partitions.Kind
+	Name    string
+	Entries []Entry
+	LastID  ID
+	Groups  []groupPack
+}
+
+// pack creates a snapshot of the fragment. The caller must hold the lock.
+func (f *fragment) pack(part *partitions.Partition, name string) *fragmentPack {
+	fp := &fragmentPack{
+		PartID:  part.ID(),
+		Kind:    part.Kind(),
+		Name:    name,
+		Entries: f.entries,
+		LastID:  f.lastID,
+	}
+	for _, g := range f.groups {
+		gp := groupPack{
+			Name:          g.Name,
+			LastDelivered: g.LastDelivered,
+		}
+		for _, p := range g.sortedPending() {
+			gp.Pending = append(gp.Pending, *p)
+		}
+		fp.Groups = append(fp.Groups, gp)
+	}
+	return fp
+}
+
+// merge merges the received snapshot into the fragment. The caller must hold the lock.
+func (f *fragment) merge(fp *fragmentPack) {
+	for _, e := range fp.Entries {
+		f.insert(e)
+	}
+	if f.lastID.Less(fp.LastID) {
+		f.lastID = fp.LastID
+	}
+
+	for _, gp := range fp.Groups {
+		g, ok := f.groups[gp.Name]
+		if !ok {
+			g = newGroup(gp.Name, gp.LastDelivered)
+			f.groups[gp.Name] = g
+		}
+		if g.LastDelivered.Less(gp.LastDelivered) {
+			g.LastDelivered = gp.LastDelivered
+		}
+		for i := range gp.Pending {
+			p := gp.Pending[i]
+			current, ok := g.Pending[p.ID]
+			if !ok || current.DeliveredAt < p.DeliveredAt {
+				g.Pending[p.ID] = &p
+			}
+		}
+	}
+	f.wakeUp()
+}
+
+func (f *fragment) Move(part *partitions.Partition, name string, owners []discovery.Member) error {
+	f.Lock()
+	defer f.Unlock()
+
+	fp := f.pack(part, strings.TrimPrefix(name, "stream."))
+	value, err := msgpack.Marshal(fp)
+	if err != nil {
+		return err
+	}
+
+	for _, owner := range owners {
+		if f.service.config.EnableClusterEventsChannel {
+			e := &events.FragmentMigrationEvent{
+				Kind:          events.KindFragmentMigrationEvent,
+				Source:        f.service.rt.This().String(),
+				Target:        owner.String(),
+				DataStructure: "stream",
+				PartitionID:   part.ID(),
+				Identifier:    fp.Name,
+				Length:        len(value),
+				IsBackup:      part.Kind() == partitions.BACKUP,
+				Timestamp:     time.Now().UnixNano(),
+			}
+			f.service.wg.Add(1)
+			go f.service.publishEvent(e)
+		}
+
+		cmd := protocol.NewMoveStream(value).Command(f.service.ctx)
+		rc := f.service.client.Get(owner.String())
+		err = rc.Process(f.service.ctx, cmd)
+		if err != nil {
+			return err
+		}
+		if err := cmd.Err(); err != nil {
+			return err
+		}
+	}
+
+	// The stream belongs to another member now.
+	part.Map().Delete(f.service.fragmentName(fp.Name))
+	f.entries = nil
+	f.groups = make(map[string]*group)
+	f.wakeUp()
+	f.cancel()
+	return nil
+}
+
+func (s *Service) checkOwnership(part *partitions.Partition) bool {
+	owners := part.Owners()
+	for _, owner := range owners {
+		if owner.CompareByID(s.rt.This()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) validateFragmentPack(fp *fragmentPack) error {
+	if fp.PartID >= s.config.PartitionCount {
+		return fmt.Errorf("invalid partition id: %d", fp.PartID)
+	}
+
+	part := s.partitionByKind(fp.Kind, fp.PartID)
+
+	// Check ownership before merging. This is useful to prevent data corruption in network partitioning case.
+	if !s.checkOwnership(part) {
+		return fmt.Errorf("%w: %s",
+			neterrors.ErrInvalidArgument, fmt.Sprintf("partID: %d (kind: %s) doesn't belong to %s",
+				fp.PartID, fp.Kind, s.rt.This()))
+	}
+	return nil
+}
+
+func (s *Service) partitionByKind(kind partitions.Kind, partID uint64) *partitions.Partition {
+	if kind == partitions.PRIMARY {
+		return s.primary.PartitionByID(partID)
+	}
+	return s.backup.PartitionByID(partID)
+}
+
+func (s *Service) moveStreamCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	moveStreamCmd, err := protocol.ParseMoveStreamCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	fp := &fragmentPack{}
+	err = msgpack.Unmarshal(moveStreamCmd.Payload, fp)
+	if err != nil {
+		s.log.V(2).Printf("[ERROR] Failed to unmarshal Stream: %v", err)
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	if err = s.validateFragmentPack(fp); err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	part := s.partitionByKind(fp.Kind, fp.PartID)
+	s.log.V(2).Printf("[INFO] Received Stream (kind: %s): %s on PartID: %d", fp.Kind, fp.Name, fp.PartID)
+
+	f := s.loadOrCreateFragment(part, s.fragmentName(fp.Name))
+	f.Lock()
+	f.merge(fp)
+	f.Unlock()
+
+	if s.config.EnableClusterEventsChannel {
+		e := &events.FragmentReceivedEvent{
+			Kind:          events.KindFragmentReceivedEvent,
+			Source:        s.rt.This().String(),
+			DataStructure: "stream",
+			PartitionID:   part.ID(),
+			Identifier:    fp.Name,
+			Length:        len(moveStreamCmd.Payload),
+			IsBackup:      part.Kind() == partitions.BACKUP,
+			Timestamp:     time.Now().UnixNano(),
+		}
+		s.wg.Add(1)
+		go s.publishEvent(e)
+	}
+
+	conn.WriteString(protocol.StatusOK)
+}
@@ -0,0 +1,84 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/olric-data/olric/internal/cluster/partitions"
+	"github.com/olric-data/olric/internal/testcluster"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_Balance_Invalid_PartID(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	fp := &fragmentPack{
+		PartID: 12312,
+		Kind:   partitions.PRIMARY,
+		Name:   "foobar",
+	}
+	require.Error(t, s.validateFragmentPack(fp))
+}
+
+func TestStream_Balancer_JoinNewNode(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s1 := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	var streams = 20
+	for i := 0; i < streams; i++ {
+		st, err := s1.NewStream("balancer-test." + strconv.Itoa(i))
+		require.NoError(t, err)
+		require.NoError(t, st.XGroupCreate(ctx, "mygroup", "0"))
+		for j := 0; j < 10; j++ {
+			_, err = st.XAdd(ctx, AutoID, fieldsOf(j), nil)
+			require.NoError(t, err)
+		}
+		_, err = st.XReadGroup(ctx, "mygroup", "consumer", NewEntriesID, &ReadConfig{HasCount: true, Count: 5})
+		require.NoError(t, err)
+	}
+
+	s2 := cluster.AddMember(nil).(*Service) // This automatically syncs the cluster.
+
+	var moved int
+	for partID := uint64(0); partID < s2.config.PartitionCount; partID++ {
+		part := s2.primary.PartitionByID(partID)
+		moved += part.Length()
+	}
+	require.Greater(t, moved, 0)
+
+	for i := 0; i < streams; i++ {
+		st, err := s2.NewStream("balancer-test." + strconv.Itoa(i))
+		require.NoError(t, err)
+
+		length, err := st.XLen(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(10), length)
+
+		pending, err := st.XPending(ctx, "mygroup")
+		require.NoError(t, err)
+		require.Len(t, pending, 5)
+
+		entries, err := st.XReadGroup(ctx, "mygroup", "consumer", NewEntriesID, nil)
+		require.NoError(t, err)
+		require.Len(t, entries, 5)
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/olric-data/olric/internal/cluster/partitions"
+	"github.com/olric-data/olric/pkg/storage"
+)
+
+// Entry is a stream entry with its fields.
+type Entry struct {
+	ID     ID
+	Fields map[string]string
+}
+
+type pendingEntry struct {
+	ID            ID
+	Consumer      string
+	DeliveredAt   int64
+	DeliveryCount int64
+}
+
+type group struct {
+	Name          string
+	LastDelivered ID
+	Pending       map[ID]*pendingEntry
+}
+
+func newGroup(name string, lastDelivered ID) *group {
+	return &group{
+		Name:          name,
+		LastDelivered: lastDelivered,
+		Pending:       make(map[ID]*pendingEntry),
+	}
+}
+
+// sortedPending returns the pending entries of the group in ID order.
+func (g *group) sortedPending() []*pendingEntry {
+	pending := make([]*pendingEntry, 0, len(g.Pending))
+	for _, p := range g.Pending {
+		pending = append(pending, p)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].ID.Less(pending[j].ID)
+	})
+	return pending
+}
+
+// fragment hosts a single stream on a partition. Entries are kept in ID order.
+type fragment struct {
+	sync.RWMutex
+
+	service *Service
+	entries []Entry
+	lastID  ID
+	groups  map[string]*group
+	// notify is closed and replaced after every write to wake up
+	// the blocked readers.
+	notify chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *Service) newFragment() *fragment {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &fragment{
+		service: s,
+		groups:  make(map[string]*group),
+		notify:  make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+func (f *fragment) Name() string {
+	return "Stream"
+}
+
+func (f *fragment) Stats() storage.Stats {
+	f.RLock()
+	defer f.RUnlock()
+
+	return storage.Stats{
+		Length: len(f.entries),
+		Extras: map[string]interface{}{
+			"groups": len(f.groups),
+		},
+	}
+}
+
+func (f *fragment) Compaction() (bool, error) {
+	// Streams are stored in Go memory, there is nothing to compact.
+	return true, nil
+}
+
+func (f *fragment) Destroy() error {
+	f.Lock()
+	defer f.Unlock()
+
+	f.entries = nil
+	f.groups = make(map[string]*group)
+	return nil
+}
+
+func (f *fragment) Close() error {
+	f.cancel()
+	return nil
+}
+
+// wakeUp notifies the blocked readers. The caller must hold the write lock.
+func (f *fragment) wakeUp() {
+	close(f.notify)
+	f.notify = make(chan struct{})
+}
+
+// search returns the index of the first entry whose ID is greater than or equal to id.
+func (f *fragment) search(id ID) int {
+	return sort.Search(len(f.entries), func(i int) bool {
+		return !f.entries[i].ID.Less(id)
+	})
+}
+
+// lookup returns the entry with the given ID, if any.
+func (f *fragment) lookup(id ID) (Entry, bool) {
+	idx := f.search(id)
+	if idx < len(f.entries) && f.entries[idx].ID == id {
+		return f.entries[idx], true
+	}
+	return Entry{}, false
+}
+
+// insert adds the entry by preserving the ID order. It's a no-op if there is
+// already an entry with the same ID.
+func (f *fragment) insert(e Entry) {
+	idx := f.search(e.ID)
+	if idx < len(f.entries) && f.entries[idx].ID == e.ID {
+		return
+	}
+	if idx == len(f.entries) {
+		f.entries = append(f.entries, e)
+	} else {
+		f.entries = append(f.entries, Entry{})
+		copy(f.entries[idx+1:], f.entries[idx:])
+		f.entries[idx] = e
+	}
+	if f.lastID.Less(e.ID) {
+		f.lastID = e.ID
+	}
+}
+
+// trim removes the oldest entries to keep the stream length at maxLen.
+func (f *fragment) trim(maxLen int64) {
+	if maxLen <= 0 || int64(len(f.entries)) <= maxLen {
+		return
+	}
+	excess := int64(len(f.entries)) - maxLen
+	// Copy the remaining entries to release the underlying array.
+	f.entries = append([]Entry(nil), f.entries[excess:]...)
+}
+
+// rangeOf returns the entries between start and end, inclusive.
+func (f *fragment) rangeOf(start, end ID, count int) []Entry {
+	var result []Entry
+	for idx := f.search(start); idx < len(f.entries); idx++ {
+		if end.Less(f.entries[idx].ID) {
+			break
+		}
+		result = append(result, f.entries[idx])
+		if count > 0 && len(result) >= count {
+			break
+		}
+	}
+	return result
+}
+
+// after returns the entries whose IDs are greater than id.
+func (f *fragment) after(id ID, count int) []Entry {
+	var result []Entry
+	idx := f.search(id)
+	if idx < len(f.entries) && f.entries[idx].ID == id {
+		idx++
+	}
+	for ; idx < len(f.entries); idx++ {
+		result = append(result, f.entries[idx])
+		if count > 0 && len(result) >= count {
+			break
+		}
+	}
+	return result
+}
+
+func (s *Service) loadOrCreateFragment(part *partitions.Partition, name string) *fragment {
+	part.Lock()
+	defer part.Unlock()
+
+	// Critical section here. It should be protected by a lock.
+	f, ok := part.Map().Load(name)
+	if ok {
+		return f.(*fragment)
+	}
+
+	fg := s.newFragment()
+	part.Map().Store(name, fg)
+	s.notifyCreation(name)
+	return fg
+}
+
+func (s *Service) loadFragment(part *partitions.Partition, name string) (*fragment, error) {
+	f, ok := part.Map().Load(name)
+	if !ok {
+		return nil, errFragmentNotFound
+	}
+	return f.(*fragment), nil
+}
+
+// creation is closed when a fragment is created. The blocking readers of a
+// stream that doesn't exist yet wait on it, they don't create the fragment.
+type creation struct {
+	done    chan struct{}
+	waiters int
+}
+
+func (s *Service) watchCreation(name string) *creation {
+	s.creationsMtx.Lock()
+	defer s.creationsMtx.Unlock()
+
+	c, ok := s.creations[name]
+	if !ok {
+		c = &creation{done: make(chan struct{})}
+		s.creations[name] = c
+	}
+	c.waiters++
+	return c
+}
+
+func (s *Service) unwatchCreation(name string, c *creation) {
+	s.creationsMtx.Lock()
+	defer s.creationsMtx.Unlock()
+
+	c.waiters--
+	if c.waiters == 0 && s.creations[name] == c {
+		delete(s.creations, name)
+	}
+}
+
+func (s *Service) notifyCreation(name string) {
+	s.creationsMtx.Lock()
+	defer s.creationsMtx.Unlock()
+
+	c, ok := s.creations[name]
+	if !ok {
+		return
+	}
+	close(c.done)
+	delete(s.creations, name)
+}
+
+// awaitFragment waits until the fragment is created. It returns nil if the
+// timer fires before.
+func (s *Service) awaitFragment(ctx context.Context, part *partitions.Partition, name string, timer *time.Timer) (*fragment, error) {
+	c := s.watchCreation(name)
+	defer func() {
+		s.unwatchCreation(name, c)
+	}()
+
+	for {
+		// The fragment may be created before the watch.
+		f, err := s.loadFragment(part, name)
+		if !errors.Is(err, errFragmentNotFound) {
+			return f, err
+		}
+
+		select {
+		case <-c.done:
+			// A fragment with the same name may be created in the backup
+			// partitions, check it again with a new watch.
+			s.unwatchCreation(name, c)
+			c = s.watchCreation(name)
+		case <-timer.C:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.ctx.Done():
+			return nil, ErrServerGone
+		}
+	}
+}
+
+var _ partitions.Fragment = (*fragment)(nil)
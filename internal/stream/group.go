@@ -0,0 +1,500 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olric-data/olric/internal/protocol"
+)
+
+// DefaultAutoClaimCount is the default number of entries claimed by XAutoClaim.
+const DefaultAutoClaimCount = 100
+
+// PendingEntry is an entry that is delivered to a consumer but not acknowledged yet.
+type PendingEntry struct {
+	ID            ID
+	Consumer      string
+	Idle          time.Duration
+	DeliveryCount int64
+}
+
+func (st *Stream) loadGroup(f *fragment, name string) (*group, error) {
+	g, ok := f.groups[name]
+	if !ok {
+		return nil, ErrNoSuchGroup
+	}
+	return g, nil
+}
+
+func (st *Stream) groupCreateOnFragment(group, rawID string) error {
+	f := st.s.loadOrCreateFragment(st.primaryPartition(), st.fragmentName)
+
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.groups[group]; ok {
+		return ErrGroupExists
+	}
+
+	var id ID
+	if rawID == LastID {
+		id = f.lastID
+	} else {
+		var err error
+		id, err = ParseID(rawID, 0)
+		if err != nil {
+			return err
+		}
+	}
+
+	op := &operation{
+		Kind:      opCreateGroup,
+		Stream:    st.name,
+		Group:     group,
+		ID:        id,
+		Timestamp: time.Now().UnixNano(),
+	}
+	f.apply(op)
+	return st.replicate(op)
+}
+
+// XGroupCreate creates a new consumer group. The group starts to consume the
+// entries with IDs greater than rawID. LastID can be used to consume only
+// the new entries.
+func (st *Stream) XGroupCreate(ctx context.Context, group, rawID string) error {
+	rc, ok := st.redirect()
+	if !ok {
+		return st.groupCreateOnFragment(group, rawID)
+	}
+
+	// Redirect to the partition owner
+	cmd := protocol.NewXGroupCreate(st.name, group, rawID).Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return protocol.ConvertError(err)
+	}
+	return protocol.ConvertError(cmd.Err())
+}
+
+// pendingOfConsumer returns the entries that are delivered to the consumer
+// but not acknowledged yet.
+func (st *Stream) pendingOfConsumer(f *fragment, g *group, consumer string, rawID string, count int) ([]Entry, error) {
+	id, err := ParseID(rawID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, p := range g.sortedPending() {
+		if p.Consumer != consumer || !id.Less(p.ID) {
+			continue
+		}
+		e, ok := f.lookup(p.ID)
+		if !ok {
+			// The entry is trimmed but still pending.
+			e = Entry{ID: p.ID}
+		}
+		entries = append(entries, e)
+		if count > 0 && len(entries) >= count {
+			break
+		}
+	}
+	return entries, nil
+}
+
+func (st *Stream) readGroupOnFragment(ctx context.Context, group, consumer, rawID string, cfg *ReadConfig) ([]Entry, error) {
+	f, err := st.s.loadFragment(st.primaryPartition(), st.fragmentName)
+	if errors.Is(err, errFragmentNotFound) {
+		return nil, ErrNoSuchGroup
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if rawID != NewEntriesID {
+		// Read the pending entries of the consumer. It never blocks.
+		f.RLock()
+		defer f.RUnlock()
+
+		g, err := st.loadGroup(f, group)
+		if err != nil {
+			return nil, err
+		}
+		return st.pendingOfConsumer(f, g, consumer, rawID, cfg.count())
+	}
+
+	timer := time.NewTimer(cfg.block())
+	defer timer.Stop()
+
+	for {
+		entries, notify, err := st.deliver(f, group, consumer, cfg.count())
+		if err != nil || len(entries) > 0 || cfg.block() <= 0 {
+			return entries, err
+		}
+
+		wait, err := st.await(ctx, f, notify, timer)
+		if err != nil || !wait {
+			return nil, err
+		}
+	}
+}
+
+// deliver assigns the undelivered entries to the consumer. If there is no new
+// entry, it returns the channel to wait on.
+func (st *Stream) deliver(f *fragment, group, consumer string, count int) ([]Entry, chan struct{}, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	g, err := st.loadGroup(f, group)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := f.after(g.LastDelivered, count)
+	if len(entries) == 0 {
+		return nil, f.notify, nil
+	}
+
+	op := &operation{
+		Kind:      opDeliver,
+		Stream:    st.name,
+		Group:     group,
+		Consumer:  consumer,
+		Timestamp: time.Now().UnixNano(),
+	}
+	for _, e := range entries {
+		op.IDs = append(op.IDs, e.ID)
+	}
+	f.apply(op)
+
+	// total number of entries delivered to consumer groups during the life of this instance.
+	DeliveredTotal.Increase(int64(len(entries)))
+
+	return entries, nil, st.replicate(op)
+}
+
+func (st *Stream) readGroupOnOwner(ctx context.Context, group, consumer, rawID string, cfg *ReadConfig) ([]Entry, error) {
+	rc, ok := st.redirect()
+	if !ok {
+		return st.readGroupOnFragment(ctx, group, consumer, rawID, cfg)
+	}
+
+	// Redirect to the partition owner
+	xreadGroupCmd := protocol.NewXReadGroup(st.name, group, consumer, rawID)
+	if cfg.HasCount {
+		xreadGroupCmd.SetCount(cfg.Count)
+	}
+	if cfg.HasBlock {
+		xreadGroupCmd.SetBlock(cfg.Block.Milliseconds())
+	}
+	cmd := xreadGroupCmd.Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	items, err := cmd.Result()
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	return ParseEntries(items)
+}
+
+// XReadGroup reads entries on behalf of a consumer in a consumer group. If rawID is
+// NewEntriesID, it delivers the entries that are never delivered to any consumer
+// in the group and adds them to the pending entries list. Otherwise, it returns
+// the pending entries of the consumer with IDs greater than rawID.
+func (st *Stream) XReadGroup(ctx context.Context, group, consumer, rawID string, cfg *ReadConfig) ([]Entry, error) {
+	if cfg == nil {
+		cfg = &ReadConfig{}
+	}
+
+	if _, ok := st.redirect(); !ok {
+		return st.readGroupOnFragment(ctx, group, consumer, rawID, cfg)
+	}
+
+	return BlockInRounds(ctx, cfg.block(), st.s.client.ReadTimeout(), func(block time.Duration) ([]Entry, error) {
+		rc := *cfg
+		rc.Block = block
+		return st.readGroupOnOwner(ctx, group, consumer, rawID, &rc)
+	})
+}
+
+func (st *Stream) ackOnFragment(group string, rawIDs []string) (int, error) {
+	var ids []ID
+	for _, rawID := range rawIDs {
+		id, err := ParseID(rawID, 0)
+		if err != nil {
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+
+	f, err := st.s.loadFragment(st.primaryPartition(), st.fragmentName)
+	if errors.Is(err, errFragmentNotFound) {
+		return 0, ErrNoSuchGroup
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	if _, err = st.loadGroup(f, group); err != nil {
+		return 0, err
+	}
+
+	op := &operation{
+		Kind:      opAck,
+		Stream:    st.name,
+		Group:     group,
+		IDs:       ids,
+		Timestamp: time.Now().UnixNano(),
+	}
+	count := f.apply(op)
+	if count == 0 {
+		// Nothing to replicate.
+		return 0, nil
+	}
+
+	// total number of acknowledged entries during the life of this instance.
+	AckedTotal.Increase(int64(count))
+
+	return count, st.replicate(op)
+}
+
+// XAck removes the given entries from the pending entries list of the
+// consumer group. It returns the number of acknowledged entries.
+func (st *Stream) XAck(ctx context.Context, group string, ids ...string) (int, error) {
+	rc, ok := st.redirect()
+	if !ok {
+		return st.ackOnFragment(group, ids)
+	}
+
+	// Redirect to the partition owner
+	cmd := protocol.NewXAck(st.name, group, ids...).Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return 0, protocol.ConvertError(err)
+	}
+	count, err := cmd.Result()
+	if err != nil {
+		return 0, protocol.ConvertError(err)
+	}
+	return int(count), nil
+}
+
+func (st *Stream) pendingOnFragment(group string) ([]PendingEntry, error) {
+	f, err := st.s.loadFragment(st.primaryPartition(), st.fragmentName)
+	if errors.Is(err, errFragmentNotFound) {
+		return nil, ErrNoSuchGroup
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f.RLock()
+	defer f.RUnlock()
+
+	g, err := st.loadGroup(f, group)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixNano()
+	var result []PendingEntry
+	for _, p := range g.sortedPending() {
+		result = append(result, PendingEntry{
+			ID:            p.ID,
+			Consumer:      p.Consumer,
+			Idle:          time.Duration(now - p.DeliveredAt),
+			DeliveryCount: p.DeliveryCount,
+		})
+	}
+	return result, nil
+}
+
+// XPending returns the pending entries list of the consumer group.
+func (st *Stream) XPending(ctx context.Context, group string) ([]PendingEntry, error) {
+	rc, ok := st.redirect()
+	if !ok {
+		return st.pendingOnFragment(group)
+	}
+
+	// Redirect to the partition owner
+	cmd := protocol.NewXPending(st.name, group).Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	items, err := cmd.Result()
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	return ParsePendingEntries(items)
+}
+
+// claim transfers the ownership of the given pending entries to the consumer.
+// The caller must hold the write lock of the fragment.
+func (st *Stream) claim(f *fragment, group, consumer string, ids []ID) ([]Entry, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	op := &operation{
+		Kind:      opClaim,
+		Stream:    st.name,
+		Group:     group,
+		Consumer:  consumer,
+		IDs:       ids,
+		Timestamp: time.Now().UnixNano(),
+	}
+	f.apply(op)
+
+	var entries []Entry
+	for _, id := range ids {
+		if e, ok := f.lookup(id); ok {
+			entries = append(entries, e)
+		}
+	}
+	return entries, st.replicate(op)
+}
+
+func (st *Stream) claimOnFragment(group, consumer string, minIdle time.Duration, rawIDs []string) ([]Entry, error) {
+	f, err := st.s.loadFragment(st.primaryPartition(), st.fragmentName)
+	if errors.Is(err, errFragmentNotFound) {
+		return nil, ErrNoSuchGroup
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	g, err := st.loadGroup(f, group)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixNano()
+	var ids []ID
+	for _, rawID := range rawIDs {
+		id, err := ParseID(rawID, 0)
+		if err != nil {
+			return nil, err
+		}
+		p, ok := g.Pending[id]
+		if !ok || time.Duration(now-p.DeliveredAt) < minIdle {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return st.claim(f, group, consumer, ids)
+}
+
+// XClaim transfers the ownership of the given pending entries to the consumer,
+// if they are idle for minIdle at least. It returns the claimed entries.
+func (st *Stream) XClaim(ctx context.Context, group, consumer string, minIdle time.Duration, ids ...string) ([]Entry, error) {
+	rc, ok := st.redirect()
+	if !ok {
+		return st.claimOnFragment(group, consumer, minIdle, ids)
+	}
+
+	// Redirect to the partition owner
+	cmd := protocol.NewXClaim(st.name, group, consumer, minIdle.Milliseconds(), ids...).Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	items, err := cmd.Result()
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	return ParseEntries(items)
+}
+
+func (st *Stream) autoClaimOnFragment(group, consumer string, minIdle time.Duration, start string, count int) ([]Entry, error) {
+	startID, err := parseRangeStart(start)
+	if err != nil {
+		return nil, err
+	}
+	if count <= 0 {
+		count = DefaultAutoClaimCount
+	}
+
+	f, err := st.s.loadFragment(st.primaryPartition(), st.fragmentName)
+	if errors.Is(err, errFragmentNotFound) {
+		return nil, ErrNoSuchGroup
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	g, err := st.loadGroup(f, group)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixNano()
+	var ids []ID
+	for _, p := range g.sortedPending() {
+		if p.ID.Less(startID) || time.Duration(now-p.DeliveredAt) < minIdle {
+			continue
+		}
+		ids = append(ids, p.ID)
+		if len(ids) >= count {
+			break
+		}
+	}
+	return st.claim(f, group, consumer, ids)
+}
+
+// XAutoClaim transfers the ownership of the pending entries that are idle for
+// minIdle at least to the consumer. It scans the pending entries list
+// starting from the given ID and returns the claimed entries. It's the way to
+// recover the entries of the consumers that are gone.
+func (st *Stream) XAutoClaim(ctx context.Context, group, consumer string, minIdle time.Duration, start string, cfg *ReadConfig) ([]Entry, error) {
+	if cfg == nil {
+		cfg = &ReadConfig{}
+	}
+
+	rc, ok := st.redirect()
+	if !ok {
+		return st.autoClaimOnFragment(group, consumer, minIdle, start, cfg.count())
+	}
+
+	// Redirect to the partition owner
+	xautoClaimCmd := protocol.NewXAutoClaim(st.name, group, consumer, minIdle.Milliseconds(), start)
+	if cfg.HasCount {
+		xautoClaimCmd.SetCount(cfg.Count)
+	}
+	cmd := xautoClaimCmd.Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	items, err := cmd.Result()
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	return ParseEntries(items)
+}
@@ -0,0 +1,196 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/internal/testcluster"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_XGroupCreate(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	st, err := s.NewStream("mystream")
+	require.NoError(t, err)
+
+	require.NoError(t, st.XGroupCreate(ctx, "mygroup", LastID))
+	require.ErrorIs(t, st.XGroupCreate(ctx, "mygroup", LastID), ErrGroupExists)
+
+	_, err = st.XReadGroup(ctx, "foobar", "consumer", NewEntriesID, nil)
+	require.ErrorIs(t, err, ErrNoSuchGroup)
+}
+
+func TestStream_XReadGroup_XAck(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s1 := cluster.AddMember(nil).(*Service)
+	s2 := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	st1, err := s1.NewStream("mystream")
+	require.NoError(t, err)
+	require.NoError(t, st1.XGroupCreate(ctx, "mygroup", "0"))
+
+	for i := 0; i < 10; i++ {
+		_, err = st1.XAdd(ctx, AutoID, fieldsOf(i), nil)
+		require.NoError(t, err)
+	}
+
+	st2, err := s2.NewStream("mystream")
+	require.NoError(t, err)
+
+	// Every entry is delivered to a single consumer in the group.
+	c1, err := st1.XReadGroup(ctx, "mygroup", "consumer-1", NewEntriesID, &ReadConfig{HasCount: true, Count: 4})
+	require.NoError(t, err)
+	require.Len(t, c1, 4)
+
+	c2, err := st2.XReadGroup(ctx, "mygroup", "consumer-2", NewEntriesID, nil)
+	require.NoError(t, err)
+	require.Len(t, c2, 6)
+	require.Equal(t, fieldsOf(4), c2[0].Fields)
+
+	pending, err := st2.XPending(ctx, "mygroup")
+	require.NoError(t, err)
+	require.Len(t, pending, 10)
+	require.Equal(t, "consumer-1", pending[0].Consumer)
+	require.Equal(t, int64(1), pending[0].DeliveryCount)
+
+	// The pending history of a consumer
+	history, err := st2.XReadGroup(ctx, "mygroup", "consumer-1", "0", nil)
+	require.NoError(t, err)
+	require.Equal(t, c1, history)
+
+	var ids []string
+	for _, e := range c1 {
+		ids = append(ids, e.ID.String())
+	}
+	count, err := st2.XAck(ctx, "mygroup", ids...)
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+
+	count, err = st2.XAck(ctx, "mygroup", ids...)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	pending, err = st1.XPending(ctx, "mygroup")
+	require.NoError(t, err)
+	require.Len(t, pending, 6)
+}
+
+func TestStream_XReadGroup_Block(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	st, err := s.NewStream("mystream")
+	require.NoError(t, err)
+	require.NoError(t, st.XGroupCreate(ctx, "mygroup", LastID))
+
+	result := make(chan []Entry, 1)
+	go func() {
+		entries, err := st.XReadGroup(ctx, "mygroup", "consumer", NewEntriesID, &ReadConfig{HasBlock: true, Block: 5 * time.Second})
+		require.NoError(t, err)
+		result <- entries
+	}()
+
+	<-time.After(100 * time.Millisecond)
+	id, err := st.XAdd(ctx, AutoID, fieldsOf(1), nil)
+	require.NoError(t, err)
+
+	select {
+	case entries := <-result:
+		require.Len(t, entries, 1)
+		require.Equal(t, id, entries[0].ID)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "No entry received")
+	}
+}
+
+func TestStream_XClaim(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s1 := cluster.AddMember(nil).(*Service)
+	s2 := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	st1, err := s1.NewStream("mystream")
+	require.NoError(t, err)
+	require.NoError(t, st1.XGroupCreate(ctx, "mygroup", "0"))
+
+	id, err := st1.XAdd(ctx, AutoID, fieldsOf(1), nil)
+	require.NoError(t, err)
+
+	_, err = st1.XReadGroup(ctx, "mygroup", "consumer-1", NewEntriesID, nil)
+	require.NoError(t, err)
+
+	st2, err := s2.NewStream("mystream")
+	require.NoError(t, err)
+
+	// Not idle enough
+	entries, err := st2.XClaim(ctx, "mygroup", "consumer-2", time.Hour, id.String())
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	<-time.After(50 * time.Millisecond)
+	entries, err = st2.XClaim(ctx, "mygroup", "consumer-2", 10*time.Millisecond, id.String())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, id, entries[0].ID)
+
+	pending, err := st2.XPending(ctx, "mygroup")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, "consumer-2", pending[0].Consumer)
+	require.Equal(t, int64(2), pending[0].DeliveryCount)
+}
+
+func TestStream_XAutoClaim(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	st, err := s.NewStream("mystream")
+	require.NoError(t, err)
+	require.NoError(t, st.XGroupCreate(ctx, "mygroup", "0"))
+
+	for i := 0; i < 10; i++ {
+		_, err = st.XAdd(ctx, AutoID, fieldsOf(i), nil)
+		require.NoError(t, err)
+	}
+	_, err = st.XReadGroup(ctx, "mygroup", "consumer-1", NewEntriesID, nil)
+	require.NoError(t, err)
+
+	<-time.After(50 * time.Millisecond)
+	entries, err := st.XAutoClaim(ctx, "mygroup", "consumer-2", 10*time.Millisecond, MinID, &ReadConfig{HasCount: true, Count: 3})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	pending, err := st.XPending(ctx, "mygroup")
+	require.NoError(t, err)
+	consumers := make(map[string]int)
+	for _, p := range pending {
+		consumers[p.Consumer]++
+	}
+	require.Equal(t, map[string]int{"consumer-1": 7, "consumer-2": 3}, consumers)
+}
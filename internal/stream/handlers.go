@@ -0,0 +1,320 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"time"
+
+	"github.com/olric-data/olric/internal/cluster/partitions"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/tidwall/redcon"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func (s *Service) RegisterHandlers() {
+	s.server.ServeMux().HandleFunc(protocol.Stream.XAdd, s.xaddCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Stream.XLen, s.xlenCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Stream.XInfo, s.xinfoCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Stream.XRange, s.xrangeCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Stream.XRead, s.xreadCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Stream.XGroupCreate, s.xgroupCreateCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Stream.XReadGroup, s.xreadGroupCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Stream.XAck, s.xackCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Stream.XPending, s.xpendingCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Stream.XClaim, s.xclaimCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Stream.XAutoClaim, s.xautoClaimCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Stream.Replicate, s.replicateCommandHandler)
+	s.server.ServeMux().HandleFunc(protocol.Internal.MoveStream, s.moveStreamCommandHandler)
+}
+
+func newReadConfig(count int, block int64) *ReadConfig {
+	cfg := &ReadConfig{}
+	if count > 0 {
+		cfg.HasCount = true
+		cfg.Count = count
+	}
+	if block > 0 {
+		cfg.HasBlock = true
+		cfg.Block = time.Duration(block) * time.Millisecond
+	}
+	return cfg
+}
+
+func (s *Service) xaddCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	xaddCmd, err := protocol.ParseXAddCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	st, err := s.NewStream(xaddCmd.Stream)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	cfg := &AddConfig{}
+	if xaddCmd.MaxLen > 0 {
+		cfg.HasMaxLen = true
+		cfg.MaxLen = xaddCmd.MaxLen
+	}
+	id, err := st.XAdd(s.ctx, xaddCmd.ID, xaddCmd.Fields, cfg)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteBulkString(id.String())
+}
+
+func (s *Service) xlenCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	xlenCmd, err := protocol.ParseXLenCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	st, err := s.NewStream(xlenCmd.Stream)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	length, err := st.XLen(s.ctx)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteInt64(length)
+}
+
+func (s *Service) xinfoCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	xinfoCmd, err := protocol.ParseXInfoCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	st, err := s.NewStream(xinfoCmd.Stream)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	info, err := st.XInfo(s.ctx)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	writeInfo(conn, info)
+}
+
+func (s *Service) xrangeCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	xrangeCmd, err := protocol.ParseXRangeCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	st, err := s.NewStream(xrangeCmd.Stream)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	entries, err := st.XRange(s.ctx, xrangeCmd.Start, xrangeCmd.End, newReadConfig(xrangeCmd.Count, 0))
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	writeEntries(conn, entries)
+}
+
+func (s *Service) xreadCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	xreadCmd, err := protocol.ParseXReadCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	st, err := s.NewStream(xreadCmd.Stream)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	entries, err := st.XRead(s.ctx, xreadCmd.ID, newReadConfig(xreadCmd.Count, xreadCmd.Block))
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	writeEntries(conn, entries)
+}
+
+func (s *Service) xgroupCreateCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	xgroupCreateCmd, err := protocol.ParseXGroupCreateCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	st, err := s.NewStream(xgroupCreateCmd.Stream)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	err = st.XGroupCreate(s.ctx, xgroupCreateCmd.Group, xgroupCreateCmd.ID)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteString(protocol.StatusOK)
+}
+
+func (s *Service) xreadGroupCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	xreadGroupCmd, err := protocol.ParseXReadGroupCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	st, err := s.NewStream(xreadGroupCmd.Stream)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	entries, err := st.XReadGroup(
+		s.ctx,
+		xreadGroupCmd.Group,
+		xreadGroupCmd.Consumer,
+		xreadGroupCmd.ID,
+		newReadConfig(xreadGroupCmd.Count, xreadGroupCmd.Block),
+	)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	writeEntries(conn, entries)
+}
+
+func (s *Service) xackCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	xackCmd, err := protocol.ParseXAckCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	st, err := s.NewStream(xackCmd.Stream)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	count, err := st.XAck(s.ctx, xackCmd.Group, xackCmd.IDs...)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteInt(count)
+}
+
+func (s *Service) xpendingCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	xpendingCmd, err := protocol.ParseXPendingCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	st, err := s.NewStream(xpendingCmd.Stream)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	pending, err := st.XPending(s.ctx, xpendingCmd.Group)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	writePendingEntries(conn, pending)
+}
+
+func (s *Service) xclaimCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	xclaimCmd, err := protocol.ParseXClaimCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	st, err := s.NewStream(xclaimCmd.Stream)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	entries, err := st.XClaim(
+		s.ctx,
+		xclaimCmd.Group,
+		xclaimCmd.Consumer,
+		time.Duration(xclaimCmd.MinIdle)*time.Millisecond,
+		xclaimCmd.IDs...,
+	)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	writeEntries(conn, entries)
+}
+
+func (s *Service) xautoClaimCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	xautoClaimCmd, err := protocol.ParseXAutoClaimCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	st, err := s.NewStream(xautoClaimCmd.Stream)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	entries, err := st.XAutoClaim(
+		s.ctx,
+		xautoClaimCmd.Group,
+		xautoClaimCmd.Consumer,
+		time.Duration(xautoClaimCmd.MinIdle)*time.Millisecond,
+		xautoClaimCmd.Start,
+		newReadConfig(xautoClaimCmd.Count, 0),
+	)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	writeEntries(conn, entries)
+}
+
+func (s *Service) replicateCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	replicateCmd, err := protocol.ParseStreamReplicateCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	op := &operation{}
+	err = msgpack.Unmarshal(replicateCmd.Payload, op)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	hkey := partitions.HKey(op.Stream, "")
+	f := s.loadOrCreateFragment(s.backup.PartitionByHKey(hkey), s.fragmentName(op.Stream))
+	f.Lock()
+	f.apply(op)
+	f.Unlock()
+
+	conn.WriteString(protocol.StatusOK)
+}
@@ -0,0 +1,107 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// AutoID asks the partition owner to generate a new ID for the entry.
+	AutoID = "*"
+
+	// LastID denotes the ID of the latest entry in a stream.
+	LastID = "$"
+
+	// MinID denotes the smallest possible ID in a range query.
+	MinID = "-"
+
+	// MaxID denotes the greatest possible ID in a range query.
+	MaxID = "+"
+
+	// NewEntriesID denotes the entries that are never delivered to a consumer group.
+	NewEntriesID = ">"
+)
+
+// ID identifies an entry in a stream. It consists of a Unix timestamp in
+// milliseconds and a sequence number to distinguish the entries created
+// in the same millisecond.
+type ID struct {
+	Ms  uint64
+	Seq uint64
+}
+
+// String returns the <milliseconds>-<sequence> representation of the ID.
+func (id ID) String() string {
+	return strconv.FormatUint(id.Ms, 10) + "-" + strconv.FormatUint(id.Seq, 10)
+}
+
+// Less reports whether id is smaller than other.
+func (id ID) Less(other ID) bool {
+	if id.Ms == other.Ms {
+		return id.Seq < other.Seq
+	}
+	return id.Ms < other.Ms
+}
+
+// IsZero reports whether id is the zero ID.
+func (id ID) IsZero() bool {
+	return id.Ms == 0 && id.Seq == 0
+}
+
+// next returns the smallest ID that is greater than id.
+func (id ID) next() ID {
+	if id.Seq == math.MaxUint64 {
+		return ID{Ms: id.Ms + 1}
+	}
+	return ID{Ms: id.Ms, Seq: id.Seq + 1}
+}
+
+// ParseID parses a <milliseconds>-<sequence> formatted ID. The sequence part
+// is optional. If it's omitted, defaultSeq is used.
+func ParseID(s string, defaultSeq uint64) (ID, error) {
+	rawMs, rawSeq, found := strings.Cut(s, "-")
+	ms, err := strconv.ParseUint(rawMs, 10, 64)
+	if err != nil {
+		return ID{}, fmt.Errorf("%w: %s", ErrInvalidID, s)
+	}
+	if !found {
+		return ID{Ms: ms, Seq: defaultSeq}, nil
+	}
+	seq, err := strconv.ParseUint(rawSeq, 10, 64)
+	if err != nil {
+		return ID{}, fmt.Errorf("%w: %s", ErrInvalidID, s)
+	}
+	return ID{Ms: ms, Seq: seq}, nil
+}
+
+// parseRangeStart parses the start argument of a range query.
+func parseRangeStart(s string) (ID, error) {
+	if s == MinID {
+		return ID{}, nil
+	}
+	return ParseID(s, 0)
+}
+
+// parseRangeEnd parses the end argument of a range query.
+func parseRangeEnd(s string) (ID, error) {
+	if s == MaxID {
+		return ID{Ms: math.MaxUint64, Seq: math.MaxUint64}, nil
+	}
+	return ParseID(s, math.MaxUint64)
+}
@@ -0,0 +1,188 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/discovery"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type opKind uint8
+
+const (
+	opAdd = opKind(iota + 1)
+	opCreateGroup
+	opDeliver
+	opAck
+	opClaim
+)
+
+// operation denotes a single mutation on a stream. The partition owner
+// computes the operation, applies it on its own fragment and sends the same
+// operation to the backup owners. So the replicas converge to the same state
+// without running the business logic again.
+type operation struct {
+	Kind      opKind
+	Stream    string
+	Entry     Entry
+	MaxLen    int64
+	Group     string
+	Consumer  string
+	ID        ID
+	IDs       []ID
+	Timestamp int64
+}
+
+// apply runs the operation on the fragment and returns the number of affected
+// items. The caller must hold the write lock of the fragment.
+func (f *fragment) apply(op *operation) int {
+	switch op.Kind {
+	case opAdd:
+		f.insert(op.Entry)
+		f.trim(op.MaxLen)
+		f.wakeUp()
+		return 1
+	case opCreateGroup:
+		if _, ok := f.groups[op.Group]; ok {
+			return 0
+		}
+		f.groups[op.Group] = newGroup(op.Group, op.ID)
+		return 1
+	case opDeliver:
+		g, ok := f.groups[op.Group]
+		if !ok {
+			// The replica may receive this operation before the group is created.
+			g = newGroup(op.Group, ID{})
+			f.groups[op.Group] = g
+		}
+		for _, id := range op.IDs {
+			p, ok := g.Pending[id]
+			if !ok {
+				p = &pendingEntry{ID: id}
+				g.Pending[id] = p
+			}
+			p.Consumer = op.Consumer
+			p.DeliveredAt = op.Timestamp
+			p.DeliveryCount++
+			if g.LastDelivered.Less(id) {
+				g.LastDelivered = id
+			}
+		}
+		return len(op.IDs)
+	case opAck:
+		g, ok := f.groups[op.Group]
+		if !ok {
+			return 0
+		}
+		var count int
+		for _, id := range op.IDs {
+			if _, ok := g.Pending[id]; ok {
+				delete(g.Pending, id)
+				count++
+			}
+		}
+		return count
+	case opClaim:
+		g, ok := f.groups[op.Group]
+		if !ok {
+			return 0
+		}
+		var count int
+		for _, id := range op.IDs {
+			p, ok := g.Pending[id]
+			if !ok {
+				continue
+			}
+			if _, ok := f.lookup(id); !ok {
+				// The entry is trimmed, there is nothing to claim.
+				delete(g.Pending, id)
+				continue
+			}
+			p.Consumer = op.Consumer
+			p.DeliveredAt = op.Timestamp
+			p.DeliveryCount++
+			count++
+		}
+		return count
+	default:
+		return 0
+	}
+}
+
+func (s *Service) replicateOnBackup(data []byte, owner discovery.Member) error {
+	rc := s.client.Get(owner.String())
+	cmd := protocol.NewStreamReplicate(data).Command(s.ctx)
+	err := rc.Process(s.ctx, cmd)
+	if err != nil {
+		return protocol.ConvertError(err)
+	}
+	return protocol.ConvertError(cmd.Err())
+}
+
+func (s *Service) asyncReplicateOnBackup(data []byte, owner discovery.Member) {
+	defer s.wg.Done()
+
+	if err := s.replicateOnBackup(data, owner); err != nil {
+		if s.log.V(3).Ok() {
+			s.log.V(3).Printf("[ERROR] Failed to replicate stream operation in async mode: %v", err)
+		}
+	}
+}
+
+// replicate sends the operation to the backup owners of the stream. The
+// operation is already applied on the partition owner.
+func (st *Stream) replicate(op *operation) error {
+	if st.s.config.ReplicaCount <= config.MinimumReplicaCount {
+		return nil
+	}
+
+	data, err := msgpack.Marshal(op)
+	if err != nil {
+		return err
+	}
+
+	owners := st.s.backup.PartitionOwnersByHKey(st.hkey)
+	if st.s.config.ReplicationMode == config.AsyncReplicationMode {
+		// Fire and forget mode.
+		for _, owner := range owners {
+			if !st.s.isAlive() {
+				return ErrServerGone
+			}
+			st.s.wg.Add(1)
+			go st.s.asyncReplicateOnBackup(data, owner)
+		}
+		return nil
+	}
+
+	// Quorum based replication. The partition owner is already successful.
+	successful := 1
+	for _, owner := range owners {
+		err = st.s.replicateOnBackup(data, owner)
+		if err != nil {
+			if st.s.log.V(3).Ok() {
+				st.s.log.V(3).Printf("[ERROR] Failed to replicate stream operation on %s for Stream: %s: %v",
+					owner, st.name, err)
+			}
+			continue
+		}
+		successful++
+	}
+	if successful >= st.s.config.WriteQuorum {
+		return nil
+	}
+	return ErrWriteQuorum
+}
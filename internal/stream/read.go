@@ -0,0 +1,255 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olric-data/olric/internal/protocol"
+)
+
+// ReadConfig defines the options of the read commands.
+type ReadConfig struct {
+	HasCount bool
+	Count    int
+	HasBlock bool
+	Block    time.Duration
+}
+
+func (c *ReadConfig) count() int {
+	if c.HasCount {
+		return c.Count
+	}
+	return 0
+}
+
+func (c *ReadConfig) block() time.Duration {
+	if c.HasBlock {
+		return c.Block
+	}
+	return 0
+}
+
+// BlockInRounds splits a blocking read into rounds that fit into the read
+// timeout of a client. Otherwise, a blocking read longer than the read
+// timeout fails with an I/O timeout error.
+func BlockInRounds[T any](
+	ctx context.Context,
+	block, readTimeout time.Duration,
+	read func(block time.Duration) ([]T, error),
+) ([]T, error) {
+	if block <= 0 || readTimeout <= 0 || block < readTimeout/2 {
+		return read(block)
+	}
+
+	deadline := time.Now().Add(block)
+	for {
+		remaining := time.Until(deadline)
+		if remaining < time.Millisecond {
+			return nil, nil
+		}
+		round := readTimeout / 2
+		if remaining < round {
+			round = remaining
+		}
+		entries, err := read(round)
+		if err != nil || len(entries) > 0 {
+			return entries, err
+		}
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// await blocks until the fragment is modified or the timer fires. It returns
+// false if the caller should stop waiting.
+func (st *Stream) await(ctx context.Context, f *fragment, notify chan struct{}, timer *time.Timer) (bool, error) {
+	select {
+	case <-notify:
+		return true, nil
+	case <-timer.C:
+		return false, nil
+	case <-f.ctx.Done():
+		// The fragment is moved to another member.
+		return false, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-st.s.ctx.Done():
+		return false, ErrServerGone
+	}
+}
+
+func (st *Stream) rangeOnFragment(start, end string, cfg *ReadConfig) ([]Entry, error) {
+	startID, err := parseRangeStart(start)
+	if err != nil {
+		return nil, err
+	}
+	endID, err := parseRangeEnd(end)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := st.s.loadFragment(st.primaryPartition(), st.fragmentName)
+	if errors.Is(err, errFragmentNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f.RLock()
+	defer f.RUnlock()
+
+	return f.rangeOf(startID, endID, cfg.count()), nil
+}
+
+// XRange returns the entries with IDs between start and end, inclusive. MinID and
+// MaxID can be used to denote the smallest and the greatest IDs.
+func (st *Stream) XRange(ctx context.Context, start, end string, cfg *ReadConfig) ([]Entry, error) {
+	if cfg == nil {
+		cfg = &ReadConfig{}
+	}
+
+	rc, ok := st.redirect()
+	if !ok {
+		return st.rangeOnFragment(start, end, cfg)
+	}
+
+	// Redirect to the partition owner
+	xrangeCmd := protocol.NewXRange(st.name, start, end)
+	if cfg.HasCount {
+		xrangeCmd.SetCount(cfg.Count)
+	}
+	cmd := xrangeCmd.Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	items, err := cmd.Result()
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	return ParseEntries(items)
+}
+
+func (st *Stream) readOnFragment(ctx context.Context, rawID string, cfg *ReadConfig) ([]Entry, error) {
+	var (
+		id  ID
+		err error
+	)
+	if rawID != LastID {
+		id, err = ParseID(rawID, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	timer := time.NewTimer(cfg.block())
+	defer timer.Stop()
+
+	f, err := st.s.loadFragment(st.primaryPartition(), st.fragmentName)
+	switch {
+	case errors.Is(err, errFragmentNotFound):
+		if cfg.block() <= 0 {
+			return nil, nil
+		}
+		// The readers don't create the stream, they wait until an entry is
+		// added. All the entries of a new stream are after LastID.
+		f, err = st.s.awaitFragment(ctx, st.primaryPartition(), st.fragmentName, timer)
+		if err != nil || f == nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case rawID == LastID:
+		f.RLock()
+		id = f.lastID
+		f.RUnlock()
+	}
+
+	for {
+		f.RLock()
+		entries := f.after(id, cfg.count())
+		notify := f.notify
+		f.RUnlock()
+
+		if len(entries) > 0 || cfg.block() <= 0 {
+			return entries, nil
+		}
+
+		wait, err := st.await(ctx, f, notify, timer)
+		if err != nil || !wait {
+			return nil, err
+		}
+	}
+}
+
+func (st *Stream) readOnOwner(ctx context.Context, rawID string, cfg *ReadConfig) ([]Entry, error) {
+	rc, ok := st.redirect()
+	if !ok {
+		return st.readOnFragment(ctx, rawID, cfg)
+	}
+
+	// Redirect to the partition owner
+	xreadCmd := protocol.NewXRead(st.name, rawID)
+	if cfg.HasCount {
+		xreadCmd.SetCount(cfg.Count)
+	}
+	if cfg.HasBlock {
+		xreadCmd.SetBlock(cfg.Block.Milliseconds())
+	}
+	cmd := xreadCmd.Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	items, err := cmd.Result()
+	if err != nil {
+		return nil, protocol.ConvertError(err)
+	}
+	return ParseEntries(items)
+}
+
+// XRead returns the entries with IDs greater than rawID. LastID can be used to
+// wait for the entries that will be added after the call. If a block duration is
+// set, it waits for new entries until the timeout.
+func (st *Stream) XRead(ctx context.Context, rawID string, cfg *ReadConfig) ([]Entry, error) {
+	if cfg == nil {
+		cfg = &ReadConfig{}
+	}
+
+	if _, ok := st.redirect(); !ok {
+		return st.readOnFragment(ctx, rawID, cfg)
+	}
+
+	if rawID == LastID && cfg.block() > 0 {
+		// Resolve the latest ID here. Otherwise, the entries added between
+		// two rounds may be missed.
+		info, err := st.XInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rawID = info.LastID.String()
+	}
+
+	return BlockInRounds(ctx, cfg.block(), st.s.client.ReadTimeout(), func(block time.Duration) ([]Entry, error) {
+		rc := *cfg
+		rc.Block = block
+		return st.readOnOwner(ctx, rawID, &rc)
+	})
+}
@@ -0,0 +1,160 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tidwall/redcon"
+)
+
+func writeEntries(conn redcon.Conn, entries []Entry) {
+	conn.WriteArray(len(entries))
+	for _, e := range entries {
+		conn.WriteArray(2)
+		conn.WriteBulkString(e.ID.String())
+
+		keys := make([]string, 0, len(e.Fields))
+		for field := range e.Fields {
+			keys = append(keys, field)
+		}
+		sort.Strings(keys)
+
+		conn.WriteArray(len(keys) * 2)
+		for _, field := range keys {
+			conn.WriteBulkString(field)
+			conn.WriteBulkString(e.Fields[field])
+		}
+	}
+}
+
+// ParseEntries parses a reply that is written by a read command.
+func ParseEntries(items []interface{}) ([]Entry, error) {
+	var entries []Entry
+	for _, item := range items {
+		pair, ok := item.([]interface{})
+		if !ok || len(pair) != 2 {
+			return nil, fmt.Errorf("invalid stream entry: %v", item)
+		}
+		rawID, ok := pair[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid stream entry ID: %v", pair[0])
+		}
+		id, err := ParseID(rawID, 0)
+		if err != nil {
+			return nil, err
+		}
+		rawFields, ok := pair[1].([]interface{})
+		if !ok || len(rawFields)%2 != 0 {
+			return nil, fmt.Errorf("invalid stream entry fields: %v", pair[1])
+		}
+		fields := make(map[string]string, len(rawFields)/2)
+		for i := 0; i < len(rawFields); i += 2 {
+			field, ok := rawFields[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid stream entry field: %v", rawFields[i])
+			}
+			value, ok := rawFields[i+1].(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid stream entry value: %v", rawFields[i+1])
+			}
+			fields[field] = value
+		}
+		entries = append(entries, Entry{ID: id, Fields: fields})
+	}
+	return entries, nil
+}
+
+func writePendingEntries(conn redcon.Conn, pending []PendingEntry) {
+	conn.WriteArray(len(pending))
+	for _, p := range pending {
+		conn.WriteArray(4)
+		conn.WriteBulkString(p.ID.String())
+		conn.WriteBulkString(p.Consumer)
+		conn.WriteInt64(p.Idle.Milliseconds())
+		conn.WriteInt64(p.DeliveryCount)
+	}
+}
+
+// ParsePendingEntries parses a reply that is written by the XPending command.
+func ParsePendingEntries(items []interface{}) ([]PendingEntry, error) {
+	var pending []PendingEntry
+	for _, item := range items {
+		fields, ok := item.([]interface{})
+		if !ok || len(fields) != 4 {
+			return nil, fmt.Errorf("invalid pending entry: %v", item)
+		}
+		rawID, ok := fields[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid pending entry ID: %v", fields[0])
+		}
+		id, err := ParseID(rawID, 0)
+		if err != nil {
+			return nil, err
+		}
+		consumer, ok := fields[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid pending entry consumer: %v", fields[1])
+		}
+		idle, ok := fields[2].(int64)
+		if !ok {
+			return nil, fmt.Errorf("invalid pending entry idle time: %v", fields[2])
+		}
+		deliveryCount, ok := fields[3].(int64)
+		if !ok {
+			return nil, fmt.Errorf("invalid pending entry delivery count: %v", fields[3])
+		}
+		pending = append(pending, PendingEntry{
+			ID:            id,
+			Consumer:      consumer,
+			Idle:          time.Duration(idle) * time.Millisecond,
+			DeliveryCount: deliveryCount,
+		})
+	}
+	return pending, nil
+}
+
+func writeInfo(conn redcon.Conn, info Info) {
+	conn.WriteArray(3)
+	conn.WriteInt64(info.Length)
+	conn.WriteBulkString(info.LastID.String())
+	conn.WriteInt64(info.Groups)
+}
+
+// ParseInfo parses a reply that is written by the XInfo command.
+func ParseInfo(items []interface{}) (Info, error) {
+	if len(items) != 3 {
+		return Info{}, fmt.Errorf("invalid stream info: %v", items)
+	}
+	length, ok := items[0].(int64)
+	if !ok {
+		return Info{}, fmt.Errorf("invalid stream length: %v", items[0])
+	}
+	rawID, ok := items[1].(string)
+	if !ok {
+		return Info{}, fmt.Errorf("invalid stream last ID: %v", items[1])
+	}
+	lastID, err := ParseID(rawID, 0)
+	if err != nil {
+		return Info{}, err
+	}
+	groups, ok := items[2].(int64)
+	if !ok {
+		return Info{}, fmt.Errorf("invalid number of consumer groups: %v", items[2])
+	}
+	return Info{Length: length, LastID: lastID, Groups: groups}, nil
+}
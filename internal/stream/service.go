@@ -0,0 +1,153 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/events"
+	"github.com/olric-data/olric/internal/cluster/partitions"
+	"github.com/olric-data/olric/internal/cluster/routingtable"
+	"github.com/olric-data/olric/internal/environment"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
+	"github.com/olric-data/olric/internal/service"
+	"github.com/olric-data/olric/internal/stats"
+	"github.com/olric-data/olric/pkg/flog"
+)
+
+var (
+	// EntriesTotal is the total number of entries appended to streams during the life of this instance.
+	EntriesTotal = stats.NewInt64Counter()
+
+	// DeliveredTotal is the total number of entries delivered to consumer groups during the life of this instance.
+	DeliveredTotal = stats.NewInt64Counter()
+
+	// AckedTotal is the total number of acknowledged entries during the life of this instance.
+	AckedTotal = stats.NewInt64Counter()
+)
+
+type Service struct {
+	log     *flog.Logger
+	config  *config.Config
+	client  *server.Client
+	server  *server.Server
+	rt      *routingtable.RoutingTable
+	primary *partitions.Partitions
+	backup  *partitions.Partitions
+	wg      sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	creationsMtx sync.Mutex
+	creations    map[string]*creation
+}
+
+// The clients convert the stream errors without a Service, so they are
+// registered at init.
+func init() {
+	registerErrors()
+}
+
+func registerErrors() {
+	protocol.SetError("INVALIDSTREAMID", ErrInvalidID)
+	protocol.SetError("STREAMIDTOOSMALL", ErrIDTooSmall)
+	protocol.SetError("NOGROUP", ErrNoSuchGroup)
+	protocol.SetError("BUSYGROUP", ErrGroupExists)
+	protocol.SetError("STREAMWRITEQUORUM", ErrWriteQuorum)
+}
+
+func NewService(e *environment.Environment) (service.Service, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Service{
+		config:    e.Get("config").(*config.Config),
+		client:    e.Get("client").(*server.Client),
+		server:    e.Get("server").(*server.Server),
+		log:       e.Get("logger").(*flog.Logger),
+		rt:        e.Get("routingtable").(*routingtable.RoutingTable),
+		primary:   e.Get("primary").(*partitions.Partitions),
+		backup:    e.Get("backup").(*partitions.Partitions),
+		ctx:       ctx,
+		cancel:    cancel,
+		creations: make(map[string]*creation),
+	}
+	s.RegisterHandlers()
+	return s, nil
+}
+
+func (s *Service) isAlive() bool {
+	select {
+	case <-s.ctx.Done():
+		// The node is gone.
+		return false
+	default:
+	}
+	return true
+}
+
+func getType(data interface{}) string {
+	t := reflect.TypeOf(data)
+	if t.Kind() == reflect.Ptr {
+		return t.Elem().Name()
+	}
+	return t.Name()
+}
+
+func (s *Service) publishEvent(e events.Event) {
+	defer s.wg.Done()
+
+	rc := s.client.Get(s.rt.This().String())
+	data, err := e.Encode()
+	if err != nil {
+		s.log.V(3).Printf("[ERROR] Failed to encode %s: %v", getType(e), err)
+		return
+	}
+	err = rc.Publish(s.ctx, events.ClusterEventsChannel, data).Err()
+	if err != nil {
+		s.log.V(3).Printf("[ERROR] Failed to publish %s to %s: %v",
+			getType(e), events.ClusterEventsChannel, err)
+	}
+}
+
+// Start starts the stream service.
+func (s *Service) Start() error {
+	// Streams have no background workers.
+	return nil
+}
+
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.cancel()
+	done := make(chan struct{})
+
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+	case <-done:
+	}
+	return nil
+}
+
+var _ service.Service = (*Service)(nil)
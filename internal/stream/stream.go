@@ -0,0 +1,93 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/olric-data/olric/internal/cluster/partitions"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrInvalidID is returned when a stream ID cannot be parsed.
+	ErrInvalidID = errors.New("invalid stream ID")
+
+	// ErrIDTooSmall is returned when an explicit ID is equal or smaller than the latest ID in the stream.
+	ErrIDTooSmall = errors.New("ID is equal or smaller than the target stream top item")
+
+	// ErrNoSuchGroup is returned when the consumer group does not exist.
+	ErrNoSuchGroup = errors.New("no such consumer group")
+
+	// ErrGroupExists is returned when the consumer group already exists.
+	ErrGroupExists = errors.New("consumer group already exists")
+
+	ErrWriteQuorum = errors.New("write quorum cannot be reached")
+	ErrServerGone  = errors.New("server is gone")
+
+	errFragmentNotFound = errors.New("fragment not found")
+)
+
+// Stream implements an append-only log. A stream lives on a single partition
+// and it is replicated to the backup owners of that partition.
+type Stream struct {
+	name         string
+	fragmentName string
+	hkey         uint64
+	s            *Service
+}
+
+// Name exposes name of the Stream.
+func (st *Stream) Name() string {
+	return st.name
+}
+
+func (s *Service) fragmentName(name string) string {
+	return fmt.Sprintf("stream.%s", name)
+}
+
+// NewStream creates and returns a new Stream instance. It checks member count quorum
+// and bootstrapping status before creating a new Stream.
+func (s *Service) NewStream(name string) (*Stream, error) {
+	if err := s.rt.CheckMemberCountQuorum(); err != nil {
+		return nil, err
+	}
+	// An Olric node has to be bootstrapped to function properly.
+	if err := s.rt.CheckBootstrap(); err != nil {
+		return nil, err
+	}
+
+	return &Stream{
+		name:         name,
+		fragmentName: s.fragmentName(name),
+		hkey:         partitions.HKey(name, ""),
+		s:            s,
+	}, nil
+}
+
+// redirect returns a client for the partition owner, if this node doesn't
+// own the stream.
+func (st *Stream) redirect() (*redis.Client, bool) {
+	member := st.s.primary.PartitionByHKey(st.hkey).Owner()
+	if member.CompareByName(st.s.rt.This()) {
+		return nil, false
+	}
+	return st.s.client.Get(member.String()), true
+}
+
+func (st *Stream) primaryPartition() *partitions.Partition {
+	return st.s.primary.PartitionByHKey(st.hkey)
+}
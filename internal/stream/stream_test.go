@@ -0,0 +1,232 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/internal/cluster/partitions"
+	"github.com/olric-data/olric/internal/testcluster"
+	"github.com/olric-data/olric/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func fieldsOf(i int) map[string]string {
+	return map[string]string{"index": strconv.Itoa(i)}
+}
+
+func TestStream_XAdd_XRange_Standalone(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	st, err := s.NewStream("mystream")
+	require.NoError(t, err)
+
+	var ids []ID
+	for i := 0; i < 10; i++ {
+		id, err := st.XAdd(ctx, AutoID, fieldsOf(i), nil)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	length, err := st.XLen(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), length)
+
+	entries, err := st.XRange(ctx, MinID, MaxID, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 10)
+	for i, e := range entries {
+		require.Equal(t, ids[i], e.ID)
+		require.Equal(t, fieldsOf(i), e.Fields)
+	}
+
+	entries, err = st.XRange(ctx, ids[2].String(), ids[5].String(), &ReadConfig{HasCount: true, Count: 2})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, ids[2], entries[0].ID)
+	require.Equal(t, ids[3], entries[1].ID)
+}
+
+func TestStream_XAdd_ExplicitID(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	st, err := s.NewStream("mystream")
+	require.NoError(t, err)
+
+	id, err := st.XAdd(ctx, "5-1", fieldsOf(1), nil)
+	require.NoError(t, err)
+	require.Equal(t, ID{Ms: 5, Seq: 1}, id)
+
+	_, err = st.XAdd(ctx, "5-1", fieldsOf(2), nil)
+	require.ErrorIs(t, err, ErrIDTooSmall)
+
+	_, err = st.XAdd(ctx, "foobar", fieldsOf(2), nil)
+	require.ErrorIs(t, err, ErrInvalidID)
+}
+
+func TestStream_XAdd_MaxLen(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	st, err := s.NewStream("mystream")
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, err = st.XAdd(ctx, AutoID, fieldsOf(i), &AddConfig{HasMaxLen: true, MaxLen: 3})
+		require.NoError(t, err)
+	}
+
+	entries, err := st.XRange(ctx, MinID, MaxID, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, fieldsOf(7), entries[0].Fields)
+}
+
+func TestStream_XRead_Cluster(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s1 := cluster.AddMember(nil).(*Service)
+	s2 := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	st1, err := s1.NewStream("mystream")
+	require.NoError(t, err)
+
+	var ids []ID
+	for i := 0; i < 10; i++ {
+		id, err := st1.XAdd(ctx, AutoID, fieldsOf(i), nil)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	st2, err := s2.NewStream("mystream")
+	require.NoError(t, err)
+
+	entries, err := st2.XRead(ctx, ids[4].String(), nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+	require.Equal(t, ids[5], entries[0].ID)
+
+	info, err := st2.XInfo(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), info.Length)
+	require.Equal(t, ids[9], info.LastID)
+}
+
+func TestStream_XRead_Block(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s1 := cluster.AddMember(nil).(*Service)
+	s2 := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	for _, s := range []*Service{s1, s2} {
+		st, err := s.NewStream("mystream")
+		require.NoError(t, err)
+
+		result := make(chan []Entry, 1)
+		go func() {
+			entries, err := st.XRead(ctx, LastID, &ReadConfig{HasBlock: true, Block: 5 * time.Second})
+			require.NoError(t, err)
+			result <- entries
+		}()
+
+		// Let the reader block on the partition owner.
+		<-time.After(250 * time.Millisecond)
+
+		id, err := st.XAdd(ctx, AutoID, fieldsOf(1), nil)
+		require.NoError(t, err)
+
+		select {
+		case entries := <-result:
+			require.Len(t, entries, 1)
+			require.Equal(t, id, entries[0].ID)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "No entry received")
+		}
+	}
+}
+
+func TestStream_XRead_Block_Timeout(t *testing.T) {
+	cluster := testcluster.New(NewService)
+	s := cluster.AddMember(nil).(*Service)
+	defer cluster.Shutdown()
+
+	st, err := s.NewStream("mystream")
+	require.NoError(t, err)
+
+	entries, err := st.XRead(context.Background(), LastID, &ReadConfig{HasBlock: true, Block: 100 * time.Millisecond})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	entries, err = st.XRead(context.Background(), "0", nil)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// The reads don't create the stream.
+	_, err = s.loadFragment(st.primaryPartition(), st.fragmentName)
+	require.ErrorIs(t, err, errFragmentNotFound)
+	require.Empty(t, s.creations)
+}
+
+func TestStream_Replication(t *testing.T) {
+	cluster := testcluster.New(NewService)
+
+	c1 := testutil.NewConfig()
+	c1.ReplicaCount = 2
+	c1.WriteQuorum = 2
+	s1 := cluster.AddMember(testcluster.NewEnvironment(c1)).(*Service)
+
+	c2 := testutil.NewConfig()
+	c2.ReplicaCount = 2
+	c2.WriteQuorum = 2
+	s2 := cluster.AddMember(testcluster.NewEnvironment(c2)).(*Service)
+	defer cluster.Shutdown()
+
+	ctx := context.Background()
+	st, err := s1.NewStream("mystream")
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, err = st.XAdd(ctx, AutoID, fieldsOf(i), nil)
+		require.NoError(t, err)
+	}
+	require.NoError(t, st.XGroupCreate(ctx, "mygroup", "0"))
+
+	hkey := partitions.HKey("mystream", "")
+	var backup *Service
+	for _, s := range []*Service{s1, s2} {
+		if !s.primary.PartitionByHKey(hkey).Owner().CompareByName(s.rt.This()) {
+			backup = s
+		}
+	}
+	require.NotNil(t, backup)
+
+	f, err := backup.loadFragment(backup.backup.PartitionByHKey(hkey), backup.fragmentName("mystream"))
+	require.NoError(t, err)
+	require.Equal(t, 10, f.Stats().Length)
+	require.Equal(t, 1, f.Stats().Extras["groups"])
+}
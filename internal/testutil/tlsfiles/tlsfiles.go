@@ -0,0 +1,135 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsfiles
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Files contains the paths of a CA certificate, and a certificate signed by
+// the CA for 127.0.0.1 and localhost. The certificate can be used by both
+// servers and clients.
+type Files struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+
+	dir   string
+	ca    *x509.Certificate
+	caKey *ecdsa.PrivateKey
+}
+
+func writePEM(t *testing.T, path, blockType string, data []byte) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, pem.Encode(f, &pem.Block{Type: blockType, Bytes: data}))
+	require.NoError(t, f.Close())
+}
+
+func newSerialNumber(t *testing.T) *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	require.NoError(t, err)
+	return serial
+}
+
+// New generates a new CA and a certificate in a temporary directory. The
+// organizational units are set to the subject of the certificate.
+func New(t *testing.T, organizationalUnits ...string) *Files {
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          newSerialNumber(t),
+		Subject:               pkix.Name{CommonName: "olric-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	data, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(data)
+	require.NoError(t, err)
+
+	f := &Files{
+		dir:      dir,
+		CAFile:   filepath.Join(dir, "ca.crt"),
+		CertFile: filepath.Join(dir, "olric.crt"),
+		KeyFile:  filepath.Join(dir, "olric.key"),
+		ca:       ca,
+		caKey:    caKey,
+	}
+	writePEM(t, f.CAFile, "CERTIFICATE", data)
+	f.Renew(t, "olric-test", organizationalUnits...)
+	return f
+}
+
+// Renew replaces the certificate and the key with a new pair signed by the same
+// CA. The common name of the certificate is set to commonName.
+func (f *Files) Renew(t *testing.T, commonName string, organizationalUnits ...string) {
+	f.writeCertificate(t, f.CertFile, f.KeyFile, commonName, organizationalUnits)
+}
+
+// NewCertificate creates another certificate signed by the same CA, and returns
+// the paths of the certificate and the key.
+func (f *Files) NewCertificate(t *testing.T, commonName string, organizationalUnits ...string) (string, string) {
+	certFile := filepath.Join(f.dir, commonName+".crt")
+	keyFile := filepath.Join(f.dir, commonName+".key")
+	f.writeCertificate(t, certFile, keyFile, commonName, organizationalUnits)
+	return certFile, keyFile
+}
+
+func (f *Files) writeCertificate(t *testing.T, certFile, keyFile, commonName string, organizationalUnits []string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: newSerialNumber(t),
+		Subject: pkix.Name{
+			CommonName:         commonName,
+			OrganizationalUnit: organizationalUnits,
+		},
+		NotBefore:   time.Now().Add(-time.Hour),
+		NotAfter:    time.Now().Add(24 * time.Hour),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	data, err := x509.CreateCertificate(rand.Reader, template, f.ca, &key.PublicKey, f.caKey)
+	require.NoError(t, err)
+
+	keyData, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	writePEM(t, certFile, "CERTIFICATE", data)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyData)
+}
@@ -0,0 +1,206 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// InstrumentationName is the name of the tracer that creates Olric spans.
+const InstrumentationName = "github.com/olric-data/olric"
+
+const traceParentHeader = "traceparent"
+
+var propagator = propagation.TraceContext{}
+
+// Tracer returns the Olric tracer of the given provider. It returns a no-op
+// tracer if the provider is nil.
+func Tracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	return tp.Tracer(InstrumentationName)
+}
+
+// Inject returns the W3C traceparent of the span in ctx. It returns an empty
+// string if ctx doesn't carry a valid span context.
+func Inject(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier.Get(traceParentHeader)
+}
+
+// Extract returns a copy of ctx that carries the remote span context in the
+// given W3C traceparent. ctx is returned as-is if traceParent is malformed.
+func Extract(ctx context.Context, traceParent string) context.Context {
+	carrier := propagation.MapCarrier{traceParentHeader: traceParent}
+	return propagator.Extract(ctx, carrier)
+}
+
+// Attributes returns the common attributes of Olric spans.
+func Attributes(command, addr string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system.name", "olric"),
+		attribute.String("db.operation.name", command),
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return attrs
+	}
+	attrs = append(attrs, attribute.String("server.address", host))
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, attribute.Int("server.port", p))
+	}
+	return attrs
+}
+
+// RecordError records err on the span and sets its status. redis.Nil is not
+// an error.
+func RecordError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// tracedCmd overrides the arguments of the wrapped command. The reply is still
+// parsed by the wrapped command.
+type tracedCmd struct {
+	redis.Cmder
+	args []interface{}
+}
+
+func (t *tracedCmd) Args() []interface{} {
+	return t.args
+}
+
+// untraced is the set of commands issued by go-redis itself while
+// initializing a connection. They are sent as-is.
+var untraced = map[string]struct{}{
+	"hello":  {},
+	"auth":   {},
+	"client": {},
+	"select": {},
+}
+
+// Traced reports whether the command is traced. The commands that initialize a
+// connection are not traced.
+func Traced(command string) bool {
+	_, ok := untraced[strings.ToLower(command)]
+	return !ok
+}
+
+func isTraced(cmd redis.Cmder) bool {
+	return Traced(cmd.Name())
+}
+
+func wrap(traceParent string, cmd redis.Cmder) redis.Cmder {
+	if traceParent == "" || !isTraced(cmd) {
+		return cmd
+	}
+	return &tracedCmd{
+		Cmder: cmd,
+		args:  protocol.WrapTrace(traceParent, cmd.Args()),
+	}
+}
+
+// Hook is a go-redis hook that creates a client span for every command and
+// propagates the trace context to the server in a TRACE envelope.
+type Hook struct {
+	tracer trace.Tracer
+	addr   string
+}
+
+var _ redis.Hook = (*Hook)(nil)
+
+// NewHook returns a new Hook for the server at addr.
+func NewHook(tp trace.TracerProvider, addr string) *Hook {
+	return &Hook{
+		tracer: Tracer(tp),
+		addr:   addr,
+	}
+}
+
+// DialHook implements redis.Hook.
+func (h *Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook.
+func (h *Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !isTraced(cmd) {
+			return next(ctx, cmd)
+		}
+		name := strings.ToLower(cmd.Name())
+
+		ctx, span := h.tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(Attributes(name, h.addr)...),
+		)
+		defer span.End()
+
+		err := next(ctx, wrap(Inject(ctx), cmd))
+		RecordError(span, err)
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook.
+func (h *Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		traced := false
+		for _, cmd := range cmds {
+			if isTraced(cmd) {
+				traced = true
+				break
+			}
+		}
+		if !traced {
+			// Connection initialization
+			return next(ctx, cmds)
+		}
+
+		ctx, span := h.tracer.Start(ctx, "pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(Attributes("pipeline", h.addr)...),
+			trace.WithAttributes(attribute.Int("db.operation.batch.size", len(cmds))),
+		)
+		defer span.End()
+
+		traceParent := Inject(ctx)
+		wrapped := make([]redis.Cmder, 0, len(cmds))
+		for _, cmd := range cmds {
+			wrapped = append(wrapped, wrap(traceParent, cmd))
+		}
+
+		err := next(ctx, wrapped)
+		RecordError(span, err)
+		return err
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing_Inject_Extract(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	ctx, span := Tracer(tp).Start(context.Background(), "test")
+	defer span.End()
+
+	traceParent := Inject(ctx)
+	require.NotEmpty(t, traceParent)
+
+	extracted := Extract(context.Background(), traceParent)
+	_, child := Tracer(tp).Start(extracted, "child")
+	child.End()
+
+	require.Equal(t, span.SpanContext().TraceID(), child.SpanContext().TraceID())
+	require.Len(t, sr.Ended(), 1)
+	require.Equal(t, span.SpanContext().SpanID(), sr.Ended()[0].Parent().SpanID())
+}
+
+func TestTracing_Inject_Without_Span(t *testing.T) {
+	require.Empty(t, Inject(context.Background()))
+
+	// The no-op tracer doesn't create valid span contexts.
+	ctx, span := Tracer(nil).Start(context.Background(), "test")
+	defer span.End()
+	require.Empty(t, Inject(ctx))
+}
+
+func TestTracing_Extract_Malformed(t *testing.T) {
+	ctx := Extract(context.Background(), "malformed")
+	require.Empty(t, Inject(ctx))
+}
+
+func TestTracing_Attributes(t *testing.T) {
+	attrs := Attributes("dm.put", "127.0.0.1:3320")
+	require.Contains(t, attrs, attribute.String("db.system.name", "olric"))
+	require.Contains(t, attrs, attribute.String("db.operation.name", "dm.put"))
+	require.Contains(t, attrs, attribute.String("server.address", "127.0.0.1"))
+	require.Contains(t, attrs, attribute.Int("server.port", 3320))
+
+	require.Len(t, Attributes("dm.put", "malformed"), 2)
+}
+
+func TestTracing_Wrap(t *testing.T) {
+	ctx := context.Background()
+	traceParent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	cmd := protocol.NewPut("mydmap", "mykey", []byte("myvalue")).Command(ctx)
+	wrapped := wrap(traceParent, cmd)
+	require.Equal(t, protocol.WrapTrace(traceParent, cmd.Args()), wrapped.Args())
+
+	// The reply is parsed by the wrapped command.
+	wrapped.SetErr(redis.Nil)
+	require.ErrorIs(t, cmd.Err(), redis.Nil)
+
+	t.Run("Connection initialization", func(t *testing.T) {
+		hello := redis.NewMapStringInterfaceCmd(ctx, "hello", 3)
+		require.Equal(t, hello, wrap(traceParent, hello))
+	})
+
+	t.Run("Empty trace context", func(t *testing.T) {
+		require.Equal(t, cmd, wrap("", cmd))
+	})
+}
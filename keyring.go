@@ -0,0 +1,373 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/discovery"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/redis/go-redis/v9"
+	"github.com/tidwall/redcon"
+	"golang.org/x/sync/errgroup"
+)
+
+// GossipKey is a gossip encryption key in the keyrings of the cluster members.
+// The key itself never leaves the members, it's identified by its fingerprint.
+type GossipKey struct {
+	// Fingerprint is the fingerprint of the key, see GossipKeyFingerprint.
+	Fingerprint string
+
+	// Members is the number of members that have the key in their keyrings.
+	Members int
+
+	// PrimaryMembers is the number of members that use the key to encrypt the
+	// gossip messages.
+	PrimaryMembers int
+}
+
+// gossipKeyFingerprintSize is the number of the bytes of the SHA-256 digest in
+// a fingerprint. It's enough to tell the keys apart, and it doesn't reveal them.
+const gossipKeyFingerprintSize = 8
+
+func fingerprint(key []byte) string {
+	digest := sha256.Sum256(key)
+	return hex.EncodeToString(digest[:gossipKeyFingerprintSize])
+}
+
+// GossipKeyFingerprint returns the fingerprint of a base64 encoded gossip
+// encryption key, the hex encoded first 8 bytes of its SHA-256 digest.
+func GossipKeyFingerprint(encodedKey string) (string, error) {
+	key, err := config.DecodeGossipKey(encodedKey)
+	if err != nil {
+		return "", err
+	}
+	return fingerprint(key), nil
+}
+
+func (db *Olric) keyring() (*memberlist.Keyring, error) {
+	kr := db.rt.Discovery().Keyring()
+	if kr == nil || len(kr.GetKeys()) == 0 {
+		return nil, ErrGossipEncryptionDisabled
+	}
+	return kr, nil
+}
+
+func (db *Olric) changeKeyring(encodedKey string, f func(kr *memberlist.Keyring, key []byte) error) error {
+	kr, err := db.keyring()
+	if err != nil {
+		return err
+	}
+	key, err := config.DecodeGossipKey(encodedKey)
+	if err != nil {
+		return fmt.Errorf("%w: %v", protocol.ErrInvalidArgument, err)
+	}
+	return f(kr, key)
+}
+
+func (db *Olric) members() []discovery.Member {
+	// Don't block the routing table, just get a copy of the members.
+	var members []discovery.Member
+	m := db.rt.Members()
+	m.RLock()
+	m.Range(func(_ uint64, member discovery.Member) bool {
+		members = append(members, member)
+		return true
+	})
+	m.RUnlock()
+	return members
+}
+
+// runOnCluster runs a command on all the cluster members, including this one.
+// The commands should be idempotent, so it's safe to retry after a failure.
+func (db *Olric) runOnCluster(ctx context.Context, command func(ctx context.Context) redis.Cmder) error {
+	var g errgroup.Group
+	for _, member := range db.members() {
+		addr := member.String()
+		g.Go(func() error {
+			cmd := command(ctx)
+			err := db.client.Get(addr).Process(ctx, cmd)
+			if err != nil {
+				db.log.V(3).Printf("[ERROR] %s returned an error on %s: %v", cmd.Name(), addr, err)
+				return fmt.Errorf("%s failed on %s: %w", cmd.Name(), addr, protocol.ConvertError(err))
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+func (db *Olric) installGossipKey(ctx context.Context, key string) error {
+	// Validate the key before sending it to the other members.
+	if _, err := config.DecodeGossipKey(key); err != nil {
+		return fmt.Errorf("%w: %v", protocol.ErrInvalidArgument, err)
+	}
+	return db.runOnCluster(ctx, func(ctx context.Context) redis.Cmder {
+		return protocol.NewClusterKeyringInstall(key).SetLocal().Command(ctx)
+	})
+}
+
+func (db *Olric) findGossipKey(ctx context.Context, key string) (GossipKey, error) {
+	fp, err := GossipKeyFingerprint(key)
+	if err != nil {
+		return GossipKey{}, fmt.Errorf("%w: %v", protocol.ErrInvalidArgument, err)
+	}
+	keys, err := db.gossipKeys(ctx)
+	if err != nil {
+		return GossipKey{}, err
+	}
+	for _, gk := range keys {
+		if gk.Fingerprint == fp {
+			return gk, nil
+		}
+	}
+	return GossipKey{Fingerprint: fp}, nil
+}
+
+func (db *Olric) useGossipKey(ctx context.Context, key string) error {
+	// A member cannot decrypt the messages encrypted with a key that is not
+	// in its keyring. So all the members must have the key before using it.
+	gk, err := db.findGossipKey(ctx, key)
+	if err != nil {
+		return err
+	}
+	if total := len(db.members()); gk.Members < total {
+		return fmt.Errorf("%w: the key is installed on %d of %d members", protocol.ErrInvalidArgument, gk.Members, total)
+	}
+	return db.runOnCluster(ctx, func(ctx context.Context) redis.Cmder {
+		return protocol.NewClusterKeyringUse(key).SetLocal().Command(ctx)
+	})
+}
+
+func (db *Olric) removeGossipKey(ctx context.Context, key string) error {
+	gk, err := db.findGossipKey(ctx, key)
+	if err != nil {
+		return err
+	}
+	if gk.PrimaryMembers > 0 {
+		return fmt.Errorf("%w: the key is the primary key of %d members", protocol.ErrInvalidArgument, gk.PrimaryMembers)
+	}
+	return db.runOnCluster(ctx, func(ctx context.Context) redis.Cmder {
+		return protocol.NewClusterKeyringRemove(key).SetLocal().Command(ctx)
+	})
+}
+
+// gossipKeys collects the keyrings of all the cluster members. The keys are
+// sorted by their fingerprints.
+func (db *Olric) gossipKeys(ctx context.Context) ([]GossipKey, error) {
+	members := db.members()
+	keyrings := make([][]interface{}, len(members))
+
+	var g errgroup.Group
+	for i, member := range members {
+		i, addr := i, member.String()
+		g.Go(func() error {
+			cmd := protocol.NewClusterKeyringList().SetLocal().Command(ctx)
+			err := db.client.Get(addr).Process(ctx, cmd)
+			if err != nil {
+				return fmt.Errorf("%s failed on %s: %w", cmd.Name(), addr, protocol.ConvertError(err))
+			}
+			keyrings[i] = cmd.Val()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*GossipKey)
+	for _, keyring := range keyrings {
+		for i, raw := range keyring {
+			fp, ok := raw.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid gossip key fingerprint: %v", raw)
+			}
+			gk, ok := keys[fp]
+			if !ok {
+				gk = &GossipKey{Fingerprint: fp}
+				keys[fp] = gk
+			}
+			gk.Members++
+			// The primary key is the first one.
+			if i == 0 {
+				gk.PrimaryMembers++
+			}
+		}
+	}
+
+	result := make([]GossipKey, 0, len(keys))
+	for _, gk := range keys {
+		result = append(result, *gk)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Fingerprint < result[j].Fingerprint
+	})
+	return result, nil
+}
+
+func (db *Olric) clusterKeyringInstallCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	installCmd, err := protocol.ParseClusterKeyringInstall(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	if installCmd.Local {
+		err = db.changeKeyring(installCmd.Key, func(kr *memberlist.Keyring, key []byte) error {
+			return kr.AddKey(key)
+		})
+	} else {
+		err = db.installGossipKey(db.ctx, installCmd.Key)
+	}
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteString(protocol.StatusOK)
+}
+
+func (db *Olric) clusterKeyringUseCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	useCmd, err := protocol.ParseClusterKeyringUse(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	if useCmd.Local {
+		err = db.changeKeyring(useCmd.Key, func(kr *memberlist.Keyring, key []byte) error {
+			return kr.UseKey(key)
+		})
+	} else {
+		err = db.useGossipKey(db.ctx, useCmd.Key)
+	}
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteString(protocol.StatusOK)
+}
+
+func (db *Olric) clusterKeyringRemoveCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	removeCmd, err := protocol.ParseClusterKeyringRemove(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	if removeCmd.Local {
+		err = db.changeKeyring(removeCmd.Key, func(kr *memberlist.Keyring, key []byte) error {
+			return kr.RemoveKey(key)
+		})
+	} else {
+		err = db.removeGossipKey(db.ctx, removeCmd.Key)
+	}
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteString(protocol.StatusOK)
+}
+
+func (db *Olric) clusterKeyringListCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	listCmd, err := protocol.ParseClusterKeyringList(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	if listCmd.Local {
+		kr, err := db.keyring()
+		if err != nil {
+			protocol.WriteError(conn, err)
+			return
+		}
+		// The primary key is the first one.
+		keys := kr.GetKeys()
+		conn.WriteArray(len(keys))
+		for _, key := range keys {
+			conn.WriteBulkString(fingerprint(key))
+		}
+		return
+	}
+
+	keys, err := db.gossipKeys(db.ctx)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteArray(len(keys))
+	for _, gk := range keys {
+		conn.WriteArray(3)
+		conn.WriteBulkString(gk.Fingerprint)
+		conn.WriteInt(gk.Members)
+		conn.WriteInt(gk.PrimaryMembers)
+	}
+}
+
+func mapGossipKeys(items []interface{}) ([]GossipKey, error) {
+	keys := make([]GossipKey, 0, len(items))
+	for _, raw := range items {
+		item, ok := raw.([]interface{})
+		if !ok || len(item) != 3 {
+			return nil, fmt.Errorf("invalid gossip key: %v", raw)
+		}
+		fp, ok := item[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid gossip key fingerprint: %v", item[0])
+		}
+		members, ok := item[1].(int64)
+		if !ok {
+			return nil, fmt.Errorf("invalid number of members: %v", item[1])
+		}
+		primaryMembers, ok := item[2].(int64)
+		if !ok {
+			return nil, fmt.Errorf("invalid number of members: %v", item[2])
+		}
+		keys = append(keys, GossipKey{
+			Fingerprint:    fp,
+			Members:        int(members),
+			PrimaryMembers: int(primaryMembers),
+		})
+	}
+	return keys, nil
+}
+
+// processStatusCommand sends a command that replies with OK.
+func processStatusCommand(ctx context.Context, rc *redis.Client, cmd *redis.StatusCmd) error {
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return processProtocolError(err)
+	}
+	return processProtocolError(cmd.Err())
+}
+
+func gossipKeys(ctx context.Context, rc *redis.Client) ([]GossipKey, error) {
+	cmd := protocol.NewClusterKeyringList().Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return nil, processProtocolError(err)
+	}
+	items, err := cmd.Result()
+	if err != nil {
+		return nil, processProtocolError(err)
+	}
+	return mapGossipKeys(items)
+}
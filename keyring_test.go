@@ -0,0 +1,175 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGossipKey(b byte) []byte {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = b
+	}
+	return key
+}
+
+func newTestConfigWithGossipKey(t *testing.T, key []byte) *config.Config {
+	c := testutil.NewConfig()
+	keyring, err := memberlist.NewKeyring(nil, key)
+	require.NoError(t, err)
+	c.MemberlistConfig.SecretKey = key
+	c.MemberlistConfig.Keyring = keyring
+	return c
+}
+
+func testFingerprint(t *testing.T, encodedKey string) string {
+	fp, err := GossipKeyFingerprint(encodedKey)
+	require.NoError(t, err)
+	return fp
+}
+
+func TestGossipKeyFingerprint(t *testing.T) {
+	key := newTestGossipKey(1)
+	fp, err := GossipKeyFingerprint(base64.StdEncoding.EncodeToString(key))
+	require.NoError(t, err)
+	digest := sha256.Sum256(key)
+	require.Equal(t, hex.EncodeToString(digest[:8]), fp)
+
+	other, err := GossipKeyFingerprint(base64.StdEncoding.EncodeToString(newTestGossipKey(2)))
+	require.NoError(t, err)
+	require.NotEqual(t, fp, other)
+
+	_, err = GossipKeyFingerprint("not-a-key")
+	require.Error(t, err)
+}
+
+func TestOlric_GossipKeys_Rotation(t *testing.T) {
+	oldKey := newTestGossipKey(1)
+	newKey := newTestGossipKey(2)
+	encodedOldKey := base64.StdEncoding.EncodeToString(oldKey)
+	encodedNewKey := base64.StdEncoding.EncodeToString(newKey)
+	oldFingerprint := testFingerprint(t, encodedOldKey)
+	newFingerprint := testFingerprint(t, encodedNewKey)
+
+	cluster := newTestOlricCluster(t)
+	db1 := cluster.addMemberWithConfig(t, newTestConfigWithGossipKey(t, oldKey))
+	db2 := cluster.addMemberWithConfig(t, newTestConfigWithGossipKey(t, oldKey))
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db1.rt.This().String()})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	keys, err := c.GossipKeys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []GossipKey{{Fingerprint: oldFingerprint, Members: 2, PrimaryMembers: 2}}, keys)
+
+	// The members don't reveal the keys to each other either.
+	listCmd := protocol.NewClusterKeyringList().SetLocal().Command(ctx)
+	require.NoError(t, c.client.Get(db2.rt.This().String()).Process(ctx, listCmd))
+	require.Equal(t, []interface{}{oldFingerprint}, listCmd.Val())
+
+	// Cannot use a key before installing it on all the members.
+	err = c.UseGossipKey(ctx, encodedNewKey)
+	require.ErrorIs(t, err, protocol.ErrInvalidArgument)
+
+	require.NoError(t, c.InstallGossipKey(ctx, encodedNewKey))
+	keys, err = c.GossipKeys(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []GossipKey{
+		{Fingerprint: oldFingerprint, Members: 2, PrimaryMembers: 2},
+		{Fingerprint: newFingerprint, Members: 2, PrimaryMembers: 0},
+	}, keys)
+
+	// Cannot remove a primary key.
+	err = c.RemoveGossipKey(ctx, encodedOldKey)
+	require.ErrorIs(t, err, protocol.ErrInvalidArgument)
+
+	require.NoError(t, c.UseGossipKey(ctx, encodedNewKey))
+	require.NoError(t, c.RemoveGossipKey(ctx, encodedOldKey))
+
+	keys, err = c.GossipKeys(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []GossipKey{{Fingerprint: newFingerprint, Members: 2, PrimaryMembers: 2}}, keys)
+
+	for _, db := range []*Olric{db1, db2} {
+		require.Equal(t, [][]byte{newKey}, db.rt.Discovery().Keyring().GetKeys())
+	}
+
+	// The members still communicate with each other.
+	members, err := c.Members(ctx)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+}
+
+func TestOlric_GossipKeys_EmbeddedClient(t *testing.T) {
+	key := newTestGossipKey(1)
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMemberWithConfig(t, newTestConfigWithGossipKey(t, key))
+
+	ctx := context.Background()
+	e := db.NewEmbeddedClient()
+
+	newKey := base64.StdEncoding.EncodeToString(newTestGossipKey(2))
+	require.NoError(t, e.InstallGossipKey(ctx, newKey))
+	require.NoError(t, e.UseGossipKey(ctx, newKey))
+
+	keys, err := e.GossipKeys(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []GossipKey{
+		{Fingerprint: testFingerprint(t, base64.StdEncoding.EncodeToString(key)), Members: 1, PrimaryMembers: 0},
+		{Fingerprint: testFingerprint(t, newKey), Members: 1, PrimaryMembers: 1},
+	}, keys)
+}
+
+func TestOlric_GossipKeys_Invalid_Key(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMemberWithConfig(t, newTestConfigWithGossipKey(t, newTestGossipKey(1)))
+
+	e := db.NewEmbeddedClient()
+	err := e.InstallGossipKey(context.Background(), "not-a-key")
+	require.ErrorIs(t, err, protocol.ErrInvalidArgument)
+}
+
+func TestOlric_GossipKeys_Encryption_Disabled(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMember(t)
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db.rt.This().String()})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	_, err = c.GossipKeys(ctx)
+	require.ErrorIs(t, err, ErrGossipEncryptionDisabled)
+
+	err = c.InstallGossipKey(ctx, base64.StdEncoding.EncodeToString(newTestGossipKey(1)))
+	require.ErrorIs(t, err, ErrGossipEncryptionDisabled)
+}
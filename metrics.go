@@ -0,0 +1,210 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/olric-data/olric/internal/metrics"
+	"github.com/olric-data/olric/stats"
+)
+
+// counter is a shortcut for a metric family with a single sample.
+func counter(name, help string, typ metrics.Type, value int64, labels ...metrics.Label) *metrics.Family {
+	f := metrics.NewFamily(name, help, typ)
+	f.Add(float64(value), labels...)
+	return f
+}
+
+func (db *Olric) collectMetrics() []*metrics.Family {
+	s := db.stats(statsConfig{})
+	member := metrics.Label{Name: "member", Value: s.Member.Name}
+
+	families := []*metrics.Family{
+		counter("olric_uptime_seconds", "Number of seconds since the server started.",
+			metrics.Gauge, s.UptimeSeconds, member),
+
+		// Network
+		counter("olric_connections_total", "Total number of connections opened since the server started running.",
+			metrics.Counter, s.Network.ConnectionsTotal, member),
+		counter("olric_current_connections", "Current number of open connections.",
+			metrics.Gauge, s.Network.CurrentConnections, member),
+		counter("olric_written_bytes_total", "Total number of bytes sent by this server to network.",
+			metrics.Counter, s.Network.WrittenBytesTotal, member),
+		counter("olric_read_bytes_total", "Total number of bytes read by this server from network.",
+			metrics.Counter, s.Network.ReadBytesTotal, member),
+		counter("olric_commands_total", "Total number of all requests.",
+			metrics.Counter, s.Network.CommandsTotal, member),
+
+		// DMaps
+		counter("olric_dmap_entries_total", "Total number of entries (including replicas) stored during the life of this instance.",
+			metrics.Counter, s.DMaps.EntriesTotal, member),
+		counter("olric_dmap_delete_hits_total", "Number of deletion requests resulting in an item being removed.",
+			metrics.Counter, s.DMaps.DeleteHits, member),
+		counter("olric_dmap_delete_misses_total", "Number of deletion requests for missing keys.",
+			metrics.Counter, s.DMaps.DeleteMisses, member),
+		counter("olric_dmap_get_hits_total", "Number of entries that have been requested and found present.",
+			metrics.Counter, s.DMaps.GetHits, member),
+		counter("olric_dmap_get_misses_total", "Number of entries that have been requested and not found.",
+			metrics.Counter, s.DMaps.GetMisses, member),
+		counter("olric_dmap_evicted_total", "Number of entries removed from cache to free memory for new entries.",
+			metrics.Counter, s.DMaps.EvictedTotal, member),
+
+		// Pub/Sub
+		counter("olric_pubsub_published_total", "Total number of published messages during the life of this instance.",
+			metrics.Counter, s.PubSub.PublishedTotal, member),
+		counter("olric_pubsub_current_subscribers", "Current number of Pub/Sub listeners.",
+			metrics.Gauge, s.PubSub.CurrentSubscribers, member),
+		counter("olric_pubsub_subscribers_total", "Total number of registered Pub/Sub listeners during the life of this instance.",
+			metrics.Counter, s.PubSub.SubscribersTotal, member),
+		counter("olric_pubsub_current_psubscribers", "Current number of pattern listeners.",
+			metrics.Gauge, s.PubSub.CurrentPSubscribers, member),
+		counter("olric_pubsub_psubscribers_total", "Total number of registered pattern listeners during the life of this instance.",
+			metrics.Counter, s.PubSub.PSubscribersTotal, member),
+		counter("olric_pubsub_current_ssubscribers", "Current number of listeners of sharded channels.",
+			metrics.Gauge, s.PubSub.CurrentSSubscribers, member),
+		counter("olric_pubsub_ssubscribers_total", "Total number of registered listeners of sharded channels during the life of this instance.",
+			metrics.Counter, s.PubSub.SSubscribersTotal, member),
+		counter("olric_pubsub_dropped_messages_total", "Total number of messages dropped because of slow subscribers.",
+			metrics.Counter, s.PubSub.DroppedMessagesTotal, member),
+
+		// Streams
+		counter("olric_stream_entries_total", "Total number of entries appended to streams during the life of this instance.",
+			metrics.Counter, s.Streams.EntriesTotal, member),
+		counter("olric_stream_delivered_total", "Total number of entries delivered to consumer groups during the life of this instance.",
+			metrics.Counter, s.Streams.DeliveredTotal, member),
+		counter("olric_stream_acked_total", "Total number of acknowledged entries during the life of this instance.",
+			metrics.Counter, s.Streams.AckedTotal, member),
+	}
+
+	families = append(families, collectCommandLatencyMetrics(member, s))
+	return append(families, collectPartitionMetrics(member, s)...)
+}
+
+func collectCommandLatencyMetrics(member metrics.Label, s stats.Stats) *metrics.Family {
+	f := metrics.NewFamily("olric_command_duration_seconds",
+		"Latency of the commands served by this member, including the internal ones.", metrics.Histogram)
+
+	commands := make([]string, 0, len(s.CommandLatencies))
+	for command := range s.CommandLatencies {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+
+	for _, command := range commands {
+		cl := s.CommandLatencies[command]
+		label := metrics.Label{Name: "command", Value: command}
+		for _, bucket := range cl.Buckets {
+			le := metrics.Label{Name: "le", Value: metrics.FormatFloat(time.Duration(bucket.UpperBound).Seconds())}
+			f.AddWithSuffix("_bucket", float64(bucket.Count), member, label, le)
+		}
+		f.AddWithSuffix("_bucket", float64(cl.Count), member, label, metrics.Label{Name: "le", Value: "+Inf"})
+		f.AddWithSuffix("_sum", time.Duration(cl.Sum).Seconds(), member, label)
+		f.AddWithSuffix("_count", float64(cl.Count), member, label)
+	}
+	return f
+}
+
+func collectPartitionMetrics(member metrics.Label, s stats.Stats) []*metrics.Family {
+	var (
+		partitionLength = metrics.NewFamily("olric_partition_length",
+			"Total number of entries in the partition.", metrics.Gauge)
+		dmapLength = metrics.NewFamily("olric_partition_dmap_length",
+			"Number of keys of the DMap in the partition.", metrics.Gauge)
+		slabAllocated = metrics.NewFamily("olric_partition_dmap_slab_allocated_bytes",
+			"Total allocated space by the storage engine of the DMap in the partition.", metrics.Gauge)
+		slabInuse = metrics.NewFamily("olric_partition_dmap_slab_inuse_bytes",
+			"Total inuse memory space of the storage engine of the DMap in the partition.", metrics.Gauge)
+		slabGarbage = metrics.NewFamily("olric_partition_dmap_slab_garbage_bytes",
+			"Total garbage space of the storage engine of the DMap in the partition.", metrics.Gauge)
+	)
+
+	collect := func(kind string, parts map[stats.PartitionID]stats.Partition) {
+		for partID, part := range parts {
+			partition := metrics.Label{Name: "partition", Value: strconv.FormatUint(uint64(partID), 10)}
+			k := metrics.Label{Name: "kind", Value: kind}
+			partitionLength.Add(float64(part.Length), member, partition, k)
+
+			for name, dm := range part.DMaps {
+				labels := []metrics.Label{member, partition, k, {Name: "dmap", Value: name}}
+				dmapLength.Add(float64(dm.Length), labels...)
+				slabAllocated.Add(float64(dm.SlabInfo.Allocated), labels...)
+				slabInuse.Add(float64(dm.SlabInfo.Inuse), labels...)
+				slabGarbage.Add(float64(dm.SlabInfo.Garbage), labels...)
+			}
+		}
+	}
+	collect("primary", s.Partitions)
+	collect("backup", s.Backups)
+
+	return []*metrics.Family{partitionLength, dmapLength, slabAllocated, slabInuse, slabGarbage}
+}
+
+func (db *Olric) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	if err := metrics.Write(w, db.collectMetrics()); err != nil {
+		db.log.V(3).Printf("[ERROR] Failed to write metrics: %v", err)
+	}
+}
+
+// newMetricsServer creates the HTTP server of the Prometheus metrics endpoint.
+func (db *Olric) newMetricsServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(db.config.Metrics.Path, db.metricsHandler)
+	return &http.Server{
+		Handler: mux,
+		BaseContext: func(_ net.Listener) context.Context {
+			return db.ctx
+		},
+	}
+}
+
+// startMetricsServer runs the HTTP server of the Prometheus metrics endpoint, if it's enabled.
+func (db *Olric) startMetricsServer() error {
+	if db.metricsServer == nil {
+		return nil
+	}
+
+	addr := net.JoinHostPort(db.config.Metrics.BindAddr, strconv.Itoa(db.config.Metrics.BindPort))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	db.wg.Add(1)
+	go func() {
+		defer db.wg.Done()
+
+		err := db.metricsServer.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			db.log.V(2).Printf("[ERROR] Metrics server returned an error: %v", err)
+		}
+	}()
+
+	db.log.V(2).Printf("[INFO] Metrics endpoint: http://%s%s", listener.Addr(), db.config.Metrics.Path)
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/internal/metrics"
+	"github.com/olric-data/olric/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOlric_Metrics(t *testing.T) {
+	port, err := testutil.GetFreePort()
+	require.NoError(t, err)
+
+	c := testutil.NewConfig()
+	c.Metrics.Enabled = true
+	c.Metrics.BindAddr = "127.0.0.1"
+	c.Metrics.BindPort = port
+
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMemberWithConfig(t, c)
+
+	e := db.NewEmbeddedClient()
+	dm, err := e.NewDMap("mymap")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, dm.Put(ctx, testutil.ToKey(i), testutil.ToVal(i)))
+	}
+
+	db.server.CommandLatencies().With("dm.get").Observe(time.Millisecond)
+
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), c.Metrics.Path)
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, metrics.ContentType, resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	data := string(body)
+
+	member := fmt.Sprintf(`member="%s"`, db.rt.This().String())
+	require.Contains(t, data, "# TYPE olric_commands_total counter\n")
+	require.Contains(t, data, fmt.Sprintf("olric_commands_total{%s}", member))
+	require.Contains(t, data, fmt.Sprintf("olric_dmap_entries_total{%s}", member))
+	require.Contains(t, data, fmt.Sprintf("olric_pubsub_published_total{%s}", member))
+	require.Contains(t, data, "# TYPE olric_partition_dmap_slab_inuse_bytes gauge\n")
+
+	require.Contains(t, data, "# TYPE olric_command_duration_seconds histogram\n")
+	require.Contains(t, data, fmt.Sprintf(`olric_command_duration_seconds_bucket{%s,command="dm.get",le="0.0005"} 0`, member))
+	require.Contains(t, data, fmt.Sprintf(`olric_command_duration_seconds_bucket{%s,command="dm.get",le="0.001"} 1`, member))
+	require.Contains(t, data, fmt.Sprintf(`olric_command_duration_seconds_bucket{%s,command="dm.get",le="+Inf"} 1`, member))
+	require.Contains(t, data, fmt.Sprintf(`olric_command_duration_seconds_sum{%s,command="dm.get"} 0.001`, member))
+	require.Contains(t, data, fmt.Sprintf(`olric_command_duration_seconds_count{%s,command="dm.get"} 1`, member))
+
+	var total int
+	for _, line := range strings.Split(data, "\n") {
+		if !strings.HasPrefix(line, "olric_partition_dmap_length{") {
+			continue
+		}
+		require.Contains(t, line, member)
+		require.Contains(t, line, `dmap="mymap"`)
+		if !strings.Contains(line, `kind="primary"`) {
+			continue
+		}
+		value, err := strconv.Atoi(line[strings.LastIndex(line, " ")+1:])
+		require.NoError(t, err)
+		total += value
+	}
+	require.Equal(t, 10, total)
+}
+
+func TestOlric_Metrics_Disabled(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMember(t)
+	require.Nil(t, db.metricsServer)
+}
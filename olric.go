@@ -30,7 +30,9 @@ package olric
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"runtime"
 	"strconv"
 	"strings"
@@ -40,6 +42,7 @@ import (
 	"github.com/hashicorp/logutils"
 	"github.com/olric-data/olric/config"
 	"github.com/olric-data/olric/hasher"
+	"github.com/olric-data/olric/internal/acl"
 	"github.com/olric-data/olric/internal/checkpoint"
 	"github.com/olric-data/olric/internal/cluster/balancer"
 	"github.com/olric-data/olric/internal/cluster/partitions"
@@ -50,6 +53,7 @@ import (
 	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/pubsub"
 	"github.com/olric-data/olric/internal/server"
+	"github.com/olric-data/olric/internal/stream"
 	"github.com/olric-data/olric/pkg/flog"
 	"github.com/pkg/errors"
 	"github.com/tidwall/redcon"
@@ -98,8 +102,28 @@ var (
 	// It is good to call RefreshMetadata to update the underlying data structures.
 	ErrConnRefused = errors.New("connection refused")
 
+	// ErrRestartRequired is returned by Reload if the new configuration has
+	// changes that cannot be applied without a restart.
+	ErrRestartRequired = errors.New("configuration changes require a restart")
+
 	// ErrWrongPass indicates that the provided password is incorrect during authentication.
 	ErrWrongPass = errors.New("wrong password")
+
+	// ErrMoved means that the partition of a sharded Pub/Sub channel is owned by
+	// another member. It is good to call RefreshMetadata to update the routing table.
+	ErrMoved = errors.New("partition moved")
+
+	// ErrNoPermission means that the authenticated user is not allowed to run the
+	// command, or to access the DMap or the Pub/Sub channel.
+	ErrNoPermission = protocol.ErrNoPerm
+
+	// ErrAuthenticationDisabled means that the authentication is not enabled on
+	// the cluster member, so its passwords cannot be changed.
+	ErrAuthenticationDisabled = errors.New("authentication is not enabled")
+
+	// ErrGossipEncryptionDisabled means that the gossip encryption is not enabled
+	// on the cluster member, so its keyring cannot be changed.
+	ErrGossipEncryptionDisabled = errors.New("gossip encryption is not enabled")
 )
 
 // Olric implements a distributed cache and in-memory key/value data store.
@@ -121,11 +145,24 @@ type Olric struct {
 	server *server.Server
 	client *server.Client
 
+	// HTTP server of the Prometheus metrics endpoint.
+	metricsServer *http.Server
+
+	// Access control lists of the users. It's nil if authentication is disabled.
+	acl *acl.ACL
+
+	// Passwords of the default user. They are changed by a credential rotation.
+	passwords passwords
+
+	// reloadMtx serializes the configuration reloads.
+	reloadMtx sync.Mutex
+
 	rt       *routingtable.RoutingTable
 	balancer *balancer.Balancer
 
 	pubsub *pubsub.Service
 	dmap   *dmap.Service
+	stream *stream.Service
 
 	// Structures for flow control
 	ctx    context.Context
@@ -159,16 +196,20 @@ func prepareConfig(c *config.Config) (*config.Config, error) {
 	c.MemberlistConfig.Name = net.JoinHostPort(c.BindAddr,
 		strconv.Itoa(c.BindPort))
 
-	filter := &logutils.LevelFilter{
-		Levels:   []logutils.LogLevel{"DEBUG", "WARN", "ERROR", "INFO"},
-		MinLevel: logutils.LogLevel(strings.ToUpper(c.LogLevel)),
-		Writer:   c.Logger.Writer(),
-	}
-	c.Logger.SetOutput(filter)
+	c.Logger.SetOutput(newLevelFilter(c.LogLevel, c.Logger.Writer()))
 
 	return c, nil
 }
 
+// newLevelFilter returns a writer that drops the log lines below the level.
+func newLevelFilter(level string, w io.Writer) *logutils.LevelFilter {
+	return &logutils.LevelFilter{
+		Levels:   []logutils.LogLevel{"DEBUG", "WARN", "ERROR", "INFO"},
+		MinLevel: logutils.LogLevel(strings.ToUpper(level)),
+		Writer:   w,
+	}
+}
+
 func initializeServices(db *Olric) error {
 	db.rt = routingtable.New(db.env)
 	db.env.Set("routingtable", db.rt)
@@ -188,6 +229,12 @@ func initializeServices(db *Olric) error {
 	}
 	db.dmap = dm.(*dmap.Service)
 
+	st, err := stream.NewService(db.env)
+	if err != nil {
+		return err
+	}
+	db.stream = st.(*stream.Service)
+
 	return nil
 }
 
@@ -215,6 +262,29 @@ func New(c *config.Config) (*Olric, error) {
 	if c.Authentication.Enabled() {
 		c.Client.Authentication = c.Authentication
 	}
+	if c.TracerProvider != nil {
+		c.Client.TracerProvider = c.TracerProvider
+	}
+
+	var certificateReloader *server.CertificateReloader
+	if c.TLS.Enabled() {
+		certificateReloader, err = server.NewCertificateReloader(c.TLS, flogger)
+		if err != nil {
+			return nil, err
+		}
+		// The members connect to each other with TLS, and present their
+		// certificates as the client certificate.
+		if c.Client.TLSConfig == nil {
+			c.Client.TLSConfig = certificateReloader.ClientConfig()
+		}
+	}
+	var auditLog *server.AuditLog
+	if c.AuditLog.Enabled() {
+		auditLog, err = server.NewAuditLog(c.AuditLog, flogger)
+		if err != nil {
+			return nil, err
+		}
+	}
 	client := server.NewClient(c.Client)
 	e.Set("client", client)
 	e.Set("primary", partitions.New(c.PartitionCount, partitions.PRIMARY))
@@ -235,16 +305,40 @@ func New(c *config.Config) (*Olric, error) {
 		cancel:   cancel,
 	}
 
+	if c.Authentication.Enabled() {
+		db.acl, err = acl.New(c.Authentication, c.RedisCompat.DMapName)
+		if err != nil {
+			return nil, err
+		}
+		db.passwords.primary = c.Authentication.Password
+		db.passwords.secondary = c.Authentication.SecondaryPassword
+	}
+
 	// Create a Redcon server instance
 	rc := &server.Config{
-		BindAddr:        c.BindAddr,
-		BindPort:        c.BindPort,
-		KeepAlivePeriod: c.KeepAlivePeriod,
-		RequireAuth:     c.Authentication.Enabled(),
+		BindAddr:              c.BindAddr,
+		BindPort:              c.BindPort,
+		KeepAlivePeriod:       c.KeepAlivePeriod,
+		RequireAuth:           c.Authentication.Enabled(),
+		Authenticate:          db.authenticate,
+		ACL:                   db.acl,
+		AuditLog:              auditLog,
+		DMapName:              c.RedisCompat.DMapName,
+		Version:               ReleaseVersion,
+		CertificateReloader:   certificateReloader,
+		ClusterRole:           c.TLS.ClusterRole,
+		CommandLatencyBuckets: c.CommandLatencyBuckets,
+		TracerProvider:        c.TracerProvider,
+		SlowLogThreshold:      c.SlowLog.Threshold,
+		SlowLogMaxLen:         c.SlowLog.MaxLen,
 	}
 	srv := server.New(rc, flogger)
 	srv.SetPreConditionFunc(db.preconditionFunc)
 
+	if c.Metrics.Enabled {
+		db.metricsServer = db.newMetricsServer()
+	}
+
 	db.server = srv
 	e.Set("server", srv)
 
@@ -254,7 +348,6 @@ func New(c *config.Config) (*Olric, error) {
 	}
 
 	db.registerCommandHandlers()
-	registerErrors()
 
 	return db, nil
 }
@@ -274,6 +367,18 @@ func (db *Olric) registerCommandHandlers() {
 	db.server.ServeMux().HandleFunc(protocol.Generic.Stats, db.statsCommandHandler)
 	db.server.ServeMux().HandleFunc(protocol.Cluster.Members, db.clusterMembersCommandHandler)
 	db.server.ServeMux().HandleFunc(protocol.Generic.Auth, db.authCommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Generic.ACLWhoAmI, db.aclWhoAmICommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Generic.ACLList, db.aclListCommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Generic.SlowLogGet, db.slowLogGetCommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Generic.SlowLogLen, db.slowLogLenCommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Generic.SlowLogReset, db.slowLogResetCommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Cluster.KeyringInstall, db.clusterKeyringInstallCommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Cluster.KeyringUse, db.clusterKeyringUseCommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Cluster.KeyringRemove, db.clusterKeyringRemoveCommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Cluster.KeyringList, db.clusterKeyringListCommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Cluster.PasswordAdd, db.clusterPasswordAddCommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Cluster.PasswordPromote, db.clusterPasswordPromoteCommandHandler)
+	db.server.ServeMux().HandleFunc(protocol.Cluster.PasswordRetire, db.clusterPasswordRetireCommandHandler)
 }
 
 // callStartedCallback checks passed checkpoint count and calls the callback
@@ -308,6 +413,8 @@ func convertClusterError(err error) error {
 		return ErrServerGone
 	case errors.Is(err, routingtable.ErrOperationTimeout):
 		return ErrOperationTimeout
+	case errors.Is(err, protocol.ErrMoved):
+		return ErrMoved
 	default:
 		return err
 	}
@@ -381,6 +488,18 @@ func (db *Olric) Start() error {
 		return err
 	}
 
+	// Start distributed stream service
+	if err := db.stream.Start(); err != nil {
+		db.log.V(2).Printf("[ERROR] Failed to run the Distributed Stream service: %v", err)
+		return err
+	}
+
+	// Start the Prometheus metrics endpoint
+	if err := db.startMetricsServer(); err != nil {
+		db.log.V(2).Printf("[ERROR] Failed to run the metrics server: %v", err)
+		return err
+	}
+
 	// Warn the user about his/her choice of configuration
 	if db.config.ReplicationMode == config.AsyncReplicationMode && db.config.WriteQuorum > 1 {
 		db.log.V(2).
@@ -430,6 +549,11 @@ func (db *Olric) Shutdown(ctx context.Context) error {
 		latestError = err
 	}
 
+	if err := db.stream.Shutdown(ctx); err != nil {
+		db.log.V(2).Printf("[ERROR] Failed to shutdown Stream service: %v", err)
+		latestError = err
+	}
+
 	if err := db.balancer.Shutdown(ctx); err != nil {
 		db.log.V(2).Printf("[ERROR] Failed to shutdown balancer service: %v", err)
 		latestError = err
@@ -440,6 +564,13 @@ func (db *Olric) Shutdown(ctx context.Context) error {
 		latestError = err
 	}
 
+	if db.metricsServer != nil {
+		if err := db.metricsServer.Shutdown(ctx); err != nil {
+			db.log.V(2).Printf("[ERROR] Failed to shutdown metrics server: %v", err)
+			latestError = err
+		}
+	}
+
 	// Shutdown Redcon server
 	if err := db.server.Shutdown(ctx); err != nil {
 		db.log.V(2).Printf("[ERROR] Failed to shutdown RESP server: %v", err)
@@ -492,7 +623,15 @@ func convertDMapError(err error) error {
 	}
 }
 
+// The errors are registered when the package is initialized, so the clients
+// convert the errors of the servers even if there is no server in the process.
+func init() {
+	registerErrors()
+}
+
 // registerErrors registers application-specific errors with their corresponding prefixes in the error management system.
 func registerErrors() {
 	protocol.SetError("WRONGPASS", ErrWrongPass)
+	protocol.SetError("NOENCRYPTION", ErrGossipEncryptionDisabled)
+	protocol.SetError("NOAUTHENTICATION", ErrAuthenticationDisabled)
 }
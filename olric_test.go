@@ -16,14 +16,19 @@ package olric
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/memberlist"
 	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/testutil"
+	"github.com/olric-data/olric/internal/testutil/tlsfiles"
 	"github.com/olric-data/olric/stats"
 	"github.com/stretchr/testify/require"
 )
@@ -137,3 +142,114 @@ func TestOlricCluster_StartAndShutdown(t *testing.T) {
 		require.Contains(t, st.ClusterMembers, stats.MemberID(member.rt.This().ID))
 	}
 }
+
+func TestOlric_TLS(t *testing.T) {
+	files := tlsfiles.New(t)
+	newConfig := func() *config.Config {
+		c := testutil.NewConfig()
+		c.ReplicaCount = 2
+		c.WriteQuorum = 2
+		c.TLS = &config.TLS{
+			CertFile:     files.CertFile,
+			KeyFile:      files.KeyFile,
+			ClientCAFile: files.CAFile,
+		}
+		return c
+	}
+
+	cluster := newTestOlricCluster(t)
+	db1 := cluster.addMemberWithConfig(t, newConfig())
+	db2 := cluster.addMemberWithConfig(t, newConfig())
+
+	caData, err := os.ReadFile(files.CAFile)
+	require.NoError(t, err)
+	rootCAs := x509.NewCertPool()
+	require.True(t, rootCAs.AppendCertsFromPEM(caData))
+	certificate, err := tls.LoadX509KeyPair(files.CertFile, files.KeyFile)
+	require.NoError(t, err)
+
+	cc := config.NewClient()
+	cc.TLSConfig = &tls.Config{
+		RootCAs:      rootCAs,
+		Certificates: []tls.Certificate{certificate},
+	}
+	require.NoError(t, cc.Sanitize())
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db1.name, db2.name}, WithConfig(cc))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	dm, err := c.NewDMap("mydmap")
+	require.NoError(t, err)
+
+	// Every key is replicated to the other member over TLS.
+	for i := 0; i < 10; i++ {
+		require.NoError(t, dm.Put(ctx, testutil.ToKey(i), testutil.ToVal(i)))
+	}
+	for i := 0; i < 10; i++ {
+		gr, err := dm.Get(ctx, testutil.ToKey(i))
+		require.NoError(t, err)
+		value, err := gr.Byte()
+		require.NoError(t, err)
+		require.Equal(t, testutil.ToVal(i), value)
+	}
+}
+
+func TestOlric_TLS_ClusterRole(t *testing.T) {
+	files := tlsfiles.New(t, "olric-cluster")
+	newConfig := func() *config.Config {
+		c := testutil.NewConfig()
+		c.ReplicaCount = 2
+		c.WriteQuorum = 2
+		c.TLS = &config.TLS{
+			CertFile:     files.CertFile,
+			KeyFile:      files.KeyFile,
+			ClientCAFile: files.CAFile,
+			ClusterRole:  "olric-cluster",
+		}
+		return c
+	}
+
+	cluster := newTestOlricCluster(t)
+	db1 := cluster.addMemberWithConfig(t, newConfig())
+	db2 := cluster.addMemberWithConfig(t, newConfig())
+
+	caData, err := os.ReadFile(files.CAFile)
+	require.NoError(t, err)
+	rootCAs := x509.NewCertPool()
+	require.True(t, rootCAs.AppendCertsFromPEM(caData))
+	certFile, keyFile := files.NewCertificate(t, "olric-client")
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+
+	cc := config.NewClient()
+	cc.TLSConfig = &tls.Config{
+		RootCAs:      rootCAs,
+		Certificates: []tls.Certificate{certificate},
+	}
+	require.NoError(t, cc.Sanitize())
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db1.name, db2.name}, WithConfig(cc))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	dm, err := c.NewDMap("mydmap")
+	require.NoError(t, err)
+
+	// The members replicate the keys to each other with the internal commands.
+	for i := 0; i < 10; i++ {
+		require.NoError(t, dm.Put(ctx, testutil.ToKey(i), testutil.ToVal(i)))
+	}
+
+	// The client certificate doesn't have the cluster role.
+	rc := c.client.Get(db1.name)
+	cmd := protocol.NewPutEntry("mydmap", testutil.ToKey(0), []byte("value")).Command(ctx)
+	err = rc.Process(ctx, cmd)
+	require.ErrorIs(t, protocol.ConvertError(err), protocol.ErrNoPerm)
+}
@@ -20,7 +20,6 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
-	"strconv"
 	"sync"
 	"time"
 
@@ -462,8 +461,11 @@ func (f *FutureIncrByFloat) Result() (float64, error) {
 		if cmd.Err() != nil {
 			return 0, processProtocolError(cmd.Err())
 		}
-		stringRes := cmd.(*redis.Cmd).Val().(string)
-		return strconv.ParseFloat(stringRes, 64)
+		// It's a double on RESP3 connections and a bulk string on RESP2 connections.
+		if res, ok := cmd.(*redis.Cmd).Val().(float64); ok {
+			return res, nil
+		}
+		return cmd.(*redis.Cmd).Float64()
 	default:
 		return 0, ErrNotReady
 	}
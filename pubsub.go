@@ -16,19 +16,64 @@ package olric
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/olric-data/olric/internal/server"
 	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/errgroup"
 )
 
 type PubSub struct {
-	config *pubsubConfig
-	rc     *redis.Client
-	client *server.Client
+	config  *pubsubConfig
+	rc      *redis.Client
+	client  *server.Client
+	cluster pubsubCluster
 }
 
-func newPubSub(client *server.Client, options ...PubSubOption) (*PubSub, error) {
+// pubsubCluster is implemented by the clients to route Pub/Sub commands
+// to the cluster members.
+type pubsubCluster interface {
+	Members(ctx context.Context) ([]Member, error)
+	RefreshMetadata(ctx context.Context) error
+
+	// shardOwner returns a client for the partition owner of a sharded channel.
+	shardOwner(channel string) (*redis.Client, error)
+}
+
+// ClusterChannels is the cluster-wide result of PUBSUB CHANNELS.
+type ClusterChannels struct {
+	// Channels is the sorted union of the active channels on all members.
+	Channels []string `json:"channels"`
+
+	// Members holds the active channels of every member, keyed by member name.
+	Members map[string][]string `json:"members"`
+}
+
+// ClusterNumSub is the cluster-wide result of PUBSUB NUMSUB.
+type ClusterNumSub struct {
+	// Total is the number of subscribers of every channel in the cluster.
+	Total map[string]int64 `json:"total"`
+
+	// Members holds the number of subscribers of every channel on every
+	// member, keyed by member name.
+	Members map[string]map[string]int64 `json:"members"`
+}
+
+// ClusterNumPat is the cluster-wide result of PUBSUB NUMPAT.
+type ClusterNumPat struct {
+	// Total is the sum of the number of unique patterns on every member.
+	Total int64 `json:"total"`
+
+	// Members holds the number of unique patterns on every member, keyed by
+	// member name.
+	Members map[string]int64 `json:"members"`
+}
+
+func newPubSub(client *server.Client, cluster pubsubCluster, options ...PubSubOption) (*PubSub, error) {
 	var (
 		err error
 		rc  *redis.Client
@@ -49,16 +94,13 @@ func newPubSub(client *server.Client, options ...PubSubOption) (*PubSub, error)
 	}
 
 	return &PubSub{
-		config: &pc,
-		rc:     rc,
-		client: client,
+		config:  &pc,
+		rc:      rc,
+		client:  client,
+		cluster: cluster,
 	}, nil
 }
 
-func (ps *PubSub) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
-	return ps.rc.Subscribe(ctx, channels...)
-}
-
 func (ps *PubSub) PSubscribe(ctx context.Context, channels ...string) *redis.PubSub {
 	return ps.rc.PSubscribe(ctx, channels...)
 }
@@ -67,6 +109,51 @@ func (ps *PubSub) Publish(ctx context.Context, channel string, message interface
 	return ps.rc.Publish(ctx, channel, message).Result()
 }
 
+// SSubscribe subscribes the client to the given sharded channels. A sharded
+// channel belongs to a partition, and its messages are only delivered by the
+// partition owner. So all the channels must be owned by the same member.
+//
+// When the partition is moved to another member, the subscription receives a
+// Subscription message with the "sunsubscribe" kind. Call SSubscribe again to
+// subscribe on the new owner. A MOVED error means that the routing table of the
+// client is stale. Call RefreshMetadata before trying again.
+func (ps *PubSub) SSubscribe(ctx context.Context, channels ...string) (*redis.PubSub, error) {
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("at least one channel is required")
+	}
+	rc, err := ps.cluster.shardOwner(channels[0])
+	if err != nil {
+		return nil, err
+	}
+	return rc.SSubscribe(ctx, channels...), nil
+}
+
+// SPublish publishes a message to a sharded channel. The message is sent to the
+// partition owner of the channel, and it's never forwarded to the other members.
+// It refreshes the routing table and tries again if the partition has moved.
+func (ps *PubSub) SPublish(ctx context.Context, channel string, message interface{}) (int64, error) {
+	spublish := func() (int64, error) {
+		rc, err := ps.cluster.shardOwner(channel)
+		if err != nil {
+			return 0, err
+		}
+		count, err := rc.SPublish(ctx, channel, message).Result()
+		if err != nil {
+			return 0, processProtocolError(err)
+		}
+		return count, nil
+	}
+
+	count, err := spublish()
+	if errors.Is(err, ErrMoved) {
+		if err = ps.cluster.RefreshMetadata(ctx); err != nil {
+			return 0, err
+		}
+		return spublish()
+	}
+	return count, err
+}
+
 func (ps *PubSub) PubSubChannels(ctx context.Context, pattern string) ([]string, error) {
 	return ps.rc.PubSubChannels(ctx, pattern).Result()
 }
@@ -78,3 +165,113 @@ func (ps *PubSub) PubSubNumSub(ctx context.Context, channels ...string) (map[str
 func (ps *PubSub) PubSubNumPat(ctx context.Context) (int64, error) {
 	return ps.rc.PubSubNumPat(ctx).Result()
 }
+
+// forEachMember calls f for every cluster member concurrently. It returns the
+// first error.
+func (ps *PubSub) forEachMember(ctx context.Context, f func(name string, rc *redis.Client) error) error {
+	members, err := ps.cluster.Members(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errGr errgroup.Group
+	for _, member := range members {
+		name := member.Name
+		errGr.Go(func() error {
+			if err := f(name, ps.client.Get(name)); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			return nil
+		})
+	}
+	return errGr.Wait()
+}
+
+// ClusterPubSubChannels runs PUBSUB CHANNELS on all cluster members and merges
+// the results. An empty pattern lists all the active channels.
+func (ps *PubSub) ClusterPubSubChannels(ctx context.Context, pattern string) (*ClusterChannels, error) {
+	var mtx sync.Mutex
+	result := &ClusterChannels{
+		Members: make(map[string][]string),
+	}
+	err := ps.forEachMember(ctx, func(name string, rc *redis.Client) error {
+		channels, err := rc.PubSubChannels(ctx, pattern).Result()
+		if err != nil {
+			return err
+		}
+		mtx.Lock()
+		defer mtx.Unlock()
+		result.Members[name] = channels
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]struct{})
+	for _, channels := range result.Members {
+		for _, channel := range channels {
+			set[channel] = struct{}{}
+		}
+	}
+	result.Channels = make([]string, 0, len(set))
+	for channel := range set {
+		result.Channels = append(result.Channels, channel)
+	}
+	sort.Strings(result.Channels)
+	return result, nil
+}
+
+// ClusterPubSubNumSub runs PUBSUB NUMSUB on all cluster members and sums
+// the results.
+func (ps *PubSub) ClusterPubSubNumSub(ctx context.Context, channels ...string) (*ClusterNumSub, error) {
+	var mtx sync.Mutex
+	result := &ClusterNumSub{
+		Total:   make(map[string]int64),
+		Members: make(map[string]map[string]int64),
+	}
+	for _, channel := range channels {
+		result.Total[channel] = 0
+	}
+	err := ps.forEachMember(ctx, func(name string, rc *redis.Client) error {
+		numsub, err := rc.PubSubNumSub(ctx, channels...).Result()
+		if err != nil {
+			return err
+		}
+		mtx.Lock()
+		defer mtx.Unlock()
+		result.Members[name] = numsub
+		for channel, count := range numsub {
+			result.Total[channel] += count
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ClusterPubSubNumPat runs PUBSUB NUMPAT on all cluster members and sums
+// the results.
+func (ps *PubSub) ClusterPubSubNumPat(ctx context.Context) (*ClusterNumPat, error) {
+	var mtx sync.Mutex
+	result := &ClusterNumPat{
+		Members: make(map[string]int64),
+	}
+	err := ps.forEachMember(ctx, func(name string, rc *redis.Client) error {
+		numpat, err := rc.PubSubNumPat(ctx).Result()
+		if err != nil {
+			return err
+		}
+		mtx.Lock()
+		defer mtx.Unlock()
+		result.Members[name] = numpat
+		result.Total += numpat
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -17,38 +17,55 @@ package olric
 import (
 	"context"
 	"fmt"
+	"io"
+	"net"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/testutil"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 )
 
 func pubsubTestRunner(t *testing.T, ps *PubSub, kind, channel string) {
 	ctx := context.Background()
-	var rp *redis.PubSub
+
+	// Go channel which receives messages.
+	var ch <-chan *Message
 	switch kind {
 	case "subscribe":
-		rp = ps.Subscribe(ctx, channel)
+		// Subscribe waits for confirmation that subscription is created.
+		s, err := ps.Subscribe(ctx, []string{channel})
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, s.Close())
+		}()
+		ch = s.Channel()
 	case "psubscribe":
-		rp = ps.PSubscribe(ctx, channel)
-	}
+		rp := ps.PSubscribe(ctx, channel)
+		defer func() {
+			require.NoError(t, rp.Close())
+		}()
 
-	defer func() {
-		require.NoError(t, rp.Close())
-	}()
-
-	// Wait for confirmation that subscription is created before publishing anything.
-	msgi, err := rp.ReceiveTimeout(ctx, time.Second)
-	require.NoError(t, err)
+		// Wait for confirmation that subscription is created before publishing anything.
+		msgi, err := rp.ReceiveTimeout(ctx, time.Second)
+		require.NoError(t, err)
 
-	subs := msgi.(*redis.Subscription)
-	require.Equal(t, kind, subs.Kind)
-	require.Equal(t, channel, subs.Channel)
-	require.Equal(t, 1, subs.Count)
+		subs := msgi.(*redis.Subscription)
+		require.Equal(t, kind, subs.Kind)
+		require.Equal(t, channel, subs.Channel)
+		require.Equal(t, 1, subs.Count)
 
-	// Go channel which receives messages.
-	ch := rp.Channel()
+		messages := make(chan *Message, 10)
+		go func() {
+			for msg := range rp.Channel() {
+				messages <- &Message{Channel: msg.Channel, Payload: msg.Payload}
+			}
+		}()
+		ch = messages
+	}
 
 	expected := make(map[string]struct{})
 	for i := 0; i < 10; i++ {
@@ -126,16 +143,13 @@ func TestPubSub_PubSubChannels(t *testing.T) {
 	ps, err := c.NewPubSub(ToAddress(db.rt.This().String()))
 	require.NoError(t, err)
 
-	rp := ps.Subscribe(ctx, "my-channel")
+	s, err := ps.Subscribe(ctx, []string{"my-channel"})
+	require.NoError(t, err)
 
 	defer func() {
-		require.NoError(t, rp.Close())
+		require.NoError(t, s.Close())
 	}()
 
-	// Wait for confirmation that subscription is created before publishing anything.
-	_, err = rp.ReceiveTimeout(ctx, time.Second)
-	require.NoError(t, err)
-
 	channels, err := ps.PubSubChannels(ctx, "my-*")
 	require.NoError(t, err)
 
@@ -156,16 +170,13 @@ func TestPubSub_PubSubNumSub(t *testing.T) {
 	ps, err := c.NewPubSub(ToAddress(db.rt.This().String()))
 	require.NoError(t, err)
 
-	rp := ps.Subscribe(ctx, "my-channel")
+	s, err := ps.Subscribe(ctx, []string{"my-channel"})
+	require.NoError(t, err)
 
 	defer func() {
-		require.NoError(t, rp.Close())
+		require.NoError(t, s.Close())
 	}()
 
-	// Wait for confirmation that subscription is created before publishing anything.
-	_, err = rp.ReceiveTimeout(ctx, time.Second)
-	require.NoError(t, err)
-
 	numsub, err := ps.PubSubNumSub(ctx, "my-channel", "foobar")
 	require.NoError(t, err)
 
@@ -221,14 +232,12 @@ func TestPubSub_Cluster(t *testing.T) {
 	ps1, err := c.NewPubSub(ToAddress(db1.rt.This().String()))
 	require.NoError(t, err)
 
-	rp := ps1.Subscribe(ctx, "my-channel")
+	s, err := ps1.Subscribe(ctx, []string{"my-channel"})
+	require.NoError(t, err)
 	defer func() {
-		require.NoError(t, rp.Close())
+		require.NoError(t, s.Close())
 	}()
-	// Wait for confirmation that subscription is created before publishing anything.
-	_, err = rp.ReceiveTimeout(ctx, time.Second)
-	require.NoError(t, err)
-	receiveChan := rp.Channel()
+	receiveChan := s.Channel()
 
 	// Create a publisher
 
@@ -261,3 +270,277 @@ L:
 		}
 	}
 }
+
+func TestPubSub_Cluster_Introspection(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db1 := cluster.addMember(t)
+	db2 := cluster.addMember(t)
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db1.name})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+
+	subscribe := func(addr, kind string, channels ...string) {
+		ps, err := c.NewPubSub(ToAddress(addr))
+		require.NoError(t, err)
+
+		if kind == "subscribe" {
+			s, err := ps.Subscribe(ctx, channels)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, s.Close())
+			})
+			return
+		}
+
+		rp := ps.PSubscribe(ctx, channels...)
+		t.Cleanup(func() {
+			require.NoError(t, rp.Close())
+		})
+
+		// Wait for confirmation that subscriptions are created.
+		for range channels {
+			_, err = rp.ReceiveTimeout(ctx, time.Second)
+			require.NoError(t, err)
+		}
+	}
+
+	member1 := db1.rt.This().String()
+	member2 := db2.rt.This().String()
+	subscribe(member1, "subscribe", "my-channel")
+	subscribe(member2, "subscribe", "my-channel", "my-other-channel")
+	subscribe(member2, "psubscribe", "my-*")
+
+	// Use the embedded client too. Both of them must see the same result.
+	e := db1.NewEmbeddedClient()
+	for _, client := range []Client{c, e} {
+		ps, err := client.NewPubSub()
+		require.NoError(t, err)
+
+		channels, err := ps.ClusterPubSubChannels(ctx, "")
+		require.NoError(t, err)
+		require.Equal(t, []string{"my-channel", "my-other-channel"}, channels.Channels)
+		require.Equal(t, []string{"my-channel"}, channels.Members[member1])
+		require.ElementsMatch(t, []string{"my-channel", "my-other-channel"}, channels.Members[member2])
+
+		numsub, err := ps.ClusterPubSubNumSub(ctx, "my-channel", "my-other-channel", "foobar")
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{
+			"my-channel":       2,
+			"my-other-channel": 1,
+			"foobar":           0,
+		}, numsub.Total)
+		require.Equal(t, int64(1), numsub.Members[member1]["my-channel"])
+		require.Equal(t, int64(0), numsub.Members[member1]["my-other-channel"])
+		require.Equal(t, int64(1), numsub.Members[member2]["my-other-channel"])
+
+		numpat, err := ps.ClusterPubSubNumPat(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), numpat.Total)
+		require.Equal(t, map[string]int64{member1: 0, member2: 1}, numpat.Members)
+	}
+}
+
+func TestPubSub_Sharded(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db1 := cluster.addMember(t)
+	db2 := cluster.addMember(t)
+
+	ctx := context.Background()
+	c, err := NewClusterClient([]string{db1.name})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(ctx))
+	}()
+	require.NoError(t, c.RefreshMetadata(ctx))
+
+	e := db2.NewEmbeddedClient()
+	for _, client := range []Client{c, e} {
+		ps, err := client.NewPubSub()
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			channel := fmt.Sprintf("my-channel-%d", i)
+			rp, err := ps.SSubscribe(ctx, channel)
+			require.NoError(t, err)
+
+			// Wait for confirmation that subscription is created before publishing anything.
+			msgi, err := rp.ReceiveTimeout(ctx, time.Second)
+			require.NoError(t, err)
+			require.Equal(t, "ssubscribe", msgi.(*redis.Subscription).Kind)
+
+			count, err := ps.SPublish(ctx, channel, "my-message")
+			require.NoError(t, err)
+			require.Equal(t, int64(1), count)
+
+			msgi, err = rp.ReceiveTimeout(ctx, time.Second)
+			require.NoError(t, err)
+			msg := msgi.(*redis.Message)
+			require.Equal(t, channel, msg.Channel)
+			require.Equal(t, "my-message", msg.Payload)
+			require.NoError(t, rp.Close())
+		}
+	}
+}
+
+// testProxy forwards TCP connections to a cluster member. closeConns closes
+// all the open connections to simulate a network failure.
+type testProxy struct {
+	mtx   sync.Mutex
+	conns []net.Conn
+	addr  string
+}
+
+func newTestProxy(t *testing.T, target string) *testProxy {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = ln.Close()
+	})
+
+	p := &testProxy{addr: ln.Addr().String()}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			upstream, err := net.Dial("tcp", target)
+			if err != nil {
+				_ = conn.Close()
+				continue
+			}
+			p.mtx.Lock()
+			p.conns = append(p.conns, conn, upstream)
+			p.mtx.Unlock()
+			go func() {
+				_, _ = io.Copy(upstream, conn)
+				_ = upstream.Close()
+			}()
+			go func() {
+				_, _ = io.Copy(conn, upstream)
+				_ = conn.Close()
+			}()
+		}
+	}()
+	t.Cleanup(p.closeConns)
+	return p
+}
+
+func (p *testProxy) closeConns() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for _, conn := range p.conns {
+		_ = conn.Close()
+	}
+	p.conns = nil
+}
+
+func receiveMessage(t *testing.T, s *Subscription) *Message {
+	select {
+	case msg := <-s.Channel():
+		require.NotNil(t, msg)
+		return msg
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "no message received")
+	}
+	return nil
+}
+
+func TestPubSub_Subscribe_Retained(t *testing.T) {
+	newConfig := func() *config.Config {
+		c := testutil.NewConfig()
+		c.PubSub.Retention = map[string]config.ChannelRetention{
+			"my-channel": {MaxMessages: 100},
+		}
+		return c
+	}
+	cluster := newTestOlricCluster(t)
+	db1 := cluster.addMemberWithConfig(t, newConfig())
+	db2 := cluster.addMemberWithConfig(t, newConfig())
+
+	ctx := context.Background()
+	e := db2.NewEmbeddedClient()
+	publisher, err := e.NewPubSub(ToAddress(db2.name))
+	require.NoError(t, err)
+	for i := 1; i <= 3; i++ {
+		_, err = publisher.Publish(ctx, "my-channel", fmt.Sprintf("my-message-%d", i))
+		require.NoError(t, err)
+	}
+
+	proxy := newTestProxy(t, db1.name)
+	ps, err := db1.NewEmbeddedClient().NewPubSub(ToAddress(proxy.addr))
+	require.NoError(t, err)
+
+	s, err := ps.Subscribe(ctx, []string{"my-channel"}, FromSequence(2))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+
+	for i := 2; i <= 3; i++ {
+		msg := receiveMessage(t, s)
+		require.Equal(t, "my-channel", msg.Channel)
+		require.Equal(t, uint64(i), msg.Sequence)
+		require.Equal(t, fmt.Sprintf("my-message-%d", i), msg.Payload)
+	}
+
+	_, err = publisher.Publish(ctx, "my-channel", "my-message-4")
+	require.NoError(t, err)
+	msg := receiveMessage(t, s)
+	require.Equal(t, uint64(4), msg.Sequence)
+
+	// The last message is not acknowledged.
+	s.Ack(&Message{Channel: "my-channel", Sequence: 3})
+	require.Equal(t, map[string]uint64{"my-channel": 3}, s.Sequences())
+
+	proxy.closeConns()
+	_, err = publisher.Publish(ctx, "my-channel", "my-message-5")
+	require.NoError(t, err)
+
+	// Resumes from the last acknowledged message after the reconnect.
+	for i := 4; i <= 5; i++ {
+		msg = receiveMessage(t, s)
+		require.Equal(t, uint64(i), msg.Sequence)
+		require.Equal(t, fmt.Sprintf("my-message-%d", i), msg.Payload)
+	}
+}
+
+func TestPubSub_Subscribe_Retained_Latest(t *testing.T) {
+	c := testutil.NewConfig()
+	c.PubSub.Retention = map[string]config.ChannelRetention{
+		"my-channel": {MaxMessages: 100},
+	}
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMemberWithConfig(t, c)
+
+	ctx := context.Background()
+	ps, err := db.NewEmbeddedClient().NewPubSub()
+	require.NoError(t, err)
+
+	_, err = ps.Publish(ctx, "my-channel", "my-message-1")
+	require.NoError(t, err)
+
+	s, err := ps.Subscribe(ctx, []string{"my-channel", "not-retained"})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+	require.Equal(t, map[string]uint64{"my-channel": 1, "not-retained": 0}, s.Sequences())
+
+	_, err = ps.Publish(ctx, "my-channel", "my-message-2")
+	require.NoError(t, err)
+	msg := receiveMessage(t, s)
+	require.Equal(t, uint64(2), msg.Sequence)
+	require.Equal(t, "my-message-2", msg.Payload)
+
+	_, err = ps.Publish(ctx, "not-retained", "my-message")
+	require.NoError(t, err)
+	msg = receiveMessage(t, s)
+	require.Equal(t, "not-retained", msg.Channel)
+	require.Equal(t, uint64(0), msg.Sequence)
+}
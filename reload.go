@@ -0,0 +1,73 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/logutils"
+	"github.com/olric-data/olric/config"
+)
+
+// Reload applies the changes in the configuration that don't require a
+// restart: the log level and verbosity, and the DMap settings, including
+// DMaps.Custom, except the storage engines and the number of the eviction
+// workers. The existing DMaps use the new settings immediately.
+//
+// If any other field is changed, Reload returns ErrRestartRequired with the
+// list of the changes, and nothing is applied. The configuration is only
+// reloaded on this member.
+func (db *Olric) Reload(c *config.Config) error {
+	db.reloadMtx.Lock()
+	defer db.reloadMtx.Unlock()
+
+	if err := c.Sanitize(); err != nil {
+		return err
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if err := c.SetupNetworkConfig(); err != nil {
+		return err
+	}
+
+	if changes := db.config.RestartRequired(c); len(changes) > 0 {
+		return fmt.Errorf("%w:\n\t%s", ErrRestartRequired, strings.Join(changes, "\n\t"))
+	}
+
+	if err := db.dmap.Reload(c.DMaps); err != nil {
+		return err
+	}
+	db.config.DMaps = c.DMaps
+
+	if db.config.LogLevel != c.LogLevel {
+		// The previous filter wraps the original writer.
+		if filter, ok := db.config.Logger.Writer().(*logutils.LevelFilter); ok {
+			db.config.Logger.SetOutput(newLevelFilter(c.LogLevel, filter.Writer))
+		}
+		if c.LogLevel == config.LogLevelDebug {
+			db.log.ShowLineNumber(1)
+		} else {
+			db.log.ShowLineNumber(0)
+		}
+		db.config.LogLevel = c.LogLevel
+	}
+	db.log.SetLevel(c.LogVerbosity)
+	db.config.LogVerbosity = c.LogVerbosity
+
+	db.log.V(2).Printf("[INFO] Configuration has been reloaded")
+	return nil
+}
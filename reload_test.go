@@ -0,0 +1,85 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// newReloadConfig returns a configuration with the same network settings as db.
+func newReloadConfig(db *Olric) *config.Config {
+	c := testutil.NewConfig()
+	c.BindAddr = db.config.BindAddr
+	c.BindPort = db.config.BindPort
+	c.MemberlistConfig.BindPort = db.config.MemberlistConfig.BindPort
+	c.MemberlistConfig.AdvertisePort = db.config.MemberlistConfig.AdvertisePort
+	return c
+}
+
+func TestOlric_Reload(t *testing.T) {
+	db := newTestOlricWithConfig(t, testutil.NewConfig())
+
+	ctx := context.Background()
+	e := db.NewEmbeddedClient()
+	dm, err := e.NewDMap("mydmap")
+	require.NoError(t, err)
+	require.NoError(t, dm.Put(ctx, "mykey", "myvalue"))
+
+	c := newReloadConfig(db)
+	c.LogLevel = config.LogLevelDebug
+	c.LogVerbosity = 6
+	c.DMaps.TriggerCompactionInterval = time.Minute
+	c.DMaps.Custom = map[string]config.DMap{
+		"mydmap": {
+			TTLDuration: time.Millisecond,
+		},
+	}
+	require.NoError(t, db.Reload(c))
+	require.Equal(t, config.LogLevelDebug, db.config.LogLevel)
+	require.Equal(t, int32(6), db.config.LogVerbosity)
+	require.Equal(t, time.Minute, db.config.DMaps.TriggerCompactionInterval)
+
+	// The existing DMap uses the new TTL.
+	require.NoError(t, dm.Put(ctx, "mykey", "myvalue"))
+	<-time.After(10 * time.Millisecond)
+	_, err = dm.Get(ctx, "mykey")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	// Reloading the same configuration again is a no-op.
+	require.NoError(t, db.Reload(newReloadConfig(db)))
+}
+
+func TestOlric_Reload_Restart_Required(t *testing.T) {
+	db := newTestOlricWithConfig(t, testutil.NewConfig())
+
+	c := newReloadConfig(db)
+	c.LogLevel = config.LogLevelDebug
+	c.ReplicaCount = 2
+	c.DMaps.NumEvictionWorkers = db.config.DMaps.NumEvictionWorkers + 1
+
+	err := db.Reload(c)
+	require.ErrorIs(t, err, ErrRestartRequired)
+	require.ErrorContains(t, err, "ReplicaCount: 1 -> 2")
+	require.ErrorContains(t, err, "DMaps.NumEvictionWorkers")
+
+	// Nothing is applied.
+	require.Equal(t, config.DefaultLogLevel, db.config.LogLevel)
+}
@@ -0,0 +1,150 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"time"
+
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/server"
+	"github.com/redis/go-redis/v9"
+	"github.com/tidwall/redcon"
+)
+
+// SlowLogEntry is a record of the slow log of a cluster member. See config.SlowLog for details.
+type SlowLogEntry struct {
+	// ID is the unique, progressive identifier of the record.
+	ID uint64
+
+	// Timestamp is the time when the command was received.
+	Timestamp time.Time
+
+	// Duration is the execution time of the command.
+	Duration time.Duration
+
+	// Args are the command and its arguments. Long arguments and argument lists are truncated.
+	Args []string
+
+	// ClientAddr is the address of the client.
+	ClientAddr string
+}
+
+func mapServerSlowLog(entries []server.SlowLogEntry) []SlowLogEntry {
+	result := make([]SlowLogEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, SlowLogEntry{
+			ID:         entry.ID,
+			Timestamp:  entry.Timestamp,
+			Duration:   entry.Duration,
+			Args:       entry.Args,
+			ClientAddr: entry.ClientAddr,
+		})
+	}
+	return result
+}
+
+func mapRedisSlowLog(entries []redis.SlowLog) []SlowLogEntry {
+	result := make([]SlowLogEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, SlowLogEntry{
+			ID:         uint64(entry.ID),
+			Timestamp:  entry.Time,
+			Duration:   entry.Duration,
+			Args:       entry.Args,
+			ClientAddr: entry.ClientAddr,
+		})
+	}
+	return result
+}
+
+func slowLogGet(ctx context.Context, rc *redis.Client, count int) ([]SlowLogEntry, error) {
+	cmd := protocol.NewSlowLogGet().SetCount(int64(count)).Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return nil, processProtocolError(err)
+	}
+	entries, err := cmd.Result()
+	if err != nil {
+		return nil, processProtocolError(err)
+	}
+	return mapRedisSlowLog(entries), nil
+}
+
+func slowLogLen(ctx context.Context, rc *redis.Client) (int, error) {
+	cmd := protocol.NewSlowLogLen().Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return 0, processProtocolError(err)
+	}
+	length, err := cmd.Result()
+	if err != nil {
+		return 0, processProtocolError(err)
+	}
+	return int(length), nil
+}
+
+func slowLogReset(ctx context.Context, rc *redis.Client) error {
+	cmd := protocol.NewSlowLogReset().Command(ctx)
+	err := rc.Process(ctx, cmd)
+	if err != nil {
+		return processProtocolError(err)
+	}
+	return processProtocolError(cmd.Err())
+}
+
+func (db *Olric) slowLogGetCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	slowLogGetCmd, err := protocol.ParseSlowLogGetCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+
+	// The reply has the same format as Redis, so the Redis clients are able to parse it.
+	entries := db.server.SlowLog().Get(int(slowLogGetCmd.Count))
+	conn.WriteArray(len(entries))
+	for _, entry := range entries {
+		conn.WriteArray(6)
+		conn.WriteInt64(int64(entry.ID))
+		conn.WriteInt64(entry.Timestamp.Unix())
+		conn.WriteInt64(entry.Duration.Microseconds())
+		conn.WriteArray(len(entry.Args))
+		for _, arg := range entry.Args {
+			conn.WriteBulkString(arg)
+		}
+		conn.WriteBulkString(entry.ClientAddr)
+		// Client name
+		conn.WriteBulkString("")
+	}
+}
+
+func (db *Olric) slowLogLenCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	_, err := protocol.ParseSlowLogLenCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	conn.WriteInt(db.server.SlowLog().Len())
+}
+
+func (db *Olric) slowLogResetCommandHandler(conn redcon.Conn, cmd redcon.Command) {
+	_, err := protocol.ParseSlowLogResetCommand(cmd)
+	if err != nil {
+		protocol.WriteError(conn, err)
+		return
+	}
+	db.server.SlowLog().Reset()
+	conn.WriteString(protocol.StatusOK)
+}
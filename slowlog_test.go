@@ -0,0 +1,117 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/olric-data/olric/config"
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/olric-data/olric/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterClient_SlowLog(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	c := testutil.NewConfig()
+	c.SlowLog = &config.SlowLog{
+		Threshold: 50 * time.Millisecond,
+	}
+	db := cluster.addMemberWithConfig(t, c)
+
+	ctx := context.Background()
+	cc, err := NewClusterClient([]string{db.name})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, cc.Close(ctx))
+	}()
+
+	dm, err := cc.NewDMap("mydmap")
+	require.NoError(t, err)
+	require.NoError(t, dm.Put(ctx, "mykey", "myvalue"))
+
+	lx, err := dm.Lock(ctx, "lock.foo.key", time.Second)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, lx.Unlock(ctx))
+	}()
+
+	// Waits for the lock until the deadline.
+	_, err = dm.Lock(ctx, "lock.foo.key", 100*time.Millisecond)
+	require.ErrorIs(t, err, ErrLockNotAcquired)
+
+	length, err := cc.SlowLogLen(ctx, db.name)
+	require.NoError(t, err)
+	require.Equal(t, 1, length)
+
+	entries, err := cc.SlowLog(ctx, db.name, -1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, protocol.DMap.Lock, entries[0].Args[0])
+	require.Equal(t, "mydmap", entries[0].Args[1])
+	require.Equal(t, "lock.foo.key", entries[0].Args[2])
+	require.GreaterOrEqual(t, entries[0].Duration, 100*time.Millisecond)
+	require.NotEmpty(t, entries[0].ClientAddr)
+	require.WithinDuration(t, time.Now(), entries[0].Timestamp, time.Minute)
+
+	require.NoError(t, cc.SlowLogReset(ctx, db.name))
+
+	length, err = cc.SlowLogLen(ctx, db.name)
+	require.NoError(t, err)
+	require.Equal(t, 0, length)
+}
+
+func TestEmbeddedClient_SlowLog(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	newConfig := func() *config.Config {
+		c := testutil.NewConfig()
+		c.SlowLog = &config.SlowLog{
+			// Records everything
+			Threshold: time.Nanosecond,
+			MaxLen:    10,
+		}
+		return c
+	}
+	db1 := cluster.addMemberWithConfig(t, newConfig())
+	db2 := cluster.addMemberWithConfig(t, newConfig())
+
+	ctx := context.Background()
+	e := db1.NewEmbeddedClient()
+	for _, db := range []*Olric{db1, db2} {
+		// The members talk to each other in the background.
+		require.NoError(t, e.SlowLogReset(ctx, db.name))
+
+		_, err := e.Ping(ctx, db.name, "")
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			entries, err := e.SlowLog(ctx, db.name, -1)
+			require.NoError(t, err)
+			for _, entry := range entries {
+				if entry.Args[0] == protocol.Generic.Ping {
+					return true
+				}
+			}
+			return false
+		}, time.Second, 10*time.Millisecond)
+
+		length, err := e.SlowLogLen(ctx, db.name)
+		require.NoError(t, err)
+		require.LessOrEqual(t, length, 10)
+		require.Greater(t, length, 0)
+	}
+}
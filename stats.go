@@ -16,6 +16,7 @@ package olric
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"runtime"
 	"strings"
@@ -26,6 +27,8 @@ import (
 	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/pubsub"
 	"github.com/olric-data/olric/internal/server"
+	internalstats "github.com/olric-data/olric/internal/stats"
+	"github.com/olric-data/olric/internal/stream"
 	"github.com/olric-data/olric/stats"
 	"github.com/tidwall/redcon"
 )
@@ -52,6 +55,7 @@ func (db *Olric) collectPartitionMetrics(partID uint64, part *partitions.Partiti
 		Backups: toMembers(db.backup.PartitionOwnersByID(partID)),
 		Length:  part.Length(),
 		DMaps:   make(map[string]stats.DMap),
+		Streams: make(map[string]stats.Stream),
 	}
 	if len(owners) > 0 {
 		p.PreviousOwners = toMembers(owners[:len(owners)-1])
@@ -59,20 +63,52 @@ func (db *Olric) collectPartitionMetrics(partID uint64, part *partitions.Partiti
 	part.Map().Range(func(name, item interface{}) bool {
 		f := item.(partitions.Fragment)
 		st := f.Stats()
+		if strings.HasPrefix(name.(string), "stream.") {
+			groups, _ := st.Extras["groups"].(int)
+			streamName := strings.TrimPrefix(name.(string), "stream.")
+			p.Streams[streamName] = stats.Stream{
+				Length: st.Length,
+				Groups: groups,
+			}
+			return true
+		}
+		dmapName := strings.TrimPrefix(name.(string), "dmap.")
 		tmp := stats.DMap{
-			Length:    st.Length,
-			NumTables: st.NumTables,
+			Length:     st.Length,
+			NumTables:  st.NumTables,
+			ConfigRule: db.dmap.ConfigRule(dmapName),
 		}
 		tmp.SlabInfo.Allocated = st.Allocated
 		tmp.SlabInfo.Garbage = st.Garbage
 		tmp.SlabInfo.Inuse = st.Inuse
-		dmapName := strings.TrimPrefix(name.(string), "dmap.")
 		p.DMaps[dmapName] = tmp
 		return true
 	})
 	return p
 }
 
+func (db *Olric) collectCommandLatencies() map[string]stats.CommandLatency {
+	latencies := make(map[string]stats.CommandLatency)
+	db.server.CommandLatencies().Range(func(command string, h *internalstats.Histogram) bool {
+		snapshot := h.Snapshot()
+		cl := stats.CommandLatency{
+			Count: snapshot.Count,
+			Sum:   snapshot.Sum.Nanoseconds(),
+		}
+		var cumulative int64
+		for i, bound := range snapshot.Buckets {
+			cumulative += snapshot.Counts[i]
+			cl.Buckets = append(cl.Buckets, stats.LatencyBucket{
+				UpperBound: bound.Nanoseconds(),
+				Count:      cumulative,
+			})
+		}
+		latencies[command] = cl
+		return true
+	})
+	return latencies
+}
+
 func (db *Olric) checkPartitionOwnership(part *partitions.Partition) bool {
 	owners := part.Owners()
 	for _, owner := range owners {
@@ -109,12 +145,21 @@ func (db *Olric) stats(cfg statsConfig) stats.Stats {
 			EvictedTotal: dmap.EvictedTotal.Read(),
 		},
 		PubSub: stats.PubSub{
-			PublishedTotal:      pubsub.PublishedTotal.Read(),
-			CurrentSubscribers:  pubsub.CurrentSubscribers.Read(),
-			SubscribersTotal:    pubsub.SubscribersTotal.Read(),
-			CurrentPSubscribers: pubsub.CurrentPSubscribers.Read(),
-			PSubscribersTotal:   pubsub.PSubscribersTotal.Read(),
+			PublishedTotal:       pubsub.PublishedTotal.Read(),
+			CurrentSubscribers:   pubsub.CurrentSubscribers.Read(),
+			SubscribersTotal:     pubsub.SubscribersTotal.Read(),
+			CurrentPSubscribers:  pubsub.CurrentPSubscribers.Read(),
+			PSubscribersTotal:    pubsub.PSubscribersTotal.Read(),
+			CurrentSSubscribers:  pubsub.CurrentSSubscribers.Read(),
+			SSubscribersTotal:    pubsub.SSubscribersTotal.Read(),
+			DroppedMessagesTotal: pubsub.DroppedMessagesTotal.Read(),
+		},
+		Streams: stats.Streams{
+			EntriesTotal:   stream.EntriesTotal.Read(),
+			DeliveredTotal: stream.DeliveredTotal.Read(),
+			AckedTotal:     stream.AckedTotal.Read(),
 		},
+		CommandLatencies: db.collectCommandLatencies(),
 	}
 
 	if cfg.CollectRuntime {
@@ -128,6 +173,15 @@ func (db *Olric) stats(cfg statsConfig) stats.Stats {
 		runtime.ReadMemStats(&s.Runtime.MemStats)
 	}
 
+	for _, sub := range db.pubsub.Subscribers() {
+		s.PubSub.Subscribers = append(s.PubSub.Subscribers, stats.Subscriber{
+			ID:              sub.ID,
+			RemoteAddr:      sub.RemoteAddr,
+			QueueLength:     sub.QueueLength,
+			DroppedMessages: sub.Dropped,
+		})
+	}
+
 	db.rt.RLock()
 	defer db.rt.RUnlock()
 
@@ -152,6 +206,49 @@ func (db *Olric) stats(cfg statsConfig) stats.Stats {
 	return s
 }
 
+// readStatsReply decodes the reply of the STATS command. It's a JSON document on
+// RESP2 connections and a map on RESP3 connections.
+func readStatsReply(reply interface{}) (stats.Stats, error) {
+	var data []byte
+	switch v := reply.(type) {
+	case string:
+		data = []byte(v)
+	default:
+		var err error
+		data, err = json.Marshal(resp3ToJSON(v))
+		if err != nil {
+			return stats.Stats{}, processProtocolError(err)
+		}
+	}
+
+	var s stats.Stats
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return stats.Stats{}, processProtocolError(err)
+	}
+	return s, nil
+}
+
+// resp3ToJSON converts the RESP3 maps in a reply to the values that can be
+// encoded as JSON objects.
+func resp3ToJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = resp3ToJSON(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = resp3ToJSON(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
 func (db *Olric) statsCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 	statsCmd, err := protocol.ParseStatsCommand(cmd)
 	if err != nil {
@@ -169,5 +266,8 @@ func (db *Olric) statsCommandHandler(conn redcon.Conn, cmd redcon.Command) {
 		protocol.WriteError(conn, err)
 		return
 	}
-	conn.WriteBulk(data)
+	// The stats is a map on RESP3 connections.
+	if err = server.WriteJSON(conn, data); err != nil {
+		protocol.WriteError(conn, err)
+	}
 }
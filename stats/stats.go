@@ -47,6 +47,20 @@ type DMap struct {
 
 	// Number of tables in a storage instance.
 	NumTables int `json:"num_tables"`
+
+	// ConfigRule is the key of DMaps.Custom that configures the DMap: its
+	// name, a prefix or a glob pattern. It's empty if the DMap uses the
+	// global configuration.
+	ConfigRule string `json:"config_rule"`
+}
+
+// Stream denotes a distributed stream instance on the cluster.
+type Stream struct {
+	// Number of entries in the stream.
+	Length int `json:"length"`
+
+	// Number of consumer groups of the stream.
+	Groups int `json:"groups"`
 }
 
 // Partition denotes a partition and its metadata in the cluster.
@@ -62,6 +76,9 @@ type Partition struct {
 
 	// DMaps is a map that contains statistics of DMaps in this partition.
 	DMaps map[string]DMap `json:"dmaps"`
+
+	// Streams is a map that contains statistics of streams in this partition.
+	Streams map[string]Stream `json:"streams"`
 }
 
 // Runtime exposes memory stats and various metrics from Go runtime.
@@ -158,6 +175,68 @@ type PubSub struct {
 
 	// SubscribersTotal is the total number of registered Pub/Sub listeners during the life of this instance.
 	PSubscribersTotal int64 `json:"psubscribers_total"`
+
+	// CurrentSSubscribers is the current number of listeners of sharded channels.
+	CurrentSSubscribers int64 `json:"current_ssubscribers"`
+
+	// SSubscribersTotal is the total number of registered listeners of sharded channels during the life of this instance.
+	SSubscribersTotal int64 `json:"ssubscribers_total"`
+
+	// DroppedMessagesTotal is the total number of messages dropped because of slow subscribers.
+	DroppedMessagesTotal int64 `json:"dropped_messages_total"`
+
+	// Subscribers holds per-connection statistics of the current subscribers.
+	Subscribers []Subscriber `json:"subscribers"`
+}
+
+// Subscriber holds statistics of a subscriber connection.
+type Subscriber struct {
+	// ID is the unique identifier of the connection on this member.
+	ID uint64 `json:"id"`
+
+	// RemoteAddr is the address of the subscriber.
+	RemoteAddr string `json:"remote_addr"`
+
+	// QueueLength is the current number of messages waiting in the outbound queue.
+	QueueLength int `json:"queue_length"`
+
+	// DroppedMessages is the number of messages dropped for this subscriber.
+	DroppedMessages int64 `json:"dropped_messages"`
+}
+
+// Streams holds global stream statistics.
+type Streams struct {
+	// EntriesTotal is the total number of entries appended to streams during the life of this instance.
+	EntriesTotal int64 `json:"entries_total"`
+
+	// DeliveredTotal is the total number of entries delivered to consumer groups during the life of this instance.
+	DeliveredTotal int64 `json:"delivered_total"`
+
+	// AckedTotal is the total number of acknowledged entries during the life of this instance.
+	AckedTotal int64 `json:"acked_total"`
+}
+
+// LatencyBucket denotes a bucket of a latency histogram.
+type LatencyBucket struct {
+	// UpperBound is the inclusive upper bound of the bucket in nanoseconds.
+	UpperBound int64 `json:"upper_bound"`
+
+	// Count is the cumulative number of commands whose latency is less than
+	// or equal to UpperBound.
+	Count int64 `json:"count"`
+}
+
+// CommandLatency denotes the latency histogram of a command.
+type CommandLatency struct {
+	// Count is the total number of commands served by this member.
+	Count int64 `json:"count"`
+
+	// Sum is the sum of the latencies in nanoseconds.
+	Sum int64 `json:"sum"`
+
+	// Buckets is the list of histogram buckets in increasing order. The commands
+	// that are slower than the last bucket are only counted in Count.
+	Buckets []LatencyBucket `json:"buckets"`
 }
 
 // Stats is a struct that exposes statistics about the current state of a member.
@@ -197,4 +276,11 @@ type Stats struct {
 
 	// PubSub holds global Pub/Sub statistics.
 	PubSub PubSub `json:"pub_sub"`
+
+	// Streams holds global stream statistics.
+	Streams Streams `json:"streams"`
+
+	// CommandLatencies holds the latency histograms of the commands, including
+	// the internal ones, keyed by command name.
+	CommandLatencies map[string]CommandLatency `json:"command_latencies"`
 }
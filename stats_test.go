@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/olric-data/olric/config"
 	"github.com/olric-data/olric/internal/dmap"
 	"github.com/olric-data/olric/internal/protocol"
 	"github.com/olric-data/olric/internal/pubsub"
@@ -90,6 +91,37 @@ func TestOlric_Stats(t *testing.T) {
 	}
 }
 
+func TestOlric_Stats_DMap_ConfigRule(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	c := testutil.NewConfig()
+	c.DMaps.Custom = map[string]config.DMap{
+		"sessions:*": {MaxKeys: 1000},
+	}
+	db := cluster.addMemberWithConfig(t, c)
+
+	e := db.NewEmbeddedClient()
+	ctx := context.Background()
+	for _, name := range []string{"sessions:tenant42", "mymap"} {
+		dm, err := e.NewDMap(name)
+		require.NoError(t, err)
+		require.NoError(t, dm.Put(ctx, "mykey", "myvalue"))
+	}
+
+	s, err := e.Stats(ctx, db.rt.This().String())
+	require.NoError(t, err)
+
+	rules := make(map[string]string)
+	for _, part := range s.Partitions {
+		for name, d := range part.DMaps {
+			rules[name] = d.ConfigRule
+		}
+	}
+	require.Equal(t, map[string]string{
+		"sessions:tenant42": "sessions:*",
+		"mymap":             "",
+	}, rules)
+}
+
 func TestOlric_Stats_CollectRuntime(t *testing.T) {
 	cluster := newTestOlricCluster(t)
 	db := cluster.addMember(t)
@@ -277,3 +309,78 @@ func TestStats_DMap(t *testing.T) {
 		require.GreaterOrEqual(t, dmap.EntriesTotal.Read(), int64(10))
 	})
 }
+
+func TestOlric_Stats_CommandLatencies(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+
+	newConfig := func() *config.Config {
+		c := testutil.NewConfig()
+		c.ReplicaCount = 2
+		c.WriteQuorum = 2
+		return c
+	}
+	db1 := cluster.addMemberWithConfig(t, newConfig())
+	db2 := cluster.addMemberWithConfig(t, newConfig())
+
+	c, err := NewClusterClient([]string{db1.name})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, c.Close(context.Background()))
+	}()
+
+	dm, err := c.NewDMap("mymap")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		require.NoError(t, dm.Put(ctx, testutil.ToKey(i), testutil.ToVal(i)))
+	}
+
+	var puts, putEntries int64
+	for _, db := range []*Olric{db1, db2} {
+		s := db.stats(statsConfig{})
+		puts += s.CommandLatencies[protocol.DMap.Put].Count
+		putEntries += s.CommandLatencies[protocol.DMap.PutEntry].Count
+
+		cl := s.CommandLatencies[protocol.DMap.PutEntry]
+		require.Len(t, cl.Buckets, len(config.DefaultCommandLatencyBuckets))
+		for i, bucket := range cl.Buckets {
+			require.Equal(t, config.DefaultCommandLatencyBuckets[i].Nanoseconds(), bucket.UpperBound)
+			require.LessOrEqual(t, bucket.Count, cl.Count)
+		}
+	}
+	require.Equal(t, int64(100), puts)
+	// Every key has a backup on the other member.
+	require.Equal(t, int64(100), putEntries)
+}
+
+func TestOlric_Stats_RESP2_RESP3(t *testing.T) {
+	cluster := newTestOlricCluster(t)
+	db := cluster.addMember(t)
+
+	ctx := context.Background()
+	for _, proto := range []int{protocol.RESP2, protocol.RESP3} {
+		t.Run(fmt.Sprintf("RESP%d", proto), func(t *testing.T) {
+			rc := redis.NewClient(&redis.Options{
+				Addr:     db.rt.This().String(),
+				Protocol: proto,
+			})
+			defer func() {
+				require.NoError(t, rc.Close())
+			}()
+
+			cmd := protocol.NewStats().Command(ctx)
+			require.NoError(t, rc.Process(ctx, cmd))
+			if proto == protocol.RESP2 {
+				require.IsType(t, "", cmd.Val())
+			} else {
+				require.IsType(t, map[interface{}]interface{}{}, cmd.Val())
+			}
+
+			s, err := readStatsReply(cmd.Val())
+			require.NoError(t, err)
+			require.Equal(t, db.rt.This().String(), s.Member.String())
+			require.Equal(t, db.rt.This().ID, s.Member.ID)
+		})
+	}
+}
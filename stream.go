@@ -0,0 +1,260 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olric-data/olric/internal/stream"
+)
+
+const (
+	// StreamAutoID lets the partition owner generate an ID for the new entry.
+	StreamAutoID = stream.AutoID
+
+	// StreamLastID denotes the latest ID in a stream. It can be used to read
+	// only the entries that are added after the call.
+	StreamLastID = stream.LastID
+
+	// StreamMinID denotes the smallest possible ID in a stream.
+	StreamMinID = stream.MinID
+
+	// StreamMaxID denotes the greatest possible ID in a stream.
+	StreamMaxID = stream.MaxID
+
+	// StreamNewEntriesID denotes the entries that are never delivered to
+	// any consumer in a consumer group.
+	StreamNewEntriesID = stream.NewEntriesID
+)
+
+var (
+	// ErrInvalidStreamID is returned when a stream ID cannot be parsed.
+	ErrInvalidStreamID = errors.New("invalid stream ID")
+
+	// ErrStreamIDTooSmall is returned when an explicit ID is equal or smaller than the latest ID in the stream.
+	ErrStreamIDTooSmall = errors.New("ID is equal or smaller than the target stream top item")
+
+	// ErrNoSuchGroup is returned when the consumer group does not exist.
+	ErrNoSuchGroup = errors.New("no such consumer group")
+
+	// ErrGroupExists is returned when the consumer group already exists.
+	ErrGroupExists = errors.New("consumer group already exists")
+)
+
+// StreamEntry is an entry in a distributed stream.
+type StreamEntry struct {
+	// ID is the unique identifier of the entry in the stream. It's in
+	// <milliseconds>-<sequence> format.
+	ID string
+
+	// Fields is the field-value pairs of the entry.
+	Fields map[string]string
+}
+
+// StreamPendingEntry is an entry that is delivered to a consumer but not acknowledged yet.
+type StreamPendingEntry struct {
+	// ID of the entry in the stream.
+	ID string
+
+	// Consumer is the current owner of the entry.
+	Consumer string
+
+	// Idle is the elapsed time since the last delivery of the entry.
+	Idle time.Duration
+
+	// DeliveryCount is the number of times that the entry is delivered.
+	DeliveryCount int64
+}
+
+// StreamInfo describes the current state of a stream.
+type StreamInfo struct {
+	// Length is the number of entries in the stream.
+	Length int64
+
+	// LastID is the latest ID that is added to the stream.
+	LastID string
+
+	// Groups is the number of consumer groups of the stream.
+	Groups int64
+}
+
+// XAddOption is a function for defining options to control behavior of the XAdd command.
+type XAddOption func(*stream.AddConfig)
+
+// MaxLen trims the stream to keep the given number of the latest entries.
+func MaxLen(maxLen int64) XAddOption {
+	return func(cfg *stream.AddConfig) {
+		cfg.HasMaxLen = true
+		cfg.MaxLen = maxLen
+	}
+}
+
+// StreamReadOption is a function for defining options to control behavior of the stream read commands.
+type StreamReadOption func(*stream.ReadConfig)
+
+// StreamCount limits the number of entries returned by a read command.
+func StreamCount(count int) StreamReadOption {
+	return func(cfg *stream.ReadConfig) {
+		cfg.HasCount = true
+		cfg.Count = count
+	}
+}
+
+// Block waits for new entries until the given timeout, if there is no entry to return.
+func Block(block time.Duration) StreamReadOption {
+	return func(cfg *stream.ReadConfig) {
+		cfg.HasBlock = true
+		cfg.Block = block
+	}
+}
+
+// Stream defines methods to access and manipulate distributed streams. A stream
+// is an append-only log that lives on a single partition and is replicated to
+// the backup owners of that partition.
+type Stream interface {
+	// Name exposes name of the Stream.
+	Name() string
+
+	// XAdd appends a new entry to the stream and returns its ID. id is either
+	// StreamAutoID or an explicit ID that is greater than the latest ID in the stream.
+	//
+	// Available options:
+	//
+	// * MaxLen
+	XAdd(ctx context.Context, id string, fields map[string]string, options ...XAddOption) (string, error)
+
+	// XLen returns the number of entries in the stream.
+	XLen(ctx context.Context) (int64, error)
+
+	// XInfo returns the length, the latest ID and the number of consumer groups of the stream.
+	XInfo(ctx context.Context) (*StreamInfo, error)
+
+	// XRange returns the entries with IDs between start and end, inclusive. StreamMinID
+	// and StreamMaxID can be used to denote the smallest and the greatest IDs.
+	//
+	// Available options:
+	//
+	// * StreamCount
+	XRange(ctx context.Context, start, end string, options ...StreamReadOption) ([]StreamEntry, error)
+
+	// XRead returns the entries with IDs greater than id. StreamLastID can be
+	// used to wait for the entries that will be added after the call.
+	//
+	// Available options:
+	//
+	// * StreamCount
+	// * Block
+	XRead(ctx context.Context, id string, options ...StreamReadOption) ([]StreamEntry, error)
+
+	// XGroupCreate creates a new consumer group. The group starts to consume the
+	// entries with IDs greater than id. StreamLastID can be used to consume only
+	// the new entries. It returns ErrGroupExists if the group already exists.
+	XGroupCreate(ctx context.Context, group, id string) error
+
+	// XReadGroup reads entries on behalf of a consumer in a consumer group. If id
+	// is StreamNewEntriesID, it delivers the entries that are never delivered to
+	// any consumer in the group. Otherwise, it returns the pending entries of the
+	// consumer with IDs greater than id.
+	//
+	// Available options:
+	//
+	// * StreamCount
+	// * Block
+	XReadGroup(ctx context.Context, group, consumer, id string, options ...StreamReadOption) ([]StreamEntry, error)
+
+	// XAck removes the given entries from the pending entries list of the consumer
+	// group. It returns the number of acknowledged entries.
+	XAck(ctx context.Context, group string, ids ...string) (int, error)
+
+	// XPending returns the pending entries list of the consumer group.
+	XPending(ctx context.Context, group string) ([]StreamPendingEntry, error)
+
+	// XClaim transfers the ownership of the given pending entries to the consumer,
+	// if they are idle for minIdle at least. It returns the claimed entries.
+	XClaim(ctx context.Context, group, consumer string, minIdle time.Duration, ids ...string) ([]StreamEntry, error)
+
+	// XAutoClaim transfers the ownership of the pending entries that are idle for
+	// minIdle at least to the consumer, starting from the given ID. It returns the
+	// claimed entries. The default number of claimed entries is 100.
+	//
+	// Available options:
+	//
+	// * StreamCount
+	XAutoClaim(ctx context.Context, group, consumer string, minIdle time.Duration, start string, options ...StreamReadOption) ([]StreamEntry, error)
+
+	// Close stops background routines and frees allocated resources.
+	Close(ctx context.Context) error
+}
+
+func convertStreamError(err error) error {
+	switch {
+	case errors.Is(err, stream.ErrInvalidID):
+		return ErrInvalidStreamID
+	case errors.Is(err, stream.ErrIDTooSmall):
+		return ErrStreamIDTooSmall
+	case errors.Is(err, stream.ErrNoSuchGroup):
+		return ErrNoSuchGroup
+	case errors.Is(err, stream.ErrGroupExists):
+		return ErrGroupExists
+	case errors.Is(err, stream.ErrWriteQuorum):
+		return ErrWriteQuorum
+	case errors.Is(err, stream.ErrServerGone):
+		return ErrServerGone
+	default:
+		return convertClusterError(err)
+	}
+}
+
+func toStreamEntries(entries []stream.Entry) []StreamEntry {
+	result := make([]StreamEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, StreamEntry{
+			ID:     e.ID.String(),
+			Fields: e.Fields,
+		})
+	}
+	return result
+}
+
+func toStreamPendingEntries(pending []stream.PendingEntry) []StreamPendingEntry {
+	result := make([]StreamPendingEntry, 0, len(pending))
+	for _, p := range pending {
+		result = append(result, StreamPendingEntry{
+			ID:            p.ID.String(),
+			Consumer:      p.Consumer,
+			Idle:          p.Idle,
+			DeliveryCount: p.DeliveryCount,
+		})
+	}
+	return result
+}
+
+func newAddConfig(options []XAddOption) *stream.AddConfig {
+	var cfg stream.AddConfig
+	for _, opt := range options {
+		opt(&cfg)
+	}
+	return &cfg
+}
+
+func newStreamReadConfig(options []StreamReadOption) *stream.ReadConfig {
+	var cfg stream.ReadConfig
+	for _, opt := range options {
+		opt(&cfg)
+	}
+	return &cfg
+}
@@ -0,0 +1,220 @@
+// Copyright 2018-2026 The Olric Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olric
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/olric-data/olric/internal/protocol"
+	"github.com/redis/go-redis/v9"
+)
+
+// Message is a message received from a channel.
+type Message struct {
+	Channel string
+	Payload string
+
+	// Sequence is the sequence ID of the message. It's zero if the channel is
+	// not retained.
+	Sequence uint64
+}
+
+// Subscription is a subscription to one or more channels. The messages of the
+// retained channels are delivered at least once, the cluster members keep a
+// short-term history of them, see config.PubSub.Retention.
+//
+// The subscription tracks the last acknowledged sequence ID of every channel.
+// It resubscribes automatically after a reconnect, and the messages after the
+// last acknowledged sequence are delivered again. So the messages that are not
+// acknowledged may be received more than once.
+type Subscription struct {
+	mtx       sync.Mutex
+	channels  []string
+	sequences map[string]uint64
+
+	rc     *redis.Client
+	pubsub *redis.PubSub
+	ch     chan *Message
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	closeOnce sync.Once
+}
+
+// Subscribe subscribes the client to the given channels. It returns after the
+// subscription is created. The messages of the retained channels are delivered
+// at least once, see Subscription. Without FromSequence, only the messages
+// published after the subscription are received.
+func (ps *PubSub) Subscribe(ctx context.Context, channels []string, options ...SubscribeOption) (*Subscription, error) {
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("at least one channel is required")
+	}
+
+	var sc subscribeConfig
+	for _, opt := range options {
+		opt(&sc)
+	}
+
+	s := &Subscription{
+		channels:  channels,
+		sequences: make(map[string]uint64),
+		ch:        make(chan *Message, 100),
+		done:      make(chan struct{}),
+	}
+	if sc.FromSequence != nil {
+		// The members expect the last seen sequence ID.
+		var sequence uint64
+		if *sc.FromSequence > 0 {
+			sequence = *sc.FromSequence - 1
+		}
+		for _, channel := range channels {
+			s.sequences[channel] = sequence
+		}
+	}
+
+	// A dedicated client is required to resume the subscription on every
+	// new connection.
+	opt := *ps.rc.Options()
+	onConnect := opt.OnConnect
+	opt.OnConnect = func(ctx context.Context, cn *redis.Conn) error {
+		if onConnect != nil {
+			if err := onConnect(ctx, cn); err != nil {
+				return err
+			}
+		}
+		return s.resume(ctx, cn)
+	}
+	s.rc = redis.NewClient(&opt)
+	s.pubsub = s.rc.Subscribe(ctx, channels...)
+
+	// Wait for confirmation that subscription is created.
+	if _, err := s.pubsub.Receive(ctx); err != nil {
+		_ = s.Close()
+		return nil, processProtocolError(err)
+	}
+
+	s.wg.Add(1)
+	go s.run()
+	return s, nil
+}
+
+// resume sends the last acknowledged sequence IDs to the member. The channels
+// without a sequence ID are resumed from the latest message.
+func (s *Subscription) resume(ctx context.Context, cn *redis.Conn) error {
+	s.mtx.Lock()
+	pubsubResume := protocol.NewPubSubResume()
+	for _, channel := range s.channels {
+		sequence, ok := s.sequences[channel]
+		if ok {
+			pubsubResume.Add(channel, int64(sequence))
+		} else {
+			pubsubResume.Add(channel, protocol.LatestSequence)
+		}
+	}
+	s.mtx.Unlock()
+
+	// The errors are returned by the Receive call in Subscribe, which converts
+	// them.
+	cmd := pubsubResume.Command(ctx)
+	if err := cn.Process(ctx, cmd); err != nil {
+		return err
+	}
+	sequences, err := cmd.Result()
+	if err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for i, channel := range s.channels {
+		if _, ok := s.sequences[channel]; !ok && i < len(sequences) {
+			s.sequences[channel] = uint64(sequences[i])
+		}
+	}
+	return nil
+}
+
+func (s *Subscription) run() {
+	defer s.wg.Done()
+	defer close(s.ch)
+
+	for msg := range s.pubsub.Channel() {
+		m := &Message{
+			Channel: msg.Channel,
+			Payload: msg.Payload,
+		}
+		if len(msg.PayloadSlice) == 2 {
+			// A sequenced message is a [sequence, payload] pair.
+			sequence, err := strconv.ParseUint(msg.PayloadSlice[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			m.Sequence = sequence
+			m.Payload = msg.PayloadSlice[1]
+		}
+		select {
+		case s.ch <- m:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Channel returns a Go channel for receiving the messages. The channel is
+// closed together with the subscription.
+func (s *Subscription) Channel() <-chan *Message {
+	return s.ch
+}
+
+// Ack acknowledges a message and all the previous messages of the channel.
+// After a reconnect, the subscription resumes from the last acknowledged
+// message.
+func (s *Subscription) Ack(msg *Message) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if msg.Sequence > s.sequences[msg.Channel] {
+		s.sequences[msg.Channel] = msg.Sequence
+	}
+}
+
+// Sequences returns the last acknowledged sequence IDs of the channels.
+func (s *Subscription) Sequences() map[string]uint64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	sequences := make(map[string]uint64)
+	for channel, sequence := range s.sequences {
+		sequences[channel] = sequence
+	}
+	return sequences
+}
+
+// Close closes the subscription.
+func (s *Subscription) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.pubsub.Close()
+		s.wg.Wait()
+		if cerr := s.rc.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	})
+	return err
+}